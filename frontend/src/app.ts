@@ -11,7 +11,9 @@ import type {
   CatalogRelationship,
   WorkspaceUIState,
   TableCatalog,
+  RelationshipSuggestion,
   TextBlock,
+  TableIndex,
 } from "./types";
 import { Store, createEmptyDiagram } from "./store";
 import { FIELD_TYPES, CARDINALITY_OPTIONS } from "./types";
@@ -19,6 +21,7 @@ import {
   getRelationshipPathData,
   getFieldAnchor,
   getTableWidth,
+  getFieldTypeLabel,
   getTableFieldColumnStart,
   getFieldNameX,
   ROW_HEIGHT,
@@ -276,6 +279,30 @@ function escapeHtml(s: string): string {
   return div.innerHTML;
 }
 
+/** Tooltip lines for a table's kind, schema, description, owner and tags. */
+function describeTable(t: Table): string[] {
+  const lines: string[] = [];
+  if (t.kind === "view") lines.push("View");
+  if (t.kind === "materialized_view") lines.push("Materialized view");
+  if (t.schema) lines.push(`Schema: ${t.schema}`);
+  if (t.description) lines.push(t.description);
+  if (t.owner) lines.push(`Owner: ${t.owner}`);
+  if (t.tags?.length) lines.push(`Tags: ${t.tags.join(", ")}`);
+  return lines;
+}
+
+/** One-line description of an index for tooltips, e.g. "unique users_email_key (email) where active". */
+function describeIndex(t: Table, ix: TableIndex): string {
+  const keys = ix.columns.map((c) => {
+    const key = c.expression ? `(${c.expression})` : t.fields.find((f) => f.id === c.fieldId)?.name ?? "?";
+    return c.desc ? `${key} desc` : key;
+  });
+  let s = `${ix.unique ? "unique " : ""}${ix.name || "index"} (${keys.join(", ")})`;
+  if (ix.method) s += ` using ${ix.method}`;
+  if (ix.where) s += ` where ${ix.where}`;
+  return s;
+}
+
 function screenToDiagram(sx: number, sy: number): { x: number; y: number } {
   const r = svg.getBoundingClientRect();
   const x = (sx - r.left - pan.x) / zoom;
@@ -660,6 +687,15 @@ function render(): void {
     header.setAttribute("y", String(HEADER_HEIGHT - 8));
     header.setAttribute("class", "table-header");
     header.textContent = t.name;
+    const headerNotes = describeTable(t);
+    if (t.indexes?.length) {
+      headerNotes.push("Indexes:\n" + t.indexes.map((ix) => describeIndex(t, ix)).join("\n"));
+    }
+    if (headerNotes.length > 0) {
+      const title = document.createElementNS("http://www.w3.org/2000/svg", "title");
+      title.textContent = headerNotes.join("\n");
+      header.appendChild(title);
+    }
     g.appendChild(header);
 
     const typeColumnStart = getTableFieldColumnStart(t);
@@ -719,6 +755,22 @@ function render(): void {
         dot.setAttribute("fill", "currentColor");
         pkIcon.appendChild(dot);
         rowGroup.appendChild(pkIcon);
+      } else {
+        // Index marker for fields that lead an index: a diamond, filled when unique.
+        const leading = (t.indexes ?? []).filter((ix) => ix.columns[0]?.fieldId === f.id);
+        if (leading.length > 0) {
+          const ixIcon = document.createElementNS("http://www.w3.org/2000/svg", "path");
+          ixIcon.setAttribute("transform", `translate(${10 + PK_GUTTER / 2}, ${rowY - 4})`);
+          ixIcon.setAttribute("class", "table-field-index-icon");
+          ixIcon.setAttribute("d", "M0,-3.5 L3.5,0 L0,3.5 L-3.5,0 Z");
+          ixIcon.setAttribute("stroke", "currentColor");
+          ixIcon.setAttribute("stroke-width", "1.2");
+          ixIcon.setAttribute("fill", leading.some((ix) => ix.unique) ? "currentColor" : "none");
+          const title = document.createElementNS("http://www.w3.org/2000/svg", "title");
+          title.textContent = leading.map((ix) => describeIndex(t, ix)).join("\n");
+          ixIcon.appendChild(title);
+          rowGroup.appendChild(ixIcon);
+        }
       }
 
       const nameText = document.createElementNS(
@@ -737,7 +789,12 @@ function render(): void {
       typeText.setAttribute("x", String(typeColumnStart));
       typeText.setAttribute("y", String(rowY));
       typeText.setAttribute("class", fieldClass);
-      typeText.textContent = f.type;
+      typeText.textContent = getFieldTypeLabel(f);
+      if (f.fields?.length) {
+        const title = document.createElementNS("http://www.w3.org/2000/svg", "title");
+        title.textContent = f.fields.map((sub) => `${sub.name} ${getFieldTypeLabel(sub)}`).join("\n");
+        typeText.appendChild(title);
+      }
 
       rowGroup.appendChild(nameText);
       rowGroup.appendChild(typeText);
@@ -1222,7 +1279,7 @@ async function exportSQL(dialect: string): Promise<void> {
   }
 }
 
-function promptPostgresOptions(): Promise<{ schema: string } | null> {
+function promptPostgresOptions(): Promise<{ schema: string; alterForeignKeys: boolean } | null> {
   return new Promise((resolve) => {
     const existing = document.querySelector(".modal-overlay");
     if (existing) existing.remove();
@@ -1269,6 +1326,24 @@ function promptPostgresOptions(): Promise<{ schema: string } | null> {
       specifySchemaToggle.setAttribute("aria-pressed", String(specifySchemaOn));
       schemaRow.style.display = specifySchemaOn ? "block" : "none";
     });
+    let alterFksOn = false;
+    const alterFksToggle = document.createElement("button");
+    alterFksToggle.type = "button";
+    alterFksToggle.className = "modal-toggle";
+    alterFksToggle.setAttribute("aria-pressed", "false");
+    alterFksToggle.title =
+      "Add foreign keys with ALTER TABLE after all tables. Foreign keys that form a cycle are always added this way.";
+    alterFksToggle.innerHTML =
+      '<span class="modal-toggle-track"><span class="modal-toggle-thumb"></span></span><span class="modal-toggle-label">Foreign Keys as ALTER TABLE</span>';
+    const alterFksRow = document.createElement("div");
+    alterFksRow.className = "modal-postgres-specify-schema-row";
+    alterFksRow.appendChild(alterFksToggle);
+    contentDiv.appendChild(alterFksRow);
+    alterFksToggle.addEventListener("click", () => {
+      alterFksOn = !alterFksOn;
+      alterFksToggle.classList.toggle("modal-toggle-on", alterFksOn);
+      alterFksToggle.setAttribute("aria-pressed", String(alterFksOn));
+    });
     panel.appendChild(contentDiv);
     const footerDiv = document.createElement("div");
     footerDiv.className = "modal-postgres-export-footer";
@@ -1281,6 +1356,7 @@ function promptPostgresOptions(): Promise<{ schema: string } | null> {
       overlay.remove();
       resolve({
         schema: specifySchemaOn ? schemaInput.value.trim() : "",
+        alterForeignKeys: alterFksOn,
       });
     };
     const cancelBtn = document.createElement("button");
@@ -1305,7 +1381,8 @@ async function exportPostgresSQL(): Promise<void> {
   if (options === null) return;
   const sql = await bridge.exportPostgres(
     JSON.stringify(store.getDiagram()),
-    options.schema
+    options.schema,
+    options.alterForeignKeys
   );
   const path = await bridge.saveFileDialog(
     "Export SQL",
@@ -1483,6 +1560,44 @@ async function exportPlantUML(): Promise<void> {
   }
 }
 
+async function exportDBML(): Promise<void> {
+  if (!bridge.isBackendAvailable()) throw new Error("Backend not available");
+  const dbml = await bridge.exportDBML(JSON.stringify(store.getDiagram()));
+  const path = await bridge.saveFileDialog(
+    "Export DBML",
+    "schema.dbml",
+    "DBML",
+    "*.dbml"
+  );
+  if (path) {
+    await bridge.saveFile(path, dbml);
+    showToast("Exported");
+  }
+}
+
+/** Code generation targets: language, menu label, default file name, filter name and pattern. */
+const CODE_TARGETS: [string, string, string, string, string][] = [
+  ["go", "Go structs", "models.go", "Go", "*.go"],
+  ["sqlalchemy", "SQLAlchemy models", "models.py", "Python", "*.py"],
+  ["typescript", "TypeScript interfaces", "models.ts", "TypeScript", "*.ts"],
+  ["prisma", "Prisma schema", "schema.prisma", "Prisma", "*.prisma"],
+];
+
+async function exportCode(
+  language: string,
+  defaultName: string,
+  filterName: string,
+  pattern: string
+): Promise<void> {
+  if (!bridge.isBackendAvailable()) throw new Error("Backend not available");
+  const code = await bridge.generateCode(language, JSON.stringify(store.getDiagram()));
+  const path = await bridge.saveFileDialog("Generate Code", defaultName, filterName, pattern);
+  if (path) {
+    await bridge.saveFile(path, code);
+    showToast("Exported");
+  }
+}
+
 const PNG_EXPORT_SCALE = 3; // higher resolution (e.g. 3x logical size)
 const PNG_EXPORT_PADDING = 40;
 
@@ -1494,6 +1609,7 @@ const EXPORT_CANVAS_STYLES = `
   .table-header { fill: #1e66f5 !important; font-family: system-ui, -apple-system, sans-serif !important; font-size: 14px !important; font-weight: 600 !important; }
   .table-field { fill: #4c4f69 !important; font-family: system-ui, -apple-system, sans-serif !important; font-size: 12px !important; }
   .table-field-pk-icon { color: #1e66f5 !important; }
+  .table-field-index-icon { color: #8c8fa1 !important; }
   .relationship-path { stroke: #bcc0cc !important; fill: none !important; }
   .relationship-path.selected { stroke: #1e66f5 !important; }
   .relationship-arrowhead { fill: #bcc0cc !important; stroke: none !important; }
@@ -1823,6 +1939,7 @@ const DRIVER_DEFAULT_PORTS: Record<string, number> = {
   mysql: 3306,
   mssql: 1433,
   bigquery: 0,
+  sqlite: 0,
 };
 
 async function openDatabaseConnectionDialog(): Promise<void> {
@@ -1914,6 +2031,7 @@ async function openDatabaseConnectionDialog(): Promise<void> {
     ["mysql", "MySQL"],
     ["mssql", "SQL Server"],
     ["bigquery", "BigQuery"],
+    ["sqlite", "SQLite"],
   ]) {
     const opt = document.createElement("option");
     opt.value = value;
@@ -1957,12 +2075,33 @@ async function openDatabaseConnectionDialog(): Promise<void> {
   dbRow.className = "modal-dbconn-row";
   const dbLabel = document.createElement("label");
   dbLabel.textContent = "Database";
+  const dbFileDiv = document.createElement("div");
+  dbFileDiv.className = "modal-dbconn-file-picker";
   const dbInput = document.createElement("input");
   dbInput.type = "text";
   dbInput.className = "modal-input";
   dbInput.placeholder = "mydb";
+  const dbBrowse = document.createElement("button");
+  dbBrowse.type = "button";
+  dbBrowse.textContent = "Browse...";
+  dbBrowse.className = "modal-dbconn-btn-sm";
+  dbBrowse.style.display = "none";
+  dbBrowse.onclick = async () => {
+    try {
+      const path = await bridge.openFileDialog(
+        "Select SQLite Database",
+        "SQLite",
+        "*.db;*.sqlite;*.sqlite3;*.db3"
+      );
+      if (path) dbInput.value = path;
+    } catch (_) {
+      /* cancelled */
+    }
+  };
+  dbFileDiv.appendChild(dbInput);
+  dbFileDiv.appendChild(dbBrowse);
   dbRow.appendChild(dbLabel);
-  dbRow.appendChild(dbInput);
+  dbRow.appendChild(dbFileDiv);
   rdbmsFields.appendChild(dbRow);
 
   const userRow = document.createElement("div");
@@ -2169,6 +2308,14 @@ async function openDatabaseConnectionDialog(): Promise<void> {
     const isBQ = driverSelect.value === "bigquery";
     rdbmsFields.style.display = isBQ ? "none" : "";
     bqFields.style.display = isBQ ? "" : "none";
+    // A SQLite database is a local file: no server, credentials or SSL.
+    const isSQLite = driverSelect.value === "sqlite";
+    for (const row of [hostRow, portRow, userRow, passRow, sslRow]) {
+      row.style.display = isSQLite ? "none" : "";
+    }
+    dbLabel.textContent = isSQLite ? "Database File" : "Database";
+    dbInput.placeholder = isSQLite ? "path/to/app.db" : "mydb";
+    dbBrowse.style.display = isSQLite ? "" : "none";
     portInput.value = String(DRIVER_DEFAULT_PORTS[driverSelect.value] || 0);
   });
 
@@ -2512,7 +2659,7 @@ async function openDatabaseConnectionDialog(): Promise<void> {
         JSON.stringify(selectedTables)
       );
       overlay.remove();
-      const catalog = JSON.parse(json) as TableCatalog;
+      const catalog = await reviewRelationshipSuggestions(JSON.parse(json) as TableCatalog);
       const tables = catalog?.tables ?? [];
       const relationships = catalog?.relationships ?? [];
       const doc = getActiveDoc();
@@ -2531,7 +2678,15 @@ async function openDatabaseConnectionDialog(): Promise<void> {
               type: f.type,
               nullable: f.nullable,
               primaryKey: f.primaryKey,
+              repeated: f.repeated,
+              fields: f.fields,
             })),
+            indexes: table.indexes,
+            schema: table.schema,
+            description: table.description,
+            tags: table.tags,
+            kind: table.kind,
+            definition: table.definition,
           });
         }
         // Convert imported relationships to catalog relationships
@@ -2600,6 +2755,101 @@ async function openDatabaseConnectionDialog(): Promise<void> {
   document.body.appendChild(overlay);
 }
 
+/** Suggestions at or above this confidence start out checked. */
+const SUGGESTION_PRESELECT_CONFIDENCE = 0.8;
+
+/**
+ * Offers relationships inferred from column names for a database import and returns the
+ * catalog with the ones the user accepts. Catalogs without suggestions are returned as is.
+ */
+async function reviewRelationshipSuggestions(catalog: TableCatalog): Promise<TableCatalog> {
+  const catalogJSON = JSON.stringify(catalog);
+  let suggestions: RelationshipSuggestion[];
+  try {
+    suggestions = JSON.parse(await bridge.suggestRelationships(catalogJSON, "")) as RelationshipSuggestion[];
+  } catch (e) {
+    appendStatus(`Relationship suggestions failed: ${e instanceof Error ? e.message : String(e)}`, "error");
+    return catalog;
+  }
+  if (suggestions.length === 0) return catalog;
+
+  const tableById = new Map(catalog.tables.map((t) => [t.id, t]));
+  const describe = (r: Relationship): string => {
+    const src = tableById.get(r.sourceTableId);
+    const tgt = tableById.get(r.targetTableId);
+    const srcField = src?.fields.find((f) => f.id === r.sourceFieldId)?.name ?? "?";
+    const tgtField = tgt?.fields.find((f) => f.id === r.targetFieldId)?.name ?? "?";
+    return `${tgt?.name ?? "?"}.${tgtField} → ${src?.name ?? "?"}.${srcField}`;
+  };
+
+  const accepted = await new Promise<RelationshipSuggestion[]>((resolve) => {
+    const overlay = document.createElement("div");
+    overlay.className = "modal-overlay";
+    const panel = document.createElement("div");
+    panel.className = "modal-panel modal-panel-postgres-export";
+    const headerDiv = document.createElement("div");
+    headerDiv.className = "modal-postgres-export-header";
+    const title = document.createElement("h2");
+    title.className = "modal-title";
+    title.textContent = "Suggested Relationships";
+    headerDiv.appendChild(title);
+    panel.appendChild(headerDiv);
+    const contentDiv = document.createElement("div");
+    contentDiv.className = "modal-postgres-export-content";
+    const hint = document.createElement("div");
+    hint.className = "modal-dbconn-hint";
+    hint.textContent =
+      "These columns look like foreign keys that the database does not declare. Choose the relationships to add.";
+    contentDiv.appendChild(hint);
+    const list = document.createElement("div");
+    list.className = "modal-dbconn-table-list";
+    const checkboxes: HTMLInputElement[] = [];
+    for (const s of suggestions) {
+      const row = document.createElement("label");
+      row.className = "modal-dbconn-table-item";
+      row.title = s.reason;
+      const cb = document.createElement("input");
+      cb.type = "checkbox";
+      cb.checked = s.confidence >= SUGGESTION_PRESELECT_CONFIDENCE;
+      checkboxes.push(cb);
+      const span = document.createElement("span");
+      span.textContent = `${describe(s.relationship)} (${Math.round(s.confidence * 100)}%)`;
+      row.appendChild(cb);
+      row.appendChild(span);
+      list.appendChild(row);
+    }
+    contentDiv.appendChild(list);
+    panel.appendChild(contentDiv);
+    const footerDiv = document.createElement("div");
+    footerDiv.className = "modal-postgres-export-footer";
+    const footerButtons = document.createElement("div");
+    footerButtons.className = "modal-postgres-export-footer-buttons";
+    const okBtn = document.createElement("button");
+    okBtn.type = "button";
+    okBtn.textContent = "Add Selected";
+    okBtn.onclick = () => {
+      overlay.remove();
+      resolve(suggestions.filter((_, i) => checkboxes[i].checked));
+    };
+    const skipBtn = document.createElement("button");
+    skipBtn.type = "button";
+    skipBtn.textContent = "Skip";
+    skipBtn.onclick = () => {
+      overlay.remove();
+      resolve([]);
+    };
+    footerButtons.appendChild(okBtn);
+    footerButtons.appendChild(skipBtn);
+    footerDiv.appendChild(footerButtons);
+    panel.appendChild(footerDiv);
+    overlay.appendChild(panel);
+    document.body.appendChild(overlay);
+  });
+  if (accepted.length === 0) return catalog;
+  const json = await bridge.acceptRelationshipSuggestions(catalogJSON, JSON.stringify(accepted));
+  return JSON.parse(json) as TableCatalog;
+}
+
 async function openAndImportJSON(): Promise<void> {
   if (!bridge.isBackendAvailable()) {
     showToast("Backend not available (run in Wails)");
@@ -2620,20 +2870,67 @@ async function openAndImportJSON(): Promise<void> {
   }
 }
 
-async function openAndImportSQL(): Promise<void> {
+/** A schema source format imported as a TableCatalog: SQL DDL, Prisma or DBML. */
+interface SchemaImportFormat {
+  label: string;
+  filterPattern: string;
+  defaultName: string;
+  parse: (raw: string, importSource: string) => Promise<string>;
+  emptyHint: string;
+}
+
+const SQL_IMPORT: SchemaImportFormat = {
+  label: "SQL",
+  filterPattern: "*.sql",
+  defaultName: "import.sql",
+  parse: (raw, importSource) => bridge.importSQL(raw, importSource),
+  emptyHint:
+    "No CREATE TABLE statements found. The parser expects PostgreSQL, MySQL, SQL Server or BigQuery DDL: CREATE TABLE name ( col type, ... ); with optional constraints and ALTER TABLE ... ADD CONSTRAINT.",
+};
+
+const PRISMA_IMPORT: SchemaImportFormat = {
+  label: "Prisma",
+  filterPattern: "*.prisma",
+  defaultName: "schema.prisma",
+  parse: (raw, importSource) => bridge.importPrisma(raw, importSource),
+  emptyHint: "No models found. Expected a Prisma schema with model { } blocks.",
+};
+
+const DBML_IMPORT: SchemaImportFormat = {
+  label: "DBML",
+  filterPattern: "*.dbml",
+  defaultName: "import.dbml",
+  parse: (raw, importSource) => bridge.importDBML(raw, importSource),
+  emptyHint: "No tables found. Expected DBML with Table name { } blocks.",
+};
+
+async function openAndImportSchema(format: SchemaImportFormat): Promise<void> {
   if (!bridge.isBackendAvailable()) {
     showToast("Backend not available (run in Wails)");
     return;
   }
-  const path = await bridge.openFileDialog("Open SQL", "SQL", "*.sql");
+  const path = await bridge.openFileDialog(
+    "Open " + format.label,
+    format.label,
+    format.filterPattern
+  );
   if (!path) return;
   try {
     const raw = await bridge.loadFile(path);
-    const importSource = path.replace(/^.*[/\\]/, "") || "import.sql";
-    const json = await bridge.importSQL(raw, importSource);
+    const importSource = path.replace(/^.*[/\\]/, "") || format.defaultName;
+    const json = await format.parse(raw, importSource);
     const catalog = JSON.parse(json) as TableCatalog;
     const tables = catalog?.tables ?? [];
     const relationships = catalog?.relationships ?? [];
+    if (catalog?.sourceDialect) {
+      appendStatus(`Parsed ${importSource} as ${catalog.sourceDialect} DDL`);
+    }
+    for (const warning of catalog?.warnings ?? []) {
+      appendStatus(
+        `${importSource}${warning.line ? ":" + warning.line : ""}: ${warning.message}`,
+        "error"
+      );
+    }
     const doc = getActiveDoc();
     if (doc?.type === "workspace") {
       const w = doc as WorkspaceDoc;
@@ -2651,6 +2948,12 @@ async function openAndImportSQL(): Promise<void> {
             nullable: f.nullable,
             primaryKey: f.primaryKey,
           })),
+          indexes: table.indexes,
+          schema: table.schema,
+          description: table.description,
+          tags: table.tags,
+          kind: table.kind,
+          definition: table.definition,
         });
       }
       // Convert imported relationships to catalog relationships
@@ -2684,9 +2987,9 @@ async function openAndImportSQL(): Promise<void> {
       updateEditorContentVisibility();
       updateWorkspaceCatalogList(w);
       appendStatus(
-        `Imported SQL to catalog: ${catalog.tables.length} tables, ${relationships.length} relationships`
+        `Imported ${format.label} to catalog: ${catalog.tables.length} tables, ${relationships.length} relationships`
       );
-      showToast("Imported SQL to catalog");
+      showToast(`Imported ${format.label} to catalog`);
     } else {
       const d: Diagram = {
         version: 1,
@@ -2695,21 +2998,18 @@ async function openAndImportSQL(): Promise<void> {
       };
       store.setDiagram(d);
       appendStatus(
-        `Imported SQL from ${path}: ${d.tables.length} tables, ${d.relationships.length} relationships`
+        `Imported ${format.label} from ${path}: ${d.tables.length} tables, ${d.relationships.length} relationships`
       );
       if (d.tables.length === 0) {
-        appendStatus(
-          "No CREATE TABLE statements found. The parser expects PostgreSQL-style DDL: CREATE TABLE name ( col type, ... ); with optional PRIMARY KEY and FOREIGN KEY.",
-          "error"
-        );
+        appendStatus(format.emptyHint, "error");
         showToast("No tables found — check Status panel for expected format");
       } else {
-        showToast("Imported SQL");
+        showToast(`Imported ${format.label}`);
       }
     }
   } catch (e) {
     const msg = e instanceof Error ? e.message : String(e);
-    appendStatus(`SQL import failed: ${msg}`, "error");
+    appendStatus(`${format.label} import failed: ${msg}`, "error");
     showToast("Import failed");
   }
 }
@@ -2743,6 +3043,12 @@ async function openAndImportCSV(): Promise<void> {
             nullable: f.nullable,
             primaryKey: f.primaryKey,
           })),
+          indexes: table.indexes,
+          schema: table.schema,
+          description: table.description,
+          tags: table.tags,
+          kind: table.kind,
+          definition: table.definition,
         });
       }
       await wsSaveFullCatalog(w);
@@ -2808,6 +3114,37 @@ async function openAndImportMermaid(): Promise<void> {
   }
 }
 
+async function openAndImportPlantUML(): Promise<void> {
+  if (!bridge.isBackendAvailable()) {
+    showToast("Backend not available (run in Wails)");
+    return;
+  }
+  const path = await bridge.openFileDialog(
+    "Open PlantUML",
+    "PlantUML",
+    "*.puml;*.plantuml;*.txt"
+  );
+  if (!path) return;
+  try {
+    const raw = await bridge.loadFile(path);
+    const json = await bridge.importPlantUML(raw);
+    const d = JSON.parse(json) as Diagram;
+    store.setDiagram(d);
+    appendStatus(`Imported PlantUML from ${path}: ${d.tables.length} tables`);
+    if (d.tables.length === 0) {
+      appendStatus(
+        "No entities found. Expected PlantUML entity { } blocks.",
+        "error"
+      );
+    }
+    showToast("Imported PlantUML");
+  } catch (e) {
+    const msg = e instanceof Error ? e.message : String(e);
+    appendStatus(`PlantUML import failed: ${msg}`, "error");
+    showToast("Import failed");
+  }
+}
+
 function setupStatusPanel(): void {
   const panel = document.createElement("div");
   panel.className = "status-panel";
@@ -4869,6 +5206,18 @@ function showRelationshipDialog(
     if (existingRel?.cardinality === c) opt.selected = true;
     cardSelect.appendChild(opt);
   });
+  // Imports (e.g. Mermaid "0/1-to-many") may carry a cardinality outside the list.
+  const existingCard = existingRel?.cardinality;
+  if (
+    existingCard &&
+    !(CARDINALITY_OPTIONS as readonly string[]).includes(existingCard)
+  ) {
+    const opt = document.createElement("option");
+    opt.value = existingCard;
+    opt.textContent = existingCard;
+    opt.selected = true;
+    cardSelect.appendChild(opt);
+  }
   contentDiv.appendChild(cardSelect);
 
   const noteLabel = document.createElement("label");
@@ -5426,7 +5775,7 @@ async function wsSaveSettings(w: WorkspaceDoc): Promise<void> {
   await bridge.saveWorkspaceSettings(w.workspaceId, JSON.stringify(settings));
 }
 
-/** Save a single catalog table (with fields and type overrides) to the workspace SQLite database. */
+/** Save a single catalog table (with fields, type overrides and indexes) to the workspace SQLite database. */
 async function wsSaveCatalogTable(w: WorkspaceDoc, table: CatalogTable): Promise<void> {
   if (!bridge.isBackendAvailable()) return;
   // Convert the frontend CatalogTable to the SQLite-backed format.
@@ -5448,12 +5797,46 @@ async function wsSaveCatalogTable(w: WorkspaceDoc, table: CatalogTable): Promise
           typeOverride: ov.type,
         }))
       : [],
+    default: f.default,
+    identity: f.identity,
+    unique: f.unique,
+    check: f.check,
+    comment: f.comment,
+    repeated: f.repeated,
+    fields: f.fields,
   }));
+  // Indexes are replaced wholesale on save, so positional IDs are stable enough.
+  const wsIndexes = (table.indexes ?? []).map((ix, i) => {
+    const id = `${table.id}-idx-${i}`;
+    return {
+      id,
+      tableId: table.id,
+      name: ix.name,
+      unique: ix.unique,
+      where: ix.where,
+      method: ix.method,
+      sortOrder: i,
+      columns: ix.columns.map((c, j) => ({
+        indexId: id,
+        fieldId: c.fieldId,
+        expression: c.expression,
+        desc: c.desc,
+        sortOrder: j,
+      })),
+    };
+  });
   const wsTable = {
     id: table.id,
     name: table.name,
+    schema: table.schema,
+    description: table.description,
+    owner: table.owner,
+    tags: table.tags,
+    kind: table.kind,
+    definition: table.definition,
     sortOrder: 0,
     fields: wsFields,
+    indexes: wsIndexes,
   };
   await bridge.saveCatalogTable(w.workspaceId, JSON.stringify(wsTable));
 }
@@ -5546,6 +5929,12 @@ function wsTablesToCatalog(wsTables: import("./types").WsCatalogTable[]): Catalo
   return wsTables.map(wt => ({
     id: wt.id,
     name: wt.name,
+    schema: wt.schema,
+    description: wt.description,
+    owner: wt.owner,
+    tags: wt.tags,
+    kind: wt.kind,
+    definition: wt.definition,
     fields: (wt.fields || []).map(wf => {
       const overrides: Record<string, import("./types").FieldTypeOverride> = {};
       for (const o of wf.typeOverrides || []) {
@@ -5561,8 +5950,22 @@ function wsTablesToCatalog(wsTables: import("./types").WsCatalogTable[]): Catalo
         precision: wf.precision,
         scale: wf.scale,
         typeOverrides: Object.keys(overrides).length > 0 ? overrides : undefined,
+        default: wf.default,
+        identity: wf.identity,
+        unique: wf.unique,
+        check: wf.check,
+        comment: wf.comment,
+        repeated: wf.repeated,
+        fields: wf.fields,
       };
     }),
+    indexes: wt.indexes?.map(wi => ({
+      name: wi.name,
+      unique: wi.unique,
+      where: wi.where,
+      method: wi.method,
+      columns: (wi.columns || []).map(wc => ({ fieldId: wc.fieldId, expression: wc.expression, desc: wc.desc })),
+    })),
   }));
 }
 
@@ -6696,6 +7099,13 @@ function wsDiagramToFrontend(w: WorkspaceDoc, wd: import("./types").WsDiagram):
       x: tp.x,
       y: tp.y,
       fields: catalogTable?.fields ? [...catalogTable.fields] : [],
+      indexes: catalogTable?.indexes,
+      schema: catalogTable?.schema,
+      description: catalogTable?.description,
+      owner: catalogTable?.owner,
+      tags: catalogTable?.tags,
+      kind: catalogTable?.kind,
+      definition: catalogTable?.definition,
       catalogTableId: tp.catalogTableId,
     };
   });
@@ -8057,9 +8467,36 @@ function setupMenuBar(menuBar: HTMLElement): void {
   fromSqlItem.textContent = "From SQL DDL";
   fromSqlItem.onclick = () => {
     hideMenus();
-    openAndImportSQL();
+    openAndImportSchema(SQL_IMPORT);
   };
   importFlyout.appendChild(fromSqlItem);
+  const fromPrismaItem = document.createElement("button");
+  fromPrismaItem.type = "button";
+  fromPrismaItem.className = "menu-bar-dropdown-item";
+  fromPrismaItem.textContent = "From Prisma Schema";
+  fromPrismaItem.onclick = () => {
+    hideMenus();
+    openAndImportSchema(PRISMA_IMPORT);
+  };
+  importFlyout.appendChild(fromPrismaItem);
+  const fromDbmlItem = document.createElement("button");
+  fromDbmlItem.type = "button";
+  fromDbmlItem.className = "menu-bar-dropdown-item";
+  fromDbmlItem.textContent = "From DBML";
+  fromDbmlItem.onclick = () => {
+    hideMenus();
+    openAndImportSchema(DBML_IMPORT);
+  };
+  importFlyout.appendChild(fromDbmlItem);
+  const fromPlantUmlItem = document.createElement("button");
+  fromPlantUmlItem.type = "button";
+  fromPlantUmlItem.className = "menu-bar-dropdown-item";
+  fromPlantUmlItem.textContent = "From PlantUML";
+  fromPlantUmlItem.onclick = () => {
+    hideMenus();
+    openAndImportPlantUML();
+  };
+  importFlyout.appendChild(fromPlantUmlItem);
   const fromCsvItem = document.createElement("button");
   fromCsvItem.type = "button";
   fromCsvItem.className = "menu-bar-dropdown-item";
@@ -8132,6 +8569,34 @@ function setupMenuBar(menuBar: HTMLElement): void {
   sqlDdlWrapper.appendChild(sqlDdlFlyout);
   exportFlyout.appendChild(sqlDdlWrapper);
 
+  const codeWrapper = document.createElement("div");
+  codeWrapper.className = "menu-bar-submenu-wrapper";
+  const codeRow = document.createElement("div");
+  codeRow.className = "menu-bar-submenu-row";
+  codeRow.textContent = "Generate Code";
+  const codeArrow = document.createElement("span");
+  codeArrow.className = "menu-bar-submenu-arrow";
+  codeArrow.textContent = "\u25B8";
+  codeRow.appendChild(codeArrow);
+  const codeFlyout = document.createElement("div");
+  codeFlyout.className = "menu-bar-flyout";
+  CODE_TARGETS.forEach(([language, label, defaultName, filterName, pattern]) => {
+    const codeItem = document.createElement("button");
+    codeItem.type = "button";
+    codeItem.className = "menu-bar-dropdown-item";
+    codeItem.textContent = label;
+    codeItem.onclick = () => {
+      hideMenus();
+      exportCode(language, defaultName, filterName, pattern).catch((e) =>
+        showToast((e as Error).message)
+      );
+    };
+    codeFlyout.appendChild(codeItem);
+  });
+  codeWrapper.appendChild(codeRow);
+  codeWrapper.appendChild(codeFlyout);
+  exportFlyout.appendChild(codeWrapper);
+
   const exportItems: [string, () => void][] = [
     ["Export as PNG", () => exportPNG()],
     ["Export as SVG", () => exportSVG()],
@@ -8143,6 +8608,10 @@ function setupMenuBar(menuBar: HTMLElement): void {
       "Export in PlantUML format",
       () => exportPlantUML().catch((e) => showToast((e as Error).message)),
     ],
+    [
+      "Export in DBML format",
+      () => exportDBML().catch((e) => showToast((e as Error).message)),
+    ],
   ];
   exportItems.forEach(([exportLabel, fn]) => {
     const subItem = document.createElement("button");
@@ -23,24 +23,39 @@ declare global {
           ListFiles(rootPath: string, pattern: string): Promise<string[]>;
           // --- Export/Import ---
           ExportSQL(dialect: string, jsonContent: string): Promise<string>;
-          ExportPostgres(jsonContent: string, schema: string): Promise<string>;
+          ExportPostgres(
+            jsonContent: string,
+            schema: string,
+            alterForeignKeys: boolean,
+          ): Promise<string>;
           ExportBigQuery(
             jsonContent: string,
             project: string,
             dataset: string,
             creationMode: string,
           ): Promise<string>;
-          ImportSQL(sqlContent: string, importSource: string): Promise<string>;
+          ImportSQL(
+            sqlContent: string,
+            importSource: string,
+            dialect: string,
+          ): Promise<string>;
           ImportCSV(csvContent: string, importSource: string): Promise<string>;
+          ImportPrisma(prismaContent: string, importSource: string): Promise<string>;
+          ImportDBML(dbmlContent: string, importSource: string): Promise<string>;
           ImportMermaid(mermaidContent: string): Promise<string>;
+          ImportPlantUML(plantUMLContent: string): Promise<string>;
           ExportMermaid(jsonContent: string): Promise<string>;
           ExportPlantUML(jsonContent: string): Promise<string>;
+          ExportDBML(jsonContent: string): Promise<string>;
+          GenerateCode(language: string, jsonContent: string): Promise<string>;
           Version(): Promise<string>;
           // --- Database connectivity ---
           TestDatabaseConnection(configJSON: string): Promise<string>;
           ListDatabaseSchemas(configJSON: string): Promise<string>;
           ListDatabaseTables(configJSON: string, schemaName: string): Promise<string>;
           ImportFromDatabase(configJSON: string, schemaName: string, tablesJSON: string): Promise<string>;
+          SuggestRelationships(catalogJSON: string, optionsJSON: string): Promise<string>;
+          AcceptRelationshipSuggestions(catalogJSON: string, suggestionsJSON: string): Promise<string>;
           SaveOAuthClientConfig(clientID: string, clientSecret: string): Promise<void>;
           LoadOAuthClientConfig(): Promise<string>;
           // --- Global connection profiles ---
@@ -165,13 +180,15 @@ export async function exportSQL(
   return app.ExportSQL(dialect, jsonContent);
 }
 
+/** alterForeignKeys: add foreign keys with ALTER TABLE after all tables instead of inline. */
 export async function exportPostgres(
   jsonContent: string,
   schema: string,
+  alterForeignKeys: boolean,
 ): Promise<string> {
   const app = getApp();
   if (!app) throw new Error("Backend not available");
-  return app.ExportPostgres(jsonContent, schema);
+  return app.ExportPostgres(jsonContent, schema, alterForeignKeys);
 }
 
 export async function exportBigQuery(
@@ -185,13 +202,15 @@ export async function exportBigQuery(
   return app.ExportBigQuery(jsonContent, project, dataset, creationMode);
 }
 
+/** dialect: "postgres", "mysql", "mssql", "bigquery", or "" to auto-detect. */
 export async function importSQL(
   sqlContent: string,
-  importSource: string
+  importSource: string,
+  dialect = ""
 ): Promise<string> {
   const app = getApp();
   if (!app) throw new Error("Backend not available");
-  return app.ImportSQL(sqlContent, importSource);
+  return app.ImportSQL(sqlContent, importSource, dialect);
 }
 
 export async function importCSV(
@@ -203,12 +222,36 @@ export async function importCSV(
   return app.ImportCSV(csvContent, importSource);
 }
 
+export async function importPrisma(
+  prismaContent: string,
+  importSource: string
+): Promise<string> {
+  const app = getApp();
+  if (!app) throw new Error("Backend not available");
+  return app.ImportPrisma(prismaContent, importSource);
+}
+
+export async function importDBML(
+  dbmlContent: string,
+  importSource: string
+): Promise<string> {
+  const app = getApp();
+  if (!app) throw new Error("Backend not available");
+  return app.ImportDBML(dbmlContent, importSource);
+}
+
 export async function importMermaid(mermaidContent: string): Promise<string> {
   const app = getApp();
   if (!app) throw new Error("Backend not available");
   return app.ImportMermaid(mermaidContent);
 }
 
+export async function importPlantUML(plantUMLContent: string): Promise<string> {
+  const app = getApp();
+  if (!app) throw new Error("Backend not available");
+  return app.ImportPlantUML(plantUMLContent);
+}
+
 export async function exportMermaid(jsonContent: string): Promise<string> {
   const app = getApp();
   if (!app) throw new Error("Backend not available");
@@ -221,6 +264,22 @@ export async function exportPlantUML(jsonContent: string): Promise<string> {
   return app.ExportPlantUML(jsonContent);
 }
 
+export async function exportDBML(jsonContent: string): Promise<string> {
+  const app = getApp();
+  if (!app) throw new Error("Backend not available");
+  return app.ExportDBML(jsonContent);
+}
+
+/** language: "go", "prisma", "sqlalchemy" or "typescript". */
+export async function generateCode(
+  language: string,
+  jsonContent: string,
+): Promise<string> {
+  const app = getApp();
+  if (!app) throw new Error("Backend not available");
+  return app.GenerateCode(language, jsonContent);
+}
+
 export function isBackendAvailable(): boolean {
   return !!getApp();
 }
@@ -271,6 +330,21 @@ export async function importFromDatabase(
   return app.ImportFromDatabase(configJSON, schemaName, tablesJSON);
 }
 
+export async function suggestRelationships(catalogJSON: string, optionsJSON: string): Promise<string> {
+  const app = getApp();
+  if (!app) throw new Error("Backend not available");
+  return app.SuggestRelationships(catalogJSON, optionsJSON);
+}
+
+export async function acceptRelationshipSuggestions(
+  catalogJSON: string,
+  suggestionsJSON: string,
+): Promise<string> {
+  const app = getApp();
+  if (!app) throw new Error("Backend not available");
+  return app.AcceptRelationshipSuggestions(catalogJSON, suggestionsJSON);
+}
+
 export async function saveOAuthClientConfig(clientID: string, clientSecret: string): Promise<void> {
   const app = getApp();
   if (!app) throw new Error("Backend not available");
@@ -1,4 +1,4 @@
-import type { Diagram, Table, Relationship } from "./types";
+import type { Diagram, Table, Field, Relationship } from "./types";
 
 const TABLE_WIDTH = 200; // minimum/default width
 const ROW_HEIGHT = 22;
@@ -34,11 +34,16 @@ export function getTableFieldColumnStart(table: Table): number {
   );
 }
 
+/** Type shown for a field: repeated (array) fields get a "[]" suffix. */
+export function getFieldTypeLabel(f: Field): string {
+  return f.repeated ? `${f.type}[]` : f.type;
+}
+
 /** Table width: at least TABLE_WIDTH, or wider if table/field names need more space. */
 export function getTableWidth(table: Table): number {
   const headerWidth = table.name.length * TABLE_HEADER_CHAR_WIDTH;
   const maxNameLen = Math.max(0, ...table.fields.map((f) => f.name.length));
-  const maxTypeLen = Math.max(0, ...table.fields.map((f) => f.type.length));
+  const maxTypeLen = Math.max(0, ...table.fields.map((f) => getFieldTypeLabel(f).length));
   const nameColWidth = maxNameLen * TABLE_CHAR_WIDTH;
   const typeColWidth = maxTypeLen * TABLE_CHAR_WIDTH;
   const fieldsWidth = PK_GUTTER + nameColWidth + TABLE_COLUMN_GAP + typeColWidth;
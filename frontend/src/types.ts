@@ -20,18 +20,54 @@ export interface Field {
   scale?: number; // for numeric types (e.g. 2)
   /** Per-database type overrides. Key = dialect ("postgres", "mysql", "mssql", "bigquery"). */
   typeOverrides?: Record<string, FieldTypeOverride>;
+  default?: string; // SQL expression, e.g. "0" or "now()"
+  identity?: FieldIdentity;
+  unique?: boolean;
+  check?: string; // CHECK expression without the surrounding parentheses
+  comment?: string;
+  repeated?: boolean; // an array of type (BigQuery REPEATED mode)
+  fields?: Field[]; // sub-fields of a "struct" field (BigQuery RECORD), in order
 }
 
+/** Auto-numbering mode: MySQL AUTO_INCREMENT and PostgreSQL serial are "by_default". */
+export type FieldIdentity = "always" | "by_default";
+
 export interface Table {
   id: string;
   name: string;
+  /** Namespace: PostgreSQL/SQL Server schema, MySQL database or BigQuery dataset. */
+  schema?: string;
+  description?: string;
+  owner?: string;
+  tags?: string[];
+  /** "view" or "materialized_view"; empty for base tables. */
+  kind?: string;
+  /** View query (the SELECT after AS); views only. */
+  definition?: string;
   x: number;
   y: number;
   fields: Field[];
+  indexes?: TableIndex[];
   /** When set, this table is an instance of the catalog entry with this id; edits sync to catalog and other diagrams. */
   catalogTableId?: string;
 }
 
+/** Secondary index on a table. An empty name means the exporter picks one (e.g. users_email_idx). */
+export interface TableIndex {
+  name: string;
+  columns: IndexColumn[];
+  unique?: boolean;
+  where?: string; // partial index predicate, without WHERE
+  method?: string; // e.g. "gin", "hash", "fulltext", "clustered"; empty for the default
+}
+
+/** One index key: a field, or an SQL expression for expression indexes. */
+export interface IndexColumn {
+  fieldId?: string;
+  expression?: string;
+  desc?: boolean;
+}
+
 export interface Relationship {
   id: string;
   sourceTableId: string;
@@ -161,6 +197,13 @@ export interface WsCatalogField {
   scale?: number;
   sortOrder: number;
   typeOverrides?: WsCatalogFieldTypeOverride[];
+  default?: string;
+  identity?: FieldIdentity;
+  unique?: boolean;
+  check?: string;
+  comment?: string;
+  repeated?: boolean;
+  fields?: Field[];
 }
 
 /** Per-dialect type override for a catalog field. */
@@ -170,12 +213,42 @@ export interface WsCatalogFieldTypeOverride {
   typeOverride: string;
 }
 
-/** Catalog table with fields, as stored in SQLite. */
+/** Catalog table with fields and indexes, as stored in SQLite. */
 export interface WsCatalogTable {
   id: string;
   name: string;
+  schema?: string;
+  description?: string;
+  owner?: string;
+  tags?: string[];
+  /** "view" or "materialized_view"; empty for base tables. */
+  kind?: string;
+  /** View query (the SELECT after AS); views only. */
+  definition?: string;
   sortOrder: number;
   fields: WsCatalogField[];
+  indexes?: WsCatalogIndex[];
+}
+
+/** Catalog index, as stored in SQLite. */
+export interface WsCatalogIndex {
+  id: string;
+  tableId: string;
+  name: string;
+  unique?: boolean;
+  where?: string;
+  method?: string;
+  sortOrder: number;
+  columns: WsCatalogIndexColumn[];
+}
+
+/** One key of a catalog index. */
+export interface WsCatalogIndexColumn {
+  indexId: string;
+  fieldId?: string;
+  expression?: string;
+  desc?: boolean;
+  sortOrder: number;
 }
 
 /** Workspace connection profile (stored in SQLite). */
@@ -214,12 +287,14 @@ export interface OpenWorkspaceResult {
 
 export const CARDINALITY_OPTIONS = [
   "1-to-1",
+  "1-to-0/1",
   "1-to-many",
   "1-to-0/many",
   "many-to-1",
   "many-to-many",
   "0/1-to-0/1",
   "0/1-to-many",
+  "0/1-to-0/many",
   "many-to-0/many",
 ] as const;
 
@@ -253,10 +328,24 @@ export interface Diagram {
 }
 
 /** Result of an import (SQL, CSV, etc.). Used to populate workspace catalog or a diagram. */
+export interface ImportWarning {
+  line?: number;
+  message: string;
+}
+
 export interface TableCatalog {
   importSource: string;
   tables: Table[];
   relationships: Relationship[];
+  warnings?: ImportWarning[];
+  sourceDialect?: string;
+}
+
+/** A relationship inferred from column names and types, offered for review after a database import. */
+export interface RelationshipSuggestion {
+  relationship: Relationship;
+  confidence: number;
+  reason: string;
 }
 
 export type Selection =
@@ -278,6 +367,7 @@ export const FIELD_TYPES = [
   "uuid",
   "json",
   "bytes",
+  "struct",
   "other",
 ] as const;
 
@@ -299,9 +389,19 @@ export interface WorkspaceConfig {
 export interface CatalogTable {
   id: string;
   name: string;
+  /** Namespace: PostgreSQL/SQL Server schema, MySQL database or BigQuery dataset. */
+  schema?: string;
+  description?: string;
+  owner?: string;
+  tags?: string[];
+  /** "view" or "materialized_view"; empty for base tables. */
+  kind?: string;
+  /** View query (the SELECT after AS); views only. */
+  definition?: string;
   x?: number;
   y?: number;
   fields: Field[];
+  indexes?: TableIndex[];
 }
 
 /** Workspace state (in-memory). */
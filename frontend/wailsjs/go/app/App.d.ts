@@ -1,8 +1,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcceptRelationshipSuggestions(arg1:string,arg2:string):Promise<string>;
+
 export function CloseWorkspace(arg1:string):Promise<void>;
 
+export function CompareWithDatabase(arg1:string,arg2:string,arg3:string,arg4:string):Promise<string>;
+
+export function CreateSnapshot(arg1:string,arg2:string):Promise<string>;
+
 export function CreateWorkspace(arg1:string):Promise<string>;
 
 export function DeleteCatalogField(arg1:string,arg2:string):Promise<void>;
@@ -17,39 +23,63 @@ export function DeleteDiagram(arg1:string,arg2:string):Promise<void>;
 
 export function DeleteProfilePassword(arg1:string):Promise<void>;
 
+export function DeleteSnapshot(arg1:string,arg2:string):Promise<void>;
+
 export function DeleteWorkspaceConnectionProfile(arg1:string,arg2:string):Promise<void>;
 
+export function DiffSnapshot(arg1:string,arg2:string):Promise<string>;
+
 export function ExportBigQuery(arg1:string,arg2:string,arg3:string,arg4:string):Promise<string>;
 
+export function ExportDBML(arg1:string):Promise<string>;
+
+export function ExportMSSQL(arg1:string,arg2:string,arg3:string,arg4:boolean,arg5:boolean):Promise<string>;
+
 export function ExportMermaid(arg1:string):Promise<string>;
 
+export function ExportMySQL(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<string>;
+
 export function ExportPlantUML(arg1:string):Promise<string>;
 
-export function ExportPostgres(arg1:string,arg2:string):Promise<string>;
+export function ExportPostgres(arg1:string,arg2:string,arg3:boolean):Promise<string>;
 
 export function ExportSQL(arg1:string,arg2:string):Promise<string>;
 
+export function GenerateCode(arg1:string,arg2:string):Promise<string>;
+
+export function GenerateMigration(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<string>;
+
 export function GetCatalogRelationships(arg1:string):Promise<string>;
 
 export function GetCatalogTables(arg1:string):Promise<string>;
 
 export function GetDiagram(arg1:string,arg2:string):Promise<string>;
 
+export function GetSnapshot(arg1:string,arg2:string):Promise<string>;
+
 export function GetUIState(arg1:string):Promise<string>;
 
+export function GetUndoState(arg1:string):Promise<string>;
+
 export function GetWorkspaceConnectionProfiles(arg1:string):Promise<string>;
 
 export function GetWorkspaceSettings(arg1:string):Promise<string>;
 
 export function ImportCSV(arg1:string,arg2:string):Promise<string>;
 
+export function ImportDBML(arg1:string,arg2:string):Promise<string>;
+
 export function ImportFromDatabase(arg1:string,arg2:string,arg3:string):Promise<string>;
 
 export function ImportGlobalProfile(arg1:string,arg2:string):Promise<void>;
 
 export function ImportMermaid(arg1:string):Promise<string>;
 
-export function ImportSQL(arg1:string,arg2:string):Promise<string>;
+export function ImportPlantUML(arg1:string):Promise<string>;
+
+export function ImportPrisma(arg1:string,arg2:string):Promise<string>;
+
+export function ImportSQL(arg1:string,arg2:string,arg3:string):Promise<string>;
 
 export function ListConnectionProfiles():Promise<string>;
 
@@ -59,6 +89,8 @@ export function ListDatabaseTables(arg1:string,arg2:string):Promise<string>;
 
 export function ListFiles(arg1:string,arg2:string):Promise<Array<string>>;
 
+export function ListSnapshots(arg1:string):Promise<string>;
+
 export function ListWorkspaceDiagrams(arg1:string):Promise<string>;
 
 export function Load(arg1:string):Promise<string>;
@@ -69,6 +101,8 @@ export function LoadOAuthClientConfig():Promise<string>;
 
 export function LoadProfilePassword(arg1:string):Promise<string>;
 
+export function MergeDatabaseImport(arg1:string,arg2:string,arg3:boolean):Promise<string>;
+
 export function MigrateWorkspace(arg1:string,arg2:string):Promise<string>;
 
 export function OpenDirectoryDialog(arg1:string):Promise<string>;
@@ -77,8 +111,14 @@ export function OpenFileDialog(arg1:string,arg2:string,arg3:string):Promise<stri
 
 export function OpenWorkspace(arg1:string):Promise<string>;
 
+export function Redo(arg1:string):Promise<string>;
+
 export function Remove(arg1:string):Promise<void>;
 
+export function RestoreSnapshot(arg1:string,arg2:string):Promise<void>;
+
+export function RestoreSnapshotTable(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function Save(arg1:string,arg2:string):Promise<void>;
 
 export function SaveBase64(arg1:string,arg2:string):Promise<void>;
@@ -107,6 +147,10 @@ export function SaveWorkspaceSetting(arg1:string,arg2:string,arg3:string):Promis
 
 export function SaveWorkspaceSettings(arg1:string,arg2:string):Promise<void>;
 
+export function SuggestRelationships(arg1:string,arg2:string):Promise<string>;
+
 export function TestDatabaseConnection(arg1:string):Promise<string>;
 
+export function Undo(arg1:string):Promise<string>;
+
 export function Version():Promise<string>;
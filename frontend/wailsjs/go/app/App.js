@@ -2,10 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcceptRelationshipSuggestions(arg1, arg2) {
+  return window['go']['app']['App']['AcceptRelationshipSuggestions'](arg1, arg2);
+}
+
 export function CloseWorkspace(arg1) {
   return window['go']['app']['App']['CloseWorkspace'](arg1);
 }
 
+export function CompareWithDatabase(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['CompareWithDatabase'](arg1, arg2, arg3, arg4);
+}
+
+export function CreateSnapshot(arg1, arg2) {
+  return window['go']['app']['App']['CreateSnapshot'](arg1, arg2);
+}
+
 export function CreateWorkspace(arg1) {
   return window['go']['app']['App']['CreateWorkspace'](arg1);
 }
@@ -34,30 +46,58 @@ export function DeleteProfilePassword(arg1) {
   return window['go']['app']['App']['DeleteProfilePassword'](arg1);
 }
 
+export function DeleteSnapshot(arg1, arg2) {
+  return window['go']['app']['App']['DeleteSnapshot'](arg1, arg2);
+}
+
 export function DeleteWorkspaceConnectionProfile(arg1, arg2) {
   return window['go']['app']['App']['DeleteWorkspaceConnectionProfile'](arg1, arg2);
 }
 
+export function DiffSnapshot(arg1, arg2) {
+  return window['go']['app']['App']['DiffSnapshot'](arg1, arg2);
+}
+
 export function ExportBigQuery(arg1, arg2, arg3, arg4) {
   return window['go']['app']['App']['ExportBigQuery'](arg1, arg2, arg3, arg4);
 }
 
+export function ExportDBML(arg1) {
+  return window['go']['app']['App']['ExportDBML'](arg1);
+}
+
+export function ExportMSSQL(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['ExportMSSQL'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function ExportMermaid(arg1) {
   return window['go']['app']['App']['ExportMermaid'](arg1);
 }
 
+export function ExportMySQL(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['ExportMySQL'](arg1, arg2, arg3, arg4);
+}
+
 export function ExportPlantUML(arg1) {
   return window['go']['app']['App']['ExportPlantUML'](arg1);
 }
 
-export function ExportPostgres(arg1, arg2) {
-  return window['go']['app']['App']['ExportPostgres'](arg1, arg2);
+export function ExportPostgres(arg1, arg2, arg3) {
+  return window['go']['app']['App']['ExportPostgres'](arg1, arg2, arg3);
 }
 
 export function ExportSQL(arg1, arg2) {
   return window['go']['app']['App']['ExportSQL'](arg1, arg2);
 }
 
+export function GenerateCode(arg1, arg2) {
+  return window['go']['app']['App']['GenerateCode'](arg1, arg2);
+}
+
+export function GenerateMigration(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GenerateMigration'](arg1, arg2, arg3, arg4);
+}
+
 export function GetCatalogRelationships(arg1) {
   return window['go']['app']['App']['GetCatalogRelationships'](arg1);
 }
@@ -70,10 +110,18 @@ export function GetDiagram(arg1, arg2) {
   return window['go']['app']['App']['GetDiagram'](arg1, arg2);
 }
 
+export function GetSnapshot(arg1, arg2) {
+  return window['go']['app']['App']['GetSnapshot'](arg1, arg2);
+}
+
 export function GetUIState(arg1) {
   return window['go']['app']['App']['GetUIState'](arg1);
 }
 
+export function GetUndoState(arg1) {
+  return window['go']['app']['App']['GetUndoState'](arg1);
+}
+
 export function GetWorkspaceConnectionProfiles(arg1) {
   return window['go']['app']['App']['GetWorkspaceConnectionProfiles'](arg1);
 }
@@ -86,6 +134,10 @@ export function ImportCSV(arg1, arg2) {
   return window['go']['app']['App']['ImportCSV'](arg1, arg2);
 }
 
+export function ImportDBML(arg1, arg2) {
+  return window['go']['app']['App']['ImportDBML'](arg1, arg2);
+}
+
 export function ImportFromDatabase(arg1, arg2, arg3) {
   return window['go']['app']['App']['ImportFromDatabase'](arg1, arg2, arg3);
 }
@@ -98,8 +150,16 @@ export function ImportMermaid(arg1) {
   return window['go']['app']['App']['ImportMermaid'](arg1);
 }
 
-export function ImportSQL(arg1, arg2) {
-  return window['go']['app']['App']['ImportSQL'](arg1, arg2);
+export function ImportPlantUML(arg1) {
+  return window['go']['app']['App']['ImportPlantUML'](arg1);
+}
+
+export function ImportPrisma(arg1, arg2) {
+  return window['go']['app']['App']['ImportPrisma'](arg1, arg2);
+}
+
+export function ImportSQL(arg1, arg2, arg3) {
+  return window['go']['app']['App']['ImportSQL'](arg1, arg2, arg3);
 }
 
 export function ListConnectionProfiles() {
@@ -118,6 +178,10 @@ export function ListFiles(arg1, arg2) {
   return window['go']['app']['App']['ListFiles'](arg1, arg2);
 }
 
+export function ListSnapshots(arg1) {
+  return window['go']['app']['App']['ListSnapshots'](arg1);
+}
+
 export function ListWorkspaceDiagrams(arg1) {
   return window['go']['app']['App']['ListWorkspaceDiagrams'](arg1);
 }
@@ -138,6 +202,10 @@ export function LoadProfilePassword(arg1) {
   return window['go']['app']['App']['LoadProfilePassword'](arg1);
 }
 
+export function MergeDatabaseImport(arg1, arg2, arg3) {
+  return window['go']['app']['App']['MergeDatabaseImport'](arg1, arg2, arg3);
+}
+
 export function MigrateWorkspace(arg1, arg2) {
   return window['go']['app']['App']['MigrateWorkspace'](arg1, arg2);
 }
@@ -154,10 +222,22 @@ export function OpenWorkspace(arg1) {
   return window['go']['app']['App']['OpenWorkspace'](arg1);
 }
 
+export function Redo(arg1) {
+  return window['go']['app']['App']['Redo'](arg1);
+}
+
 export function Remove(arg1) {
   return window['go']['app']['App']['Remove'](arg1);
 }
 
+export function RestoreSnapshot(arg1, arg2) {
+  return window['go']['app']['App']['RestoreSnapshot'](arg1, arg2);
+}
+
+export function RestoreSnapshotTable(arg1, arg2, arg3) {
+  return window['go']['app']['App']['RestoreSnapshotTable'](arg1, arg2, arg3);
+}
+
 export function Save(arg1, arg2) {
   return window['go']['app']['App']['Save'](arg1, arg2);
 }
@@ -214,10 +294,18 @@ export function SaveWorkspaceSettings(arg1, arg2) {
   return window['go']['app']['App']['SaveWorkspaceSettings'](arg1, arg2);
 }
 
+export function SuggestRelationships(arg1, arg2) {
+  return window['go']['app']['App']['SuggestRelationships'](arg1, arg2);
+}
+
 export function TestDatabaseConnection(arg1) {
   return window['go']['app']['App']['TestDatabaseConnection'](arg1);
 }
 
+export function Undo(arg1) {
+  return window['go']['app']['App']['Undo'](arg1);
+}
+
 export function Version() {
   return window['go']['app']['App']['Version']();
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 
+	"schemastudio/internal/codegen"
 	"schemastudio/internal/dbconn"
 	"schemastudio/internal/importers"
 	"schemastudio/internal/schema"
@@ -215,7 +216,14 @@ func (a *App) SaveCatalogTable(wsID string, tableJSON string) error {
 	if err := json.Unmarshal([]byte(tableJSON), &t); err != nil {
 		return err
 	}
-	return repo.SaveCatalogTable(t)
+	err := repo.Journaled("Save table "+t.Name, workspace.ChangeScope{TableIDs: []string{t.ID}}, func() error {
+		return repo.SaveCatalogTable(t)
+	})
+	if err != nil {
+		return err
+	}
+	autoSnapshot(repo)
+	return nil
 }
 
 // DeleteCatalogTable removes a catalog table by ID.
@@ -224,7 +232,9 @@ func (a *App) DeleteCatalogTable(wsID string, tableID string) error {
 	if repo == nil {
 		return fmt.Errorf("workspace %s not open", wsID)
 	}
-	return repo.DeleteCatalogTable(tableID)
+	return repo.Journaled("Delete table", workspace.ChangeScope{TableIDs: []string{tableID}, Cascade: true}, func() error {
+		return repo.DeleteCatalogTable(tableID)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -241,7 +251,9 @@ func (a *App) SaveCatalogField(wsID string, fieldJSON string) error {
 	if err := json.Unmarshal([]byte(fieldJSON), &f); err != nil {
 		return err
 	}
-	return repo.SaveField(f)
+	return repo.Journaled("Save column "+f.Name, workspace.ChangeScope{TableIDs: []string{f.TableID}}, func() error {
+		return repo.SaveField(f)
+	})
 }
 
 // DeleteCatalogField removes a catalog field by ID.
@@ -250,7 +262,13 @@ func (a *App) DeleteCatalogField(wsID string, fieldID string) error {
 	if repo == nil {
 		return fmt.Errorf("workspace %s not open", wsID)
 	}
-	return repo.DeleteField(fieldID)
+	tableID, err := repo.FieldTableID(fieldID)
+	if err != nil {
+		return err
+	}
+	return repo.Journaled("Delete column", workspace.ChangeScope{TableIDs: []string{tableID}}, func() error {
+		return repo.DeleteField(fieldID)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -283,7 +301,9 @@ func (a *App) SaveCatalogRelationship(wsID string, relJSON string) error {
 	if err := json.Unmarshal([]byte(relJSON), &rel); err != nil {
 		return err
 	}
-	return repo.SaveCatalogRelationship(rel)
+	return repo.Journaled("Save relationship", workspace.ChangeScope{RelationshipIDs: []string{rel.ID}}, func() error {
+		return repo.SaveCatalogRelationship(rel)
+	})
 }
 
 // DeleteCatalogRelationship removes a catalog relationship by ID.
@@ -292,7 +312,9 @@ func (a *App) DeleteCatalogRelationship(wsID string, relID string) error {
 	if repo == nil {
 		return fmt.Errorf("workspace %s not open", wsID)
 	}
-	return repo.DeleteCatalogRelationship(relID)
+	return repo.Journaled("Delete relationship", workspace.ChangeScope{RelationshipIDs: []string{relID}, Cascade: true}, func() error {
+		return repo.DeleteCatalogRelationship(relID)
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -341,7 +363,14 @@ func (a *App) SaveDiagram(wsID string, diagramJSON string) error {
 	if err := json.Unmarshal([]byte(diagramJSON), &d); err != nil {
 		return err
 	}
-	return repo.SaveDiagram(d)
+	err := repo.Journaled("Save diagram "+d.Name, workspace.ChangeScope{DiagramIDs: []string{d.ID}}, func() error {
+		return repo.SaveDiagram(d)
+	})
+	if err != nil {
+		return err
+	}
+	autoSnapshot(repo)
+	return nil
 }
 
 // DeleteDiagram removes a diagram by ID.
@@ -350,7 +379,154 @@ func (a *App) DeleteDiagram(wsID string, diagramID string) error {
 	if repo == nil {
 		return fmt.Errorf("workspace %s not open", wsID)
 	}
-	return repo.DeleteDiagram(diagramID)
+	return repo.Journaled("Delete diagram", workspace.ChangeScope{DiagramIDs: []string{diagramID}}, func() error {
+		return repo.DeleteDiagram(diagramID)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Snapshots (version history)
+// ---------------------------------------------------------------------------
+
+// CreateSnapshot saves the current catalog and diagrams as a named snapshot and returns it as JSON.
+func (a *App) CreateSnapshot(wsID string, name string) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	snap, err := repo.CreateSnapshot(name)
+	if err != nil {
+		return "", err
+	}
+	return marshalJSON(snap)
+}
+
+// ListSnapshots returns the workspace snapshots (newest first) as JSON.
+func (a *App) ListSnapshots(wsID string) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	snaps, err := repo.ListSnapshots()
+	if err != nil {
+		return "", err
+	}
+	if snaps == nil {
+		snaps = []workspace.Snapshot{}
+	}
+	return marshalJSON(snaps)
+}
+
+// GetSnapshot returns a snapshot with its catalog tables, relationships and diagrams as JSON,
+// for read-only viewing.
+func (a *App) GetSnapshot(wsID string, snapshotID string) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	sc, err := repo.GetSnapshot(snapshotID)
+	if err != nil {
+		return "", err
+	}
+	if sc == nil {
+		return "", fmt.Errorf("snapshot %s not found", snapshotID)
+	}
+	return marshalJSON(sc)
+}
+
+// DeleteSnapshot removes a snapshot by ID.
+func (a *App) DeleteSnapshot(wsID string, snapshotID string) error {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return fmt.Errorf("workspace %s not open", wsID)
+	}
+	return repo.DeleteSnapshot(snapshotID)
+}
+
+// DiffSnapshot returns the schema diff (JSON) from a snapshot's catalog to the current catalog.
+func (a *App) DiffSnapshot(wsID string, snapshotID string) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	diff, err := repo.DiffSnapshot(snapshotID)
+	if err != nil {
+		return "", err
+	}
+	return marshalJSON(diff)
+}
+
+// RestoreSnapshot replaces the workspace catalog and diagrams with a snapshot's.
+func (a *App) RestoreSnapshot(wsID string, snapshotID string) error {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return fmt.Errorf("workspace %s not open", wsID)
+	}
+	return repo.Journaled("Restore snapshot", workspace.ChangeScope{All: true}, func() error {
+		return repo.RestoreSnapshot(snapshotID)
+	})
+}
+
+// RestoreSnapshotTable restores a single catalog table from a snapshot.
+func (a *App) RestoreSnapshotTable(wsID string, snapshotID string, tableID string) error {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return fmt.Errorf("workspace %s not open", wsID)
+	}
+	return repo.Journaled("Restore table from snapshot", workspace.ChangeScope{TableIDs: []string{tableID}, Cascade: true}, func() error {
+		return repo.RestoreSnapshotTable(snapshotID, tableID)
+	})
+}
+
+// autoSnapshot takes an automatic snapshot after a save when the workspace has it enabled.
+// Failures are ignored: the save itself already succeeded.
+func autoSnapshot(repo *workspace.WorkspaceRepo) {
+	_, _ = repo.AutoSnapshot()
+}
+
+// ---------------------------------------------------------------------------
+// Undo / Redo
+// ---------------------------------------------------------------------------
+
+// Undo reverts the most recent catalog or diagram change in the workspace and returns
+// the journal entry as JSON ("null" if there was nothing to undo).
+func (a *App) Undo(wsID string) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	entry, err := repo.Undo()
+	if err != nil {
+		return "", err
+	}
+	return marshalJSON(entry)
+}
+
+// Redo reapplies the most recently undone change and returns the journal entry as JSON
+// ("null" if there was nothing to redo).
+func (a *App) Redo(wsID string) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	entry, err := repo.Redo()
+	if err != nil {
+		return "", err
+	}
+	return marshalJSON(entry)
+}
+
+// GetUndoState returns whether undo/redo are available, with their labels, as JSON.
+func (a *App) GetUndoState(wsID string) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	state, err := repo.GetUndoState()
+	if err != nil {
+		return "", err
+	}
+	return marshalJSON(state)
 }
 
 // ---------------------------------------------------------------------------
@@ -552,7 +728,7 @@ func (a *App) ListFiles(rootPath string, pattern string) ([]string, error) {
 	return out, nil
 }
 
-// ExportSQL returns DDL for the given dialect ("postgres" or "bigquery") from the diagram JSON.
+// ExportSQL returns DDL for the given dialect ("postgres", "mysql", "mssql" or "bigquery") from the diagram JSON.
 func (a *App) ExportSQL(dialect string, jsonContent string) (string, error) {
 	var d schema.Diagram
 	if err := json.Unmarshal([]byte(jsonContent), &d); err != nil {
@@ -572,17 +748,55 @@ func (a *App) ExportBigQuery(jsonContent string, project string, dataset string,
 }
 
 // ExportPostgres returns PostgreSQL DDL. If schemaName is non-empty, table names are schema-qualified (e.g. "myschema"."mytable").
-func (a *App) ExportPostgres(jsonContent string, schemaName string) (string, error) {
+// If alterForeignKeys is true, foreign keys are added with ALTER TABLE after all tables.
+func (a *App) ExportPostgres(jsonContent string, schemaName string, alterForeignKeys bool) (string, error) {
+	var d schema.Diagram
+	if err := json.Unmarshal([]byte(jsonContent), &d); err != nil {
+		return "", err
+	}
+	return sqlx.ExportPostgresWithSchema(d, schemaName, alterForeignKeys)
+}
+
+// ExportMySQL returns MySQL DDL with the given table options (e.g. engine "InnoDB", charset "utf8mb4").
+// Empty engine or charset omits that option. If alterForeignKeys is true, foreign keys are added with ALTER TABLE after all tables.
+func (a *App) ExportMySQL(jsonContent string, engine string, charset string, alterForeignKeys bool) (string, error) {
+	var d schema.Diagram
+	if err := json.Unmarshal([]byte(jsonContent), &d); err != nil {
+		return "", err
+	}
+	return sqlx.ExportMySQLWithOptions(d, engine, charset, alterForeignKeys)
+}
+
+// ExportMSSQL returns SQL Server DDL with [schema].[table] names (schemaName defaults to "dbo").
+// creationMode is "if_not_exists", "create_or_replace", or "". If goBatches is true, statements are separated by GO.
+// If alterForeignKeys is true, foreign keys are added with ALTER TABLE after all tables.
+func (a *App) ExportMSSQL(jsonContent string, schemaName string, creationMode string, goBatches bool, alterForeignKeys bool) (string, error) {
 	var d schema.Diagram
 	if err := json.Unmarshal([]byte(jsonContent), &d); err != nil {
 		return "", err
 	}
-	return sqlx.ExportPostgres(d, schemaName)
+	return sqlx.ExportMSSQLWithOptions(d, schemaName, creationMode, goBatches, alterForeignKeys)
+}
+
+// GenerateMigration returns an ALTER script for the dialect that migrates a schema matching
+// fromJSON into one matching toJSON. Both accept diagram or TableCatalog JSON; tables and
+// columns are matched by name. With matchByID, ones left unmatched that share an ID are
+// migrated as renames; set it only when both sides come from the same workspace.
+func (a *App) GenerateMigration(dialect string, fromJSON string, toJSON string, matchByID bool) (string, error) {
+	var from, to schema.Diagram
+	if err := json.Unmarshal([]byte(fromJSON), &from); err != nil {
+		return "", fmt.Errorf("parse from schema: %w", err)
+	}
+	if err := json.Unmarshal([]byte(toJSON), &to); err != nil {
+		return "", fmt.Errorf("parse to schema: %w", err)
+	}
+	return sqlx.GenerateMigrationWithOptions(dialect, from, to, schema.DiffOptions{MatchByID: matchByID})
 }
 
-// ImportSQL parses DDL and returns TableCatalog JSON (importSource set to the given name).
-func (a *App) ImportSQL(sqlContent string, importSource string) (string, error) {
-	catalog, err := importers.ParseSQL(sqlContent)
+// ImportSQL parses DDL written for dialect ("postgres", "mysql", "mssql", "bigquery";
+// "" auto-detects) and returns TableCatalog JSON (importSource set to the given name).
+func (a *App) ImportSQL(sqlContent string, importSource string, dialect string) (string, error) {
+	catalog, err := importers.ParseSQLDialect(sqlContent, dialect)
 	if err != nil {
 		return "", err
 	}
@@ -608,6 +822,35 @@ func (a *App) ImportCSV(csvContent string, importSource string) (string, error)
 	return string(b), nil
 }
 
+// ImportPrisma parses a Prisma schema and returns TableCatalog JSON (importSource set to
+// the given name).
+func (a *App) ImportPrisma(prismaContent string, importSource string) (string, error) {
+	catalog, err := importers.ParsePrisma(prismaContent)
+	if err != nil {
+		return "", err
+	}
+	catalog.ImportSource = importSource
+	b, err := json.Marshal(catalog)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ImportDBML parses DBML and returns TableCatalog JSON (importSource set to the given name).
+func (a *App) ImportDBML(dbmlContent string, importSource string) (string, error) {
+	catalog, err := importers.ParseDBML(dbmlContent)
+	if err != nil {
+		return "", err
+	}
+	catalog.ImportSource = importSource
+	b, err := json.Marshal(catalog)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 // ImportMermaid parses Mermaid ERD and returns diagram JSON.
 func (a *App) ImportMermaid(mermaidContent string) (string, error) {
 	d, err := importers.ParseMermaid(mermaidContent)
@@ -617,6 +860,15 @@ func (a *App) ImportMermaid(mermaidContent string) (string, error) {
 	return d.MarshalJSONString()
 }
 
+// ImportPlantUML parses a PlantUML entity diagram and returns diagram JSON.
+func (a *App) ImportPlantUML(plantUMLContent string) (string, error) {
+	d, err := importers.ParsePlantUML(plantUMLContent)
+	if err != nil {
+		return "", err
+	}
+	return d.MarshalJSONString()
+}
+
 // ExportMermaid returns Mermaid ERD syntax from the diagram JSON.
 func (a *App) ExportMermaid(jsonContent string) (string, error) {
 	var d schema.Diagram
@@ -635,6 +887,25 @@ func (a *App) ExportPlantUML(jsonContent string) (string, error) {
 	return schema.ToPlantUML(d), nil
 }
 
+// ExportDBML returns DBML (dbdiagram.io) from the diagram JSON.
+func (a *App) ExportDBML(jsonContent string) (string, error) {
+	var d schema.Diagram
+	if err := json.Unmarshal([]byte(jsonContent), &d); err != nil {
+		return "", err
+	}
+	return schema.ToDBML(d), nil
+}
+
+// GenerateCode returns application code for the language ("go", "prisma", "sqlalchemy" or
+// "typescript") from the diagram JSON.
+func (a *App) GenerateCode(language string, jsonContent string) (string, error) {
+	var d schema.Diagram
+	if err := json.Unmarshal([]byte(jsonContent), &d); err != nil {
+		return "", err
+	}
+	return codegen.Generate(language, d)
+}
+
 // --- Database connectivity methods ---
 
 // TestDatabaseConnection validates connectivity to a database and returns a status message.
@@ -735,6 +1006,166 @@ func (a *App) ImportFromDatabase(configJSON string, schemaName string, tablesJSO
 	return string(b), nil
 }
 
+// SuggestRelationships returns a JSON array of relationships inferred from column names
+// and types for a TableCatalog (JSON) that lacks foreign keys. optionsJSON is a
+// schema.SuggestOptions object, or empty for the default patterns.
+func (a *App) SuggestRelationships(catalogJSON string, optionsJSON string) (string, error) {
+	var catalog schema.TableCatalog
+	if err := json.Unmarshal([]byte(catalogJSON), &catalog); err != nil {
+		return "", err
+	}
+	var opts schema.SuggestOptions
+	if optionsJSON != "" {
+		if err := json.Unmarshal([]byte(optionsJSON), &opts); err != nil {
+			return "", err
+		}
+	}
+	suggestions, err := schema.SuggestRelationships(catalog, opts)
+	if err != nil {
+		return "", err
+	}
+	if suggestions == nil {
+		suggestions = []schema.RelationshipSuggestion{}
+	}
+	b, err := json.Marshal(suggestions)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// AcceptRelationshipSuggestions adds the chosen suggestions (JSON, as returned by
+// SuggestRelationships) to a TableCatalog (JSON) and returns the updated catalog JSON.
+func (a *App) AcceptRelationshipSuggestions(catalogJSON string, suggestionsJSON string) (string, error) {
+	var catalog schema.TableCatalog
+	if err := json.Unmarshal([]byte(catalogJSON), &catalog); err != nil {
+		return "", err
+	}
+	var suggestions []schema.RelationshipSuggestion
+	if err := json.Unmarshal([]byte(suggestionsJSON), &suggestions); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(schema.AcceptSuggestions(catalog, suggestions))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// MergeDatabaseImport merges a TableCatalog (JSON, as returned by ImportFromDatabase) into the
+// workspace catalog by table and column name, keeping existing IDs, type overrides and
+// relationship metadata. Returns the merge report as JSON. If dryRun is true, the catalog
+// is not modified and the report describes what would change.
+func (a *App) MergeDatabaseImport(wsID string, catalogJSON string, dryRun bool) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	var catalog schema.TableCatalog
+	if err := json.Unmarshal([]byte(catalogJSON), &catalog); err != nil {
+		return "", err
+	}
+	if dryRun {
+		report, err := repo.MergeImportedCatalog(catalog, true)
+		if err != nil {
+			return "", err
+		}
+		return marshalJSON(report)
+	}
+	var report workspace.CatalogMergeReport
+	err := repo.Journaled("Merge database import", workspace.ChangeScope{All: true}, func() error {
+		var err error
+		report, err = repo.MergeImportedCatalog(catalog, false)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return marshalJSON(report)
+}
+
+// CompareWithDatabase introspects the database behind a workspace connection profile and
+// returns a drift report (JSON) comparing it with the workspace catalog. Tables and columns
+// are matched by name. If password is empty, it is loaded from the OS credential manager.
+func (a *App) CompareWithDatabase(wsID string, profileID string, schemaName string, password string) (string, error) {
+	repo := a.wm.GetRepo(wsID)
+	if repo == nil {
+		return "", fmt.Errorf("workspace %s not open", wsID)
+	}
+	cfg, err := a.workspaceConnectionConfig(wsID, repo, profileID, password)
+	if err != nil {
+		return "", err
+	}
+	catalogDiagram, err := repo.CatalogDiagram()
+	if err != nil {
+		return "", err
+	}
+	inspector, err := dbconn.NewInspector(cfg.Driver)
+	if err != nil {
+		return "", err
+	}
+	if err := inspector.Connect(cfg); err != nil {
+		return "", err
+	}
+	defer inspector.Close()
+	dbCatalog, err := inspector.InspectSchema(schemaName, nil)
+	if err != nil {
+		return "", err
+	}
+	// Inspected tables carry no schema; they are in the one inspected, so they match the
+	// catalog tables of that schema.
+	for i := range dbCatalog.Tables {
+		if dbCatalog.Tables[i].Schema == "" {
+			dbCatalog.Tables[i].Schema = schemaName
+		}
+	}
+	return marshalJSON(dbconn.CompareCatalog(catalogDiagram, dbCatalog, cfg.Driver))
+}
+
+// workspaceConnectionConfig builds a ConnectionConfig from a workspace connection profile.
+// If password is empty, it is looked up in the OS credential manager under the keys the
+// frontend saves it with (workspace-scoped key first, then the profile name).
+func (a *App) workspaceConnectionConfig(wsID string, repo *workspace.WorkspaceRepo, profileID string, password string) (dbconn.ConnectionConfig, error) {
+	profiles, err := repo.ListConnectionProfiles()
+	if err != nil {
+		return dbconn.ConnectionConfig{}, err
+	}
+	var p *workspace.ConnectionProfile
+	for i := range profiles {
+		if profiles[i].ID == profileID {
+			p = &profiles[i]
+			break
+		}
+	}
+	if p == nil {
+		return dbconn.ConnectionConfig{}, fmt.Errorf("connection profile %s not found", profileID)
+	}
+	cfg := dbconn.ConnectionConfig{
+		Driver:           p.Driver,
+		Host:             p.Host,
+		Database:         p.DatabaseName,
+		Username:         p.Username,
+		Password:         password,
+		SSLMode:          p.SSLMode,
+		Project:          p.Project,
+		Dataset:          p.Dataset,
+		CredentialsFile:  p.CredentialsFile,
+		BigQueryAuthMode: p.BigQueryAuthMode,
+	}
+	if p.Port != nil {
+		cfg.Port = *p.Port
+	}
+	if cfg.Password == "" {
+		for _, key := range []string{wsID + ":" + p.ID, p.Name} {
+			if pw, err := dbconn.LoadPassword(key); err == nil && pw != "" {
+				cfg.Password = pw
+				break
+			}
+		}
+	}
+	return cfg, nil
+}
+
 // SaveOAuthClientConfig saves the OAuth client ID and secret for BigQuery user auth.
 func (a *App) SaveOAuthClientConfig(clientID string, clientSecret string) error {
 	return dbconn.SaveOAuthClientConfig(dbconn.OAuthClientConfig{
@@ -0,0 +1,587 @@
+// Package cli implements the headless command-line mode of Schema Studio, for
+// generating DDL and diagrams in scripts and CI without opening the GUI.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"schemastudio/internal/codegen"
+	"schemastudio/internal/dbconn"
+	"schemastudio/internal/importers"
+	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
+	"schemastudio/internal/workspace"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK      = 0
+	ExitError   = 1
+	ExitUsage   = 2
+	ExitChanges = 3 // diff -exit-code found differences
+)
+
+// errUsage marks errors caused by bad arguments; the message has already been printed.
+var errUsage = errors.New("usage")
+
+type command struct {
+	summary string
+	run     func(c *cmdContext, args []string) error
+}
+
+var commands = map[string]command{
+	"export":  {"Export a workspace, diagram or schema file as DDL, Mermaid, PlantUML, DBML, JSON or application code", runExport},
+	"import":  {"Import SQL, Prisma, DBML, Mermaid, PlantUML or CSV into a workspace catalog or as diagram JSON", runImport},
+	"inspect": {"Introspect a live database", runInspect},
+	"diff":    {"Compare two schemas and print the differences or a migration script", runDiff},
+}
+
+// cmdContext carries the I/O streams and the exit code a command wants to return.
+type cmdContext struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	exitCode       int
+}
+
+// IsCommand reports whether arg names a CLI subcommand (or asks for CLI help), in
+// which case main should call Run instead of starting the GUI.
+func IsCommand(arg string) bool {
+	_, ok := commands[arg]
+	return ok || arg == "help" || arg == "-h" || arg == "--help"
+}
+
+// Run executes a CLI subcommand. args excludes the program name. It returns the
+// process exit code.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return ExitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "schemastudio: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return ExitUsage
+	}
+	c := &cmdContext{stdin: stdin, stdout: stdout, stderr: stderr}
+	if err := cmd.run(c, args[1:]); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			return ExitUsage
+		}
+		fmt.Fprintf(stderr, "schemastudio %s: %v\n", args[0], err)
+		return ExitError
+	}
+	return c.exitCode
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: schemastudio <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "A SOURCE is a .schemastudio workspace (whole catalog), WORKSPACE#DIAGRAM (a diagram")
+	fmt.Fprintln(w, "by name or ID), a diagram/catalog .json file, or a .sql, .mmd or .csv file.")
+	fmt.Fprintln(w, "Run 'schemastudio <command> -h' for command flags.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes: 0 success, 1 error, 2 usage error, 3 differences found (diff -exit-code).")
+}
+
+// newFlagSet returns a flag set that reports errors to stderr and prints usage with
+// the given argument synopsis.
+func newFlagSet(c *cmdContext, name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: schemastudio %s [flags] %s\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and checks the number of positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string, positional int) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != positional {
+		fmt.Fprintf(fs.Output(), "expected %d argument(s), got %d\n", positional, fs.NArg())
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// export
+// ---------------------------------------------------------------------------
+
+func runExport(c *cmdContext, args []string) error {
+	fs := newFlagSet(c, "export", "SOURCE")
+	format := fs.String("f", "postgres", "output format: "+strings.Join(outputFormats(), ", "))
+	schemaName := fs.String("schema", "", "schema-qualify table names (postgres, mssql)")
+	alterFKs := fs.Bool("fk-alter", false, "add all foreign keys with ALTER TABLE after the tables (postgres, mysql, mssql)")
+	out := fs.String("o", "", "output file (default stdout)")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	d, err := loadSource(c, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	text, err := render(d, *format, *schemaName, *alterFKs)
+	if err != nil {
+		return err
+	}
+	return writeOutput(c, *out, text)
+}
+
+// ---------------------------------------------------------------------------
+// import
+// ---------------------------------------------------------------------------
+
+func runImport(c *cmdContext, args []string) error {
+	fs := newFlagSet(c, "import", "INPUT (file, or - for stdin)")
+	format := fs.String("f", "auto", "input format: auto, sql, prisma, dbml, mermaid, plantuml, csv (auto uses the file extension or content)")
+	dialect := fs.String("dialect", "auto", "SQL input dialect: auto, "+strings.Join(importers.SQLImportDialects(), ", "))
+	ws := fs.String("w", "", "merge into this .schemastudio workspace catalog instead of printing diagram JSON")
+	dryRun := fs.Bool("dry-run", false, "with -w, report what the merge would change without writing")
+	out := fs.String("o", "", "output file (default stdout)")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	input := fs.Arg(0)
+	f := *format
+	content, err := readInput(c, input)
+	if err != nil {
+		return err
+	}
+	if f == "auto" {
+		if f = formatForExt(input); f == "" {
+			f = sniffFormat(content)
+		}
+	}
+	d, err := c.parseContent(f, *dialect, content, input)
+	if err != nil {
+		return err
+	}
+	if *ws == "" {
+		text, err := render(d, "json", "", false)
+		if err != nil {
+			return err
+		}
+		return writeOutput(c, *out, text)
+	}
+
+	catalog := schema.TableCatalog{ImportSource: filepath.Base(input), Tables: d.Tables, Relationships: d.Relationships}
+	report, err := mergeIntoWorkspace(*ws, "Merge import of "+filepath.Base(input), catalog, *dryRun)
+	if err != nil {
+		return err
+	}
+	return writeJSON(c, *out, report)
+}
+
+// ---------------------------------------------------------------------------
+// inspect
+// ---------------------------------------------------------------------------
+
+func runInspect(c *cmdContext, args []string) error {
+	fs := newFlagSet(c, "inspect", "")
+	var cfg dbconn.ConnectionConfig
+	fs.StringVar(&cfg.Driver, "driver", "postgres", "database driver: postgres, mysql, mssql, bigquery, sqlite")
+	fs.StringVar(&cfg.Host, "host", "localhost", "database host")
+	fs.IntVar(&cfg.Port, "port", 0, "database port (default: driver's default)")
+	fs.StringVar(&cfg.Database, "database", "", "database name (file path for sqlite)")
+	fs.StringVar(&cfg.Username, "user", "", "user name")
+	fs.StringVar(&cfg.Password, "password", "", "password (default $SCHEMASTUDIO_DB_PASSWORD)")
+	fs.StringVar(&cfg.SSLMode, "sslmode", "", "SSL mode (postgres)")
+	fs.StringVar(&cfg.Project, "project", "", "BigQuery project")
+	fs.StringVar(&cfg.CredentialsFile, "credentials", "", "BigQuery service account JSON file")
+	fs.StringVar(&cfg.BigQueryAuthMode, "bigquery-auth", "", "BigQuery auth mode: service_account, adc")
+	schemaName := fs.String("schema", "", "schema (dataset for BigQuery) to inspect")
+	tables := fs.String("tables", "", "comma-separated table names (default all)")
+	format := fs.String("f", "json", "output format: "+strings.Join(outputFormats(), ", "))
+	ws := fs.String("w", "", "merge the result into this .schemastudio workspace catalog")
+	dryRun := fs.Bool("dry-run", false, "with -w, report what the merge would change without writing")
+	suggest := fs.Float64("suggest", 0, "add relationships inferred from column names with at least this confidence, 0 to 1 (default off)")
+	fkPatterns := fs.String("fk-patterns", "", "comma-separated foreign key column patterns for -suggest (default "+strings.Join(schema.DefaultSuggestPatterns, ",")+")")
+	out := fs.String("o", "", "output file (default stdout)")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if cfg.Password == "" {
+		cfg.Password = os.Getenv("SCHEMASTUDIO_DB_PASSWORD")
+	}
+	if cfg.Driver == "bigquery" && cfg.Dataset == "" {
+		cfg.Dataset = *schemaName
+	}
+	var tableNames []string
+	for _, t := range strings.Split(*tables, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tableNames = append(tableNames, t)
+		}
+	}
+
+	inspector, err := dbconn.NewInspector(cfg.Driver)
+	if err != nil {
+		return err
+	}
+	if err := inspector.Connect(cfg); err != nil {
+		return err
+	}
+	defer inspector.Close()
+	catalog, err := inspector.InspectSchema(*schemaName, tableNames)
+	if err != nil {
+		return err
+	}
+	if *suggest > 0 {
+		opts := schema.SuggestOptions{MinConfidence: *suggest}
+		for _, p := range strings.Split(*fkPatterns, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				opts.Patterns = append(opts.Patterns, p)
+			}
+		}
+		if catalog, err = c.acceptSuggestions(catalog, opts); err != nil {
+			return err
+		}
+	}
+
+	if *ws != "" {
+		report, err := mergeIntoWorkspace(*ws, "Merge database import", catalog, *dryRun)
+		if err != nil {
+			return err
+		}
+		return writeJSON(c, *out, report)
+	}
+	if *format == "json" {
+		return writeJSON(c, *out, catalog)
+	}
+	d := schema.Diagram{Version: schema.CurrentVersion, Tables: catalog.Tables, Relationships: catalog.Relationships}
+	text, err := render(d, *format, "", false)
+	if err != nil {
+		return err
+	}
+	return writeOutput(c, *out, text)
+}
+
+// acceptSuggestions adds the relationships SuggestRelationships infers for catalog,
+// reporting each added one on stderr.
+func (c *cmdContext) acceptSuggestions(catalog schema.TableCatalog, opts schema.SuggestOptions) (schema.TableCatalog, error) {
+	suggestions, err := schema.SuggestRelationships(catalog, opts)
+	if err != nil {
+		return catalog, err
+	}
+	n := len(catalog.Relationships)
+	catalog = schema.AcceptSuggestions(catalog, suggestions)
+	names := make(map[string]string)
+	for _, t := range catalog.Tables {
+		for _, f := range t.Fields {
+			names[t.ID+"."+f.ID] = t.Name + "." + f.Name
+		}
+	}
+	// Report what was added: AcceptSuggestions keeps the first suggestion per column.
+	added := make(map[string]bool)
+	for _, r := range catalog.Relationships[n:] {
+		added[r.SourceTableID+"\x00"+r.SourceFieldID+"\x00"+r.TargetTableID+"\x00"+r.TargetFieldID] = true
+	}
+	for _, s := range suggestions {
+		r := s.Relationship
+		key := r.SourceTableID + "\x00" + r.SourceFieldID + "\x00" + r.TargetTableID + "\x00" + r.TargetFieldID
+		if !added[key] {
+			continue
+		}
+		delete(added, key)
+		fmt.Fprintf(c.stderr, "suggested: %s -> %s (%.2f: %s)\n",
+			names[r.TargetTableID+"."+r.TargetFieldID], names[r.SourceTableID+"."+r.SourceFieldID], s.Confidence, s.Reason)
+	}
+	return catalog, nil
+}
+
+// ---------------------------------------------------------------------------
+// diff
+// ---------------------------------------------------------------------------
+
+func runDiff(c *cmdContext, args []string) error {
+	fs := newFlagSet(c, "diff", "FROM TO")
+	dialect := fs.String("f", "", "print a migration script for this dialect ("+strings.Join(sortedDialects(), ", ")+") instead of the diff as JSON")
+	exitCode := fs.Bool("exit-code", false, "exit with status 3 if the schemas differ")
+	matchIDs := fs.Bool("match-ids", false, "report tables and columns that share an ID as renames (only when both sides come from one workspace, whose IDs are stable)")
+	out := fs.String("o", "", "output file (default stdout)")
+	if err := parseFlags(fs, args, 2); err != nil {
+		return err
+	}
+	from, err := loadSource(c, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	to, err := loadSource(c, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	sd := schema.DiffDiagramsWithOptions(from, to, schema.DiffOptions{MatchByID: *matchIDs})
+	if *exitCode && !sd.IsEmpty() {
+		c.exitCode = ExitChanges
+	}
+	if *dialect == "" {
+		return writeJSON(c, *out, sd)
+	}
+	script, err := sqlx.MigrationScript(*dialect, sd)
+	if err != nil {
+		return err
+	}
+	return writeOutput(c, *out, script)
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+// loadSource loads a SOURCE argument (see printUsage) as a diagram.
+func loadSource(c *cmdContext, spec string) (schema.Diagram, error) {
+	path, diagram := spec, ""
+	if i := strings.LastIndex(spec, "#"); i >= 0 && strings.HasSuffix(strings.ToLower(spec[:i]), ".schemastudio") {
+		path, diagram = spec[:i], spec[i+1:]
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".schemastudio") {
+		var d schema.Diagram
+		err := withWorkspace(path, func(repo *workspace.WorkspaceRepo) error {
+			var err error
+			if diagram == "" {
+				d, err = repo.CatalogDiagram()
+				return err
+			}
+			id, err := findDiagram(repo, diagram)
+			if err != nil {
+				return err
+			}
+			d, err = repo.SchemaDiagram(id)
+			return err
+		})
+		return d, err
+	}
+	content, err := readInput(c, path)
+	if err != nil {
+		return schema.Diagram{}, err
+	}
+	format := formatForExt(path)
+	if format == "" {
+		format = sniffFormat(content)
+	}
+	return c.parseContent(format, "auto", content, path)
+}
+
+// parseContent parses content in an input format (json, sql, prisma, dbml, mermaid,
+// plantuml, csv) into a diagram. dialect applies to SQL input ("auto" detects it). Importer warnings are
+// printed to stderr, prefixed with name.
+func (c *cmdContext) parseContent(format, dialect, content, name string) (schema.Diagram, error) {
+	var catalog schema.TableCatalog
+	var err error
+	switch format {
+	case "json":
+		var d schema.Diagram
+		if err := json.Unmarshal([]byte(content), &d); err != nil {
+			return schema.Diagram{}, fmt.Errorf("parse JSON: %w", err)
+		}
+		return d, nil
+	case "mermaid":
+		return importers.ParseMermaid(content)
+	case "plantuml":
+		return importers.ParsePlantUML(content)
+	case "sql":
+		catalog, err = importers.ParseSQLDialect(content, dialect)
+	case "csv":
+		catalog, err = importers.ParseCSV(content)
+	case "prisma":
+		catalog, err = importers.ParsePrisma(content)
+	case "dbml":
+		catalog, err = importers.ParseDBML(content)
+	default:
+		return schema.Diagram{}, fmt.Errorf("unknown input format: %s", format)
+	}
+	if err != nil {
+		return schema.Diagram{}, err
+	}
+	for _, w := range catalog.Warnings {
+		if w.Line > 0 {
+			fmt.Fprintf(c.stderr, "%s:%d: warning: %s\n", name, w.Line, w.Message)
+		} else {
+			fmt.Fprintf(c.stderr, "%s: warning: %s\n", name, w.Message)
+		}
+	}
+	return schema.Diagram{Version: schema.CurrentVersion, Tables: catalog.Tables, Relationships: catalog.Relationships}, nil
+}
+
+// render formats a diagram as DDL for a dialect, Mermaid, PlantUML, DBML, JSON or code
+// for a codegen language. If
+// alterForeignKeys is true, DDL adds foreign keys with ALTER TABLE after all tables.
+func render(d schema.Diagram, format, schemaName string, alterForeignKeys bool) (string, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		b, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	case "mermaid":
+		return schema.ToMermaid(d), nil
+	case "plantuml":
+		return schema.ToPlantUML(d), nil
+	case "dbml":
+		return schema.ToDBML(d), nil
+	case "postgres":
+		if schemaName != "" || alterForeignKeys {
+			return sqlx.ExportPostgresWithSchema(d, schemaName, alterForeignKeys)
+		}
+	case "mysql":
+		if alterForeignKeys {
+			return sqlx.ExportMySQLWithOptions(d, "InnoDB", "utf8mb4", true)
+		}
+	case "mssql":
+		if schemaName != "" || alterForeignKeys {
+			return sqlx.ExportMSSQLWithOptions(d, schemaName, "", true, alterForeignKeys)
+		}
+	}
+	for _, lang := range codegen.Languages() {
+		if strings.EqualFold(format, lang) {
+			return codegen.Generate(lang, d)
+		}
+	}
+	return sqlx.Export(format, d)
+}
+
+func outputFormats() []string {
+	return append(append(sortedDialects(), "mermaid", "plantuml", "dbml", "json"), codegen.Languages()...)
+}
+
+func sortedDialects() []string {
+	dialects := sqlx.Dialects()
+	sort.Strings(dialects)
+	return dialects
+}
+
+// formatForExt returns the input format for a file name, or "" if unknown.
+func formatForExt(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sql", ".ddl":
+		return "sql"
+	case ".mmd", ".mermaid":
+		return "mermaid"
+	case ".puml", ".plantuml":
+		return "plantuml"
+	case ".csv":
+		return "csv"
+	case ".prisma":
+		return "prisma"
+	case ".dbml":
+		return "dbml"
+	case ".json":
+		return "json"
+	}
+	return ""
+}
+
+// sniffFormat guesses the input format of content without a known file extension.
+func sniffFormat(content string) string {
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		return "json"
+	case strings.HasPrefix(trimmed, "erDiagram"):
+		return "mermaid"
+	case strings.HasPrefix(trimmed, "@startuml"):
+		return "plantuml"
+	}
+	return "sql"
+}
+
+// withWorkspace opens an existing .schemastudio file, runs fn and closes it.
+func withWorkspace(path string, fn func(repo *workspace.WorkspaceRepo) error) error {
+	// OpenWorkspace would create a missing file; a typo should be an error instead.
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	wm := workspace.NewManager()
+	defer wm.CloseAll()
+	_, repo, err := wm.OpenWorkspace(path)
+	if err != nil {
+		return err
+	}
+	return fn(repo)
+}
+
+// mergeIntoWorkspace merges an imported catalog into the catalog of the workspace at
+// path. A merge that writes is journaled under label, as in the app, so it can be undone.
+func mergeIntoWorkspace(path, label string, catalog schema.TableCatalog, dryRun bool) (workspace.CatalogMergeReport, error) {
+	var report workspace.CatalogMergeReport
+	err := withWorkspace(path, func(repo *workspace.WorkspaceRepo) error {
+		merge := func() error {
+			var err error
+			report, err = repo.MergeImportedCatalog(catalog, dryRun)
+			return err
+		}
+		if dryRun {
+			return merge()
+		}
+		return repo.Journaled(label, workspace.ChangeScope{All: true}, merge)
+	})
+	return report, err
+}
+
+// findDiagram returns the ID of the diagram with the given ID or (case-insensitive) name.
+func findDiagram(repo *workspace.WorkspaceRepo, nameOrID string) (string, error) {
+	diagrams, err := repo.ListDiagrams()
+	if err != nil {
+		return "", err
+	}
+	for _, d := range diagrams {
+		if d.ID == nameOrID {
+			return d.ID, nil
+		}
+	}
+	for _, d := range diagrams {
+		if strings.EqualFold(d.Name, nameOrID) {
+			return d.ID, nil
+		}
+	}
+	return "", fmt.Errorf("diagram %q not found", nameOrID)
+}
+
+func readInput(c *cmdContext, path string) (string, error) {
+	if path == "-" {
+		b, err := io.ReadAll(c.stdin)
+		return string(b), err
+	}
+	b, err := os.ReadFile(path)
+	return string(b), err
+}
+
+func writeOutput(c *cmdContext, path, text string) error {
+	if path == "" || path == "-" {
+		_, err := io.WriteString(c.stdout, text)
+		return err
+	}
+	return os.WriteFile(path, []byte(text), 0644)
+}
+
+func writeJSON(c *cmdContext, path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeOutput(c, path, string(b)+"\n")
+}
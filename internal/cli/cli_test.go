@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"schemastudio/internal/workspace"
+)
+
+func run(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := Run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_ExportSQLFile(t *testing.T) {
+	dir := t.TempDir()
+	src := writeFile(t, dir, "schema.sql", "CREATE TABLE users (id INT PRIMARY KEY, email VARCHAR(100) NOT NULL);")
+	code, out, stderr := run(t, "", "export", "-f", "mysql", src)
+	if code != ExitOK {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "create table `users`") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	outFile := filepath.Join(dir, "out.mmd")
+	if code, _, stderr := run(t, "", "export", "-f", "mermaid", "-o", outFile, src); code != ExitOK {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	b, err := os.ReadFile(outFile)
+	if err != nil || !strings.Contains(string(b), "erDiagram") {
+		t.Errorf("mermaid file = %q, %v", b, err)
+	}
+
+	code, out, stderr = run(t, "", "export", "-f", "typescript", src)
+	if code != ExitOK {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "export interface User {\n  id: number;\n  email: string;\n}") {
+		t.Errorf("unexpected typescript output:\n%s", out)
+	}
+}
+
+func TestRun_ExportSchemaLanguages(t *testing.T) {
+	dir := t.TempDir()
+	prisma := writeFile(t, dir, "schema.prisma", "model User {\n  id Int @id\n  posts Post[]\n}\nmodel Post {\n  id Int @id\n  userId Int\n  user User @relation(fields: [userId], references: [id])\n}\n")
+	dbml := writeFile(t, dir, "schema.dbml", "Table users {\n  id int [pk]\n}\nTable posts {\n  id int [pk]\n  user_id int [ref: > users.id]\n}\n")
+	puml := writeFile(t, dir, "schema.puml", "@startuml\nentity users {\n  * id : int\n  --\n}\nentity posts {\n  * id : int\n  --\n  * user_id : int <<FK>>\n}\nusers ||--o{ posts\n@enduml\n")
+	for _, src := range []string{prisma, dbml, puml} {
+		code, out, stderr := run(t, "", "export", "-f", "postgres", src)
+		if code != ExitOK {
+			t.Fatalf("%s: exit %d: %s", src, code, stderr)
+		}
+		if !strings.Contains(out, "foreign key") {
+			t.Errorf("%s: expected a foreign key in output:\n%s", src, out)
+		}
+	}
+}
+
+func TestRun_ExportStdin(t *testing.T) {
+	code, out, stderr := run(t, "CREATE TABLE t (id INT);", "export", "-f", "postgres", "-")
+	if code != ExitOK {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "create table") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestRun_DiffExitCode(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.sql", "CREATE TABLE users (id INT PRIMARY KEY);")
+	b := writeFile(t, dir, "b.sql", "CREATE TABLE users (id INT PRIMARY KEY, name TEXT);")
+
+	code, out, stderr := run(t, "", "diff", "-exit-code", "-f", "postgres", a, b)
+	if code != ExitChanges {
+		t.Fatalf("exit %d, want %d: %s", code, ExitChanges, stderr)
+	}
+	if !strings.Contains(out, "add column") {
+		t.Errorf("unexpected migration:\n%s", out)
+	}
+	if code, _, _ := run(t, "", "diff", "-exit-code", a, a); code != ExitOK {
+		t.Errorf("identical schemas: exit %d", code)
+	}
+}
+
+func TestRun_DiffIndependentImports(t *testing.T) {
+	dir := t.TempDir()
+	// Each file is imported afresh, so users and accounts (and name and title) share IDs.
+	a := writeFile(t, dir, "a.sql", "CREATE TABLE users (id INT PRIMARY KEY, name TEXT);\nCREATE TABLE items (id INT PRIMARY KEY, name TEXT);")
+	b := writeFile(t, dir, "b.sql", "CREATE TABLE accounts (id INT PRIMARY KEY, name TEXT);\nCREATE TABLE items (id INT PRIMARY KEY, title TEXT);")
+
+	code, out, stderr := run(t, "", "diff", a, b)
+	if code != ExitOK {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if strings.Contains(out, "oldName") || strings.Contains(out, "renamedFields") {
+		t.Errorf("unrelated objects reported as renames:\n%s", out)
+	}
+	for _, want := range []string{`"droppedTables"`, `"addedTables"`, `"droppedFields"`, `"addedFields"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diff missing %s:\n%s", want, out)
+		}
+	}
+
+	code, out, stderr = run(t, "", "diff", "-match-ids", a, b)
+	if code != ExitOK {
+		t.Fatalf("-match-ids: exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, `"oldName": "users"`) || !strings.Contains(out, `"renamedFields"`) {
+		t.Errorf("-match-ids should pair by ID:\n%s", out)
+	}
+}
+
+func undoState(t *testing.T, wsPath string) workspace.UndoState {
+	t.Helper()
+	var s workspace.UndoState
+	err := withWorkspace(wsPath, func(repo *workspace.WorkspaceRepo) error {
+		var err error
+		s, err = repo.GetUndoState()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRun_WorkspaceImportAndExport(t *testing.T) {
+	dir := t.TempDir()
+	wsPath := filepath.Join(dir, "test.schemastudio")
+	wm := workspace.NewManager()
+	_, _, err := wm.CreateWorkspace(wsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wm.CloseAll()
+
+	src := writeFile(t, dir, "schema.sql", "CREATE TABLE users (id INT PRIMARY KEY);\nCREATE TABLE orders (id INT PRIMARY KEY);")
+	code, out, stderr := run(t, "", "import", "-w", wsPath, "-dry-run", src)
+	if code != ExitOK || !strings.Contains(out, `"dryRun": true`) {
+		t.Fatalf("dry run: exit %d, out %s, err %s", code, out, stderr)
+	}
+	if s := undoState(t, wsPath); s.CanUndo {
+		t.Errorf("dry run was journaled: %+v", s)
+	}
+	if code, _, stderr := run(t, "", "import", "-w", wsPath, src); code != ExitOK {
+		t.Fatalf("import: exit %d: %s", code, stderr)
+	}
+	// The merge can be undone in the app.
+	if s := undoState(t, wsPath); !s.CanUndo || s.UndoLabel != "Merge import of schema.sql" {
+		t.Errorf("undo state after import = %+v", s)
+	}
+	code, out, stderr = run(t, "", "export", "-f", "postgres", wsPath)
+	if code != ExitOK {
+		t.Fatalf("export: exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "users") || !strings.Contains(out, "orders") {
+		t.Errorf("catalog export missing tables:\n%s", out)
+	}
+	if code, _, _ := run(t, "", "export", wsPath+"#missing"); code != ExitError {
+		t.Errorf("missing diagram: exit %d, want %d", code, ExitError)
+	}
+}
+
+func TestRun_InspectSuggest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		"CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL)",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	code, out, stderr := run(t, "", "inspect", "-driver", "sqlite", "-database", path, "-f", "mermaid")
+	if code != ExitOK || strings.Contains(out, "customers ||") {
+		t.Fatalf("exit %d without -suggest: %s\n%s", code, stderr, out)
+	}
+	code, out, stderr = run(t, "", "inspect", "-driver", "sqlite", "-database", path, "-f", "mermaid", "-suggest", "0.8")
+	if code != ExitOK {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(out, "customers ||..o{ orders") {
+		t.Errorf("suggested relationship missing:\n%s", out)
+	}
+	if !strings.Contains(stderr, "suggested: orders.customer_id -> customers.id (0.90: ") {
+		t.Errorf("stderr = %q", stderr)
+	}
+	if code, _, _ := run(t, "", "inspect", "-driver", "sqlite", "-database", path, "-suggest", "0.5", "-fk-patterns", "{column}"); code != ExitError {
+		t.Errorf("pattern without {table}: exit %d", code)
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	if code, _, _ := run(t, "", "export"); code != ExitUsage {
+		t.Errorf("missing argument: exit %d", code)
+	}
+	if code, _, _ := run(t, "", "nope"); code != ExitUsage {
+		t.Errorf("unknown command: exit %d", code)
+	}
+	if code, _, _ := run(t, "", "export", filepath.Join(t.TempDir(), "missing.schemastudio")); code != ExitError {
+		t.Errorf("missing workspace: exit %d", code)
+	}
+	if !IsCommand("diff") || IsCommand("-psn_0_123") {
+		t.Error("IsCommand mismatch")
+	}
+}
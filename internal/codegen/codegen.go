@@ -0,0 +1,204 @@
+// Package codegen turns a diagram into application code: model types for a
+// programming language or ORM, one per table or view.
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
+)
+
+// Generator generates source code for a language or ORM.
+type Generator interface {
+	Language() string
+	Generate(d schema.Diagram) (string, error)
+}
+
+var registry = map[string]Generator{
+	"go":         &GoGenerator{},
+	"sqlalchemy": &SQLAlchemyGenerator{},
+	"typescript": &TypeScriptGenerator{},
+	"prisma":     &PrismaGenerator{},
+}
+
+// Register adds a generator for a language name.
+func Register(name string, g Generator) {
+	registry[name] = g
+}
+
+// Generate returns source code for the given language, or an error if unknown.
+func Generate(language string, d schema.Diagram) (string, error) {
+	g, ok := registry[strings.ToLower(language)]
+	if !ok {
+		return "", fmt.Errorf("unknown language: %s", language)
+	}
+	return g.Generate(d)
+}
+
+// Languages returns the registered language names, sorted.
+func Languages() []string {
+	names := make([]string, 0, len(registry))
+	for k := range registry {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// genericType returns the field's generic type ("string", "integer", "float", "numeric",
+// "boolean", "date", "time", "timestamp", "uuid", "json", "bytes" or "other"). Fields
+// from older diagrams may hold a raw database type, which is normalized. Structs and
+// repeated fields are treated as JSON documents.
+func genericType(f schema.Field) string {
+	if f.Repeated {
+		return "json"
+	}
+	gt, _, _, _ := sqlx.NormalizeType(f.Type)
+	if gt == schema.TypeStruct {
+		return "json"
+	}
+	return gt
+}
+
+// words splits an identifier into its words at underscores, spaces, punctuation and
+// lower-to-upper case changes: "orderLine_ID" -> ["order", "Line", "ID"].
+func words(name string) []string {
+	var out []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = nil
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if len(cur) > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return out
+}
+
+// pascalCase joins the words of name with each capitalized: "order_lines" -> "OrderLines".
+func pascalCase(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		b.WriteString(capitalize(strings.ToLower(w)))
+	}
+	return b.String()
+}
+
+// camelCase is pascalCase with a lower-case first word: "order_lines" -> "orderLines".
+func camelCase(name string) string {
+	p := pascalCase(name)
+	if p == "" {
+		return ""
+	}
+	r := []rune(p)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// snakeCase joins the lower-cased words of name with underscores: "OrderLines" -> "order_lines".
+func snakeCase(name string) string {
+	ws := words(name)
+	for i, w := range ws {
+		ws[i] = strings.ToLower(w)
+	}
+	return strings.Join(ws, "_")
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// singular returns a plain English singular for a table name: "categories" ->
+// "category", "orders" -> "order". Names that do not look plural are kept.
+func singular(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"), strings.HasSuffix(lower, "is"):
+		return name
+	case strings.HasSuffix(lower, "s") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// typeName returns the model type name for a table: the singular of its name in PascalCase.
+func typeName(t schema.Table) string {
+	name := pascalCase(singular(t.Name))
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "T" + name
+	}
+	return name
+}
+
+// uniqueNames hands out names that have not been used yet, appending 2, 3, ... to
+// names that have.
+type uniqueNames map[string]bool
+
+func (u uniqueNames) claim(name string) string {
+	candidate := name
+	for n := 2; u[strings.ToLower(candidate)]; n++ {
+		candidate = fmt.Sprintf("%s%d", name, n)
+	}
+	u[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// typeNames assigns each table a unique type name, by table ID.
+func typeNames(d schema.Diagram) map[string]string {
+	used := uniqueNames{}
+	names := make(map[string]string, len(d.Tables))
+	for _, t := range d.Tables {
+		names[t.ID] = used.claim(typeName(t))
+	}
+	return names
+}
+
+// primaryKey returns the table's primary key fields.
+func primaryKey(t schema.Table) []schema.Field {
+	var pk []schema.Field
+	for _, f := range t.Fields {
+		if f.PrimaryKey {
+			pk = append(pk, f)
+		}
+	}
+	return pk
+}
+
+// commentLines splits a description into trimmed lines, dropping blank ones.
+func commentLines(s string) []string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
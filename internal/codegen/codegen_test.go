@@ -0,0 +1,205 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+func intP(v int) *int { return &v }
+
+func sampleDiagram() schema.Diagram {
+	return schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "c", Name: "customers", Schema: "sales", Description: "People who buy things.", Fields: []schema.Field{
+				{ID: "c1", Name: "id", Type: "integer", PrimaryKey: true, Identity: schema.IdentityByDefault},
+				{ID: "c2", Name: "email", Type: "string", Length: intP(255), Unique: true},
+				{ID: "c3", Name: "created_at", Type: "timestamp", Default: "now()"},
+				{ID: "c4", Name: "profile", Type: "jsonb", Nullable: true},
+			}},
+			{ID: "o", Name: "orders", Fields: []schema.Field{
+				{ID: "o1", Name: "id", Type: "uuid", PrimaryKey: true, Default: "gen_random_uuid()"},
+				{ID: "o2", Name: "customer_id", Type: "integer"},
+				{ID: "o3", Name: "total", Type: "numeric", Precision: intP(10), Scale: intP(2), Comment: "Including tax."},
+				{ID: "o4", Name: "status", Type: "string", Default: "'new'"},
+				{ID: "o5", Name: "shipped on", Type: "date", Nullable: true},
+			}, Indexes: []schema.Index{{Name: "orders_status_idx", Columns: []schema.IndexColumn{{FieldID: "o4"}}}}},
+			{ID: "v", Name: "order_totals", Kind: schema.KindView, Definition: "select customer_id, sum(total) as total from orders group by customer_id", Fields: []schema.Field{
+				{ID: "v1", Name: "customer_id", Type: "integer"},
+				{ID: "v2", Name: "total", Type: "numeric", Nullable: true},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "c", SourceFieldID: "c1", TargetTableID: "o", TargetFieldID: "o2"},
+		},
+	}
+}
+
+func TestGenerate_UnknownLanguage(t *testing.T) {
+	if _, err := Generate("cobol", sampleDiagram()); err == nil {
+		t.Error("expected error for unknown language")
+	}
+	want := []string{"go", "prisma", "sqlalchemy", "typescript"}
+	if got := Languages(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Languages() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerate_Go(t *testing.T) {
+	out, err := Generate("go", sampleDiagram())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"package models",
+		`"encoding/json"`,
+		`"time"`,
+		"// Customer is a row of the sales.customers table.",
+		"// People who buy things.",
+		"type Customer struct {",
+		"ID        int64           `db:\"id\" json:\"id\"`",
+		"Profile   json.RawMessage `db:\"profile\" json:\"profile\"`",
+		"type Order struct {",
+		"CustomerID int64  `db:\"customer_id\" json:\"customer_id\"`",
+		"// Including tax.",
+		"ShippedOn *time.Time `db:\"shipped on\" json:\"shipped on\"`",
+		"type OrderTotal struct {",
+		"Total      *string `db:\"total\" json:\"total\"`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerate_TypeScript(t *testing.T) {
+	out, err := Generate("typescript", sampleDiagram())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"/** People who buy things. */\nexport interface Customer {",
+		"  id: number;",
+		"  profile: unknown | null;",
+		"  /** Including tax. */\n  total: string;",
+		`  "shipped on": string | null;`,
+		"export interface OrderTotal {",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerate_SQLAlchemy(t *testing.T) {
+	out, err := Generate("sqlalchemy", sampleDiagram())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"from datetime import date, datetime\nfrom decimal import Decimal\nfrom typing import Any, Optional\nfrom uuid import UUID\n\n",
+		"from sqlalchemy import Column, Date, DateTime, ForeignKey, Identity, Integer, JSON, Numeric, String, Table, Uuid, text\n",
+		"class Customer(Base):\n    \"\"\"People who buy things.\"\"\"\n\n    __tablename__ = \"customers\"\n",
+		`    __table_args__ = {"schema": "sales", "comment": "People who buy things."}`,
+		"    id: Mapped[int] = mapped_column(Integer, Identity(), primary_key=True)",
+		"    email: Mapped[str] = mapped_column(String(255), unique=True)",
+		`    created_at: Mapped[datetime] = mapped_column(DateTime, server_default=text("now()"))`,
+		"    profile: Mapped[Optional[Any]] = mapped_column(JSON)",
+		`    customer_id: Mapped[int] = mapped_column(Integer, ForeignKey("sales.customers.id"))`,
+		`    total: Mapped[Decimal] = mapped_column(Numeric(10, 2), comment="Including tax.")`,
+		`    shipped_on: Mapped[Optional[date]] = mapped_column("shipped on", Date)`,
+		"order_totals_table = Table(\n    \"order_totals\",\n    Base.metadata,\n    Column(\"customer_id\", Integer, nullable=False),\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerate_Prisma(t *testing.T) {
+	out, err := Generate("prisma", sampleDiagram())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"/// People who buy things.\nmodel Customer {\n",
+		"  id         Int      @id @default(autoincrement())\n",
+		"  email      String   @unique\n",
+		"  created_at DateTime @default(now())\n",
+		"  profile    Json?\n",
+		"  orders     Order[]\n",
+		"  @@map(\"customers\")\n",
+		"  id          String    @id @default(uuid())\n",
+		"  status      String    @default(\"new\")\n",
+		"  shippedOn   DateTime? @map(\"shipped on\")\n",
+		"  customer    Customer  @relation(fields: [customer_id], references: [id])\n",
+		"  @@index([status], map: \"orders_status_idx\")\n",
+		"view OrderTotal {\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrisma_SelfAndParallelRelations(t *testing.T) {
+	d := schema.Diagram{
+		Tables: []schema.Table{
+			{ID: "e", Name: "employees", Fields: []schema.Field{
+				{ID: "e1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "e2", Name: "manager_id", Type: "integer", Nullable: true},
+			}},
+			{ID: "m", Name: "messages", Fields: []schema.Field{
+				{ID: "m1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "m2", Name: "sender_id", Type: "integer"},
+				{ID: "m3", Name: "recipient_id", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{SourceTableID: "e", SourceFieldID: "e1", TargetTableID: "e", TargetFieldID: "e2"},
+			{SourceTableID: "e", SourceFieldID: "e1", TargetTableID: "m", TargetFieldID: "m2"},
+			{SourceTableID: "e", SourceFieldID: "e1", TargetTableID: "m", TargetFieldID: "m3", Name: "fk_recipient"},
+		},
+	}
+	out, err := Generate("prisma", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`  manager    Employee?  @relation("Employee_manager", fields: [manager_id], references: [id])`,
+		`  employees  Employee[] @relation("Employee_manager")`,
+		`  messages   Message[]  @relation("Message_sender")`,
+		`  messages2  Message[]  @relation("fk_recipient")`,
+		`  sender       Employee @relation("Message_sender", fields: [sender_id], references: [id])`,
+		`  recipient    Employee @relation("fk_recipient", fields: [recipient_id], references: [id], map: "fk_recipient")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestNaming(t *testing.T) {
+	for _, c := range []struct{ in, pascal, snake, single string }{
+		{"order_lines", "OrderLines", "order_lines", "order_line"},
+		{"OrderLineID", "OrderLineId", "order_line_id", "OrderLineID"},
+		{"categories", "Categories", "categories", "category"},
+		{"addresses", "Addresses", "addresses", "address"},
+		{"status", "Status", "status", "status"},
+	} {
+		if got := pascalCase(c.in); got != c.pascal {
+			t.Errorf("pascalCase(%q) = %q, want %q", c.in, got, c.pascal)
+		}
+		if got := snakeCase(c.in); got != c.snake {
+			t.Errorf("snakeCase(%q) = %q, want %q", c.in, got, c.snake)
+		}
+		if got := singular(c.in); got != c.single {
+			t.Errorf("singular(%q) = %q, want %q", c.in, got, c.single)
+		}
+	}
+	if got := goFieldName("customer_url_id"); got != "CustomerURLID" {
+		t.Errorf("goFieldName = %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	"schemastudio/internal/schema"
+)
+
+// GoGenerator generates Go structs in package models, with db and json tags. Nullable
+// columns are pointers, except []byte and json.RawMessage, which can already be nil.
+type GoGenerator struct{}
+
+func (g *GoGenerator) Language() string { return "go" }
+
+func (g *GoGenerator) Generate(d schema.Diagram) (string, error) {
+	names := typeNames(d)
+	var body bytes.Buffer
+	imports := map[string]bool{}
+	for i, t := range d.Tables {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		kind := "table"
+		if t.IsView() {
+			kind = "view"
+		}
+		fmt.Fprintf(&body, "// %s is a row of the %s %s.\n", names[t.ID], t.QualifiedName(), kind)
+		if lines := commentLines(t.Description); len(lines) > 0 {
+			body.WriteString("//\n")
+			for _, l := range lines {
+				fmt.Fprintf(&body, "// %s\n", l)
+			}
+		}
+		fmt.Fprintf(&body, "type %s struct {\n", names[t.ID])
+		used := uniqueNames{}
+		for _, f := range t.Fields {
+			for _, l := range commentLines(f.Comment) {
+				fmt.Fprintf(&body, "\t// %s\n", l)
+			}
+			typ, pkg := goType(f)
+			if pkg != "" {
+				imports[pkg] = true
+			}
+			fmt.Fprintf(&body, "\t%s %s `db:%q json:%q`\n", used.claim(goFieldName(f.Name)), typ, f.Name, f.Name)
+		}
+		body.WriteString("}\n")
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by schemastudio. DO NOT EDIT.\n\npackage models\n")
+	if len(imports) > 0 {
+		b.WriteString("\nimport (\n")
+		for _, pkg := range []string{"encoding/json", "time"} {
+			if imports[pkg] {
+				fmt.Fprintf(&b, "\t%q\n", pkg)
+			}
+		}
+		b.WriteString(")\n")
+	}
+	if body.Len() > 0 {
+		b.WriteString("\n")
+		b.Write(body.Bytes())
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("format generated Go: %w", err)
+	}
+	return string(src), nil
+}
+
+// goType returns the Go type for a field and the package it needs, if any.
+func goType(f schema.Field) (typ, pkg string) {
+	switch genericType(f) {
+	case "integer":
+		typ = "int64"
+	case "float":
+		typ = "float64"
+	case "boolean":
+		typ = "bool"
+	case "date", "time", "timestamp":
+		typ, pkg = "time.Time", "time"
+	case "json":
+		return "json.RawMessage", "encoding/json"
+	case "bytes":
+		return "[]byte", ""
+	default:
+		// Strings, and numeric kept as its decimal text to avoid rounding.
+		typ = "string"
+	}
+	if f.Nullable {
+		typ = "*" + typ
+	}
+	return typ, pkg
+}
+
+// goInitialisms are words written in all capitals in Go names.
+var goInitialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "UUID": true, "JSON": true, "HTTP": true,
+	"API": true, "SQL": true, "IP": true, "XML": true, "HTML": true, "SKU": true,
+}
+
+// goFieldName returns the exported Go name for a column: "user_id" -> "UserID".
+func goFieldName(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		if up := strings.ToUpper(w); goInitialisms[up] {
+			b.WriteString(up)
+		} else {
+			b.WriteString(capitalize(strings.ToLower(w)))
+		}
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "F" + s
+	}
+	return s
+}
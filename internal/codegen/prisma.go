@@ -0,0 +1,338 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// PrismaGenerator generates a Prisma schema: a model per table with @map/@@map to the
+// database names, relation fields on both sides of each relationship, and a view block
+// per view. Tables without a primary key are marked @@ignore, as prisma db pull does.
+type PrismaGenerator struct{}
+
+func (g *PrismaGenerator) Language() string { return "prisma" }
+
+// prismaField is one line of a model: name, type and attributes.
+type prismaField struct {
+	name, typ, attrs string
+}
+
+// prismaModel collects a model's fields and block attributes while relations are added.
+type prismaModel struct {
+	table     *schema.Table
+	name      string
+	fields    []prismaField
+	fieldName map[string]string // column field ID -> Prisma field name
+	used      uniqueNames
+	ignored   bool
+}
+
+func (g *PrismaGenerator) Generate(d schema.Diagram) (string, error) {
+	names := typeNames(d)
+	models := make([]*prismaModel, len(d.Tables))
+	byID := make(map[string]*prismaModel, len(d.Tables))
+	for i := range d.Tables {
+		t := &d.Tables[i]
+		m := &prismaModel{table: t, name: names[t.ID], fieldName: map[string]string{}, used: uniqueNames{}}
+		pk := primaryKey(*t)
+		m.ignored = !t.IsView() && len(pk) == 0
+		for _, f := range t.Fields {
+			name := m.used.claim(prismaFieldName(f.Name))
+			m.fieldName[f.ID] = name
+			typ := prismaType(f)
+			if f.Nullable {
+				typ += "?"
+			}
+			var attrs []string
+			if f.PrimaryKey && len(pk) == 1 {
+				attrs = append(attrs, "@id")
+			}
+			if f.Unique && !f.PrimaryKey {
+				attrs = append(attrs, "@unique")
+			}
+			if def := prismaDefault(f); def != "" {
+				attrs = append(attrs, "@default("+def+")")
+			}
+			if name != f.Name {
+				attrs = append(attrs, "@map("+strconv.Quote(f.Name)+")")
+			}
+			m.fields = append(m.fields, prismaField{name: name, typ: typ, attrs: strings.Join(attrs, " ")})
+		}
+		models[i] = m
+		byID[t.ID] = m
+	}
+	addPrismaRelations(d, byID)
+
+	var b bytes.Buffer
+	b.WriteString("// Generated by schemastudio.\n\n")
+	b.WriteString("generator client {\n  provider = \"prisma-client-js\"\n}\n\n")
+	b.WriteString("datasource db {\n  provider = \"postgresql\"\n  url      = env(\"DATABASE_URL\")\n}\n")
+	for _, m := range models {
+		b.WriteString("\n")
+		writePrismaModel(&b, m)
+	}
+	return b.String(), nil
+}
+
+// addPrismaRelations adds a relation field to the child model of each relationship and
+// a back-relation field to the parent. Relationships between the same two models, and
+// self-relations, are told apart by relation name.
+func addPrismaRelations(d schema.Diagram, byID map[string]*prismaModel) {
+	pairs := make(map[string]int)
+	pairKey := func(r schema.Relationship) string {
+		a, b := r.SourceTableID, r.TargetTableID
+		if a > b {
+			a, b = b, a
+		}
+		return a + "\x00" + b
+	}
+	for _, r := range d.Relationships {
+		pairs[pairKey(r)]++
+	}
+	for _, r := range d.Relationships {
+		parent, child := byID[r.SourceTableID], byID[r.TargetTableID]
+		if parent == nil || child == nil || parent.ignored || child.ignored || parent.table.IsView() || child.table.IsView() {
+			continue
+		}
+		var childFields, parentFields []*schema.Field
+		srcIDs, tgtIDs := r.FieldIDPairs()
+		for i := range srcIDs {
+			pf, cf := fieldByID(parent.table, srcIDs[i]), fieldByID(child.table, tgtIDs[i])
+			if pf != nil && cf != nil {
+				parentFields = append(parentFields, pf)
+				childFields = append(childFields, cf)
+			}
+		}
+		if len(childFields) == 0 {
+			continue
+		}
+
+		relField := camelCase(parent.name)
+		if len(childFields) == 1 {
+			if base := trimIDSuffix(childFields[0].Name); base != "" {
+				relField = camelCase(base)
+			}
+		}
+		relField = child.used.claim(relField)
+		backField := parent.used.claim(camelCase(child.table.Name))
+
+		relName := ""
+		if r.SourceTableID == r.TargetTableID || pairs[pairKey(r)] > 1 {
+			relName = r.Name
+			if relName == "" {
+				relName = child.name + "_" + relField
+			}
+			relName = strconv.Quote(relName)
+		}
+
+		optional := false
+		var cols, refs []string
+		for i, cf := range childFields {
+			optional = optional || cf.Nullable
+			cols = append(cols, child.fieldName[cf.ID])
+			refs = append(refs, parent.fieldName[parentFields[i].ID])
+		}
+		relArgs := []string{}
+		if relName != "" {
+			relArgs = append(relArgs, relName)
+		}
+		relArgs = append(relArgs, "fields: ["+strings.Join(cols, ", ")+"]", "references: ["+strings.Join(refs, ", ")+"]")
+		if r.Name != "" {
+			relArgs = append(relArgs, "map: "+strconv.Quote(r.Name))
+		}
+		typ := parent.name
+		if optional {
+			typ += "?"
+		}
+		child.fields = append(child.fields, prismaField{name: relField, typ: typ, attrs: "@relation(" + strings.Join(relArgs, ", ") + ")"})
+
+		backType := child.name + "[]"
+		if uniqueColumns(child.table, childFields) {
+			backType = child.name + "?"
+		}
+		backAttrs := ""
+		if relName != "" {
+			backAttrs = "@relation(" + relName + ")"
+		}
+		parent.fields = append(parent.fields, prismaField{name: backField, typ: backType, attrs: backAttrs})
+	}
+}
+
+func writePrismaModel(b *bytes.Buffer, m *prismaModel) {
+	t := m.table
+	for _, l := range commentLines(t.Description) {
+		fmt.Fprintf(b, "/// %s\n", l)
+	}
+	if m.ignored {
+		b.WriteString("/// The underlying table does not contain a valid unique identifier and can therefore currently not be handled by Prisma Client.\n")
+	}
+	block := "model"
+	if t.IsView() {
+		block = "view"
+	}
+	fmt.Fprintf(b, "%s %s {\n", block, m.name)
+	nameWidth, typeWidth := 0, 0
+	for _, f := range m.fields {
+		nameWidth = max(nameWidth, len(f.name))
+		typeWidth = max(typeWidth, len(f.typ))
+	}
+	for _, f := range m.fields {
+		line := fmt.Sprintf("  %-*s %-*s %s", nameWidth, f.name, typeWidth, f.typ, f.attrs)
+		b.WriteString(strings.TrimRight(line, " "))
+		b.WriteString("\n")
+	}
+
+	var blockAttrs []string
+	if pk := primaryKey(*t); len(pk) > 1 {
+		blockAttrs = append(blockAttrs, "@@id(["+m.fieldList(pk)+"])")
+	}
+	for _, def := range t.ResolvedIndexes() {
+		var keys []string
+		for _, k := range def.Keys {
+			if k.Expression != "" {
+				keys = nil
+				break
+			}
+			for _, f := range t.Fields {
+				if f.Name == k.Column {
+					keys = append(keys, m.fieldName[f.ID])
+				}
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		attr := "@@index"
+		if def.Unique {
+			attr = "@@unique"
+		}
+		blockAttrs = append(blockAttrs, fmt.Sprintf("%s([%s], map: %s)", attr, strings.Join(keys, ", "), strconv.Quote(def.Name)))
+	}
+	if m.name != t.Name {
+		blockAttrs = append(blockAttrs, "@@map("+strconv.Quote(t.Name)+")")
+	}
+	if m.ignored {
+		blockAttrs = append(blockAttrs, "@@ignore")
+	}
+	if len(blockAttrs) > 0 {
+		b.WriteString("\n")
+		for _, a := range blockAttrs {
+			fmt.Fprintf(b, "  %s\n", a)
+		}
+	}
+	b.WriteString("}\n")
+}
+
+func (m *prismaModel) fieldList(fields []schema.Field) string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = m.fieldName[f.ID]
+	}
+	return strings.Join(names, ", ")
+}
+
+// uniqueColumns reports whether fields are the table's whole primary key or a single
+// unique column, making the relationship one-to-one.
+func uniqueColumns(t *schema.Table, fields []*schema.Field) bool {
+	if len(fields) == 1 && fields[0].Unique {
+		return true
+	}
+	pk := primaryKey(*t)
+	if len(pk) != len(fields) {
+		return false
+	}
+	for _, f := range fields {
+		if !f.PrimaryKey {
+			return false
+		}
+	}
+	return true
+}
+
+// trimIDSuffix returns a foreign key column name without its id suffix ("customer_id"
+// -> "customer"), or "" if it has none.
+func trimIDSuffix(name string) string {
+	ws := words(name)
+	if len(ws) < 2 || !strings.EqualFold(ws[len(ws)-1], "id") {
+		return ""
+	}
+	return strings.Join(ws[:len(ws)-1], "_")
+}
+
+// prismaType returns the Prisma scalar type for a field.
+func prismaType(f schema.Field) string {
+	switch genericType(f) {
+	case "integer":
+		return "Int"
+	case "float":
+		return "Float"
+	case "numeric":
+		return "Decimal"
+	case "boolean":
+		return "Boolean"
+	case "date", "time", "timestamp":
+		return "DateTime"
+	case "json":
+		return "Json"
+	case "bytes":
+		return "Bytes"
+	default:
+		return "String"
+	}
+}
+
+var (
+	prismaIdent   = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	prismaNumber  = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	prismaSQLText = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+)
+
+// prismaFieldName returns the Prisma field name for a column: the column name when it
+// is a valid identifier, else its camelCase form.
+func prismaFieldName(name string) string {
+	if prismaIdent.MatchString(name) {
+		return name
+	}
+	if c := camelCase(name); prismaIdent.MatchString(c) {
+		return c
+	}
+	return "f" + pascalCase(name)
+}
+
+// prismaDefault returns the @default argument for a field, or "" for none. Defaults
+// Prisma has no function for are passed through with dbgenerated.
+func prismaDefault(f schema.Field) string {
+	if f.Identity != "" {
+		return "autoincrement()"
+	}
+	def := strings.TrimSpace(f.Default)
+	for strings.HasPrefix(def, "(") && strings.HasSuffix(def, ")") {
+		def = strings.TrimSpace(def[1 : len(def)-1])
+	}
+	if def == "" {
+		return ""
+	}
+	gt := genericType(f)
+	switch lower := strings.ToLower(def); {
+	case lower == "now()" || lower == "current_timestamp" || lower == "current_timestamp()" ||
+		lower == "getdate()" || lower == "sysdatetime()" || lower == "localtimestamp":
+		return "now()"
+	case lower == "gen_random_uuid()" || lower == "uuid_generate_v4()" || lower == "newid()" || lower == "uuid()":
+		return "uuid()"
+	case gt == "boolean" && (lower == "true" || lower == "1"):
+		return "true"
+	case gt == "boolean" && (lower == "false" || lower == "0"):
+		return "false"
+	case prismaNumber.MatchString(def) && (gt == "integer" || gt == "float" || gt == "numeric"):
+		return def
+	}
+	if m := prismaSQLText.FindStringSubmatch(def); m != nil && (gt == "string" || gt == "uuid") {
+		return strconv.Quote(strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return "dbgenerated(" + strconv.Quote(def) + ")"
+}
@@ -0,0 +1,355 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// SQLAlchemyGenerator generates SQLAlchemy 2.0 declarative models. Tables without a
+// primary key, which the ORM cannot map, are generated as Core Table objects instead.
+type SQLAlchemyGenerator struct{}
+
+func (g *SQLAlchemyGenerator) Language() string { return "sqlalchemy" }
+
+func (g *SQLAlchemyGenerator) Generate(d schema.Diagram) (string, error) {
+	names := typeNames(d)
+	tableByID := make(map[string]*schema.Table, len(d.Tables))
+	for i := range d.Tables {
+		tableByID[d.Tables[i].ID] = &d.Tables[i]
+	}
+	// Single-column foreign keys are declared on the column, composite ones on the table.
+	columnFKs := make(map[string]string)  // child field ID -> "schema.table.column"
+	tableFKs := make(map[string][]string) // child table ID -> ForeignKeyConstraint(...)
+	for _, r := range d.Relationships {
+		parent, child := tableByID[r.SourceTableID], tableByID[r.TargetTableID]
+		if parent == nil || child == nil || parent.IsView() || child.IsView() {
+			continue
+		}
+		var cols, refs []string
+		var childIDs []string
+		srcIDs, tgtIDs := r.FieldIDPairs()
+		for i := range srcIDs {
+			pc, cc := fieldByID(parent, srcIDs[i]), fieldByID(child, tgtIDs[i])
+			if pc == nil || cc == nil {
+				continue
+			}
+			cols = append(cols, pyString(cc.Name))
+			refs = append(refs, pyString(parent.QualifiedName()+"."+pc.Name))
+			childIDs = append(childIDs, cc.ID)
+		}
+		switch {
+		case len(cols) == 1:
+			columnFKs[childIDs[0]] = refs[0]
+		case len(cols) > 1:
+			tableFKs[child.ID] = append(tableFKs[child.ID], fmt.Sprintf("ForeignKeyConstraint([%s], [%s])",
+				strings.Join(cols, ", "), strings.Join(refs, ", ")))
+		}
+	}
+
+	im := pyImports{}
+	im.add("sqlalchemy.orm", "DeclarativeBase")
+	var body bytes.Buffer
+	for _, t := range d.Tables {
+		body.WriteString("\n\n")
+		if len(primaryKey(t)) == 0 {
+			writeSATable(&body, im, t, columnFKs, tableFKs[t.ID])
+		} else {
+			writeSAModel(&body, im, t, names[t.ID], columnFKs, tableFKs[t.ID])
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("# Generated by schemastudio.\n\n")
+	b.WriteString(im.String())
+	b.WriteString("\n\nclass Base(DeclarativeBase):\n    pass\n")
+	b.Write(body.Bytes())
+	return b.String(), nil
+}
+
+// writeSAModel writes a declarative class for a table with a primary key.
+func writeSAModel(b *bytes.Buffer, im pyImports, t schema.Table, className string, columnFKs map[string]string, tableFKs []string) {
+	im.add("sqlalchemy.orm", "Mapped", "mapped_column")
+	fmt.Fprintf(b, "class %s(Base):\n", className)
+	if lines := commentLines(t.Description); len(lines) > 0 {
+		fmt.Fprintf(b, "    %s\n\n", pyDocstring(lines))
+	}
+	fmt.Fprintf(b, "    __tablename__ = %s\n", pyString(t.Name))
+	if args := saTableArgs(im, t, tableFKs); args != "" {
+		fmt.Fprintf(b, "    __table_args__ = %s\n", args)
+	}
+	b.WriteString("\n")
+	used := uniqueNames{"metadata": true, "registry": true}
+	for _, f := range t.Fields {
+		attr := used.claim(pyAttrName(f.Name))
+		pyType := pyValueType(im, f)
+		if f.Nullable {
+			im.add("typing", "Optional")
+			pyType = "Optional[" + pyType + "]"
+		}
+		var args []string
+		if attr != f.Name {
+			args = append(args, pyString(f.Name))
+		}
+		args = append(args, saColumnArgs(im, f, columnFKs)...)
+		fmt.Fprintf(b, "    %s: Mapped[%s] = mapped_column(%s)\n", attr, pyType, strings.Join(args, ", "))
+	}
+}
+
+// writeSATable writes a Core Table for a table or view without a primary key.
+func writeSATable(b *bytes.Buffer, im pyImports, t schema.Table, columnFKs map[string]string, tableFKs []string) {
+	im.add("sqlalchemy", "Column", "Table")
+	kind := "table"
+	if t.IsView() {
+		kind = "view"
+	}
+	fmt.Fprintf(b, "# The %s %s has no primary key, so it is a Table rather than a mapped class.\n", t.QualifiedName(), kind)
+	fmt.Fprintf(b, "%s = Table(\n    %s,\n    Base.metadata,\n", pyAttrName(t.Name)+"_table", pyString(t.Name))
+	for _, f := range t.Fields {
+		args := append([]string{pyString(f.Name)}, saColumnArgs(im, f, columnFKs)...)
+		if !f.Nullable {
+			args = append(args, "nullable=False")
+		}
+		fmt.Fprintf(b, "    Column(%s),\n", strings.Join(args, ", "))
+	}
+	for _, fk := range tableFKs {
+		im.add("sqlalchemy", "ForeignKeyConstraint")
+		fmt.Fprintf(b, "    %s,\n", fk)
+	}
+	if t.Schema != "" {
+		fmt.Fprintf(b, "    schema=%s,\n", pyString(t.Schema))
+	}
+	if t.Description != "" {
+		fmt.Fprintf(b, "    comment=%s,\n", pyString(t.Description))
+	}
+	b.WriteString(")\n")
+}
+
+// saTableArgs returns the __table_args__ value for composite foreign keys, the schema
+// and the table comment, or "" if there are none.
+func saTableArgs(im pyImports, t schema.Table, tableFKs []string) string {
+	var opts []string
+	if t.Schema != "" {
+		opts = append(opts, `"schema": `+pyString(t.Schema))
+	}
+	if t.Description != "" {
+		opts = append(opts, `"comment": `+pyString(t.Description))
+	}
+	dict := ""
+	if len(opts) > 0 {
+		dict = "{" + strings.Join(opts, ", ") + "}"
+	}
+	if len(tableFKs) == 0 {
+		return dict
+	}
+	im.add("sqlalchemy", "ForeignKeyConstraint")
+	items := append([]string{}, tableFKs...)
+	if dict != "" {
+		items = append(items, dict)
+	}
+	return "(" + strings.Join(items, ", ") + ",)"
+}
+
+// saColumnArgs returns the column type and options for mapped_column or Column.
+func saColumnArgs(im pyImports, f schema.Field, columnFKs map[string]string) []string {
+	args := []string{saType(im, f)}
+	if ref, ok := columnFKs[f.ID]; ok {
+		im.add("sqlalchemy", "ForeignKey")
+		args = append(args, "ForeignKey("+ref+")")
+	}
+	switch f.Identity {
+	case schema.IdentityAlways:
+		im.add("sqlalchemy", "Identity")
+		args = append(args, "Identity(always=True)")
+	case schema.IdentityByDefault:
+		im.add("sqlalchemy", "Identity")
+		args = append(args, "Identity()")
+	}
+	if f.PrimaryKey {
+		args = append(args, "primary_key=True")
+	}
+	if f.Unique && !f.PrimaryKey {
+		args = append(args, "unique=True")
+	}
+	if f.Default != "" && f.Identity == "" {
+		im.add("sqlalchemy", "text")
+		args = append(args, "server_default=text("+pyString(f.Default)+")")
+	}
+	if f.Comment != "" {
+		args = append(args, "comment="+pyString(f.Comment))
+	}
+	return args
+}
+
+// saType returns the SQLAlchemy column type for a field.
+func saType(im pyImports, f schema.Field) string {
+	var typ string
+	switch genericType(f) {
+	case "integer":
+		typ = "Integer"
+	case "float":
+		typ = "Float"
+	case "numeric":
+		im.add("sqlalchemy", "Numeric")
+		if f.Precision != nil && f.Scale != nil {
+			return fmt.Sprintf("Numeric(%d, %d)", *f.Precision, *f.Scale)
+		}
+		if f.Precision != nil {
+			return fmt.Sprintf("Numeric(%d)", *f.Precision)
+		}
+		return "Numeric"
+	case "boolean":
+		typ = "Boolean"
+	case "date":
+		typ = "Date"
+	case "time":
+		typ = "Time"
+	case "timestamp":
+		typ = "DateTime"
+	case "uuid":
+		typ = "Uuid"
+	case "json":
+		typ = "JSON"
+	case "bytes":
+		typ = "LargeBinary"
+	default:
+		im.add("sqlalchemy", "String")
+		if f.Length != nil {
+			return fmt.Sprintf("String(%d)", *f.Length)
+		}
+		return "String"
+	}
+	im.add("sqlalchemy", typ)
+	return typ
+}
+
+// pyValueType returns the Python type of a field's values, for Mapped[...].
+func pyValueType(im pyImports, f schema.Field) string {
+	switch genericType(f) {
+	case "integer":
+		return "int"
+	case "float":
+		return "float"
+	case "numeric":
+		im.add("decimal", "Decimal")
+		return "Decimal"
+	case "boolean":
+		return "bool"
+	case "date":
+		im.add("datetime", "date")
+		return "date"
+	case "time":
+		im.add("datetime", "time")
+		return "time"
+	case "timestamp":
+		im.add("datetime", "datetime")
+		return "datetime"
+	case "uuid":
+		im.add("uuid", "UUID")
+		return "UUID"
+	case "json":
+		im.add("typing", "Any")
+		return "Any"
+	case "bytes":
+		return "bytes"
+	default:
+		return "str"
+	}
+}
+
+// pyImports collects "from module import name" imports.
+type pyImports map[string]map[string]bool
+
+func (im pyImports) add(module string, names ...string) {
+	if im[module] == nil {
+		im[module] = map[string]bool{}
+	}
+	for _, n := range names {
+		im[module][n] = true
+	}
+}
+
+// String renders the imports with the standard library first, each group and name sorted.
+func (im pyImports) String() string {
+	var stdlib, thirdParty []string
+	for m := range im {
+		if strings.HasPrefix(m, "sqlalchemy") {
+			thirdParty = append(thirdParty, m)
+		} else {
+			stdlib = append(stdlib, m)
+		}
+	}
+	sort.Strings(stdlib)
+	sort.Strings(thirdParty)
+	var b strings.Builder
+	for gi, group := range [][]string{stdlib, thirdParty} {
+		if len(group) == 0 {
+			continue
+		}
+		if gi > 0 && len(stdlib) > 0 {
+			b.WriteString("\n")
+		}
+		for _, m := range group {
+			names := make([]string, 0, len(im[m]))
+			for n := range im[m] {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			fmt.Fprintf(&b, "from %s import %s\n", m, strings.Join(names, ", "))
+		}
+	}
+	return b.String()
+}
+
+var pyIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var pyKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// pyAttrName returns a Python attribute name for a column: the name itself when it is a
+// valid identifier, else its snake_case form; keywords get a trailing underscore.
+func pyAttrName(name string) string {
+	attr := name
+	if !pyIdent.MatchString(attr) {
+		attr = snakeCase(name)
+		if attr == "" || !pyIdent.MatchString(attr) {
+			attr = "c_" + attr
+		}
+	}
+	if pyKeywords[attr] {
+		attr += "_"
+	}
+	return attr
+}
+
+// pyString returns s as a double-quoted Python string literal.
+func pyString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func pyDocstring(lines []string) string {
+	text := strings.ReplaceAll(strings.Join(lines, "\n    "), `"""`, `\"\"\"`)
+	if len(lines) == 1 {
+		return `"""` + text + `"""`
+	}
+	return `"""` + text + "\n    " + `"""`
+}
+
+func fieldByID(t *schema.Table, id string) *schema.Field {
+	for i := range t.Fields {
+		if t.Fields[i].ID == id {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
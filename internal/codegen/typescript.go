@@ -0,0 +1,77 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// TypeScriptGenerator generates a TypeScript interface per table, with properties
+// named after the columns. Nullable columns are typed T | null.
+type TypeScriptGenerator struct{}
+
+func (g *TypeScriptGenerator) Language() string { return "typescript" }
+
+func (g *TypeScriptGenerator) Generate(d schema.Diagram) (string, error) {
+	names := typeNames(d)
+	var b bytes.Buffer
+	b.WriteString("// Generated by schemastudio.\n")
+	for _, t := range d.Tables {
+		b.WriteString("\n")
+		writeJSDoc(&b, "", t.Description)
+		fmt.Fprintf(&b, "export interface %s {\n", names[t.ID])
+		for _, f := range t.Fields {
+			writeJSDoc(&b, "  ", f.Comment)
+			typ := tsType(f)
+			if f.Nullable {
+				typ += " | null"
+			}
+			fmt.Fprintf(&b, "  %s: %s;\n", tsPropertyName(f.Name), typ)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String(), nil
+}
+
+// tsType returns the TypeScript type for a field's values as they arrive in JSON.
+// Decimals, temporal values, UUIDs and bytes are strings there.
+func tsType(f schema.Field) string {
+	switch genericType(f) {
+	case "integer", "float":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "json", "other":
+		return "unknown"
+	default:
+		return "string"
+	}
+}
+
+var tsIdent = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsPropertyName(name string) string {
+	if tsIdent.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func writeJSDoc(b *bytes.Buffer, indent, text string) {
+	lines := commentLines(strings.ReplaceAll(text, "*/", "*\\/"))
+	switch len(lines) {
+	case 0:
+	case 1:
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+	default:
+		fmt.Fprintf(b, "%s/**\n", indent)
+		for _, l := range lines {
+			fmt.Fprintf(b, "%s * %s\n", indent, l)
+		}
+		fmt.Fprintf(b, "%s */\n", indent)
+	}
+}
@@ -121,7 +121,7 @@ func (b *BigQueryInspector) ListSchemas() ([]string, error) {
 	return datasets, nil
 }
 
-// ListTables returns the list of table IDs in the given dataset.
+// ListTables returns the list of table and view IDs in the given dataset.
 func (b *BigQueryInspector) ListTables(schemaName string) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
 	defer cancel()
@@ -142,7 +142,42 @@ func (b *BigQueryInspector) ListTables(schemaName string) ([]string, error) {
 	return tables, nil
 }
 
-// InspectSchema introspects BigQuery tables and returns a TableCatalog.
+// bigQueryField converts a column schema, walking the sub-fields of RECORD columns.
+// REPEATED columns become Repeated fields of their element type.
+func bigQueryField(fs *bigquery.FieldSchema, gen *idGen) schema.Field {
+	rawType := string(fs.Type)
+	genericType, normLen, normPrec, normScale := sqlx.NormalizeType(rawType)
+
+	f := schema.Field{
+		ID:        gen.field(),
+		Name:      fs.Name,
+		Type:      genericType,
+		Nullable:  !fs.Required,
+		Length:    normLen,
+		Precision: normPrec,
+		Scale:     normScale,
+		Default:   fs.DefaultValueExpression,
+		Comment:   fs.Description,
+		Repeated:  fs.Repeated,
+	}
+	if genericType == schema.TypeStruct {
+		for _, sub := range fs.Schema {
+			f.Fields = append(f.Fields, bigQueryField(sub, gen))
+		}
+		return f
+	}
+
+	// Store the original BigQuery type as a dialect-specific override
+	rawLower := strings.ToLower(strings.TrimSpace(rawType))
+	if rawLower != genericType {
+		f.TypeOverrides = map[string]schema.FieldTypeOverride{
+			"bigquery": {Type: rawLower},
+		}
+	}
+	return f
+}
+
+// InspectSchema introspects BigQuery tables and views and returns a TableCatalog.
 // BigQuery has no foreign key constraints, so relationships will be empty.
 func (b *BigQueryInspector) InspectSchema(schemaName string, tableNames []string) (schema.TableCatalog, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout*2) // BQ can be slower
@@ -179,38 +214,43 @@ func (b *BigQueryInspector) InspectSchema(schemaName string, tableNames []string
 		tID := gen.table()
 		var fields []schema.Field
 		for _, fs := range md.Schema {
-			fID := gen.field()
-			rawType := string(fs.Type)
-			genericType, normLen, normPrec, normScale := sqlx.NormalizeType(rawType)
-
-			f := schema.Field{
-				ID:        fID,
-				Name:      fs.Name,
-				Type:      genericType,
-				Nullable:  !fs.Required,
-				Length:    normLen,
-				Precision: normPrec,
-				Scale:     normScale,
-			}
+			fields = append(fields, bigQueryField(fs, gen))
+		}
 
-			// Store the original BigQuery type as a dialect-specific override
-			rawLower := strings.ToLower(strings.TrimSpace(rawType))
-			if rawLower != genericType {
-				f.TypeOverrides = map[string]schema.FieldTypeOverride{
-					"bigquery": {Type: rawLower},
-				}
+		// Labels are key/value pairs; they become "key:value" tags.
+		var tags []string
+		for k, v := range md.Labels {
+			if v == "" {
+				tags = append(tags, k)
+			} else {
+				tags = append(tags, k+":"+v)
 			}
+		}
+		sort.Strings(tags)
 
-			fields = append(fields, f)
+		var kind, definition string
+		switch md.Type {
+		case bigquery.ViewTable:
+			kind, definition = schema.KindView, md.ViewQuery
+		case bigquery.MaterializedView:
+			kind = schema.KindMaterializedView
+			if md.MaterializedView != nil {
+				definition = md.MaterializedView.Query
+			}
 		}
 
 		row, col := i/cols, i%cols
 		tables = append(tables, schema.Table{
-			ID:     tID,
-			Name:   tableName,
-			X:      float64(col * 320),
-			Y:      float64(row * 240),
-			Fields: fields,
+			ID:          tID,
+			Name:        tableName,
+			Schema:      schemaName,
+			X:           float64(col * 320),
+			Y:           float64(row * 240),
+			Fields:      fields,
+			Description: md.Description,
+			Tags:        tags,
+			Kind:        kind,
+			Definition:  viewQuery(definition),
 		})
 	}
 
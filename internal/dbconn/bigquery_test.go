@@ -0,0 +1,64 @@
+package dbconn
+
+import (
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+
+	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
+)
+
+func TestBigQueryField_Nested(t *testing.T) {
+	fs := &bigquery.FieldSchema{
+		Name:     "items",
+		Type:     bigquery.RecordFieldType,
+		Repeated: true,
+		Schema: bigquery.Schema{
+			{Name: "sku", Type: bigquery.StringFieldType, Required: true},
+			{Name: "codes", Type: bigquery.IntegerFieldType, Repeated: true},
+			{Name: "price", Type: bigquery.RecordFieldType, Description: "unit price", Schema: bigquery.Schema{
+				{Name: "amount", Type: bigquery.NumericFieldType},
+				{Name: "currency", Type: bigquery.StringFieldType},
+			}},
+		},
+	}
+	f := bigQueryField(fs, newIDGen())
+	if f.Type != schema.TypeStruct || !f.Repeated || f.TypeOverrides != nil || len(f.Fields) != 3 {
+		t.Fatalf("items = %+v", f)
+	}
+	if sku := f.Fields[0]; sku.Type != "string" || sku.Nullable || sku.Repeated {
+		t.Errorf("sku = %+v", sku)
+	}
+	if codes := f.Fields[1]; codes.Type != "integer" || !codes.Repeated {
+		t.Errorf("codes = %+v", codes)
+	}
+	price := f.Fields[2]
+	if price.Type != schema.TypeStruct || price.Repeated || price.Comment != "unit price" || len(price.Fields) != 2 ||
+		price.Fields[0].Type != "numeric" || price.Fields[1].Name != "currency" {
+		t.Errorf("price = %+v", price)
+	}
+	ids := map[string]bool{f.ID: true}
+	for _, sub := range append(append([]schema.Field{}, f.Fields...), price.Fields...) {
+		if ids[sub.ID] {
+			t.Errorf("duplicate field ID %s", sub.ID)
+		}
+		ids[sub.ID] = true
+	}
+}
+
+func TestBigQueryField_RepeatedRoundTrip(t *testing.T) {
+	// DATETIME is stored as a "datetime" override of the generic timestamp type; exporting
+	// must keep the repetition around it.
+	fs := &bigquery.FieldSchema{Name: "seen_at", Type: bigquery.DateTimeFieldType, Repeated: true}
+	f := bigQueryField(fs, newIDGen())
+	if got := sqlx.FieldType("bigquery", f); !strings.EqualFold(got, "ARRAY<DATETIME>") {
+		t.Errorf("FieldType = %q, want ARRAY<DATETIME>", got)
+	}
+
+	nested := &bigquery.FieldSchema{Name: "visits", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{fs}}
+	if got := sqlx.FieldType("bigquery", bigQueryField(nested, newIDGen())); !strings.EqualFold(got, "STRUCT<seen_at ARRAY<DATETIME>>") {
+		t.Errorf("FieldType = %q, want STRUCT<seen_at ARRAY<DATETIME>>", got)
+	}
+}
@@ -10,10 +10,10 @@ import (
 
 // ConnectionConfig holds the parameters needed to connect to a database backend.
 type ConnectionConfig struct {
-	Driver   string `json:"driver"`   // postgres, mysql, mssql, bigquery
+	Driver   string `json:"driver"`   // postgres, mysql, mssql, bigquery, sqlite
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
-	Database string `json:"database"`
+	Database string `json:"database"` // file path for sqlite
 	Username string `json:"username"`
 	Password string `json:"password"`
 	SSLMode  string `json:"sslMode,omitempty"`
@@ -57,6 +57,8 @@ func NewInspector(driver string) (SchemaInspector, error) {
 		return &MSSQLInspector{}, nil
 	case "bigquery":
 		return &BigQueryInspector{}, nil
+	case "sqlite":
+		return &SQLiteInspector{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported database driver: %s", driver)
 	}
@@ -6,7 +6,7 @@ import (
 )
 
 func TestNewInspector_ValidDrivers(t *testing.T) {
-	for _, driver := range []string{"postgres", "mysql", "mssql", "bigquery"} {
+	for _, driver := range []string{"postgres", "mysql", "mssql", "bigquery", "sqlite"} {
 		insp, err := NewInspector(driver)
 		if err != nil {
 			t.Errorf("NewInspector(%q) returned error: %v", driver, err)
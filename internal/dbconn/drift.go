@@ -0,0 +1,225 @@
+package dbconn
+
+import (
+	"sort"
+	"strings"
+
+	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
+)
+
+// DriftReport lists the differences between a designed catalog and a live database.
+// "Missing" items exist in the catalog but not in the database; "extra" items exist
+// only in the database.
+type DriftReport struct {
+	Source             string              `json:"source"`
+	MissingTables      []string            `json:"missingTables"`
+	ExtraTables        []string            `json:"extraTables"`
+	Tables             []TableDrift        `json:"tables"`
+	MissingForeignKeys []schema.ForeignKey `json:"missingForeignKeys"`
+	ExtraForeignKeys   []schema.ForeignKey `json:"extraForeignKeys"`
+}
+
+// TableDrift lists column and primary key differences for a table present on both sides.
+type TableDrift struct {
+	Table              string        `json:"table"`
+	MissingColumns     []string      `json:"missingColumns,omitempty"`
+	ExtraColumns       []string      `json:"extraColumns,omitempty"`
+	Columns            []ColumnDrift `json:"columns,omitempty"`
+	CatalogPrimaryKey  []string      `json:"catalogPrimaryKey,omitempty"`
+	DatabasePrimaryKey []string      `json:"databasePrimaryKey,omitempty"`
+	PrimaryKeyMismatch bool          `json:"primaryKeyMismatch,omitempty"`
+}
+
+// ColumnDrift describes a column whose definition differs between catalog and database.
+type ColumnDrift struct {
+	Column           string `json:"column"`
+	CatalogType      string `json:"catalogType"`
+	DatabaseType     string `json:"databaseType"`
+	CatalogNullable  bool   `json:"catalogNullable"`
+	DatabaseNullable bool   `json:"databaseNullable"`
+	CatalogLength    *int   `json:"catalogLength,omitempty"`
+	DatabaseLength   *int   `json:"databaseLength,omitempty"`
+	TypeMismatch     bool   `json:"typeMismatch,omitempty"`
+	NullableMismatch bool   `json:"nullableMismatch,omitempty"`
+	LengthMismatch   bool   `json:"lengthMismatch,omitempty"`
+}
+
+// HasDrift reports whether any difference was found.
+func (r DriftReport) HasDrift() bool {
+	return len(r.MissingTables) > 0 || len(r.ExtraTables) > 0 || len(r.Tables) > 0 ||
+		len(r.MissingForeignKeys) > 0 || len(r.ExtraForeignKeys) > 0
+}
+
+// CompareCatalog compares a catalog with an introspected database schema for the
+// given dialect. Tables are matched by schema and name (see schema.MatchTables) and
+// columns by name, case-insensitively; the IDs generated by the inspectors are ignored.
+// Types are compared by their generic family (using the catalog's dialect override when
+// present), lengths only for strings.
+func CompareCatalog(catalog schema.Diagram, db schema.TableCatalog, dialect string) DriftReport {
+	report := DriftReport{Source: db.ImportSource}
+
+	match := schema.MatchTables(catalog.Tables, db.Tables)
+	matchedDB := make([]bool, len(db.Tables))
+	// dbTable maps the key of each matched catalog table to its database table, so
+	// catalog foreign keys can be compared in database terms.
+	dbTable := make(map[string]*schema.Table)
+	for i, ct := range catalog.Tables {
+		j := match[i]
+		if j < 0 {
+			report.MissingTables = append(report.MissingTables, tableLabel(ct))
+			continue
+		}
+		matchedDB[j] = true
+		dt := &db.Tables[j]
+		dbTable[schema.TableKey(ct.Schema, ct.Name)] = dt
+		if td, ok := compareTable(ct, *dt, dialect); ok {
+			report.Tables = append(report.Tables, td)
+		}
+	}
+	dbMatched := make(map[string]bool)
+	for j, dt := range db.Tables {
+		if matchedDB[j] {
+			dbMatched[schema.TableKey(dt.Schema, dt.Name)] = true
+		} else {
+			report.ExtraTables = append(report.ExtraTables, tableLabel(dt))
+		}
+	}
+
+	// Foreign keys are only compared between tables present on both sides, so a
+	// missing table is not also reported as a set of missing foreign keys.
+	dbDiagram := schema.Diagram{Tables: db.Tables, Relationships: db.Relationships}
+	dbSigs := make(map[string]bool)
+	for _, fk := range schema.ForeignKeys(dbDiagram) {
+		dbSigs[fk.Signature()] = true
+	}
+	catSigs := make(map[string]bool)
+	for _, fk := range schema.ForeignKeys(catalog) {
+		child, parent := dbTable[schema.TableKey(fk.Schema, fk.Table)], dbTable[schema.TableKey(fk.RefSchema, fk.RefTable)]
+		if child == nil || parent == nil {
+			continue
+		}
+		translated := fk
+		translated.Schema, translated.Table = child.Schema, child.Name
+		translated.RefSchema, translated.RefTable = parent.Schema, parent.Name
+		sig := translated.Signature()
+		catSigs[sig] = true
+		if !dbSigs[sig] {
+			report.MissingForeignKeys = append(report.MissingForeignKeys, fk)
+		}
+	}
+	for _, fk := range schema.ForeignKeys(dbDiagram) {
+		present := dbMatched[schema.TableKey(fk.Schema, fk.Table)] && dbMatched[schema.TableKey(fk.RefSchema, fk.RefTable)]
+		if present && !catSigs[fk.Signature()] {
+			report.ExtraForeignKeys = append(report.ExtraForeignKeys, fk)
+		}
+	}
+
+	sort.Strings(report.MissingTables)
+	sort.Strings(report.ExtraTables)
+	return report
+}
+
+// tableLabel names a table in a drift report, with its schema if it has one.
+func tableLabel(t schema.Table) string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// compareTable returns the drift for a matched table and whether there was any.
+func compareTable(ct, dt schema.Table, dialect string) (TableDrift, bool) {
+	td := TableDrift{Table: tableLabel(ct)}
+	dbFields := make(map[string]*schema.Field)
+	for i := range dt.Fields {
+		dbFields[strings.ToLower(dt.Fields[i].Name)] = &dt.Fields[i]
+	}
+	catFields := make(map[string]bool)
+	var catPK, dbPK []string
+	for _, cf := range ct.Fields {
+		catFields[strings.ToLower(cf.Name)] = true
+		if cf.PrimaryKey {
+			catPK = append(catPK, cf.Name)
+		}
+		df := dbFields[strings.ToLower(cf.Name)]
+		if df == nil {
+			td.MissingColumns = append(td.MissingColumns, cf.Name)
+			continue
+		}
+		if cd, ok := compareField(cf, *df, dialect); ok {
+			td.Columns = append(td.Columns, cd)
+		}
+	}
+	for _, df := range dt.Fields {
+		if df.PrimaryKey {
+			dbPK = append(dbPK, df.Name)
+		}
+		if !catFields[strings.ToLower(df.Name)] {
+			td.ExtraColumns = append(td.ExtraColumns, df.Name)
+		}
+	}
+	if !sameNameSet(catPK, dbPK) {
+		td.CatalogPrimaryKey = catPK
+		td.DatabasePrimaryKey = dbPK
+		td.PrimaryKeyMismatch = true
+	}
+	changed := len(td.MissingColumns) > 0 || len(td.ExtraColumns) > 0 || len(td.Columns) > 0 || td.PrimaryKeyMismatch
+	return td, changed
+}
+
+// compareField returns the drift for a matched column and whether there was any.
+func compareField(cf, df schema.Field, dialect string) (ColumnDrift, bool) {
+	catType, catLen := effectiveType(cf, dialect)
+	dbType, dbLen := effectiveType(df, dialect)
+	cd := ColumnDrift{
+		Column:           cf.Name,
+		CatalogType:      typeLabel(cf, dialect),
+		DatabaseType:     typeLabel(df, dialect),
+		CatalogNullable:  cf.Nullable,
+		DatabaseNullable: df.Nullable,
+		CatalogLength:    catLen,
+		DatabaseLength:   dbLen,
+	}
+	cd.TypeMismatch = catType != dbType
+	cd.NullableMismatch = cf.Nullable != df.Nullable
+	// An unspecified catalog length means "any length".
+	cd.LengthMismatch = !cd.TypeMismatch && catType == "string" && catLen != nil &&
+		(dbLen == nil || *catLen != *dbLen)
+	return cd, cd.TypeMismatch || cd.NullableMismatch || cd.LengthMismatch
+}
+
+// effectiveType returns the generic type family and string length of a field as
+// it would exist in the given dialect.
+func effectiveType(f schema.Field, dialect string) (string, *int) {
+	raw := f.Type
+	if ov, ok := f.TypeOverrides[dialect]; ok && ov.Type != "" {
+		raw = ov.Type
+	}
+	gt, length, _, _ := sqlx.NormalizeType(raw)
+	if f.Length != nil {
+		length = f.Length
+	}
+	return gt, length
+}
+
+// typeLabel returns the dialect type a field maps to, for display in reports.
+func typeLabel(f schema.Field, dialect string) string {
+	return sqlx.FieldType(dialect, f)
+}
+
+func sameNameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[strings.ToLower(s)] = true
+	}
+	for _, s := range b {
+		if !set[strings.ToLower(s)] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package dbconn
+
+import (
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+func intPtr(n int) *int { return &n }
+
+func driftCatalog() schema.Diagram {
+	return schema.Diagram{
+		Tables: []schema.Table{
+			{ID: "c1", Name: "users", Fields: []schema.Field{
+				{ID: "c1f1", Name: "id", Type: "int", PrimaryKey: true},
+				{ID: "c1f2", Name: "email", Type: "string", Length: intPtr(255)},
+				{ID: "c1f3", Name: "meta", Type: "json", TypeOverrides: map[string]schema.FieldTypeOverride{"postgres": {Type: "jsonb"}}},
+			}},
+			{ID: "c2", Name: "orders", Fields: []schema.Field{
+				{ID: "c2f1", Name: "id", Type: "int", PrimaryKey: true},
+				{ID: "c2f2", Name: "user_id", Type: "int"},
+			}},
+			{ID: "c3", Name: "audit", Fields: []schema.Field{{ID: "c3f1", Name: "id", Type: "int"}}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "cr1", SourceTableID: "c1", SourceFieldID: "c1f1", TargetTableID: "c2", TargetFieldID: "c2f2"},
+		},
+	}
+}
+
+func TestCompareCatalog_NoDrift(t *testing.T) {
+	cat := driftCatalog()
+	db := schema.TableCatalog{
+		Tables: []schema.Table{
+			{ID: "t1", Name: "USERS", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "email", Type: "varchar", Length: intPtr(255)},
+				{ID: "f3", Name: "meta", Type: "jsonb"},
+			}},
+			{ID: "t2", Name: "orders", Fields: []schema.Field{
+				{ID: "f4", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f5", Name: "user_id", Type: "integer"},
+			}},
+			{ID: "t3", Name: "audit", Fields: []schema.Field{{ID: "f6", Name: "id", Type: "integer"}}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f5"},
+		},
+	}
+	r := CompareCatalog(cat, db, "postgres")
+	if r.HasDrift() {
+		t.Errorf("expected no drift, got %+v", r)
+	}
+}
+
+func TestCompareCatalog_Drift(t *testing.T) {
+	cat := driftCatalog()
+	db := schema.TableCatalog{
+		ImportSource: "postgres",
+		Tables: []schema.Table{
+			{ID: "t1", Name: "users", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer"},
+				{ID: "f2", Name: "email", Type: "varchar", Length: intPtr(100), Nullable: true},
+				{ID: "f3", Name: "meta", Type: "text"},
+				{ID: "f4", Name: "created_at", Type: "timestamp"},
+			}},
+			{ID: "t2", Name: "orders", Fields: []schema.Field{
+				{ID: "f5", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f6", Name: "user_id", Type: "integer"},
+				{ID: "f7", Name: "total", Type: "numeric"},
+			}},
+			{ID: "t3", Name: "sessions", Fields: []schema.Field{{ID: "f8", Name: "id", Type: "integer"}}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t2", SourceFieldID: "f5", TargetTableID: "t1", TargetFieldID: "f1"},
+		},
+	}
+	r := CompareCatalog(cat, db, "postgres")
+	if !r.HasDrift() {
+		t.Fatal("expected drift")
+	}
+	if r.Source != "postgres" {
+		t.Errorf("source = %q", r.Source)
+	}
+	if len(r.MissingTables) != 1 || r.MissingTables[0] != "audit" {
+		t.Errorf("missing tables = %v", r.MissingTables)
+	}
+	if len(r.ExtraTables) != 1 || r.ExtraTables[0] != "sessions" {
+		t.Errorf("extra tables = %v", r.ExtraTables)
+	}
+	if len(r.Tables) != 2 {
+		t.Fatalf("expected 2 drifted tables, got %+v", r.Tables)
+	}
+	users := r.Tables[0]
+	if users.Table != "users" || !users.PrimaryKeyMismatch || len(users.CatalogPrimaryKey) != 1 || len(users.DatabasePrimaryKey) != 0 {
+		t.Errorf("users pk drift = %+v", users)
+	}
+	if len(users.ExtraColumns) != 1 || users.ExtraColumns[0] != "created_at" {
+		t.Errorf("users extra columns = %v", users.ExtraColumns)
+	}
+	if len(users.Columns) != 2 {
+		t.Fatalf("users column drift = %+v", users.Columns)
+	}
+	email := users.Columns[0]
+	if email.Column != "email" || !email.LengthMismatch || !email.NullableMismatch || email.TypeMismatch {
+		t.Errorf("email drift = %+v", email)
+	}
+	meta := users.Columns[1]
+	if meta.Column != "meta" || !meta.TypeMismatch || meta.CatalogType != "jsonb" {
+		t.Errorf("meta drift = %+v", meta)
+	}
+	orders := r.Tables[1]
+	if len(orders.ExtraColumns) != 1 || orders.ExtraColumns[0] != "total" || orders.PrimaryKeyMismatch {
+		t.Errorf("orders drift = %+v", orders)
+	}
+	if len(r.MissingForeignKeys) != 1 || r.MissingForeignKeys[0].Table != "orders" {
+		t.Errorf("missing fks = %+v", r.MissingForeignKeys)
+	}
+	if len(r.ExtraForeignKeys) != 1 || r.ExtraForeignKeys[0].Table != "users" {
+		t.Errorf("extra fks = %+v", r.ExtraForeignKeys)
+	}
+}
+
+func TestCompareCatalog_SameNameInTwoSchemas(t *testing.T) {
+	orders := func(schemaName, prefix string, extra ...schema.Field) schema.Table {
+		return schema.Table{ID: prefix, Schema: schemaName, Name: "orders", Fields: append([]schema.Field{
+			{ID: prefix + "f1", Name: "id", Type: "integer", PrimaryKey: true},
+		}, extra...)}
+	}
+	cat := schema.Diagram{Tables: []schema.Table{
+		orders("sales", "s", schema.Field{ID: "sf2", Name: "total", Type: "numeric"}),
+		orders("hr", "h"),
+	}}
+	db := schema.TableCatalog{Tables: []schema.Table{
+		orders("hr", "t1"),
+		orders("sales", "t2", schema.Field{ID: "t2f2", Name: "total", Type: "numeric"}),
+	}}
+	if r := CompareCatalog(cat, db, "postgres"); r.HasDrift() {
+		t.Errorf("expected no drift, got %+v", r)
+	}
+
+	// hr.orders lacks the column sales.orders has: only sales.orders drifts.
+	db.Tables[1].Fields = db.Tables[1].Fields[:1]
+	r := CompareCatalog(cat, db, "postgres")
+	if len(r.Tables) != 1 || r.Tables[0].Table != "sales.orders" || len(r.Tables[0].MissingColumns) != 1 {
+		t.Errorf("tables = %+v", r.Tables)
+	}
+
+	// A database table reported without a schema still matches the only catalog table of its name.
+	cat.Tables = cat.Tables[:1]
+	db.Tables = []schema.Table{orders("", "t1", schema.Field{ID: "t1f2", Name: "total", Type: "numeric"})}
+	if r := CompareCatalog(cat, db, "postgres"); r.HasDrift() {
+		t.Errorf("expected no drift without a database schema, got %+v", r)
+	}
+}
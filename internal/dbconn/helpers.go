@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -32,17 +33,58 @@ type columnInfo struct {
 	DataType     string
 	IsNullable   bool
 	OrdinalPos   int
-	CharMaxLen   *int // from character_maximum_length
-	NumPrecision *int // from numeric_precision
-	NumScale     *int // from numeric_scale
+	CharMaxLen   *int    // from character_maximum_length
+	NumPrecision *int    // from numeric_precision
+	NumScale     *int    // from numeric_scale
+	Default      *string // from column_default
+	Identity     string  // schema.IdentityAlways or schema.IdentityByDefault
+	Unique       bool    // column has a single-column UNIQUE constraint
+	Check        string  // single-column CHECK expression, without the outer parentheses
+	Comment      string
 }
 
-// fkInfo holds a foreign key relationship as read from the database.
+// columnMeta holds column attributes read from a dialect's system catalog, which
+// INFORMATION_SCHEMA does not expose portably. A non-nil Default replaces the
+// INFORMATION_SCHEMA column_default.
+type columnMeta struct {
+	TableName  string
+	ColumnName string
+	Default    *string
+	Identity   string
+	Check      string
+	Comment    string
+}
+
+// fkInfo holds one column pair of a foreign key constraint as read from the database.
+// Rows of the same constraint share SourceTable and ConstraintName and arrive in key
+// order, so a row with a lower Ordinal than the one before it starts a new constraint.
 type fkInfo struct {
-	SourceTable  string
-	SourceColumn string
-	TargetTable  string
-	TargetColumn string
+	ConstraintName string
+	SourceTable    string
+	SourceColumn   string
+	TargetTable    string
+	TargetColumn   string
+	Ordinal        int // position of the column in the key, from 1
+}
+
+// indexInfo holds one key column of a secondary index. Rows of the same index share
+// TableName and IndexName and arrive in key order.
+type indexInfo struct {
+	TableName  string
+	IndexName  string
+	Unique     bool
+	Method     string // lower-case access method, e.g. "gin"; "" for the dialect default
+	Where      string // partial index predicate
+	ColumnName string
+	Expression string // set instead of ColumnName for expression keys
+	Desc       bool
+}
+
+// viewInfo holds a view or materialized view and its query.
+type viewInfo struct {
+	Name         string
+	Definition   string
+	Materialized bool
 }
 
 // pkInfo holds a primary key column reference.
@@ -80,18 +122,18 @@ func listSchemasSQL(db *sql.DB, excludes []string) ([]string, error) {
 	return schemas, rows.Err()
 }
 
-// listTablesSQL queries INFORMATION_SCHEMA.TABLES for base tables in the given schema.
+// listTablesSQL queries INFORMATION_SCHEMA.TABLES for base tables and views in the given schema.
 func listTablesSQL(db *sql.DB, schemaName string) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
 	defer cancel()
 
 	rows, err := db.QueryContext(ctx,
-		"SELECT table_name FROM information_schema.tables WHERE table_schema = $1 AND table_type = 'BASE TABLE' ORDER BY table_name",
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = $1 AND table_type IN ('BASE TABLE', 'VIEW') ORDER BY table_name",
 		schemaName)
 	if err != nil {
 		// Try with ? placeholder for MySQL/MSSQL
 		rows, err = db.QueryContext(ctx,
-			"SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_type = 'BASE TABLE' ORDER BY table_name",
+			"SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_type IN ('BASE TABLE', 'VIEW') ORDER BY table_name",
 			schemaName)
 		if err != nil {
 			return nil, fmt.Errorf("listing tables: %w", err)
@@ -117,7 +159,7 @@ func queryColumnsGeneric(db *sql.DB, schemaName string, tableNames []string, ph
 	defer cancel()
 
 	query := fmt.Sprintf(`SELECT table_name, column_name, data_type, is_nullable, ordinal_position,
-		character_maximum_length, numeric_precision, numeric_scale
+		character_maximum_length, numeric_precision, numeric_scale, column_default
 		FROM information_schema.columns
 		WHERE table_schema = %s`, ph(1))
 
@@ -143,7 +185,7 @@ func queryColumnsGeneric(db *sql.DB, schemaName string, tableNames []string, ph
 		var c columnInfo
 		var nullable string
 		if err := rows.Scan(&c.TableName, &c.ColumnName, &c.DataType, &nullable, &c.OrdinalPos,
-			&c.CharMaxLen, &c.NumPrecision, &c.NumScale); err != nil {
+			&c.CharMaxLen, &c.NumPrecision, &c.NumScale, &c.Default); err != nil {
 			return nil, err
 		}
 		c.IsNullable = strings.EqualFold(nullable, "YES")
@@ -192,8 +234,107 @@ func queryPKsGeneric(db *sql.DB, schemaName string, tableNames []string, ph func
 	return pks, rows.Err()
 }
 
-// buildCatalog assembles a TableCatalog from column, PK, and FK data.
-// dialect identifies the source database ("postgres", "mysql", "mssql", "bigquery").
+// queryUniqueColumnsGeneric returns the columns covered by single-column UNIQUE constraints.
+// Multi-column unique constraints do not make any one column unique and are ignored.
+func queryUniqueColumnsGeneric(db *sql.DB, schemaName string, tableNames []string, ph func(int) string) ([]pkInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT kcu.table_name, MIN(kcu.column_name)
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+		  ON tc.constraint_name = kcu.constraint_name
+		  AND tc.table_schema = kcu.table_schema
+		  AND tc.table_name = kcu.table_name
+		WHERE tc.table_schema = %s
+		  AND tc.constraint_type = 'UNIQUE'`, ph(1))
+
+	args := []interface{}{schemaName}
+	if len(tableNames) > 0 {
+		placeholders := make([]string, len(tableNames))
+		for i := range tableNames {
+			placeholders[i] = ph(i + 2)
+			args = append(args, tableNames[i])
+		}
+		query += fmt.Sprintf(" AND tc.table_name IN (%s)", strings.Join(placeholders, ","))
+	}
+	query += " GROUP BY kcu.table_name, tc.constraint_name HAVING COUNT(*) = 1"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying unique constraints: %w", err)
+	}
+	defer rows.Close()
+
+	var uniques []pkInfo
+	for rows.Next() {
+		var u pkInfo
+		if err := rows.Scan(&u.TableName, &u.ColumnName); err != nil {
+			return nil, err
+		}
+		uniques = append(uniques, u)
+	}
+	return uniques, rows.Err()
+}
+
+// applyColumnMeta copies unique flags and catalog metadata onto the matching columns.
+func applyColumnMeta(columns []columnInfo, uniques []pkInfo, meta []columnMeta) {
+	index := make(map[string]*columnInfo, len(columns))
+	for i := range columns {
+		index[columns[i].TableName+"."+columns[i].ColumnName] = &columns[i]
+	}
+	for _, u := range uniques {
+		if c := index[u.TableName+"."+u.ColumnName]; c != nil {
+			c.Unique = true
+		}
+	}
+	for _, m := range meta {
+		c := index[m.TableName+"."+m.ColumnName]
+		if c == nil {
+			continue
+		}
+		if m.Default != nil {
+			c.Default = m.Default
+		}
+		if m.Identity != "" {
+			c.Identity = m.Identity
+		}
+		if m.Check != "" {
+			c.Check = m.Check
+		}
+		if m.Comment != "" {
+			c.Comment = m.Comment
+		}
+	}
+}
+
+// stripParens removes parentheses enclosing the whole expression, repeatedly:
+// SQL Server reports defaults as "((0))" and PostgreSQL checks as "((qty > 0))".
+func stripParens(expr string) string {
+	expr = strings.TrimSpace(expr)
+	for len(expr) >= 2 && expr[0] == '(' && expr[len(expr)-1] == ')' {
+		depth := 0
+		for i := 0; i < len(expr)-1; i++ {
+			switch expr[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 {
+				// The opening parenthesis closes before the end: "(a) + (b)".
+				return expr
+			}
+		}
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// buildCatalog assembles a TableCatalog from column, PK, and FK data. Relationship
+// cardinalities are inferred from the primary keys and unique columns; indexes are
+// attached later, so a unique index does not make a relationship one-to-one.
+// dialect identifies the source database ("postgres", "mysql", "mssql", "bigquery", "sqlite").
 func buildCatalog(columns []columnInfo, pks []pkInfo, fks []fkInfo, importSource, dialect string) schema.TableCatalog {
 	gen := newIDGen()
 
@@ -264,6 +405,21 @@ func buildCatalog(columns []columnInfo, pks []pkInfo, fks []fkInfo, importSource
 				Length:     fLen,
 				Precision:  fPrec,
 				Scale:      fScale,
+				Identity:   c.Identity,
+				Unique:     c.Unique && !pkSet[tblName+"."+c.ColumnName],
+				Check:      c.Check,
+				Comment:    c.Comment,
+			}
+			if c.Default != nil {
+				f.Default = stripParens(*c.Default)
+			}
+			// PostgreSQL serial columns default to nextval() of an owned sequence.
+			if strings.HasPrefix(strings.ToLower(f.Default), "nextval(") {
+				f.Identity = schema.IdentityByDefault
+				f.Default = ""
+			}
+			if f.Identity != "" {
+				f.Default = ""
 			}
 
 			// Store the original raw data type as a dialect-specific override
@@ -287,23 +443,20 @@ func buildCatalog(columns []columnInfo, pks []pkInfo, fks []fkInfo, importSource
 		})
 	}
 
-	// Build relationships from FK data
+	// Build one relationship per FK constraint
 	var rels []schema.Relationship
-	for _, fk := range fks {
-		srcTID := tableIDMap[fk.TargetTable]
-		srcFID := fieldIDMap[fk.TargetTable+"."+fk.TargetColumn]
-		tgtTID := tableIDMap[fk.SourceTable]
-		tgtFID := fieldIDMap[fk.SourceTable+"."+fk.SourceColumn]
-		if srcTID != "" && srcFID != "" && tgtTID != "" && tgtFID != "" {
-			rels = append(rels, schema.Relationship{
-				ID:            gen.rel(),
-				SourceTableID: srcTID,
-				SourceFieldID: srcFID,
-				TargetTableID: tgtTID,
-				TargetFieldID: tgtFID,
-			})
+	for start := 0; start < len(fks); {
+		end := start + 1
+		for end < len(fks) && sameConstraint(fks[end-1], fks[end]) {
+			end++
+		}
+		if rel, ok := fkRelationship(fks[start:end], tableIDMap, fieldIDMap); ok {
+			rel.ID = gen.rel()
+			rels = append(rels, rel)
 		}
+		start = end
 	}
+	schema.InferCardinalities(tables, rels)
 
 	return schema.TableCatalog{
 		ImportSource:  importSource,
@@ -311,3 +464,179 @@ func buildCatalog(columns []columnInfo, pks []pkInfo, fks []fkInfo, importSource
 		Relationships: rels,
 	}
 }
+
+// sameConstraint reports whether next continues the foreign key constraint of prev.
+func sameConstraint(prev, next fkInfo) bool {
+	return next.SourceTable == prev.SourceTable && next.ConstraintName == prev.ConstraintName &&
+		next.TargetTable == prev.TargetTable && next.Ordinal > prev.Ordinal
+}
+
+// fkRelationship returns the relationship for the rows of one FK constraint, with the
+// referenced (parent) table as the source. A composite key lists all its columns in
+// SourceFieldIDs and TargetFieldIDs. It returns false if a table or column is missing.
+func fkRelationship(cols []fkInfo, tableIDMap, fieldIDMap map[string]string) (schema.Relationship, bool) {
+	fk := cols[0]
+	srcTID, tgtTID := tableIDMap[fk.TargetTable], tableIDMap[fk.SourceTable]
+	if srcTID == "" || tgtTID == "" {
+		return schema.Relationship{}, false
+	}
+	var srcFIDs, tgtFIDs []string
+	for _, c := range cols {
+		srcFID := fieldIDMap[c.TargetTable+"."+c.TargetColumn]
+		tgtFID := fieldIDMap[c.SourceTable+"."+c.SourceColumn]
+		if srcFID == "" || tgtFID == "" {
+			return schema.Relationship{}, false
+		}
+		srcFIDs = append(srcFIDs, srcFID)
+		tgtFIDs = append(tgtFIDs, tgtFID)
+	}
+	rel := schema.Relationship{
+		SourceTableID: srcTID,
+		SourceFieldID: srcFIDs[0],
+		TargetTableID: tgtTID,
+		TargetFieldID: tgtFIDs[0],
+		Name:          fk.ConstraintName,
+	}
+	if len(cols) > 1 {
+		rel.SourceFieldIDs, rel.TargetFieldIDs = srcFIDs, tgtFIDs
+	}
+	return rel, true
+}
+
+// attachIndexes adds the introspected indexes to the catalog's tables. A unique index
+// on a single column that is already marked Unique is the column's UNIQUE constraint and
+// is skipped, as are indexes with a key the dialect query could not describe.
+func attachIndexes(catalog schema.TableCatalog, indexes []indexInfo) schema.TableCatalog {
+	type tableIndex struct {
+		table int
+		index schema.Index
+		bad   bool
+	}
+	tableIdx := make(map[string]int)
+	fields := make(map[string]schema.Field) // tableName.columnName -> field
+	constrained := make(map[string]bool)    // field ID -> already unique on its own
+	for i, t := range catalog.Tables {
+		tableIdx[t.Name] = i
+		for _, f := range t.Fields {
+			fields[t.Name+"."+f.Name] = f
+			constrained[f.ID] = f.Unique || f.PrimaryKey
+		}
+	}
+
+	var order []string
+	byKey := make(map[string]*tableIndex)
+	for _, ix := range indexes {
+		ti, ok := tableIdx[ix.TableName]
+		if !ok {
+			continue
+		}
+		key := ix.TableName + "." + ix.IndexName
+		entry := byKey[key]
+		if entry == nil {
+			entry = &tableIndex{table: ti, index: schema.Index{
+				Name:   ix.IndexName,
+				Unique: ix.Unique,
+				Where:  ix.Where,
+				Method: ix.Method,
+			}}
+			byKey[key] = entry
+			order = append(order, key)
+		}
+		col := schema.IndexColumn{Expression: ix.Expression, Desc: ix.Desc}
+		if ix.Expression == "" {
+			f, ok := fields[ix.TableName+"."+ix.ColumnName]
+			if !ok {
+				entry.bad = true
+				continue
+			}
+			col.FieldID = f.ID
+		}
+		entry.index.Columns = append(entry.index.Columns, col)
+	}
+
+	for _, key := range order {
+		entry := byKey[key]
+		if entry.bad || len(entry.index.Columns) == 0 {
+			continue
+		}
+		ix := entry.index
+		if ix.Unique && ix.Where == "" && len(ix.Columns) == 1 && constrained[ix.Columns[0].FieldID] {
+			continue
+		}
+		t := &catalog.Tables[entry.table]
+		t.Indexes = append(t.Indexes, ix)
+	}
+	return catalog
+}
+
+// describeTables sets the schema of every table in the catalog and the description of
+// those with an entry in descriptions (keyed by table name).
+func describeTables(catalog schema.TableCatalog, schemaName string, descriptions map[string]string) schema.TableCatalog {
+	for i := range catalog.Tables {
+		t := &catalog.Tables[i]
+		t.Schema = schemaName
+		t.Description = descriptions[t.Name]
+	}
+	return catalog
+}
+
+// scanTableComments reads (table name, comment) rows into a map, skipping empty comments.
+func scanTableComments(rows *sql.Rows) (map[string]string, error) {
+	defer rows.Close()
+	comments := make(map[string]string)
+	for rows.Next() {
+		var name, comment string
+		if err := rows.Scan(&name, &comment); err != nil {
+			return nil, err
+		}
+		if comment != "" {
+			comments[name] = comment
+		}
+	}
+	return comments, rows.Err()
+}
+
+// markViews sets the kind and definition of the catalog tables that are views.
+func markViews(catalog schema.TableCatalog, views []viewInfo) schema.TableCatalog {
+	byName := make(map[string]viewInfo, len(views))
+	for _, v := range views {
+		byName[v.Name] = v
+	}
+	for i := range catalog.Tables {
+		t := &catalog.Tables[i]
+		v, ok := byName[t.Name]
+		if !ok {
+			continue
+		}
+		t.Kind = schema.KindView
+		if v.Materialized {
+			t.Kind = schema.KindMaterializedView
+		}
+		t.Definition = viewQuery(v.Definition)
+	}
+	return catalog
+}
+
+// viewHeader matches the CREATE VIEW ... AS prefix that SQL Server keeps in a view's definition.
+var viewHeader = regexp.MustCompile(`(?is)^\s*create\s+(or\s+alter\s+)?view\s+.*?\bas\b`)
+
+// viewQuery returns a view's query without a CREATE VIEW ... AS prefix, surrounding
+// whitespace or a trailing semicolon.
+func viewQuery(def string) string {
+	def = viewHeader.ReplaceAllString(def, "")
+	return strings.TrimRight(strings.TrimSpace(def), "; \t\r\n")
+}
+
+// scanViews reads (name, definition) rows of non-materialized views.
+func scanViews(rows *sql.Rows) ([]viewInfo, error) {
+	defer rows.Close()
+	var views []viewInfo
+	for rows.Next() {
+		var v viewInfo
+		if err := rows.Scan(&v.Name, &v.Definition); err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
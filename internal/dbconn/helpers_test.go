@@ -163,6 +163,138 @@ func TestBuildCatalog_FKUnresolvedIgnored(t *testing.T) {
 	}
 }
 
+func TestBuildCatalog_ColumnMetadata(t *testing.T) {
+	seq := "nextval('orders_id_seq'::regclass)"
+	qty := "((1))"
+	status := "'new'::character varying"
+	columns := []columnInfo{
+		{TableName: "orders", ColumnName: "id", DataType: "integer", OrdinalPos: 1, Default: &seq},
+		{TableName: "orders", ColumnName: "qty", DataType: "integer", OrdinalPos: 2, Default: &qty},
+		{TableName: "orders", ColumnName: "status", DataType: "varchar", OrdinalPos: 3, Default: &status},
+		{TableName: "orders", ColumnName: "code", DataType: "varchar", OrdinalPos: 4},
+	}
+	uniques := []pkInfo{{TableName: "orders", ColumnName: "code"}, {TableName: "orders", ColumnName: "id"}}
+	meta := []columnMeta{
+		{TableName: "orders", ColumnName: "qty", Check: "(qty > 0)"},
+		{TableName: "orders", ColumnName: "code", Comment: "External reference"},
+		{TableName: "missing", ColumnName: "x", Comment: "ignored"},
+	}
+	applyColumnMeta(columns, uniques, meta)
+	pks := []pkInfo{{TableName: "orders", ColumnName: "id"}}
+
+	fields := buildCatalog(columns, pks, nil, "meta", "postgres").Tables[0].Fields
+	if fields[0].Identity != "by_default" || fields[0].Default != "" || fields[0].Unique {
+		t.Errorf("serial id: got identity %q, default %q, unique %v", fields[0].Identity, fields[0].Default, fields[0].Unique)
+	}
+	if fields[1].Default != "1" || fields[1].Check != "(qty > 0)" {
+		t.Errorf("qty: got default %q, check %q", fields[1].Default, fields[1].Check)
+	}
+	if fields[2].Default != status {
+		t.Errorf("status: got default %q", fields[2].Default)
+	}
+	if !fields[3].Unique || fields[3].Comment != "External reference" {
+		t.Errorf("code: got unique %v, comment %q", fields[3].Unique, fields[3].Comment)
+	}
+}
+
+func TestAttachIndexes(t *testing.T) {
+	columns := []columnInfo{
+		{TableName: "users", ColumnName: "id", DataType: "integer", OrdinalPos: 1},
+		{TableName: "users", ColumnName: "email", DataType: "text", OrdinalPos: 2, Unique: true},
+		{TableName: "users", ColumnName: "org_id", DataType: "integer", OrdinalPos: 3},
+		{TableName: "users", ColumnName: "created_at", DataType: "timestamp", OrdinalPos: 4},
+	}
+	pks := []pkInfo{{TableName: "users", ColumnName: "id"}}
+	indexes := []indexInfo{
+		{TableName: "users", IndexName: "users_email_key", Unique: true, ColumnName: "email"},
+		{TableName: "users", IndexName: "users_org_created_idx", ColumnName: "org_id"},
+		{TableName: "users", IndexName: "users_org_created_idx", ColumnName: "created_at", Desc: true},
+		{TableName: "users", IndexName: "users_lower_email_idx", Unique: true, Expression: "lower(email)", Where: "org_id IS NOT NULL"},
+		{TableName: "users", IndexName: "users_func_idx", ColumnName: ""},
+		{TableName: "missing", IndexName: "missing_idx", ColumnName: "x"},
+	}
+
+	catalog := attachIndexes(buildCatalog(columns, pks, nil, "idx", "postgres"), indexes)
+	got := catalog.Tables[0].Indexes
+	if len(got) != 2 {
+		t.Fatalf("expected 2 indexes, got %d: %+v", len(got), got)
+	}
+	if got[0].Name != "users_org_created_idx" || len(got[0].Columns) != 2 ||
+		got[0].Columns[0].FieldID != "f3" || got[0].Columns[1].FieldID != "f4" || !got[0].Columns[1].Desc {
+		t.Errorf("composite index: got %+v", got[0])
+	}
+	if !got[1].Unique || got[1].Columns[0].Expression != "lower(email)" || got[1].Where != "org_id IS NOT NULL" {
+		t.Errorf("expression index: got %+v", got[1])
+	}
+}
+
+func TestMarkViews(t *testing.T) {
+	columns := []columnInfo{
+		{TableName: "orders", ColumnName: "id", DataType: "integer", OrdinalPos: 1},
+		{TableName: "order_totals", ColumnName: "total", DataType: "numeric", OrdinalPos: 1},
+		{TableName: "recent_orders", ColumnName: "id", DataType: "integer", OrdinalPos: 1},
+	}
+	views := []viewInfo{
+		{Name: "order_totals", Definition: " SELECT sum(total) AS total\n   FROM orders;", Materialized: true},
+		{Name: "recent_orders", Definition: "CREATE VIEW [dbo].[recent_orders]\nAS\nSELECT id FROM dbo.orders"},
+		{Name: "not_selected", Definition: "SELECT 1"},
+	}
+
+	catalog := markViews(buildCatalog(columns, nil, nil, "views", "postgres"), views)
+	byName := make(map[string]int)
+	for i, tbl := range catalog.Tables {
+		byName[tbl.Name] = i
+	}
+	if tbl := catalog.Tables[byName["orders"]]; tbl.Kind != "" || tbl.Definition != "" {
+		t.Errorf("orders: kind %q, definition %q", tbl.Kind, tbl.Definition)
+	}
+	if tbl := catalog.Tables[byName["order_totals"]]; tbl.Kind != "materialized_view" || tbl.Definition != "SELECT sum(total) AS total\n   FROM orders" {
+		t.Errorf("order_totals: kind %q, definition %q", tbl.Kind, tbl.Definition)
+	}
+	if tbl := catalog.Tables[byName["recent_orders"]]; tbl.Kind != "view" || tbl.Definition != "SELECT id FROM dbo.orders" {
+		t.Errorf("recent_orders: kind %q, definition %q", tbl.Kind, tbl.Definition)
+	}
+	if len(catalog.Tables) != 3 {
+		t.Errorf("expected 3 tables, got %d", len(catalog.Tables))
+	}
+}
+
+func TestStripParens(t *testing.T) {
+	tests := map[string]string{
+		"((0))":              "0",
+		"(getdate())":        "getdate()",
+		"([qty]>(0))":        "[qty]>(0)",
+		"(a) + (b)":          "(a) + (b)",
+		"'x'":                "'x'",
+		" ( (price >= 0) ) ": "price >= 0",
+	}
+	for in, want := range tests {
+		if got := stripParens(in); got != want {
+			t.Errorf("stripParens(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMySQLDefaultExpr(t *testing.T) {
+	tests := []struct {
+		def, dataType string
+		generated     bool
+		want          string
+	}{
+		{"draft", "varchar", false, "'draft'"},
+		{"it's", "varchar", false, "'it''s'"},
+		{"0", "int", false, "0"},
+		{"1.50", "decimal", false, "1.50"},
+		{"42", "varchar", false, "'42'"},
+		{"CURRENT_TIMESTAMP", "timestamp", true, "CURRENT_TIMESTAMP"},
+	}
+	for _, tt := range tests {
+		if got := mysqlDefaultExpr(tt.def, tt.dataType, tt.generated); got != tt.want {
+			t.Errorf("mysqlDefaultExpr(%q, %q, %v) = %q, want %q", tt.def, tt.dataType, tt.generated, got, tt.want)
+		}
+	}
+}
+
 func TestIDGen(t *testing.T) {
 	gen := newIDGen()
 
@@ -197,3 +329,49 @@ func TestPlaceholderFunctions(t *testing.T) {
 		t.Errorf("mssqlPlaceholder(3) = %q, want @p3", got)
 	}
 }
+
+func TestBuildCatalog_CompositeForeignKeys(t *testing.T) {
+	columns := []columnInfo{
+		{TableName: "orders", ColumnName: "region", DataType: "text", OrdinalPos: 1},
+		{TableName: "orders", ColumnName: "order_no", DataType: "int", OrdinalPos: 2},
+		{TableName: "lines", ColumnName: "region", DataType: "text", OrdinalPos: 1},
+		{TableName: "lines", ColumnName: "order_no", DataType: "int", OrdinalPos: 2},
+		{TableName: "lines", ColumnName: "parent_region", DataType: "text", OrdinalPos: 3},
+		{TableName: "lines", ColumnName: "parent_no", DataType: "int", OrdinalPos: 4},
+	}
+	pks := []pkInfo{{TableName: "orders", ColumnName: "region"}, {TableName: "orders", ColumnName: "order_no"}}
+	fks := []fkInfo{
+		{ConstraintName: "lines_order_fkey", SourceTable: "lines", SourceColumn: "region", TargetTable: "orders", TargetColumn: "region", Ordinal: 1},
+		{ConstraintName: "lines_order_fkey", SourceTable: "lines", SourceColumn: "order_no", TargetTable: "orders", TargetColumn: "order_no", Ordinal: 2},
+		// Unnamed constraints (SQLite) are told apart by the ordinal starting over.
+		{SourceTable: "lines", SourceColumn: "parent_region", TargetTable: "orders", TargetColumn: "region", Ordinal: 1},
+		{SourceTable: "lines", SourceColumn: "parent_no", TargetTable: "orders", TargetColumn: "order_no", Ordinal: 2},
+		{SourceTable: "lines", SourceColumn: "region", TargetTable: "orders", TargetColumn: "region", Ordinal: 1},
+		// A constraint with a missing column is dropped as a whole.
+		{ConstraintName: "lines_gone_fkey", SourceTable: "lines", SourceColumn: "region", TargetTable: "orders", TargetColumn: "region", Ordinal: 1},
+		{ConstraintName: "lines_gone_fkey", SourceTable: "lines", SourceColumn: "gone", TargetTable: "orders", TargetColumn: "order_no", Ordinal: 2},
+	}
+	catalog := buildCatalog(columns, pks, fks, "composite", "postgres")
+	lines, orders := catalog.Tables[0], catalog.Tables[1]
+
+	if len(catalog.Relationships) != 3 {
+		t.Fatalf("expected 3 relationships, got %+v", catalog.Relationships)
+	}
+	named := catalog.Relationships[0]
+	if named.Name != "lines_order_fkey" || named.Cardinality != "1-to-0/many" || named.SourceTableID != orders.ID || named.TargetTableID != lines.ID ||
+		named.SourceFieldID != orders.Fields[0].ID || named.TargetFieldID != lines.Fields[0].ID {
+		t.Errorf("named = %+v", named)
+	}
+	if len(named.SourceFieldIDs) != 2 || named.SourceFieldIDs[1] != orders.Fields[1].ID ||
+		len(named.TargetFieldIDs) != 2 || named.TargetFieldIDs[1] != lines.Fields[1].ID {
+		t.Errorf("named field pairs = %v -> %v", named.SourceFieldIDs, named.TargetFieldIDs)
+	}
+	parent := catalog.Relationships[1]
+	if parent.Name != "" || len(parent.TargetFieldIDs) != 2 || parent.TargetFieldIDs[0] != lines.Fields[2].ID || parent.TargetFieldIDs[1] != lines.Fields[3].ID {
+		t.Errorf("parent = %+v", parent)
+	}
+	single := catalog.Relationships[2]
+	if single.SourceFieldIDs != nil || single.TargetFieldID != lines.Fields[0].ID {
+		t.Errorf("single = %+v", single)
+	}
+}
@@ -66,7 +66,7 @@ func (m *MSSQLInspector) ListTables(schemaName string) ([]string, error) {
 	defer cancel()
 
 	rows, err := m.db.QueryContext(ctx,
-		"SELECT table_name FROM information_schema.tables WHERE table_schema = @p1 AND table_type = 'BASE TABLE' ORDER BY table_name",
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = @p1 AND table_type IN ('BASE TABLE', 'VIEW') ORDER BY table_name",
 		schemaName)
 	if err != nil {
 		return nil, fmt.Errorf("listing tables: %w", err)
@@ -102,19 +102,79 @@ func (m *MSSQLInspector) InspectSchema(schemaName string, tableNames []string) (
 	if err != nil {
 		return schema.TableCatalog{}, err
 	}
-	return buildCatalog(columns, pks, fks, fmt.Sprintf("%s (SQL Server)", schemaName), "mssql"), nil
+	uniques, err := queryUniqueColumnsGeneric(m.db, schemaName, tableNames, mssqlPlaceholder)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	meta, err := m.queryColumnMeta(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	indexes, err := m.queryIndexes(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	comments, err := m.queryTableComments(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	views, err := m.queryViews(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	applyColumnMeta(columns, uniques, meta)
+	catalog := buildCatalog(columns, pks, fks, fmt.Sprintf("%s (SQL Server)", schemaName), "mssql")
+	return describeTables(markViews(attachIndexes(catalog, indexes), views), schemaName, comments), nil
+}
+
+// queryTableComments reads table- and view-level MS_Description extended properties.
+func (m *MSSQLInspector) queryTableComments(schemaName string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `SELECT t.name, CAST(ep.value AS nvarchar(max))
+	FROM sys.objects t
+	JOIN sys.schemas s ON s.schema_id = t.schema_id
+	JOIN sys.extended_properties ep
+		ON ep.class = 1 AND ep.major_id = t.object_id AND ep.minor_id = 0
+		AND ep.name = 'MS_Description'
+	WHERE s.name = @p1
+		AND t.type IN ('U', 'V')`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying mssql table comments: %w", err)
+	}
+	return scanTableComments(rows)
+}
+
+// queryViews reads view definitions from sys.sql_modules.
+func (m *MSSQLInspector) queryViews(schemaName string) ([]viewInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `SELECT v.name, COALESCE(sm.definition, '')
+	FROM sys.views v
+	JOIN sys.schemas s ON s.schema_id = v.schema_id
+	JOIN sys.sql_modules sm ON sm.object_id = v.object_id
+	WHERE s.name = @p1`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying mssql views: %w", err)
+	}
+	return scanViews(rows)
 }
 
-// queryForeignKeys retrieves FK relationships for SQL Server using referential_constraints + key_column_usage.
+// queryForeignKeys retrieves FK relationships for SQL Server using referential_constraints +
+// key_column_usage, in key order.
 func (m *MSSQLInspector) queryForeignKeys(schemaName string, tableNames []string) ([]fkInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
 	defer cancel()
 
 	query := `SELECT
+		rc.CONSTRAINT_NAME AS constraint_name,
 		fk_kcu.TABLE_NAME AS source_table,
 		fk_kcu.COLUMN_NAME AS source_column,
 		pk_kcu.TABLE_NAME AS target_table,
-		pk_kcu.COLUMN_NAME AS target_column
+		pk_kcu.COLUMN_NAME AS target_column,
+		fk_kcu.ORDINAL_POSITION AS ordinal
 	FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
 	JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE fk_kcu
 		ON rc.CONSTRAINT_NAME = fk_kcu.CONSTRAINT_NAME
@@ -134,6 +194,7 @@ func (m *MSSQLInspector) queryForeignKeys(schemaName string, tableNames []string
 		}
 		query += fmt.Sprintf(" AND fk_kcu.TABLE_NAME IN (%s)", strings.Join(placeholders, ","))
 	}
+	query += " ORDER BY fk_kcu.TABLE_NAME, rc.CONSTRAINT_NAME, fk_kcu.ORDINAL_POSITION"
 
 	rows, err := m.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -144,10 +205,91 @@ func (m *MSSQLInspector) queryForeignKeys(schemaName string, tableNames []string
 	var fks []fkInfo
 	for rows.Next() {
 		var fk fkInfo
-		if err := rows.Scan(&fk.SourceTable, &fk.SourceColumn, &fk.TargetTable, &fk.TargetColumn); err != nil {
+		if err := rows.Scan(&fk.ConstraintName, &fk.SourceTable, &fk.SourceColumn, &fk.TargetTable, &fk.TargetColumn, &fk.Ordinal); err != nil {
 			return nil, err
 		}
 		fks = append(fks, fk)
 	}
 	return fks, rows.Err()
 }
+
+// queryColumnMeta reads IDENTITY columns, MS_Description extended properties and
+// column-level CHECK constraints from the sys catalog views.
+func (m *MSSQLInspector) queryColumnMeta(schemaName string) ([]columnMeta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `SELECT t.name, c.name, c.is_identity,
+		COALESCE(CAST(ep.value AS nvarchar(max)), ''), COALESCE(cc.definition, '')
+	FROM sys.columns c
+	JOIN sys.tables t ON t.object_id = c.object_id
+	JOIN sys.schemas s ON s.schema_id = t.schema_id
+	LEFT JOIN sys.extended_properties ep
+		ON ep.class = 1 AND ep.major_id = c.object_id AND ep.minor_id = c.column_id
+		AND ep.name = 'MS_Description'
+	LEFT JOIN sys.check_constraints cc
+		ON cc.parent_object_id = c.object_id AND cc.parent_column_id = c.column_id
+	WHERE s.name = @p1`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying mssql column metadata: %w", err)
+	}
+	defer rows.Close()
+
+	var meta []columnMeta
+	for rows.Next() {
+		var c columnMeta
+		var identity bool
+		var check string
+		if err := rows.Scan(&c.TableName, &c.ColumnName, &identity, &c.Comment, &check); err != nil {
+			return nil, err
+		}
+		if identity {
+			c.Identity = schema.IdentityAlways
+		}
+		c.Check = stripParens(check)
+		meta = append(meta, c)
+	}
+	return meta, rows.Err()
+}
+
+// queryIndexes reads clustered and nonclustered rowstore indexes from sys.indexes.
+// Included columns (key_ordinal 0) are not keys and are skipped.
+func (m *MSSQLInspector) queryIndexes(schemaName string) ([]indexInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `SELECT t.name, i.name, i.is_unique, i.type_desc,
+		COALESCE(i.filter_definition, ''), c.name, ic.is_descending_key
+	FROM sys.indexes i
+	JOIN sys.tables t ON t.object_id = i.object_id
+	JOIN sys.schemas s ON s.schema_id = t.schema_id
+	JOIN sys.index_columns ic
+		ON ic.object_id = i.object_id AND ic.index_id = i.index_id AND ic.key_ordinal > 0
+	JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+	WHERE s.name = @p1
+		AND i.is_primary_key = 0
+		AND i.is_hypothetical = 0
+		AND i.type IN (1, 2)
+	ORDER BY t.name, i.name, ic.key_ordinal`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying mssql indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []indexInfo
+	for rows.Next() {
+		var ix indexInfo
+		if err := rows.Scan(&ix.TableName, &ix.IndexName, &ix.Unique, &ix.Method, &ix.Where,
+			&ix.ColumnName, &ix.Desc); err != nil {
+			return nil, err
+		}
+		// NONCLUSTERED is the CREATE INDEX default.
+		ix.Method = strings.ToLower(ix.Method)
+		if ix.Method == "nonclustered" {
+			ix.Method = ""
+		}
+		ix.Where = stripParens(ix.Where)
+		indexes = append(indexes, ix)
+	}
+	return indexes, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -66,7 +67,7 @@ func (m *MySQLInspector) ListTables(schemaName string) ([]string, error) {
 	defer cancel()
 
 	rows, err := m.db.QueryContext(ctx,
-		"SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_type = 'BASE TABLE' ORDER BY table_name",
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_type IN ('BASE TABLE', 'VIEW') ORDER BY table_name",
 		schemaName)
 	if err != nil {
 		return nil, fmt.Errorf("listing tables: %w", err)
@@ -102,19 +103,73 @@ func (m *MySQLInspector) InspectSchema(schemaName string, tableNames []string) (
 	if err != nil {
 		return schema.TableCatalog{}, err
 	}
-	return buildCatalog(columns, pks, fks, fmt.Sprintf("%s (MySQL)", schemaName), "mysql"), nil
+	uniques, err := queryUniqueColumnsGeneric(m.db, schemaName, tableNames, mysqlPlaceholder)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	meta, err := m.queryColumnMeta(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	indexes, err := m.queryIndexes(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	comments, err := m.queryTableComments(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	views, err := m.queryViews(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	applyColumnMeta(columns, uniques, meta)
+	catalog := buildCatalog(columns, pks, fks, fmt.Sprintf("%s (MySQL)", schemaName), "mysql")
+	return describeTables(markViews(attachIndexes(catalog, indexes), views), schemaName, comments), nil
+}
+
+// queryTableComments reads table comments from information_schema.TABLES.
+func (m *MySQLInspector) queryTableComments(schemaName string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `SELECT TABLE_NAME, COALESCE(TABLE_COMMENT, '')
+	FROM information_schema.TABLES
+	WHERE TABLE_SCHEMA = ?
+		AND TABLE_TYPE = 'BASE TABLE'`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying mysql table comments: %w", err)
+	}
+	return scanTableComments(rows)
+}
+
+// queryViews reads view definitions from information_schema.VIEWS.
+func (m *MySQLInspector) queryViews(schemaName string) ([]viewInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `SELECT TABLE_NAME, COALESCE(VIEW_DEFINITION, '')
+	FROM information_schema.VIEWS
+	WHERE TABLE_SCHEMA = ?`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying mysql views: %w", err)
+	}
+	return scanViews(rows)
 }
 
-// queryForeignKeys retrieves FK relationships for MySQL using REFERENCED_TABLE_NAME/COLUMN_NAME.
+// queryForeignKeys retrieves FK relationships for MySQL using REFERENCED_TABLE_NAME/COLUMN_NAME,
+// in key order.
 func (m *MySQLInspector) queryForeignKeys(schemaName string, tableNames []string) ([]fkInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
 	defer cancel()
 
 	query := `SELECT
+		kcu.CONSTRAINT_NAME AS constraint_name,
 		kcu.TABLE_NAME AS source_table,
 		kcu.COLUMN_NAME AS source_column,
 		kcu.REFERENCED_TABLE_NAME AS target_table,
-		kcu.REFERENCED_COLUMN_NAME AS target_column
+		kcu.REFERENCED_COLUMN_NAME AS target_column,
+		kcu.ORDINAL_POSITION AS ordinal
 	FROM information_schema.KEY_COLUMN_USAGE kcu
 	WHERE kcu.TABLE_SCHEMA = ?
 		AND kcu.REFERENCED_TABLE_NAME IS NOT NULL`
@@ -128,6 +183,7 @@ func (m *MySQLInspector) queryForeignKeys(schemaName string, tableNames []string
 		}
 		query += fmt.Sprintf(" AND kcu.TABLE_NAME IN (%s)", strings.Join(placeholders, ","))
 	}
+	query += " ORDER BY kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION"
 
 	rows, err := m.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -138,10 +194,99 @@ func (m *MySQLInspector) queryForeignKeys(schemaName string, tableNames []string
 	var fks []fkInfo
 	for rows.Next() {
 		var fk fkInfo
-		if err := rows.Scan(&fk.SourceTable, &fk.SourceColumn, &fk.TargetTable, &fk.TargetColumn); err != nil {
+		if err := rows.Scan(&fk.ConstraintName, &fk.SourceTable, &fk.SourceColumn, &fk.TargetTable, &fk.TargetColumn, &fk.Ordinal); err != nil {
 			return nil, err
 		}
 		fks = append(fks, fk)
 	}
 	return fks, rows.Err()
 }
+
+// queryColumnMeta reads AUTO_INCREMENT and column comments from information_schema.COLUMNS
+// and rewrites literal defaults as SQL expressions: MySQL reports the string default
+// 'draft' as draft, while expression defaults are flagged DEFAULT_GENERATED in EXTRA.
+// MySQL does not record which column a CHECK constraint belongs to, so checks are not read.
+func (m *MySQLInspector) queryColumnMeta(schemaName string) ([]columnMeta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `SELECT TABLE_NAME, COLUMN_NAME, COLUMN_DEFAULT, DATA_TYPE, EXTRA, COLUMN_COMMENT
+	FROM information_schema.COLUMNS
+	WHERE TABLE_SCHEMA = ?`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying mysql column metadata: %w", err)
+	}
+	defer rows.Close()
+
+	var meta []columnMeta
+	for rows.Next() {
+		var c columnMeta
+		var def *string
+		var dataType, extra string
+		if err := rows.Scan(&c.TableName, &c.ColumnName, &def, &dataType, &extra, &c.Comment); err != nil {
+			return nil, err
+		}
+		extra = strings.ToLower(extra)
+		if strings.Contains(extra, "auto_increment") {
+			c.Identity = schema.IdentityByDefault
+		}
+		if def != nil {
+			expr := mysqlDefaultExpr(*def, dataType, strings.Contains(extra, "default_generated"))
+			c.Default = &expr
+		}
+		meta = append(meta, c)
+	}
+	return meta, rows.Err()
+}
+
+// mysqlDefaultExpr turns an information_schema COLUMN_DEFAULT value into a SQL expression.
+func mysqlDefaultExpr(def, dataType string, generated bool) string {
+	if generated || strings.EqualFold(def, "NULL") {
+		return def
+	}
+	if _, err := strconv.ParseFloat(def, 64); err == nil {
+		switch strings.ToLower(dataType) {
+		case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		default:
+			return def
+		}
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(def) + "'"
+}
+
+// queryIndexes reads secondary indexes from information_schema.STATISTICS. Functional
+// key parts have no COLUMN_NAME, and EXPRESSION is not available on every server
+// version, so indexes containing one are left out by attachIndexes.
+func (m *MySQLInspector) queryIndexes(schemaName string) ([]indexInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := m.db.QueryContext(ctx, `SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE, INDEX_TYPE,
+		COALESCE(COLUMN_NAME, ''), COALESCE(COLLATION, 'A')
+	FROM information_schema.STATISTICS
+	WHERE TABLE_SCHEMA = ?
+		AND INDEX_NAME <> 'PRIMARY'
+	ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying mysql indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []indexInfo
+	for rows.Next() {
+		var ix indexInfo
+		var nonUnique int
+		var collation string
+		if err := rows.Scan(&ix.TableName, &ix.IndexName, &nonUnique, &ix.Method, &ix.ColumnName, &collation); err != nil {
+			return nil, err
+		}
+		ix.Unique = nonUnique == 0
+		ix.Method = strings.ToLower(ix.Method)
+		if ix.Method == "btree" {
+			ix.Method = ""
+		}
+		ix.Desc = collation == "D"
+		indexes = append(indexes, ix)
+	}
+	return indexes, rows.Err()
+}
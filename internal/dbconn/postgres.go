@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -60,8 +61,23 @@ func (p *PostgresInspector) ListSchemas() ([]string, error) {
 	return listSchemasSQL(p.db, pgSystemSchemas)
 }
 
+// ListTables returns the tables, views and materialized views in the schema.
 func (p *PostgresInspector) ListTables(schemaName string) ([]string, error) {
-	return listTablesSQL(p.db, schemaName)
+	tables, err := listTablesSQL(p.db, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	views, err := p.queryViews(schemaName)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		if v.Materialized {
+			tables = append(tables, v.Name)
+		}
+	}
+	sort.Strings(tables)
+	return tables, nil
 }
 
 // pgPlaceholder returns $1, $2, ... style placeholders for PostgreSQL.
@@ -82,28 +98,141 @@ func (p *PostgresInspector) InspectSchema(schemaName string, tableNames []string
 	if err != nil {
 		return schema.TableCatalog{}, err
 	}
-	return buildCatalog(columns, pks, fks, fmt.Sprintf("%s (PostgreSQL)", schemaName), "postgres"), nil
+	uniques, err := queryUniqueColumnsGeneric(p.db, schemaName, tableNames, pgPlaceholder)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	meta, err := p.queryColumnMeta(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	indexes, err := p.queryIndexes(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	comments, err := p.queryTableComments(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	views, err := p.queryViews(schemaName)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	// Materialized views are missing from information_schema.columns.
+	matviewColumns, err := p.queryMatviewColumns(schemaName, tableNames)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	columns = append(columns, matviewColumns...)
+	applyColumnMeta(columns, uniques, meta)
+	catalog := buildCatalog(columns, pks, fks, fmt.Sprintf("%s (PostgreSQL)", schemaName), "postgres")
+	catalog = markViews(attachIndexes(catalog, indexes), views)
+	return describeTables(catalog, schemaName, comments), nil
 }
 
-// queryForeignKeys retrieves FK relationships for PostgreSQL using constraint_column_usage.
+// queryViews reads view and materialized view definitions from pg_views and pg_matviews.
+func (p *PostgresInspector) queryViews(schemaName string) ([]viewInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `SELECT viewname, COALESCE(definition, ''), false
+	FROM pg_views WHERE schemaname = $1
+	UNION ALL
+	SELECT matviewname, COALESCE(definition, ''), true
+	FROM pg_matviews WHERE schemaname = $1`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying postgres views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []viewInfo
+	for rows.Next() {
+		var v viewInfo
+		if err := rows.Scan(&v.Name, &v.Definition, &v.Materialized); err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
+
+// queryMatviewColumns reads the columns of materialized views from pg_attribute.
+func (p *PostgresInspector) queryMatviewColumns(schemaName string, tableNames []string) ([]columnInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod),
+		NOT a.attnotnull, a.attnum
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1
+		AND c.relkind = 'm'
+		AND a.attnum > 0
+		AND NOT a.attisdropped
+	ORDER BY c.relname, a.attnum`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying postgres materialized view columns: %w", err)
+	}
+	defer rows.Close()
+
+	wanted := make(map[string]bool, len(tableNames))
+	for _, n := range tableNames {
+		wanted[n] = true
+	}
+	var cols []columnInfo
+	for rows.Next() {
+		var c columnInfo
+		if err := rows.Scan(&c.TableName, &c.ColumnName, &c.DataType, &c.IsNullable, &c.OrdinalPos); err != nil {
+			return nil, err
+		}
+		if len(tableNames) == 0 || wanted[c.TableName] {
+			cols = append(cols, c)
+		}
+	}
+	return cols, rows.Err()
+}
+
+// queryTableComments reads table comments (COMMENT ON TABLE) from pg_description.
+func (p *PostgresInspector) queryTableComments(schemaName string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `SELECT c.relname, COALESCE(obj_description(c.oid, 'pg_class'), '')
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1
+		AND c.relkind IN ('r', 'p', 'v', 'm')`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying postgres table comments: %w", err)
+	}
+	return scanTableComments(rows)
+}
+
+// queryForeignKeys retrieves FK relationships for PostgreSQL from pg_constraint, pairing
+// the referencing and referenced columns by their position in conkey and confkey.
+// (information_schema.constraint_column_usage has no positions, so joining it pairs
+// every column of a composite key with every referenced column.)
 func (p *PostgresInspector) queryForeignKeys(schemaName string, tableNames []string) ([]fkInfo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
 	defer cancel()
 
 	query := `SELECT
-		kcu.table_name AS source_table,
-		kcu.column_name AS source_column,
-		ccu.table_name AS target_table,
-		ccu.column_name AS target_column
-	FROM information_schema.table_constraints tc
-	JOIN information_schema.key_column_usage kcu
-		ON tc.constraint_name = kcu.constraint_name
-		AND tc.table_schema = kcu.table_schema
-	JOIN information_schema.constraint_column_usage ccu
-		ON tc.constraint_name = ccu.constraint_name
-		AND tc.table_schema = ccu.table_schema
-	WHERE tc.table_schema = $1
-		AND tc.constraint_type = 'FOREIGN KEY'`
+		con.conname AS constraint_name,
+		src.relname AS source_table,
+		sa.attname AS source_column,
+		tgt.relname AS target_table,
+		ta.attname AS target_column,
+		k.ord AS ordinal
+	FROM pg_constraint con
+	JOIN pg_class src ON src.oid = con.conrelid
+	JOIN pg_namespace n ON n.oid = src.relnamespace
+	JOIN pg_class tgt ON tgt.oid = con.confrelid
+	CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(src_att, tgt_att, ord)
+	JOIN pg_attribute sa ON sa.attrelid = con.conrelid AND sa.attnum = k.src_att
+	JOIN pg_attribute ta ON ta.attrelid = con.confrelid AND ta.attnum = k.tgt_att
+	WHERE n.nspname = $1
+		AND con.contype = 'f'`
 
 	args := []interface{}{schemaName}
 	if len(tableNames) > 0 {
@@ -112,8 +241,9 @@ func (p *PostgresInspector) queryForeignKeys(schemaName string, tableNames []str
 			placeholders[i] = fmt.Sprintf("$%d", i+2)
 			args = append(args, tableNames[i])
 		}
-		query += fmt.Sprintf(" AND kcu.table_name IN (%s)", strings.Join(placeholders, ","))
+		query += fmt.Sprintf(" AND src.relname IN (%s)", strings.Join(placeholders, ","))
 	}
+	query += " ORDER BY src.relname, con.conname, k.ord"
 
 	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -124,10 +254,119 @@ func (p *PostgresInspector) queryForeignKeys(schemaName string, tableNames []str
 	var fks []fkInfo
 	for rows.Next() {
 		var fk fkInfo
-		if err := rows.Scan(&fk.SourceTable, &fk.SourceColumn, &fk.TargetTable, &fk.TargetColumn); err != nil {
+		if err := rows.Scan(&fk.ConstraintName, &fk.SourceTable, &fk.SourceColumn, &fk.TargetTable, &fk.TargetColumn, &fk.Ordinal); err != nil {
 			return nil, err
 		}
 		fks = append(fks, fk)
 	}
 	return fks, rows.Err()
 }
+
+// queryColumnMeta reads identity modes and column comments from pg_attribute, and
+// single-column CHECK constraints from pg_constraint.
+func (p *PostgresInspector) queryColumnMeta(schemaName string) ([]columnMeta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `SELECT c.relname, a.attname, a.attidentity,
+		COALESCE(col_description(c.oid, a.attnum), '')
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = $1
+		AND c.relkind IN ('r', 'p', 'v', 'm')
+		AND a.attnum > 0
+		AND NOT a.attisdropped`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying postgres column metadata: %w", err)
+	}
+	defer rows.Close()
+
+	var meta []columnMeta
+	for rows.Next() {
+		var m columnMeta
+		var identity string
+		if err := rows.Scan(&m.TableName, &m.ColumnName, &identity, &m.Comment); err != nil {
+			return nil, err
+		}
+		switch identity {
+		case "a":
+			m.Identity = schema.IdentityAlways
+		case "d":
+			m.Identity = schema.IdentityByDefault
+		}
+		meta = append(meta, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	checks, err := p.db.QueryContext(ctx, `SELECT c.relname, a.attname, pg_get_constraintdef(con.oid)
+	FROM pg_constraint con
+	JOIN pg_class c ON c.oid = con.conrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = con.conkey[1]
+	WHERE n.nspname = $1
+		AND con.contype = 'c'
+		AND array_length(con.conkey, 1) = 1`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying postgres check constraints: %w", err)
+	}
+	defer checks.Close()
+
+	for checks.Next() {
+		var m columnMeta
+		var def string
+		if err := checks.Scan(&m.TableName, &m.ColumnName, &def); err != nil {
+			return nil, err
+		}
+		// pg_get_constraintdef returns e.g. "CHECK ((qty > 0)) NOT VALID".
+		def = strings.TrimSuffix(strings.TrimSpace(def), " NOT VALID")
+		m.Check = stripParens(strings.TrimPrefix(def, "CHECK "))
+		meta = append(meta, m)
+	}
+	return meta, checks.Err()
+}
+
+// queryIndexes reads secondary indexes from pg_index, one row per key column. Expression
+// keys are rendered with pg_get_indexdef; INCLUDE columns are not keys and are skipped.
+func (p *PostgresInspector) queryIndexes(schemaName string) ([]indexInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `SELECT t.relname, i.relname, ix.indisunique, am.amname,
+		COALESCE(pg_get_expr(ix.indpred, ix.indrelid), ''),
+		COALESCE(a.attname, ''),
+		CASE WHEN a.attname IS NULL THEN pg_get_indexdef(ix.indexrelid, k.n, true) ELSE '' END,
+		(ix.indoption[k.n - 1] & 1) = 1
+	FROM pg_index ix
+	JOIN pg_class i ON i.oid = ix.indexrelid
+	JOIN pg_class t ON t.oid = ix.indrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	JOIN pg_am am ON am.oid = i.relam
+	CROSS JOIN LATERAL generate_series(1, ix.indnkeyatts) AS k(n)
+	LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ix.indkey[k.n - 1] AND a.attnum > 0
+	WHERE n.nspname = $1
+		AND NOT ix.indisprimary
+	ORDER BY t.relname, i.relname, k.n`, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying postgres indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []indexInfo
+	for rows.Next() {
+		var ix indexInfo
+		if err := rows.Scan(&ix.TableName, &ix.IndexName, &ix.Unique, &ix.Method, &ix.Where,
+			&ix.ColumnName, &ix.Expression, &ix.Desc); err != nil {
+			return nil, err
+		}
+		if ix.Method == "btree" {
+			ix.Method = ""
+		}
+		ix.Where = stripParens(ix.Where)
+		ix.Expression = stripParens(ix.Expression)
+		indexes = append(indexes, ix)
+	}
+	return indexes, rows.Err()
+}
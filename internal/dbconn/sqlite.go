@@ -0,0 +1,393 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"schemastudio/internal/schema"
+)
+
+// SQLiteInspector implements SchemaInspector for SQLite database files. The file path
+// is taken from ConnectionConfig.Database and is opened read-only.
+type SQLiteInspector struct {
+	db   *sql.DB
+	path string
+}
+
+var (
+	sqliteAutoIncrement = regexp.MustCompile(`(?i)\bautoincrement\b`)
+	sqliteIndexWhere    = regexp.MustCompile(`(?is)\bwhere\b(.*)$`)
+)
+
+func (s *SQLiteInspector) Connect(cfg ConnectionConfig) error {
+	if cfg.Database == "" {
+		return fmt.Errorf("sqlite connect: no database file given")
+	}
+	// Opening a missing file would create an empty database.
+	if _, err := os.Stat(cfg.Database); err != nil {
+		return fmt.Errorf("sqlite connect: %w", err)
+	}
+	// A URI file name ends at "?" or "#" and decodes "%" escapes.
+	path := strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(filepath.ToSlash(cfg.Database))
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("sqlite connect: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	// Ping does not read the file; reading the schema catches files that are not databases.
+	var n int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master").Scan(&n); err != nil {
+		db.Close()
+		return fmt.Errorf("sqlite open %s: %w", cfg.Database, err)
+	}
+	s.db = db
+	s.path = cfg.Database
+	return nil
+}
+
+func (s *SQLiteInspector) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// ListSchemas returns the names of the main and attached databases.
+func (s *SQLiteInspector) ListSchemas() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT name FROM pragma_database_list WHERE name <> 'temp' ORDER BY seq")
+	if err != nil {
+		return nil, fmt.Errorf("listing schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, name)
+	}
+	return schemas, rows.Err()
+}
+
+func (s *SQLiteInspector) ListTables(schemaName string) ([]string, error) {
+	objects, err := s.queryObjects(schemaName, "'table', 'view'")
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	tables := make([]string, 0, len(objects))
+	for _, o := range objects {
+		tables = append(tables, o.name)
+	}
+	return tables, nil
+}
+
+// sqliteObject is a row of sqlite_master.
+type sqliteObject struct {
+	kind, name, table, sql string
+}
+
+// sqliteSchema returns schemaName as a quoted schema qualifier, "main" if it is empty.
+func sqliteSchema(schemaName string) string {
+	if schemaName == "" {
+		schemaName = "main"
+	}
+	return `"` + strings.ReplaceAll(schemaName, `"`, `""`) + `"`
+}
+
+// queryObjects reads the user objects of the given kinds (a quoted, comma-separated
+// list) from sqlite_master, ordered by name.
+func (s *SQLiteInspector) queryObjects(schemaName, kinds string) ([]sqliteObject, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT type, name, tbl_name, COALESCE(sql, '')
+	FROM %s.sqlite_master
+	WHERE type IN (%s)
+		AND name NOT LIKE 'sqlite\_%%' ESCAPE '\'
+	ORDER BY name`, sqliteSchema(schemaName), kinds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []sqliteObject
+	for rows.Next() {
+		var o sqliteObject
+		if err := rows.Scan(&o.kind, &o.name, &o.table, &o.sql); err != nil {
+			return nil, err
+		}
+		objects = append(objects, o)
+	}
+	return objects, rows.Err()
+}
+
+func (s *SQLiteInspector) InspectSchema(schemaName string, tableNames []string) (schema.TableCatalog, error) {
+	if schemaName == "" {
+		schemaName = "main"
+	}
+	objects, err := s.queryObjects(schemaName, "'table', 'view'")
+	if err != nil {
+		return schema.TableCatalog{}, fmt.Errorf("querying sqlite tables: %w", err)
+	}
+	wanted := make(map[string]bool, len(tableNames))
+	for _, t := range tableNames {
+		wanted[t] = true
+	}
+
+	var columns []columnInfo
+	var pks, uniques []pkInfo
+	var meta []columnMeta
+	var fks []fkInfo
+	pkColumns := make(map[string][]string) // table -> primary key columns in key order
+	var indexes []indexInfo
+	var views []viewInfo
+	for _, o := range objects {
+		if len(wanted) > 0 && !wanted[o.name] {
+			continue
+		}
+		cols, err := s.queryColumns(schemaName, o.name)
+		if err != nil {
+			return schema.TableCatalog{}, err
+		}
+		var tablePKs []pkInfo
+		keyColumns := make([]string, len(cols))
+		for _, c := range cols {
+			columns = append(columns, c.columnInfo)
+			if c.pk > 0 {
+				tablePKs = append(tablePKs, pkInfo{TableName: o.name, ColumnName: c.ColumnName})
+				keyColumns[c.pk-1] = c.ColumnName
+			}
+		}
+		pks = append(pks, tablePKs...)
+		pkColumns[o.name] = keyColumns[:len(tablePKs)]
+		if o.kind == "view" {
+			views = append(views, viewInfo{Name: o.name, Definition: o.sql})
+			continue
+		}
+		// An AUTOINCREMENT column is the table's INTEGER PRIMARY KEY.
+		if len(tablePKs) == 1 && sqliteAutoIncrement.MatchString(o.sql) {
+			meta = append(meta, columnMeta{TableName: o.name, ColumnName: tablePKs[0].ColumnName, Identity: schema.IdentityByDefault})
+		}
+
+		tableFKs, err := s.queryForeignKeys(schemaName, o.name)
+		if err != nil {
+			return schema.TableCatalog{}, err
+		}
+		fks = append(fks, tableFKs...)
+
+		tableIndexes, tableUniques, err := s.queryIndexes(schemaName, o.name)
+		if err != nil {
+			return schema.TableCatalog{}, err
+		}
+		indexes = append(indexes, tableIndexes...)
+		uniques = append(uniques, tableUniques...)
+	}
+
+	// A foreign key without referenced columns references the parent's primary key.
+	for i := range fks {
+		if fk := &fks[i]; fk.TargetColumn == "" && fk.Ordinal <= len(pkColumns[fk.TargetTable]) {
+			fk.TargetColumn = pkColumns[fk.TargetTable][fk.Ordinal-1]
+		}
+	}
+
+	applyColumnMeta(columns, uniques, meta)
+	catalog := buildCatalog(columns, pks, fks, fmt.Sprintf("%s (SQLite)", filepath.Base(s.path)), "sqlite")
+	catalog = markViews(attachIndexes(catalog, indexes), views)
+	if schemaName != "main" {
+		// Tables of an attached database are qualified by its name.
+		catalog = describeTables(catalog, schemaName, nil)
+	}
+	return catalog, nil
+}
+
+// sqliteColumn is a row of pragma_table_info; pk is the column's position in the
+// primary key, or 0.
+type sqliteColumn struct {
+	columnInfo
+	pk int
+}
+
+// queryColumns reads a table's columns from pragma_table_info. The declared type is
+// kept as it is, so it is stored as the column's sqlite type override.
+func (s *SQLiteInspector) queryColumns(schemaName, table string) ([]sqliteColumn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT cid, name, type, "notnull", dflt_value, pk
+	FROM pragma_table_info(?, ?)
+	ORDER BY cid`, table, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying sqlite columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var cols []sqliteColumn
+	for rows.Next() {
+		var c sqliteColumn
+		var notNull bool
+		if err := rows.Scan(&c.OrdinalPos, &c.ColumnName, &c.DataType, &notNull, &c.Default, &c.pk); err != nil {
+			return nil, err
+		}
+		c.TableName = table
+		c.OrdinalPos++
+		// SQLite allows NULL in a primary key other than INTEGER PRIMARY KEY, but it is a
+		// legacy quirk no schema relies on.
+		c.IsNullable = !notNull && c.pk == 0
+		if strings.TrimSpace(c.DataType) == "" {
+			// A column without a declared type takes values of any type.
+			c.DataType = "any"
+		}
+		cols = append(cols, c)
+	}
+	return cols, rows.Err()
+}
+
+// queryForeignKeys reads a table's foreign key constraints from pragma_foreign_key_list,
+// in key order. SQLite does not keep constraint names. TargetColumn is empty when the
+// constraint references the parent's primary key implicitly.
+func (s *SQLiteInspector) queryForeignKeys(schemaName, table string) ([]fkInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT "table", "from", COALESCE("to", ''), seq + 1
+	FROM pragma_foreign_key_list(?, ?)
+	ORDER BY id, seq`, table, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying sqlite foreign keys of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var fks []fkInfo
+	for rows.Next() {
+		fk := fkInfo{SourceTable: table}
+		if err := rows.Scan(&fk.TargetTable, &fk.SourceColumn, &fk.TargetColumn, &fk.Ordinal); err != nil {
+			return nil, err
+		}
+		fks = append(fks, fk)
+	}
+	return fks, rows.Err()
+}
+
+// queryIndexes reads a table's indexes from pragma_index_list and pragma_index_xinfo,
+// in creation order. The primary key index is skipped and single-column UNIQUE constraints are returned as
+// unique columns. A multi-column UNIQUE constraint becomes a unique index named
+// table_col_col_key, since SQLite names its index sqlite_autoindex_table_N. Expression
+// keys have no column name, so attachIndexes leaves out the indexes containing one.
+func (s *SQLiteInspector) queryIndexes(schemaName, table string) ([]indexInfo, []pkInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	type sqliteIndex struct {
+		name, origin    string
+		unique, partial bool
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT name, "unique", origin, partial
+	FROM pragma_index_list(?, ?)
+	ORDER BY seq DESC`, table, schemaName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying sqlite indexes of %s: %w", table, err)
+	}
+	var list []sqliteIndex
+	for rows.Next() {
+		var ix sqliteIndex
+		if err := rows.Scan(&ix.name, &ix.unique, &ix.origin, &ix.partial); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		if ix.origin != "pk" {
+			list = append(list, ix)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var definitions map[string]string
+	var indexes []indexInfo
+	var uniques []pkInfo
+	for _, ix := range list {
+		keys, err := s.queryIndexKeys(ctx, schemaName, ix.name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ix.origin == "u" && len(keys) == 1 && keys[0].ColumnName != "" {
+			uniques = append(uniques, pkInfo{TableName: table, ColumnName: keys[0].ColumnName})
+			continue
+		}
+		name := ix.name
+		if ix.origin == "u" {
+			name = table
+			for _, k := range keys {
+				name += "_" + k.ColumnName
+			}
+			name += "_key"
+		}
+		var where string
+		if ix.partial {
+			if definitions == nil {
+				if definitions, err = s.indexDefinitions(schemaName); err != nil {
+					return nil, nil, err
+				}
+			}
+			if m := sqliteIndexWhere.FindStringSubmatch(definitions[ix.name]); m != nil {
+				where = strings.TrimRight(strings.TrimSpace(m[1]), "; \t\r\n")
+			}
+		}
+		for _, k := range keys {
+			k.TableName, k.IndexName, k.Unique, k.Where = table, name, ix.unique, where
+			indexes = append(indexes, k)
+		}
+	}
+	return indexes, uniques, nil
+}
+
+// queryIndexKeys reads the key columns of an index in key order. Only ColumnName and
+// Desc are set.
+func (s *SQLiteInspector) queryIndexKeys(ctx context.Context, schemaName, index string) ([]indexInfo, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT COALESCE(name, ''), "desc"
+	FROM pragma_index_xinfo(?, ?)
+	WHERE key = 1
+	ORDER BY seqno`, index, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("querying sqlite index %s: %w", index, err)
+	}
+	defer rows.Close()
+
+	var keys []indexInfo
+	for rows.Next() {
+		var k indexInfo
+		if err := rows.Scan(&k.ColumnName, &k.Desc); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// indexDefinitions returns the CREATE INDEX statements of the schema by index name.
+func (s *SQLiteInspector) indexDefinitions(schemaName string) (map[string]string, error) {
+	objects, err := s.queryObjects(schemaName, "'index'")
+	if err != nil {
+		return nil, fmt.Errorf("querying sqlite index definitions: %w", err)
+	}
+	defs := make(map[string]string, len(objects))
+	for _, o := range objects {
+		defs[o.name] = o.sql
+	}
+	return defs, nil
+}
@@ -0,0 +1,159 @@
+package dbconn
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+func newSQLiteFile(t *testing.T, ddl ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range ddl {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	return path
+}
+
+func TestSQLiteInspector(t *testing.T) {
+	path := newSQLiteFile(t,
+		`CREATE TABLE customers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			email VARCHAR(255) NOT NULL UNIQUE,
+			status TEXT DEFAULT 'active',
+			note
+		)`,
+		`CREATE TABLE orders (
+			customer_id INTEGER NOT NULL REFERENCES customers,
+			order_no INT NOT NULL,
+			total DECIMAL(10,2),
+			PRIMARY KEY (customer_id, order_no)
+		)`,
+		`CREATE TABLE order_lines (
+			customer_id INTEGER NOT NULL,
+			order_no INT NOT NULL,
+			line_no INT NOT NULL,
+			sku TEXT,
+			UNIQUE (customer_id, sku),
+			FOREIGN KEY (customer_id, order_no) REFERENCES orders (customer_id, order_no)
+		)`,
+		`CREATE INDEX order_lines_sku ON order_lines (sku DESC) WHERE sku IS NOT NULL`,
+		`CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100`,
+	)
+
+	var insp SQLiteInspector
+	if err := insp.Connect(ConnectionConfig{Driver: "sqlite", Database: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer insp.Close()
+
+	schemas, err := insp.ListSchemas()
+	if err != nil || len(schemas) != 1 || schemas[0] != "main" {
+		t.Fatalf("ListSchemas() = %v, %v", schemas, err)
+	}
+	tables, err := insp.ListTables("main")
+	if err != nil || len(tables) != 4 {
+		t.Fatalf("ListTables() = %v, %v", tables, err)
+	}
+
+	catalog, err := insp.InspectSchema("main", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog.ImportSource != "app.db (SQLite)" {
+		t.Errorf("ImportSource = %q", catalog.ImportSource)
+	}
+	byName := make(map[string]schema.Table)
+	for _, tbl := range catalog.Tables {
+		byName[tbl.Name] = tbl
+		if tbl.Schema != "" {
+			t.Errorf("%s: Schema = %q, want none for main", tbl.Name, tbl.Schema)
+		}
+	}
+	customers, orders, lines := byName["customers"], byName["orders"], byName["order_lines"]
+
+	id := customers.Fields[0]
+	if !id.PrimaryKey || id.Nullable || id.Identity != schema.IdentityByDefault || id.Type != "integer" {
+		t.Errorf("customers.id = %+v", id)
+	}
+	email := customers.Fields[1]
+	if !email.Unique || email.Nullable || email.Length == nil || *email.Length != 255 ||
+		email.TypeOverrides["sqlite"].Type != "varchar(255)" {
+		t.Errorf("customers.email = %+v", email)
+	}
+	if status := customers.Fields[2]; status.Default != "'active'" || !status.Nullable {
+		t.Errorf("customers.status = %+v", status)
+	}
+	if note := customers.Fields[3]; note.TypeOverrides["sqlite"].Type != "any" {
+		t.Errorf("customers.note = %+v", note)
+	}
+	if !orders.Fields[0].PrimaryKey || !orders.Fields[1].PrimaryKey || orders.Fields[0].Identity != "" {
+		t.Errorf("orders primary key = %+v", orders.Fields)
+	}
+	if total := orders.Fields[2]; total.Type != "numeric" || total.TypeOverrides["sqlite"].Type != "decimal(10,2)" {
+		t.Errorf("orders.total = %+v", total)
+	}
+
+	if len(lines.Indexes) != 2 {
+		t.Fatalf("order_lines indexes = %+v", lines.Indexes)
+	}
+	// Indexes are in creation order, so the constraint comes first.
+	sku := lines.Indexes[1]
+	if sku.Name != "order_lines_sku" || sku.Unique || sku.Where != "sku IS NOT NULL" || !sku.Columns[0].Desc {
+		t.Errorf("sku index = %+v", sku)
+	}
+	if uq := lines.Indexes[0]; uq.Name != "order_lines_customer_id_sku_key" || !uq.Unique || len(uq.Columns) != 2 {
+		t.Errorf("unique constraint = %+v", uq)
+	}
+
+	if len(catalog.Relationships) != 2 {
+		t.Fatalf("relationships = %+v", catalog.Relationships)
+	}
+	for _, r := range catalog.Relationships {
+		switch r.TargetTableID {
+		case orders.ID:
+			// REFERENCES customers: the parent's primary key.
+			if r.SourceTableID != customers.ID || r.SourceFieldID != id.ID || r.TargetFieldID != orders.Fields[0].ID || r.SourceFieldIDs != nil {
+				t.Errorf("orders -> customers = %+v", r)
+			}
+		case lines.ID:
+			want := []string{orders.Fields[0].ID, orders.Fields[1].ID}
+			if r.SourceTableID != orders.ID || len(r.SourceFieldIDs) != 2 || r.SourceFieldIDs[0] != want[0] || r.SourceFieldIDs[1] != want[1] ||
+				len(r.TargetFieldIDs) != 2 || r.TargetFieldIDs[0] != lines.Fields[0].ID || r.TargetFieldIDs[1] != lines.Fields[1].ID {
+				t.Errorf("order_lines -> orders = %+v", r)
+			}
+		default:
+			t.Errorf("unexpected relationship %+v", r)
+		}
+	}
+
+	view := byName["big_orders"]
+	if view.Kind != schema.KindView || view.Definition != "SELECT * FROM orders WHERE total > 100" || len(view.Fields) != 3 {
+		t.Errorf("big_orders = %+v", view)
+	}
+
+	only, err := insp.InspectSchema("", []string{"orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(only.Tables) != 1 || len(only.Relationships) != 0 {
+		t.Errorf("InspectSchema(orders) = %+v", only)
+	}
+}
+
+func TestSQLiteInspector_MissingFile(t *testing.T) {
+	var insp SQLiteInspector
+	if err := insp.Connect(ConnectionConfig{Driver: "sqlite", Database: filepath.Join(t.TempDir(), "missing.db")}); err == nil {
+		insp.Close()
+		t.Fatal("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,519 @@
+package importers
+
+import (
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// dbmlDatabaseTypes maps DBML Project database_type values to the dialect that column
+// types are kept under as type overrides.
+var dbmlDatabaseTypes = map[string]string{
+	"postgresql": "postgres",
+	"postgres":   "postgres",
+	"mysql":      "mysql",
+	"sql server": "mssql",
+	"sqlserver":  "mssql",
+	"mssql":      "mssql",
+	"bigquery":   "bigquery",
+}
+
+// dbmlRef is a relationship recorded while parsing and turned into a foreign key once
+// all tables are known, since a one-to-one ref's direction depends on primary keys.
+type dbmlRef struct {
+	name       string
+	leftTable  string
+	leftCols   []string
+	op         string
+	rightTable string
+	rightCols  []string
+	line       int
+}
+
+type dbmlParser struct {
+	b       *ddlBuilder
+	aliases map[string]string   // alias -> table name
+	enums   map[string][]string // lower-case enum name, qualified and not -> values
+	refs    []dbmlRef
+	groups  []dbmlGroup
+}
+
+// dbmlGroup is a TableGroup, applied once all tables are known.
+type dbmlGroup struct {
+	name    string
+	members [][]schemaToken
+	line    int
+}
+
+// ParseDBML parses a DBML file and returns a TableCatalog. Tables become tables with
+// their notes as descriptions, column settings (pk, not null, unique, increment,
+// default, note) map onto fields, indexes blocks become indexes, and Ref statements and
+// inline refs become relationships with their stated cardinality. Enum columns are
+// strings with a CHECK listing the values. Column types are kept as type overrides when
+// the Project names its database_type, and a TableGroup becomes a tag on its tables.
+// Constructs it cannot represent (sticky notes, unknown blocks) are reported in
+// catalog.Warnings. ImportSource is left empty; the caller should set it.
+func ParseDBML(src string) (schema.TableCatalog, error) {
+	toks, err := tokenizeSchema(src)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	lines := schemaLines(toks)
+
+	dialect := ""
+	for i := 0; i < len(lines); i++ {
+		if l := lines[i]; l[0].isWord("Project") && l[len(l)-1].isPunct("{") {
+			body, next := dbmlBody(lines, i+1)
+			for _, bl := range body {
+				if len(bl) >= 3 && bl[0].isWord("database_type") && bl[1].isPunct(":") && bl[2].kind == stString {
+					dialect = dbmlDatabaseTypes[strings.ToLower(bl[2].text)]
+				}
+			}
+			i = next - 1
+		}
+	}
+
+	p := &dbmlParser{b: newDDLBuilder(dialect), aliases: make(map[string]string), enums: make(map[string][]string)}
+	// Enums first, so columns defined before their enum are still recognized.
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		if !l[len(l)-1].isPunct("{") {
+			continue
+		}
+		body, next := dbmlBody(lines, i+1)
+		if l[0].isWord("Enum") {
+			p.enum(l[1:len(l)-1], body)
+		}
+		i = next - 1
+	}
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		head := l[0]
+		if !l[len(l)-1].isPunct("{") {
+			if head.isWord("Ref") {
+				p.ref(l[1:], head.line)
+			} else {
+				p.b.warn(head.line, "unexpected %s; skipped", schemaText(l))
+			}
+			continue
+		}
+		body, next := dbmlBody(lines, i+1)
+		i = next - 1
+		switch {
+		case head.isWord("Table"):
+			p.table(l[1:len(l)-1], body, head.line)
+		case head.isWord("Ref"):
+			// Give each line of a named block the name, as if written "Ref name: ...".
+			name := l[1 : len(l)-1]
+			for _, bl := range body {
+				toks := append([]schemaToken{}, name...)
+				if len(name) > 0 {
+					toks = append(toks, schemaToken{kind: stPunct, text: ":", line: bl[0].line})
+				}
+				p.ref(append(toks, bl...), bl[0].line)
+			}
+		case head.isWord("Project"), head.isWord("Enum"):
+		case head.isWord("TableGroup"):
+			if parts, _ := dbmlName(l, 1); len(parts) > 0 {
+				p.groups = append(p.groups, dbmlGroup{name: parts[len(parts)-1], members: body, line: head.line})
+			}
+		case head.isWord("Note"):
+			p.b.warn(head.line, "%s not imported", schemaText(l[:len(l)-1]))
+		default:
+			p.b.warn(head.line, "%s block not imported", head.text)
+		}
+	}
+	p.applyGroups()
+	p.resolveRefs()
+	return p.b.catalog(), nil
+}
+
+// dbmlBody returns the lines of the block whose body starts at lines[start], with any
+// nested blocks included, and the index just after its closing brace.
+func dbmlBody(lines [][]schemaToken, start int) ([][]schemaToken, int) {
+	depth := 1
+	for i := start; i < len(lines); i++ {
+		l := lines[i]
+		switch {
+		case l[0].isPunct("}"):
+			depth--
+			if depth == 0 {
+				return lines[start:i], i + 1
+			}
+		case l[len(l)-1].isPunct("{"):
+			depth++
+		}
+	}
+	return lines[start:], len(lines)
+}
+
+// dbmlName reads a possibly schema-qualified name (schema.table, "quoted name") and
+// returns its parts and the index of the first token after it.
+func dbmlName(toks []schemaToken, start int) ([]string, int) {
+	var parts []string
+	i := start
+	for i < len(toks) && (toks[i].kind == stIdent || toks[i].kind == stString) {
+		parts = append(parts, toks[i].text)
+		i++
+		if i+1 < len(toks) && toks[i].isPunct(".") && (toks[i+1].kind == stIdent || toks[i+1].kind == stString) {
+			i++
+			continue
+		}
+		break
+	}
+	return parts, i
+}
+
+// dbmlSettings returns the comma-separated settings of a trailing [...] group, or nil if
+// toks[start] does not open one.
+func dbmlSettings(toks []schemaToken, start int) [][]schemaToken {
+	if start >= len(toks) || !toks[start].isPunct("[") {
+		return nil
+	}
+	inner, _, _ := bracketed(toks, start)
+	return splitSchemaList(inner)
+}
+
+// dbmlSetting returns the value tokens of a key: value setting, or nil if part is not
+// that setting.
+func dbmlSetting(part []schemaToken, key string) []schemaToken {
+	if len(part) >= 2 && part[0].isWord(key) && part[1].isPunct(":") {
+		return part[2:]
+	}
+	return nil
+}
+
+// dbmlValue renders a setting value as SQL: strings are quoted, `expressions` and
+// numbers are kept as they are.
+func dbmlValue(v []schemaToken) string {
+	if len(v) == 1 && v[0].kind == stString {
+		return quoteSQLString(v[0].text)
+	}
+	if len(v) == 1 && v[0].kind == stExpr {
+		return v[0].text
+	}
+	return schemaText(v)
+}
+
+func (p *dbmlParser) enum(head []schemaToken, body [][]schemaToken) {
+	parts, _ := dbmlName(head, 0)
+	if len(parts) == 0 {
+		return
+	}
+	var values []string
+	for _, l := range body {
+		if l[0].kind == stIdent || l[0].kind == stString {
+			values = append(values, l[0].text)
+		}
+	}
+	p.enums[strings.ToLower(strings.Join(parts, "."))] = values
+	p.enums[strings.ToLower(parts[len(parts)-1])] = values
+}
+
+// table builds a table from its header (name, alias and settings) and body.
+func (p *dbmlParser) table(head []schemaToken, body [][]schemaToken, line int) {
+	parts, i := dbmlName(head, 0)
+	if len(parts) == 0 {
+		p.b.warn(line, "table without a name skipped")
+		return
+	}
+	name, schemaName := parts[len(parts)-1], ""
+	if len(parts) > 1 {
+		schemaName = parts[len(parts)-2]
+	}
+	t := p.b.addTable(schemaName, name, line)
+	if t == nil {
+		return
+	}
+	if i+1 < len(head) && head[i].isWord("as") {
+		p.aliases[strings.ToLower(head[i+1].text)] = name
+		i += 2
+	}
+	for _, s := range dbmlSettings(head, i) {
+		if v := dbmlSetting(s, "note"); len(v) > 0 {
+			t.table.Description = v[0].text
+		}
+	}
+
+	for j := 0; j < len(body); j++ {
+		l := body[j]
+		switch {
+		case l[len(l)-1].isPunct("{"):
+			inner, next := dbmlBody(body, j+1)
+			j = next - 1
+			switch {
+			case l[0].isWord("indexes"):
+				for _, il := range inner {
+					p.index(t, il)
+				}
+			case l[0].isWord("Note"):
+				if len(inner) > 0 && inner[0][0].kind == stString {
+					t.table.Description = inner[0][0].text
+				}
+			default:
+				p.b.warn(l[0].line, "%s block in table %s not imported", l[0].text, name)
+			}
+		case len(l) >= 3 && l[0].isWord("Note") && l[1].isPunct(":"):
+			t.table.Description = l[2].text
+		default:
+			p.column(t, l)
+		}
+	}
+}
+
+// column adds a column from a "name type [settings]" line.
+func (p *dbmlParser) column(t *ddlTable, l []schemaToken) {
+	line := l[0].line
+	if len(l) < 2 || (l[0].kind != stIdent && l[0].kind != stString) {
+		p.b.warn(line, "unexpected %s in table %s; skipped", schemaText(l), t.table.Name)
+		return
+	}
+	name := l[0].text
+	end := len(l)
+	for i := 1; i < len(l); i++ {
+		if l[i].isPunct("[") {
+			if inner, _, _ := bracketed(l, i); len(inner) > 0 {
+				end = i
+				break
+			}
+		}
+	}
+	typeToks := l[1:end]
+	rawType := schemaText(typeToks)
+	if len(typeToks) == 1 && typeToks[0].kind == stString {
+		rawType = typeToks[0].text
+	}
+	values, isEnum := p.enums[strings.ToLower(rawType)]
+	if isEnum {
+		rawType = "string"
+	}
+	f := p.b.addField(t, name, rawType, line)
+	if f == nil {
+		return
+	}
+	if isEnum {
+		f.TypeOverrides = nil
+		f.Check = enumCheck(name, values)
+	}
+	for _, s := range dbmlSettings(l, end) {
+		switch {
+		case len(s) == 1 && s[0].isWord("pk"), len(s) == 2 && s[0].isWord("primary") && s[1].isWord("key"):
+			p.b.setPrimaryKey(t, []string{name}, line)
+		case len(s) == 2 && s[0].isWord("not") && s[1].isWord("null"):
+			f.Nullable = false
+		case len(s) == 1 && s[0].isWord("null"):
+			f.Nullable = true
+		case len(s) == 1 && s[0].isWord("unique"):
+			f.Unique = true
+		case len(s) == 1 && s[0].isWord("increment"):
+			f.Identity = schema.IdentityByDefault
+		case dbmlSetting(s, "default") != nil:
+			f.Default = dbmlValue(dbmlSetting(s, "default"))
+		case dbmlSetting(s, "note") != nil:
+			f.Comment = dbmlSetting(s, "note")[0].text
+		case dbmlSetting(s, "check") != nil:
+			addCheck(f, dbmlSetting(s, "check")[0].text)
+		case dbmlSetting(s, "ref") != nil:
+			v := dbmlSetting(s, "ref")
+			if len(v) == 0 || v[0].kind != stPunct {
+				p.b.warn(line, "ref on %s.%s has no direction; skipped", t.table.Name, name)
+				continue
+			}
+			tbl, cols := p.endpoint(v[1:])
+			p.refs = append(p.refs, dbmlRef{
+				leftTable: t.table.Name, leftCols: []string{name}, op: v[0].text,
+				rightTable: tbl, rightCols: cols, line: line,
+			})
+		}
+	}
+}
+
+// index adds an index from a line of an indexes block: a column, an `expression` or a
+// (composite, key) list, followed by settings.
+func (p *dbmlParser) index(t *ddlTable, l []schemaToken) {
+	ix := ddlIndex{schema: t.table.Schema, table: t.table.Name, line: l[0].line}
+	keyToks, i := l[:1], 1
+	if l[0].isPunct("(") {
+		inner, next, _ := bracketed(l, 0)
+		keyToks, i = inner, next
+	}
+	var cols []string
+	for _, k := range splitSchemaList(keyToks) {
+		switch {
+		case len(k) == 1 && k[0].kind == stExpr:
+			ix.keys = append(ix.keys, ddlIndexKey{expression: k[0].text})
+		case len(k) == 1 && (k[0].kind == stIdent || k[0].kind == stString):
+			ix.keys = append(ix.keys, ddlIndexKey{column: k[0].text})
+			cols = append(cols, k[0].text)
+		}
+	}
+	pk := false
+	for _, s := range dbmlSettings(l, i) {
+		switch {
+		case len(s) == 1 && s[0].isWord("pk"):
+			pk = true
+		case len(s) == 1 && s[0].isWord("unique"):
+			ix.unique = true
+		case dbmlSetting(s, "name") != nil:
+			ix.name = dbmlSetting(s, "name")[0].text
+		case dbmlSetting(s, "type") != nil:
+			ix.method = normalizeIndexMethod(dbmlSetting(s, "type")[0].text)
+		}
+	}
+	if pk {
+		p.b.setPrimaryKey(t, cols, ix.line)
+		return
+	}
+	if len(ix.keys) == 0 {
+		p.b.warn(ix.line, "index on %s has no keys; skipped", t.table.Name)
+		return
+	}
+	p.b.indexes = append(p.b.indexes, ix)
+}
+
+// endpoint reads a ref endpoint: table.column, schema.table.column or table.(a, b).
+// Table aliases are resolved.
+func (p *dbmlParser) endpoint(toks []schemaToken) (string, []string) {
+	var cols []string
+	if n := len(toks); n > 0 && toks[n-1].isPunct(")") {
+		open := n - 1
+		for open > 0 && !toks[open].isPunct("(") {
+			open--
+		}
+		inner, _, _ := bracketed(toks, open)
+		for _, c := range splitSchemaList(inner) {
+			if len(c) > 0 {
+				cols = append(cols, c[0].text)
+			}
+		}
+		toks = toks[:open]
+		if n := len(toks); n > 0 && toks[n-1].isPunct(".") {
+			toks = toks[:n-1]
+		}
+	} else {
+		parts, _ := dbmlName(toks, 0)
+		if len(parts) < 2 {
+			return "", nil
+		}
+		cols = []string{parts[len(parts)-1]}
+		toks = toks[:len(toks)-2]
+	}
+	parts, _ := dbmlName(toks, 0)
+	if len(parts) == 0 {
+		return "", nil
+	}
+	table := parts[len(parts)-1]
+	if real, ok := p.aliases[strings.ToLower(table)]; ok {
+		table = real
+	}
+	return table, cols
+}
+
+// ref records a "[name]: left op right [settings]" relationship.
+func (p *dbmlParser) ref(toks []schemaToken, line int) {
+	r := dbmlRef{line: line}
+	if len(toks) > 0 && (toks[0].kind == stIdent || toks[0].kind == stString) && len(toks) > 1 && toks[1].isPunct(":") {
+		r.name, toks = toks[0].text, toks[1:]
+	}
+	if len(toks) > 0 && toks[0].isPunct(":") {
+		toks = toks[1:]
+	}
+	if end := len(toks); end > 0 && toks[end-1].isPunct("]") {
+		for i := range toks {
+			if toks[i].isPunct("[") {
+				toks = toks[:i]
+				break
+			}
+		}
+	}
+	depth := 0
+	for i, t := range toks {
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case depth == 0 && (t.isPunct(">") || t.isPunct("<") || t.isPunct("-") || t.isPunct("<>")):
+			r.op = t.text
+			r.leftTable, r.leftCols = p.endpoint(toks[:i])
+			r.rightTable, r.rightCols = p.endpoint(toks[i+1:])
+		}
+	}
+	if r.op == "" || r.leftTable == "" || r.rightTable == "" {
+		p.b.warn(line, "ref %s not understood; skipped", schemaText(toks))
+		return
+	}
+	p.refs = append(p.refs, r)
+}
+
+// resolveRefs turns refs into foreign keys. In "a > b" the left side holds the foreign
+// key, in "a < b" the right side does; in a one-to-one "a - b" the right side does unless
+// its columns are its primary key and the left side's are not. A many-to-many "a <> b"
+// is kept as a relationship from the left side to the right.
+func (p *dbmlParser) resolveRefs() {
+	for _, r := range p.refs {
+		childTable, childCols, parentTable, parentCols := r.leftTable, r.leftCols, r.rightTable, r.rightCols
+		card := "1-to-many"
+		switch r.op {
+		case "<":
+			childTable, childCols, parentTable, parentCols = r.rightTable, r.rightCols, r.leftTable, r.leftCols
+		case "-":
+			card = "1-to-1"
+			if !p.isPrimaryKey(r.rightTable, r.rightCols) || p.isPrimaryKey(r.leftTable, r.leftCols) {
+				childTable, childCols, parentTable, parentCols = r.rightTable, r.rightCols, r.leftTable, r.leftCols
+			}
+		case "<>":
+			card = "many-to-many"
+			childTable, childCols, parentTable, parentCols = r.rightTable, r.rightCols, r.leftTable, r.leftCols
+		}
+		p.b.fks = append(p.b.fks, ddlForeignKey{
+			name: r.name, table: childTable, cols: childCols,
+			refTable: parentTable, refCols: parentCols, card: card, line: r.line,
+		})
+	}
+}
+
+// applyGroups adds each TableGroup's name to the tags of its tables.
+func (p *dbmlParser) applyGroups() {
+	for _, g := range p.groups {
+		for _, m := range g.members {
+			parts, _ := dbmlName(m, 0)
+			if len(parts) == 0 {
+				continue
+			}
+			name := parts[len(parts)-1]
+			if real, ok := p.aliases[strings.ToLower(name)]; ok {
+				name = real
+			}
+			t := p.b.lookup("", name)
+			if t == nil {
+				p.b.warn(g.line, "table group %s lists unknown table %s", g.name, name)
+				continue
+			}
+			t.table.Tags = append(t.table.Tags, g.name)
+		}
+	}
+}
+
+// isPrimaryKey reports whether cols are exactly the primary key of the named table.
+func (p *dbmlParser) isPrimaryKey(table string, cols []string) bool {
+	t := p.b.lookup("", table)
+	if t == nil {
+		return false
+	}
+	n := 0
+	for _, f := range t.table.Fields {
+		if f.PrimaryKey {
+			n++
+		}
+	}
+	if n != len(cols) {
+		return false
+	}
+	for _, c := range cols {
+		if f := t.field(c); f == nil || !f.PrimaryKey {
+			return false
+		}
+	}
+	return true
+}
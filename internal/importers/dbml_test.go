@@ -0,0 +1,223 @@
+package importers
+
+import (
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+func TestParseDBML(t *testing.T) {
+	src := `
+Project shop {
+  database_type: 'PostgreSQL'
+  Note: 'Online shop'
+}
+
+Enum order_status {
+  pending
+  "in transit" [note: 'With the courier']
+}
+
+// Customers and their orders.
+Table public.users as U [note: 'People who buy things'] {
+  id integer [pk, increment]
+  email varchar(255) [not null, unique, note: 'Login name']
+  created_at timestamp [default: ` + "`now()`" + `]
+}
+
+Table orders {
+  id int [pk]
+  user_id int [ref: > U.id]
+  status order_status [default: 'pending']
+  total decimal(10,2)
+  Note {
+    '''
+    One row per checkout.
+    '''
+  }
+
+  indexes {
+    (user_id, status) [unique, name: 'orders_user_status']
+    ` + "`lower(status)`" + ` [type: hash]
+  }
+}
+
+Table addresses {
+  user_id int [pk]
+  city text
+}
+
+Table order_lines {
+  order_id int
+  line_no int
+  indexes {
+    (order_id, line_no) [pk]
+  }
+}
+
+Ref: users.id - addresses.user_id
+Ref order_lines_fk: order_lines.order_id > orders.id [delete: cascade]
+Ref: users.id <> orders.id
+
+TableGroup sales {
+  U
+  orders
+}
+`
+	catalog, err := ParseDBML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Tables) != 4 {
+		t.Fatalf("expected 4 tables, got %d", len(catalog.Tables))
+	}
+	users, orders, addresses, lines := catalog.Tables[0], catalog.Tables[1], catalog.Tables[2], catalog.Tables[3]
+	if users.Name != "users" || users.Schema != "public" || users.Description != "People who buy things" {
+		t.Errorf("users = %s.%s %q", users.Schema, users.Name, users.Description)
+	}
+	if f := users.Fields[0]; !f.PrimaryKey || f.Identity != schema.IdentityByDefault {
+		t.Errorf("users.id = %+v", f)
+	}
+	if f := users.Fields[1]; f.Nullable || !f.Unique || f.Comment != "Login name" || f.TypeOverrides["postgres"].Type != "varchar(255)" {
+		t.Errorf("users.email = %+v", f)
+	}
+	if f := users.Fields[2]; f.Default != "now()" {
+		t.Errorf("users.created_at default = %q", f.Default)
+	}
+	if orders.Description != "One row per checkout." {
+		t.Errorf("orders description = %q", orders.Description)
+	}
+	if f := orders.Fields[2]; f.Type != "string" || f.Check != "status in ('pending', 'in transit')" || f.Default != "'pending'" {
+		t.Errorf("orders.status = %+v", f)
+	}
+	if f := orders.Fields[3]; f.Type != "numeric" || *f.Precision != 10 || *f.Scale != 2 {
+		t.Errorf("orders.total = %+v", f)
+	}
+	if len(orders.Indexes) != 2 {
+		t.Fatalf("orders indexes = %+v", orders.Indexes)
+	}
+	if ix := orders.Indexes[0]; ix.Name != "orders_user_status" || !ix.Unique || len(ix.Columns) != 2 {
+		t.Errorf("composite index = %+v", ix)
+	}
+	if ix := orders.Indexes[1]; ix.Method != "hash" || ix.Columns[0].Expression != "lower(status)" {
+		t.Errorf("expression index = %+v", ix)
+	}
+	if !lines.Fields[0].PrimaryKey || !lines.Fields[1].PrimaryKey {
+		t.Errorf("order_lines primary key = %+v", lines.Fields)
+	}
+
+	if len(catalog.Relationships) != 4 {
+		t.Fatalf("expected 4 relationships, got %+v", catalog.Relationships)
+	}
+	inline, oneToOne, named, manyToMany := catalog.Relationships[0], catalog.Relationships[1], catalog.Relationships[2], catalog.Relationships[3]
+	if inline.SourceTableID != users.ID || inline.TargetTableID != orders.ID ||
+		inline.TargetFieldID != orders.Fields[1].ID || inline.Cardinality != "1-to-many" {
+		t.Errorf("inline ref = %+v", inline)
+	}
+	// Both sides are primary keys, so the left side is the parent.
+	if oneToOne.SourceTableID != users.ID || oneToOne.TargetTableID != addresses.ID || oneToOne.Cardinality != "1-to-1" {
+		t.Errorf("one-to-one ref = %+v", oneToOne)
+	}
+	if named.Name != "order_lines_fk" || named.SourceTableID != orders.ID || named.TargetTableID != lines.ID {
+		t.Errorf("named ref = %+v", named)
+	}
+	if manyToMany.SourceTableID != users.ID || manyToMany.TargetTableID != orders.ID || manyToMany.Cardinality != "many-to-many" {
+		t.Errorf("many-to-many ref = %+v", manyToMany)
+	}
+	if len(users.Tags) != 1 || users.Tags[0] != "sales" || len(orders.Tags) != 1 || len(addresses.Tags) != 0 {
+		t.Errorf("table group tags = %v %v %v", users.Tags, orders.Tags, addresses.Tags)
+	}
+	if len(catalog.Warnings) != 0 {
+		t.Errorf("unexpected warnings %v", catalog.Warnings)
+	}
+}
+
+func TestParseDBML_CompositeRefBlock(t *testing.T) {
+	src := `
+Table a {
+  x int
+  y int
+  indexes {
+    (x, y) [pk]
+  }
+}
+Table b {
+  id int [pk]
+  ax int
+  ay int
+}
+Ref {
+  a.(x, y) < b.(ax, ay)
+}
+`
+	catalog, err := ParseDBML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Relationships) != 1 {
+		t.Fatalf("expected 1 relationship, got %+v (warnings %v)", catalog.Relationships, catalog.Warnings)
+	}
+	r := catalog.Relationships[0]
+	if r.SourceTableID != catalog.Tables[0].ID || len(r.TargetFieldIDs) != 2 || r.TargetFieldIDs[1] != catalog.Tables[1].Fields[2].ID {
+		t.Errorf("composite ref = %+v", r)
+	}
+	if catalog.Tables[0].Fields[0].TypeOverrides != nil {
+		t.Errorf("no overrides expected without a database_type: %+v", catalog.Tables[0].Fields[0])
+	}
+}
+
+func TestParseDBML_Unterminated(t *testing.T) {
+	if _, err := ParseDBML("Table t {\n  note: 'oops\n}"); err == nil {
+		t.Error("expected error for unterminated string")
+	}
+}
+
+func TestParseDBML_RoundTrip(t *testing.T) {
+	src := `
+Table app.users [note: 'Accounts'] {
+  id integer [pk, increment]
+  email varchar(255) [not null, unique, note: 'It\'s the login']
+  score "double precision" [default: 1.5]
+  indexes {
+    ` + "`lower(email)`" + ` [unique, name: 'users_lower_email']
+  }
+}
+Table lines {
+  order_id int
+  line_no int
+  user_id int [ref: > app.users.id, default: 0]
+  indexes {
+    (order_id, line_no) [pk]
+  }
+}
+Table shipments {
+  order_id int
+  line_no int
+}
+Ref ship_fk: lines.(order_id, line_no) - shipments.(order_id, line_no)
+Ref: app.users.id <> shipments.order_id
+TableGroup core {
+  app.users
+  lines
+}
+`
+	first, err := ParseDBML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := schema.ToDBML(schema.Diagram{Tables: first.Tables, Relationships: first.Relationships})
+	second, err := ParseDBML(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Warnings) != 0 {
+		t.Errorf("re-import warnings: %v", second.Warnings)
+	}
+	again := schema.ToDBML(schema.Diagram{Tables: second.Tables, Relationships: second.Relationships})
+	if again != out {
+		t.Errorf("DBML changed on round trip:\n%s\nvs\n%s", out, again)
+	}
+	if len(second.Relationships) != 3 || second.Relationships[1].Name != "ship_fk" || second.Relationships[2].Cardinality != "many-to-many" {
+		t.Errorf("relationships = %+v", second.Relationships)
+	}
+}
@@ -0,0 +1,181 @@
+package importers
+
+import (
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// crowsFootEnds maps the crow's foot markers of Mermaid and PlantUML entity diagrams to
+// the cardinality of that end. Left markers ("}o") mirror right ones ("o{").
+var crowsFootEnds = map[string]string{
+	"|o": "0/1", "o|": "0/1", "||": "1", "}o": "0/many", "o{": "0/many", "}|": "many", "|{": "many",
+}
+
+// erdRel is a relationship line of an entity diagram, resolved to fields once all
+// entities are known.
+type erdRel struct {
+	left, right       string // table names
+	leftEnd, rightEnd string // cardinality of each end: "1", "0/1", "many" or "0/many"
+	label             string
+}
+
+// resolveERDRelationships adds a relationship for each line. Entity diagrams do not say
+// which columns a relationship joins, so the parent's primary key is matched to FK
+// columns of the child named after it (customer_id for customers.id), falling back to
+// the child's first unused FK columns. The child is the entity on the right unless only
+// the left one has matching FK columns, or the left end is the many side. Lines whose
+// columns cannot be matched are dropped.
+func resolveERDRelationships(d *schema.Diagram, tableByName map[string]int, rels []erdRel, foreignKeys map[string]bool, ids *idGen) {
+	used := make(map[string]bool) // FK field IDs already joined by a relationship
+	for _, r := range rels {
+		left, right := &d.Tables[tableByName[r.left]], &d.Tables[tableByName[r.right]]
+		parent, child, card := left, right, r.leftEnd+"-to-"+r.rightEnd
+		parentIDs, childIDs := matchKeyColumns(left, right, foreignKeys, used, true)
+		if childIDs == nil {
+			if p, c := matchKeyColumns(right, left, foreignKeys, used, true); c != nil {
+				parent, child, parentIDs, childIDs = right, left, p, c
+			}
+		}
+		if childIDs == nil {
+			if isManyEnd(r.leftEnd) && !isManyEnd(r.rightEnd) {
+				parent, child = right, left
+			}
+			parentIDs, childIDs = matchKeyColumns(parent, child, foreignKeys, used, false)
+		}
+		if childIDs == nil {
+			continue
+		}
+		if parent != left {
+			card = r.rightEnd + "-to-" + r.leftEnd
+		}
+		for _, id := range childIDs {
+			used[id] = true
+		}
+		rel := schema.Relationship{
+			ID:            ids.rel(),
+			SourceTableID: parent.ID,
+			SourceFieldID: parentIDs[0],
+			TargetTableID: child.ID,
+			TargetFieldID: childIDs[0],
+			Label:         r.label,
+			Cardinality:   card,
+		}
+		if len(parentIDs) > 1 {
+			rel.SourceFieldIDs, rel.TargetFieldIDs = parentIDs, childIDs
+		}
+		d.Relationships = append(d.Relationships, rel)
+	}
+}
+
+// matchKeyColumns picks the parent key (its primary key, else its first unique
+// column, else its first column) and the child columns that reference it. With byName,
+// each child column must be named after the key column (id, customer_id, customerId for
+// customers.id); otherwise columns are taken in order. Child columns are those marked
+// FK, or all columns when none are, less those an earlier relationship joined. It
+// returns nil if the key cannot be matched.
+func matchKeyColumns(parent, child *schema.Table, foreignKeys, used map[string]bool, byName bool) ([]string, []string) {
+	var key []schema.Field
+	for _, f := range parent.Fields {
+		if f.PrimaryKey {
+			key = append(key, f)
+		}
+	}
+	if len(key) == 0 {
+		for _, f := range parent.Fields {
+			if f.Unique {
+				key = append(key, f)
+				break
+			}
+		}
+	}
+	if len(key) == 0 && len(parent.Fields) > 0 {
+		key = parent.Fields[:1]
+	}
+	var candidates []schema.Field
+	for _, f := range child.Fields {
+		if foreignKeys[f.ID] {
+			candidates = append(candidates, f)
+		}
+	}
+	marked := len(candidates) > 0
+	if !marked {
+		candidates = child.Fields
+	}
+	if len(key) == 0 || len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var parentIDs, childIDs []string
+	taken := make(map[string]bool)
+	for _, k := range key {
+		names := []string{normalizeKeyName(parent.Name + k.Name), normalizeKeyName(singularName(parent.Name) + k.Name)}
+		if marked {
+			names = append(names, normalizeKeyName(k.Name))
+		}
+		found := ""
+		for _, c := range candidates {
+			if taken[c.ID] || used[c.ID] || (parent == child && c.ID == k.ID) {
+				continue
+			}
+			if !byName {
+				found = c.ID
+				break
+			}
+			for _, n := range names {
+				if normalizeKeyName(c.Name) == n {
+					found = c.ID
+				}
+			}
+			if found != "" {
+				break
+			}
+		}
+		if found == "" {
+			return nil, nil
+		}
+		taken[found] = true
+		parentIDs = append(parentIDs, k.ID)
+		childIDs = append(childIDs, found)
+	}
+	return parentIDs, childIDs
+}
+
+func isManyEnd(end string) bool {
+	return end == "many" || end == "0/many"
+}
+
+// normalizeKeyName lower-cases a name and drops everything but letters and digits, so
+// customer_id, customerId and CustomerID compare equal.
+func normalizeKeyName(s string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(s) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// singularName returns a plural table name in the singular: customers -> customer,
+// addresses -> address, categories -> category.
+func singularName(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss"):
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// unquoteName removes the double quotes around a quoted entity name or label.
+func unquoteName(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
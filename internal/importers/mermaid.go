@@ -5,98 +5,119 @@ import (
 	"strings"
 
 	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
 )
 
-// ParseMermaid parses Mermaid erDiagram syntax and returns a Diagram.
-// Positions are assigned on a grid.
+// mermaidWordEnds maps Mermaid's word aliases for relationship ends to the cardinality
+// of that end.
+var mermaidWordEnds = map[string]string{
+	"one or zero": "0/1", "zero or one": "0/1", "only one": "1", "1": "1",
+	"zero or more": "0/many", "zero or many": "0/many", "many(0)": "0/many", "0+": "0/many",
+	"one or more": "many", "one or many": "many", "many(1)": "many", "1+": "many",
+}
+
+const (
+	mermaidEntity = `([A-Za-z_][A-Za-z0-9_-]*|"[^"]*")`
+	mermaidWords  = `(one or zero|zero or one|only one|zero or more|zero or many|many\(0\)|0\+|one or more|one or many|many\(1\)|1\+|1)`
+)
+
+var (
+	mermaidEntityBlock = regexp.MustCompile(`^` + mermaidEntity + `\s*(?:\[[^\]]*\])?\s*\{\s*(\})?$`)
+	mermaidAttribute   = regexp.MustCompile(`^(\S+)\s+(\S+)((?:\s*,?\s*\b(?:PK|FK|UK)\b)*)\s*(?:"([^"]*)")?$`)
+	mermaidKey         = regexp.MustCompile(`\b(PK|FK|UK)\b`)
+	erdRelSymbols      = regexp.MustCompile(`^` + mermaidEntity + `\s*(\|o|\|\||\}o|\}\|)(--|\.\.)(o\||\|\||o\{|\|\{)\s*` + mermaidEntity + `\s*(?::\s*(.*))?$`)
+	erdRelWords        = regexp.MustCompile(`(?i)^` + mermaidEntity + `\s+` + mermaidWords + `\s+(to|optionally to)\s+` + mermaidWords + `\s+` + mermaidEntity + `\s*(?::\s*(.*))?$`)
+)
+
+// ParseMermaid parses Mermaid erDiagram syntax and returns a Diagram. Attributes become
+// fields with their PK and UK keys and quoted comments; types are normalized. A
+// relationship's markers (or their word aliases) become its cardinality, and its label
+// the relationship label; its columns are matched as described at
+// resolveERDRelationships. Positions are assigned on a grid.
 func ParseMermaid(mermaid string) (schema.Diagram, error) {
 	d := schema.NewDiagram()
-	lines := strings.Split(mermaid, "\n")
 	tableByName := make(map[string]int) // name -> index in d.Tables
 	idGen := newIDGen()
+	foreignKeys := make(map[string]bool) // field IDs marked FK
+	var rels []erdRel
 
-	// Find entity blocks: "    name {" then "        type name PK/FK" until "    }"
-	entityBlock := regexp.MustCompile(`^\s*(\w[\w\d_]*)\s*\{\s*$`)
-	fieldLine := regexp.MustCompile(`^\s*(\w+)\s+(\w[\w\d_]*)\s*(PK|FK)?\s*$`)
-	closeBlock := regexp.MustCompile(`^\s*\}\s*$`)
-
-	currentTableIndex := -1
-	for _, line := range lines {
-		line = strings.TrimRight(line, " \t")
-		if m := entityBlock.FindStringSubmatch(line); len(m) > 0 {
-			name := m[1]
-			if strings.EqualFold(name, "erDiagram") {
+	current := -1
+	for _, line := range strings.Split(mermaid, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%%") {
+			continue
+		}
+		if current >= 0 {
+			if line == "}" {
+				current = -1
 				continue
 			}
-			tID := idGen.table()
-			t := schema.Table{
-				ID:     tID,
-				Name:   name,
-				X:      0,
-				Y:      0,
-				Fields: []schema.Field{},
+			m := mermaidAttribute.FindStringSubmatch(line)
+			if m == nil {
+				continue
 			}
-			d.Tables = append(d.Tables, t)
-			currentTableIndex = len(d.Tables) - 1
-			tableByName[name] = currentTableIndex
-			continue
-		}
-		if currentTableIndex >= 0 && closeBlock.MatchString(line) {
-			currentTableIndex = -1
-			continue
-		}
-		if currentTableIndex >= 0 {
-			if m := fieldLine.FindStringSubmatch(line); len(m) >= 3 {
-				typ, name := m[1], m[2]
-				fID := idGen.field()
-				d.Tables[currentTableIndex].Fields = append(d.Tables[currentTableIndex].Fields, schema.Field{
-					ID:   fID,
-					Name: name,
-					Type: strings.ToLower(typ),
-				})
+			genericType, length, precision, scale := sqlx.NormalizeType(m[1])
+			f := schema.Field{
+				ID:        idGen.field(),
+				Name:      m[2],
+				Type:      genericType,
+				Length:    length,
+				Precision: precision,
+				Scale:     scale,
+				Comment:   m[4],
 			}
-		}
-	}
-
-	// Relationship lines: "    A ||--o{ B : label" or "    A ||--o{ B"
-	relLine := regexp.MustCompile(`^\s*(\w[\w\d_]*)\s*\|[\|\-]+\w*\{\s*(\w[\w\d_]*)\s*(?::\s*(.*))?$`)
-	for _, line := range lines {
-		m := relLine.FindStringSubmatch(line)
-		if len(m) < 3 {
+			for _, k := range mermaidKey.FindAllString(m[3], -1) {
+				switch k {
+				case "PK":
+					f.PrimaryKey = true
+				case "FK":
+					foreignKeys[f.ID] = true
+				case "UK":
+					f.Unique = true
+				}
+			}
+			d.Tables[current].Fields = append(d.Tables[current].Fields, f)
 			continue
 		}
-		srcName, tgtName := m[1], m[2]
-		srcIdx, srcOk := tableByName[srcName]
-		tgtIdx, tgtOk := tableByName[tgtName]
-		if !srcOk || !tgtOk || srcIdx < 0 || srcIdx >= len(d.Tables) || tgtIdx < 0 || tgtIdx >= len(d.Tables) {
+		if m := mermaidEntityBlock.FindStringSubmatch(line); m != nil {
+			name := unquoteName(m[1])
+			if strings.EqualFold(name, "erDiagram") {
+				continue
+			}
+			if i, ok := tableByName[name]; ok {
+				current = i // Mermaid merges repeated blocks for one entity.
+			} else {
+				d.Tables = append(d.Tables, schema.Table{ID: idGen.table(), Name: name, Fields: []schema.Field{}})
+				current = len(d.Tables) - 1
+				tableByName[name] = current
+			}
+			if m[2] != "" {
+				current = -1
+			}
 			continue
 		}
-		srcT := &d.Tables[srcIdx]
-		tgtT := &d.Tables[tgtIdx]
-		var srcFID, tgtFID string
-		if len(srcT.Fields) > 0 {
-			srcFID = srcT.Fields[0].ID
-		}
-		if len(tgtT.Fields) > 0 {
-			tgtFID = tgtT.Fields[0].ID
-		}
-		if srcFID == "" || tgtFID == "" {
+		var r erdRel
+		if m := erdRelSymbols.FindStringSubmatch(line); m != nil {
+			r = erdRel{left: m[1], leftEnd: crowsFootEnds[m[2]], rightEnd: crowsFootEnds[m[4]], right: m[5], label: m[6]}
+		} else if m := erdRelWords.FindStringSubmatch(line); m != nil {
+			r = erdRel{left: m[1], leftEnd: mermaidWordEnds[strings.ToLower(m[2])], rightEnd: mermaidWordEnds[strings.ToLower(m[4])], right: m[5], label: m[6]}
+		} else {
 			continue
 		}
-		label := ""
-		if len(m) > 3 {
-			label = strings.TrimSpace(m[3])
+		r.left, r.right = unquoteName(r.left), unquoteName(r.right)
+		r.label = unquoteName(strings.TrimSpace(r.label))
+		for _, name := range []string{r.left, r.right} {
+			// Entities may appear only in relationships.
+			if _, ok := tableByName[name]; !ok {
+				d.Tables = append(d.Tables, schema.Table{ID: idGen.table(), Name: name, Fields: []schema.Field{}})
+				tableByName[name] = len(d.Tables) - 1
+			}
 		}
-		d.Relationships = append(d.Relationships, schema.Relationship{
-			ID:             idGen.rel(),
-			SourceTableID:  srcT.ID,
-			SourceFieldID:  srcFID,
-			TargetTableID:  tgtT.ID,
-			TargetFieldID: tgtFID,
-			Label:         label,
-		})
+		rels = append(rels, r)
 	}
 
+	resolveERDRelationships(&d, tableByName, rels, foreignKeys, idGen)
+
 	// Grid layout
 	cols := 3
 	for i := range d.Tables {
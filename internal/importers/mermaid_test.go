@@ -1,7 +1,10 @@
 package importers
 
 import (
+	"strings"
 	"testing"
+
+	"schemastudio/internal/schema"
 )
 
 func TestParseMermaid_Simple(t *testing.T) {
@@ -28,3 +31,120 @@ erDiagram
 		t.Errorf("expected 1 relationship, got %d", len(d.Relationships))
 	}
 }
+
+func TestParseMermaid_KeysCommentsAndCardinality(t *testing.T) {
+	mm := `
+erDiagram
+    %% orders reference customers
+    customers {
+        int id PK "Surrogate key"
+        varchar(255) email UK
+    }
+    orders {
+        int id PK
+        int customer_id FK "Who ordered"
+        int billing_id FK
+    }
+    "order lines" {
+        int order_id PK, FK
+        int line_no PK
+    }
+    orders }o..|| customers : "placed by"
+    orders ||--|{ "order lines" : contains
+    CUSTOMER only one to zero or more ORDER : places
+    customers |o..o| orders : billing
+`
+	d, err := ParseMermaid(mm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Tables) != 5 {
+		t.Fatalf("expected 5 tables, got %d", len(d.Tables))
+	}
+	customers, orders, lines := d.Tables[0], d.Tables[1], d.Tables[2]
+	if f := customers.Fields[0]; !f.PrimaryKey || f.Type != "integer" || f.Comment != "Surrogate key" {
+		t.Errorf("customers.id = %+v", f)
+	}
+	if f := customers.Fields[1]; !f.Unique || f.Type != "string" || f.Length == nil || *f.Length != 255 {
+		t.Errorf("customers.email = %+v", f)
+	}
+	if f := orders.Fields[1]; f.Comment != "Who ordered" {
+		t.Errorf("orders.customer_id = %+v", f)
+	}
+	if lines.Name != "order lines" || !lines.Fields[0].PrimaryKey || !lines.Fields[1].PrimaryKey {
+		t.Errorf("order lines = %+v", lines)
+	}
+	if len(d.Relationships) != 3 {
+		t.Fatalf("expected 3 relationships, got %+v", d.Relationships)
+	}
+	// The customer is the parent even though it is written on the right.
+	placed := d.Relationships[0]
+	if placed.SourceTableID != customers.ID || placed.TargetFieldID != orders.Fields[1].ID ||
+		placed.Cardinality != "1-to-0/many" || placed.Label != "placed by" {
+		t.Errorf("placed by = %+v", placed)
+	}
+	contains := d.Relationships[1]
+	if contains.SourceTableID != orders.ID || contains.TargetFieldID != lines.Fields[0].ID || contains.Cardinality != "1-to-many" {
+		t.Errorf("contains = %+v", contains)
+	}
+	// billing_id is not named after customers, so it is the first unused FK column.
+	billing := d.Relationships[2]
+	if billing.SourceTableID != customers.ID || billing.TargetFieldID != orders.Fields[2].ID || billing.Cardinality != "0/1-to-0/1" {
+		t.Errorf("billing = %+v", billing)
+	}
+}
+
+func TestParseMermaid_RoundTrip(t *testing.T) {
+	length := 100
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "teams", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "name", Type: "string", Length: &length, Unique: true, Comment: `The "display" name`},
+			}},
+			{ID: "t2", Name: "members", Fields: []schema.Field{
+				{ID: "f3", Name: "team_id", Type: "integer", PrimaryKey: true},
+				{ID: "f4", Name: "user_id", Type: "integer", PrimaryKey: true},
+				{ID: "f5", Name: "mentor", Type: "integer"},
+			}},
+			{ID: "t3", Name: "users", Fields: []schema.Field{
+				{ID: "f6", Name: "id", Type: "integer", PrimaryKey: true},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f3", Cardinality: "1-to-many", Label: "has"},
+			{ID: "r2", SourceTableID: "t3", SourceFieldID: "f6", TargetTableID: "t2", TargetFieldID: "f4", Cardinality: "many-to-1"},
+			{ID: "r3", SourceTableID: "t3", SourceFieldID: "f6", TargetTableID: "t2", TargetFieldID: "f5", Name: "fk_mentor"},
+		},
+	}
+	out := schema.ToMermaid(d)
+	for _, want := range []string{
+		`        string(100) name UK "The 'display' name"`,
+		"        integer team_id PK, FK\n",
+		"        integer mentor FK\n",
+		`    teams ||--|{ members : "has"`,
+		`    users }|--|| members : ""`,
+		`    users ||..o{ members : "fk_mentor"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	back, err := ParseMermaid(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Relationships) != 3 {
+		t.Fatalf("expected 3 relationships, got %+v", back.Relationships)
+	}
+	for i, r := range back.Relationships {
+		orig := d.Relationships[i]
+		if r.SourceFieldID != orig.SourceFieldID || r.TargetFieldID != orig.TargetFieldID {
+			t.Errorf("relationship %d joins %s -> %s, want %s -> %s", i, r.SourceFieldID, r.TargetFieldID, orig.SourceFieldID, orig.TargetFieldID)
+		}
+	}
+	if again := schema.ToMermaid(back); again != out {
+		t.Errorf("Mermaid changed on round trip:\n%s\nvs\n%s", out, again)
+	}
+}
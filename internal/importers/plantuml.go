@@ -0,0 +1,163 @@
+package importers
+
+import (
+	"regexp"
+	"strings"
+
+	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
+)
+
+const plantUMLName = `("[^"]*"|[A-Za-z_][A-Za-z0-9_.]*)`
+
+var (
+	// entity "name" as alias <<stereotype>> #color {, entity alias as "name" {, entity name
+	plantUMLEntity    = regexp.MustCompile(`^entity\s+` + plantUMLName + `(?:\s+as\s+` + plantUMLName + `)?((?:\s+<<[^>]*>>|\s+#\S+)*)\s*(\{\s*(\})?)?$`)
+	plantUMLField     = regexp.MustCompile(`^(\*)?\s*[+#~-]?\s*("[^"]*"|[^\s:<]+)\s*(?::\s*([^<]*?))?\s*((?:<<[^>]*>>\s*)*)$`)
+	plantUMLSeparator = regexp.MustCompile(`^(--|\.\.|==|__)`)
+	// e0 ||--o{ e1 : label, with optional direction or style inside the line: |o-left-o{, ||-[#red]-|{
+	plantUMLRel = regexp.MustCompile(`^` + plantUMLName + `\s+(\|o|\|\||\}o|\}\|)(?:[-.]+(?:\w+|\[[^\]]*\]))?[-.]+(o\||\|\||o\{|\|\{)\s+` + plantUMLName + `\s*(?::\s*(.*))?$`)
+)
+
+// ParsePlantUML parses a PlantUML entity diagram (as written by schema.ToPlantUML) and
+// returns a Diagram. Fields above an entity's "--" separator form its primary key,
+// fields marked "*" are mandatory (not null), and <<PK>>, <<FK>> and <<unique>>
+// stereotypes are honored. Crow's foot arrows become relationships with the matching
+// cardinality; their columns are matched as described at resolveERDRelationships.
+// Positions are assigned on a grid.
+func ParsePlantUML(src string) (schema.Diagram, error) {
+	d := schema.NewDiagram()
+	tableByName := make(map[string]int) // name and alias -> index in d.Tables
+	idGen := newIDGen()
+	foreignKeys := make(map[string]bool) // field IDs marked <<FK>>
+	var rels []erdRel
+
+	current, separated, inComment := -1, false, false
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if inComment {
+			if strings.Contains(line, "'/") {
+				inComment = false
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "/'") {
+			inComment = !strings.Contains(line[2:], "'/")
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "'") {
+			continue
+		}
+		if current >= 0 {
+			switch {
+			case line == "}":
+				current = -1
+			case plantUMLSeparator.MatchString(line):
+				separated = true
+			default:
+				if f, fk, ok := plantUMLAttribute(line, !separated, idGen); ok {
+					if fk {
+						foreignKeys[f.ID] = true
+					}
+					d.Tables[current].Fields = append(d.Tables[current].Fields, f)
+				}
+			}
+			continue
+		}
+		if m := plantUMLEntity.FindStringSubmatch(line); m != nil {
+			name, alias := unquoteName(m[1]), unquoteName(m[2])
+			if strings.HasPrefix(m[2], `"`) {
+				// entity alias as "name"
+				name, alias = alias, name
+			}
+			i, ok := tableByName[name]
+			if !ok {
+				d.Tables = append(d.Tables, schema.Table{ID: idGen.table(), Name: name, Fields: []schema.Field{}})
+				i = len(d.Tables) - 1
+				tableByName[name] = i
+			}
+			if alias != "" {
+				tableByName[alias] = i
+			}
+			if m[4] != "" && m[5] == "" {
+				current, separated = i, false
+			}
+			continue
+		}
+		if m := plantUMLRel.FindStringSubmatch(line); m != nil {
+			r := erdRel{
+				left: unquoteName(m[1]), leftEnd: crowsFootEnds[m[2]],
+				rightEnd: crowsFootEnds[m[3]], right: unquoteName(m[4]),
+				label: unquoteName(strings.TrimSpace(m[5])),
+			}
+			for _, name := range []string{r.left, r.right} {
+				// Entities may appear only in relationships.
+				if _, ok := tableByName[name]; !ok {
+					d.Tables = append(d.Tables, schema.Table{ID: idGen.table(), Name: name, Fields: []schema.Field{}})
+					tableByName[name] = len(d.Tables) - 1
+				}
+			}
+			rels = append(rels, r)
+		}
+	}
+
+	// Relationships name entities by alias; resolve them to the table names.
+	for i := range rels {
+		rels[i].left = d.Tables[tableByName[rels[i].left]].Name
+		rels[i].right = d.Tables[tableByName[rels[i].right]].Name
+	}
+	byTable := make(map[string]int, len(d.Tables))
+	for i, t := range d.Tables {
+		byTable[t.Name] = i
+	}
+	resolveERDRelationships(&d, byTable, rels, foreignKeys, idGen)
+
+	// Grid layout
+	cols := 3
+	for i := range d.Tables {
+		row, col := i/cols, i%cols
+		d.Tables[i].X = float64(col * 320)
+		d.Tables[i].Y = float64(row * 240)
+	}
+	return d, nil
+}
+
+// plantUMLAttribute parses a field line such as "* id : integer <<generated>>". Fields
+// above the separator (aboveSeparator) are the primary key. It reports whether the
+// field is marked <<FK>>, and false if the line is not a field.
+func plantUMLAttribute(line string, aboveSeparator bool, ids *idGen) (schema.Field, bool, bool) {
+	m := plantUMLField.FindStringSubmatch(line)
+	if m == nil {
+		return schema.Field{}, false, false
+	}
+	rawType := strings.TrimSpace(m[3])
+	if rawType == "" {
+		rawType = "other"
+	}
+	genericType, length, precision, scale := sqlx.NormalizeType(rawType)
+	f := schema.Field{
+		ID:         ids.field(),
+		Name:       unquoteName(m[2]),
+		Type:       genericType,
+		Length:     length,
+		Precision:  precision,
+		Scale:      scale,
+		Nullable:   m[1] == "",
+		PrimaryKey: aboveSeparator,
+	}
+	fk := false
+	for _, st := range strings.Split(strings.NewReplacer("<<", " ", ">>", " ").Replace(m[4]), " ") {
+		switch strings.ToLower(strings.TrimSpace(st)) {
+		case "pk":
+			f.PrimaryKey = true
+		case "fk":
+			fk = true
+		case "unique", "uk", "uq":
+			f.Unique = true
+		}
+	}
+	if f.PrimaryKey {
+		f.Nullable = false
+	}
+	return f, fk, true
+}
@@ -0,0 +1,134 @@
+package importers
+
+import (
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+func TestParsePlantUML(t *testing.T) {
+	src := `@startuml
+' generated elsewhere
+skinparam linetype ortho
+hide circle
+
+entity "customers" as c <<table>> #lightblue {
+  * id : int
+  --
+  * email : varchar(255) <<unique>>
+    nickname : text
+}
+
+entity orders {
+  * id : bigint
+  --
+  * customer_id : int <<FK>>
+    billing_id : int <<FK>>
+    total : numeric(10, 2)
+}
+
+/' order lines are
+   keyed by order '/
+entity "order lines" as ol {
+  * order_id : bigint <<FK>>
+  * line_no : int
+  ..
+  sku
+}
+
+c ||--o{ orders : placed by
+orders ||-down-|{ ol : contains
+c |o..o| orders
+@enduml
+`
+	d, err := ParsePlantUML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Tables) != 3 {
+		t.Fatalf("expected 3 tables, got %+v", d.Tables)
+	}
+	customers, orders, lines := d.Tables[0], d.Tables[1], d.Tables[2]
+	if customers.Name != "customers" || lines.Name != "order lines" {
+		t.Errorf("table names = %q, %q", customers.Name, lines.Name)
+	}
+	if f := customers.Fields[0]; !f.PrimaryKey || f.Nullable || f.Type != "integer" {
+		t.Errorf("customers.id = %+v", f)
+	}
+	if f := customers.Fields[1]; f.PrimaryKey || f.Nullable || !f.Unique || f.Length == nil || *f.Length != 255 {
+		t.Errorf("customers.email = %+v", f)
+	}
+	if f := customers.Fields[2]; !f.Nullable {
+		t.Errorf("customers.nickname = %+v", f)
+	}
+	if f := orders.Fields[3]; f.Type != "numeric" || f.Precision == nil || *f.Precision != 10 || f.Scale == nil || *f.Scale != 2 {
+		t.Errorf("orders.total = %+v", f)
+	}
+	if !lines.Fields[0].PrimaryKey || !lines.Fields[1].PrimaryKey || lines.Fields[2].PrimaryKey || lines.Fields[2].Type != "other" {
+		t.Errorf("order lines = %+v", lines.Fields)
+	}
+	if len(d.Relationships) != 3 {
+		t.Fatalf("expected 3 relationships, got %+v", d.Relationships)
+	}
+	placed := d.Relationships[0]
+	if placed.SourceTableID != customers.ID || placed.TargetFieldID != orders.Fields[1].ID ||
+		placed.Cardinality != "1-to-0/many" || placed.Label != "placed by" {
+		t.Errorf("placed by = %+v", placed)
+	}
+	contains := d.Relationships[1]
+	if contains.SourceTableID != orders.ID || contains.TargetFieldID != lines.Fields[0].ID || contains.Cardinality != "1-to-many" {
+		t.Errorf("contains = %+v", contains)
+	}
+	billing := d.Relationships[2]
+	if billing.SourceTableID != customers.ID || billing.TargetFieldID != orders.Fields[2].ID || billing.Cardinality != "0/1-to-0/1" {
+		t.Errorf("billing = %+v", billing)
+	}
+}
+
+func TestParsePlantUML_RoundTrip(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "teams", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "name", Type: "string"},
+			}},
+			{ID: "t2", Name: "team members", Fields: []schema.Field{
+				{ID: "f3", Name: "team_id", Type: "integer", PrimaryKey: true},
+				{ID: "f4", Name: "user_id", Type: "integer", PrimaryKey: true},
+				{ID: "f5", Name: "role", Type: "string", Nullable: true},
+			}},
+			{ID: "t3", Name: "users", Fields: []schema.Field{
+				{ID: "f6", Name: "id", Type: "integer", PrimaryKey: true},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f3", Cardinality: "1-to-many", Label: "has"},
+			{ID: "r2", SourceTableID: "t3", SourceFieldID: "f6", TargetTableID: "t2", TargetFieldID: "f4", Cardinality: "1-to-0/many"},
+		},
+	}
+	out := schema.ToPlantUML(d)
+	back, err := ParsePlantUML(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Tables) != 3 || back.Tables[1].Name != "team members" {
+		t.Fatalf("tables = %+v", back.Tables)
+	}
+	if f := back.Tables[1].Fields[2]; f.Name != "role" || !f.Nullable || f.PrimaryKey {
+		t.Errorf("role = %+v", f)
+	}
+	if len(back.Relationships) != 2 {
+		t.Fatalf("expected 2 relationships, got %+v", back.Relationships)
+	}
+	for i, r := range back.Relationships {
+		orig := d.Relationships[i]
+		if r.SourceFieldID != orig.SourceFieldID || r.TargetFieldID != orig.TargetFieldID || r.Cardinality != orig.Cardinality {
+			t.Errorf("relationship %d = %s -> %s (%s), want %s -> %s (%s)", i,
+				r.SourceFieldID, r.TargetFieldID, r.Cardinality, orig.SourceFieldID, orig.TargetFieldID, orig.Cardinality)
+		}
+	}
+	if again := schema.ToPlantUML(back); again != out {
+		t.Errorf("PlantUML changed on round trip:\n%s\nvs\n%s", out, again)
+	}
+}
@@ -0,0 +1,446 @@
+package importers
+
+import (
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// prismaProviders maps Prisma datasource providers to the dialect that native types
+// (@db.VarChar(255)) are kept under as type overrides.
+var prismaProviders = map[string]string{
+	"postgresql":  "postgres",
+	"postgres":    "postgres",
+	"cockroachdb": "postgres",
+	"mysql":       "mysql",
+	"sqlserver":   "mssql",
+}
+
+// prismaScalarTypes maps Prisma scalar types to the SQL type they are normalized from.
+var prismaScalarTypes = map[string]string{
+	"String":   "string",
+	"Int":      "integer",
+	"BigInt":   "bigint",
+	"Float":    "float",
+	"Decimal":  "decimal",
+	"Boolean":  "boolean",
+	"DateTime": "timestamp",
+	"Json":     "json",
+	"Bytes":    "bytes",
+}
+
+// prismaBlock is a top-level block: datasource, generator, model, view, enum or type.
+type prismaBlock struct {
+	kind, name string
+	doc        []string
+	body       [][]schemaToken
+	line       int
+}
+
+// prismaModelInfo holds the database names of a model, known before any table is built
+// so relations can refer to models defined later.
+type prismaModelInfo struct {
+	table   string
+	columns map[string]string // field name -> column name
+}
+
+// prismaAttr is an attribute such as @id, @default(now()) or @@index([a, b]), with the
+// tokens between its parentheses.
+type prismaAttr struct {
+	name string // without @ or @@; native types keep their prefix, e.g. "db.VarChar"
+	args []schemaToken
+	line int
+}
+
+// ParsePrisma parses a Prisma schema and returns a TableCatalog. Models and views become
+// tables named after their @@map, fields become columns named after their @map, and
+// relation fields with fields/references become relationships. Enum fields are strings
+// with a CHECK listing the values. Native types (@db.VarChar(255)) are kept as type
+// overrides for the datasource provider's dialect. /// comments become table
+// descriptions and column comments. Constructs it cannot represent are reported in
+// catalog.Warnings. ImportSource is left empty; the caller should set it.
+func ParsePrisma(src string) (schema.TableCatalog, error) {
+	toks, err := tokenizeSchema(src)
+	if err != nil {
+		return schema.TableCatalog{}, err
+	}
+	blocks := prismaBlocks(schemaLines(toks))
+
+	dialect := ""
+	models := make(map[string]*prismaModelInfo)
+	enums := make(map[string][]string)
+	for _, blk := range blocks {
+		switch blk.kind {
+		case "datasource":
+			for _, l := range blk.body {
+				if len(l) >= 3 && l[0].isWord("provider") && l[1].isPunct("=") && l[2].kind == stString {
+					dialect = prismaProviders[strings.ToLower(l[2].text)]
+				}
+			}
+		case "enum":
+			enums[blk.name] = prismaEnumValues(blk)
+		case "model", "view":
+			info := &prismaModelInfo{table: blk.name, columns: make(map[string]string)}
+			for _, l := range blk.body {
+				if l[0].isPunct("@@") {
+					if a := prismaAttrs(l); len(a) > 0 && a[0].name == "map" && len(a[0].args) > 0 {
+						info.table = a[0].args[0].text
+					}
+					continue
+				}
+				if l[0].kind != stIdent {
+					continue
+				}
+				info.columns[l[0].text] = l[0].text
+				for _, a := range prismaAttrs(l) {
+					if a.name == "map" && len(a.args) > 0 {
+						info.columns[l[0].text] = a.args[0].text
+					}
+				}
+			}
+			models[blk.name] = info
+		}
+	}
+
+	b := newDDLBuilder(dialect)
+	for _, blk := range blocks {
+		switch blk.kind {
+		case "datasource", "generator", "enum":
+		case "model", "view":
+			prismaModel(b, blk, models, enums)
+		default:
+			b.warn(blk.line, "%s %s not imported", blk.kind, blk.name)
+		}
+	}
+	return b.catalog(), nil
+}
+
+// prismaBlocks groups lines into top-level blocks, attaching the /// comments before
+// each block and dropping /// lines inside blocks into the following field line.
+func prismaBlocks(lines [][]schemaToken) []prismaBlock {
+	var blocks []prismaBlock
+	var doc []string
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		if l[0].kind == stDoc {
+			doc = append(doc, l[0].text)
+			continue
+		}
+		if len(l) < 3 || !l[len(l)-1].isPunct("{") || l[0].kind != stIdent {
+			doc = nil
+			continue
+		}
+		blk := prismaBlock{kind: l[0].text, name: l[1].text, doc: doc, line: l[0].line}
+		doc = nil
+		var pending []schemaToken
+		for i++; i < len(lines) && !lines[i][0].isPunct("}"); i++ {
+			if lines[i][0].kind == stDoc {
+				pending = append(pending, lines[i]...)
+				continue
+			}
+			blk.body = append(blk.body, append(lines[i], pending...))
+			pending = nil
+		}
+		blocks = append(blocks, blk)
+	}
+	return blocks
+}
+
+// prismaEnumValues returns an enum's database values: each value's @map, or its name.
+func prismaEnumValues(blk prismaBlock) []string {
+	var values []string
+	for _, l := range blk.body {
+		if l[0].kind != stIdent {
+			continue
+		}
+		v := l[0].text
+		for _, a := range prismaAttrs(l) {
+			if a.name == "map" && len(a.args) > 0 {
+				v = a.args[0].text
+			}
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// prismaAttrs returns the @ and @@ attributes on a line.
+func prismaAttrs(l []schemaToken) []prismaAttr {
+	var attrs []prismaAttr
+	for i := 0; i < len(l); i++ {
+		if !(l[i].isPunct("@") || l[i].isPunct("@@")) || i+1 >= len(l) || l[i+1].kind != stIdent {
+			continue
+		}
+		a := prismaAttr{name: l[i+1].text, line: l[i].line}
+		i += 2
+		for i+1 < len(l) && l[i].isPunct(".") && l[i+1].kind == stIdent {
+			a.name += "." + l[i+1].text
+			i += 2
+		}
+		if i < len(l) && l[i].isPunct("(") {
+			args, next, _ := bracketed(l, i)
+			a.args = args
+			i = next
+		}
+		i--
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+// prismaNamedArg returns the value tokens of name: value among an attribute's
+// arguments, or of the first positional argument when name is "".
+func prismaNamedArg(args []schemaToken, name string) []schemaToken {
+	for _, part := range splitSchemaList(args) {
+		named := len(part) >= 2 && part[0].kind == stIdent && part[1].isPunct(":")
+		switch {
+		case name == "" && !named:
+			return part
+		case named && part[0].text == name:
+			return part[2:]
+		}
+	}
+	return nil
+}
+
+// prismaFieldList reads a [a, b(sort: Desc)] list of field names.
+func prismaFieldList(toks []schemaToken) (names []string, desc []bool) {
+	if len(toks) == 0 || !toks[0].isPunct("[") {
+		return nil, nil
+	}
+	inner, _, _ := bracketed(toks, 0)
+	for _, part := range splitSchemaList(inner) {
+		if len(part) == 0 || part[0].kind != stIdent {
+			continue
+		}
+		names = append(names, part[0].text)
+		isDesc := false
+		if len(part) > 1 && part[1].isPunct("(") {
+			args, _, _ := bracketed(part, 1)
+			if s := prismaNamedArg(args, "sort"); len(s) > 0 && s[0].isWord("Desc") {
+				isDesc = true
+			}
+		}
+		desc = append(desc, isDesc)
+	}
+	return names, desc
+}
+
+// prismaModel builds the table for a model or view block.
+func prismaModel(b *ddlBuilder, blk prismaBlock, models map[string]*prismaModelInfo, enums map[string][]string) {
+	info := models[blk.name]
+	t := b.addTable("", info.table, blk.line)
+	if t == nil {
+		return
+	}
+	t.table.Description = strings.Join(blk.doc, "\n")
+	if blk.kind == "view" {
+		t.table.Kind = schema.KindView
+	}
+	columns := func(fields []string, m *prismaModelInfo) []string {
+		cols := make([]string, len(fields))
+		for i, f := range fields {
+			if c, ok := m.columns[f]; ok {
+				cols[i] = c
+			} else {
+				cols[i] = f
+			}
+		}
+		return cols
+	}
+	var blockAttrs []prismaAttr
+	for _, l := range blk.body {
+		if l[0].isPunct("@@") {
+			blockAttrs = append(blockAttrs, prismaAttrs(l)...)
+			continue
+		}
+		if l[0].kind != stIdent || len(l) < 2 || l[1].kind != stIdent {
+			continue
+		}
+		fieldName, typeName, line := l[0].text, l[1].text, l[0].line
+		rest := l[2:]
+		optional, list, native := false, false, false
+		rawType := prismaScalarTypes[typeName]
+		if typeName == "Unsupported" && len(rest) > 0 && rest[0].isPunct("(") {
+			args, next, _ := bracketed(rest, 0)
+			if len(args) > 0 {
+				rawType, native = args[0].text, true
+			}
+			rest = rest[next:]
+		}
+		for len(rest) > 0 && (rest[0].isPunct("?") || rest[0].isPunct("[") || rest[0].isPunct("]")) {
+			optional = optional || rest[0].isPunct("?")
+			list = list || rest[0].isPunct("[")
+			rest = rest[1:]
+		}
+		attrs := prismaAttrs(rest)
+
+		if target, ok := models[typeName]; ok {
+			if list {
+				continue
+			}
+			for _, a := range attrs {
+				if a.name != "relation" {
+					continue
+				}
+				fields, _ := prismaFieldList(prismaNamedArg(a.args, "fields"))
+				refs, _ := prismaFieldList(prismaNamedArg(a.args, "references"))
+				if len(fields) == 0 {
+					continue
+				}
+				fk := ddlForeignKey{table: info.table, cols: columns(fields, info), refTable: target.table, refCols: columns(refs, target), line: line}
+				if m := prismaNamedArg(a.args, "map"); len(m) > 0 {
+					fk.name = m[0].text
+				}
+				b.fks = append(b.fks, fk)
+			}
+			continue
+		}
+
+		column := info.columns[fieldName]
+		values, isEnum := enums[typeName]
+		switch {
+		case isEnum:
+			rawType = "string"
+		case rawType == "":
+			b.warn(line, "field %s.%s has unknown type %s; imported as other", blk.name, fieldName, typeName)
+			rawType = "other"
+		}
+		for _, a := range attrs {
+			if strings.HasPrefix(a.name, "db.") {
+				native = true
+				rawType = strings.TrimPrefix(a.name, "db.")
+				if len(a.args) > 0 {
+					rawType += "(" + schemaText(a.args) + ")"
+				}
+			}
+		}
+		if list {
+			rawType += "[]"
+		}
+		f := b.addField(t, column, rawType, line)
+		if f == nil {
+			continue
+		}
+		if !native {
+			f.TypeOverrides = nil
+		}
+		if list {
+			f.Type, f.Length, f.Precision, f.Scale = "other", nil, nil, nil
+		}
+		f.Nullable = optional || list
+		if isEnum {
+			f.Check = enumCheck(column, values)
+		}
+		var comments []string
+		for _, d := range l {
+			if d.kind == stDoc {
+				comments = append(comments, d.text)
+			}
+		}
+		f.Comment = strings.Join(comments, "\n")
+		for _, a := range attrs {
+			switch a.name {
+			case "id":
+				b.setPrimaryKey(t, []string{column}, line)
+			case "unique":
+				f.Unique = true
+			case "default":
+				prismaDefault(b, f, a, isEnum, blk.name+"."+fieldName)
+			}
+		}
+	}
+
+	for _, a := range blockAttrs {
+		fields, desc := prismaFieldList(prismaNamedArg(a.args, "fields"))
+		if len(fields) == 0 {
+			fields, desc = prismaFieldList(prismaNamedArg(a.args, ""))
+		}
+		switch a.name {
+		case "id":
+			b.setPrimaryKey(t, columns(fields, info), a.line)
+		case "unique", "index":
+			ix := ddlIndex{table: info.table, unique: a.name == "unique", line: a.line}
+			if m := prismaNamedArg(a.args, "map"); len(m) > 0 {
+				ix.name = m[0].text
+			} else if n := prismaNamedArg(a.args, "name"); len(n) > 0 {
+				ix.name = n[0].text
+			}
+			if typ := prismaNamedArg(a.args, "type"); len(typ) > 0 {
+				ix.method = normalizeIndexMethod(typ[0].text)
+			}
+			for i, c := range columns(fields, info) {
+				ix.keys = append(ix.keys, ddlIndexKey{column: c, desc: desc[i]})
+			}
+			if ix.name == "" {
+				ix.name = info.table + "_" + strings.Join(columns(fields, info), "_") + "_idx"
+				if ix.unique {
+					ix.name = strings.TrimSuffix(ix.name, "_idx") + "_key"
+				}
+			}
+			b.indexes = append(b.indexes, ix)
+		case "schema":
+			if len(a.args) > 0 {
+				t.table.Schema = a.args[0].text
+			}
+		case "map", "ignore":
+		default:
+			b.warn(a.line, "@@%s on model %s not imported", a.name, blk.name)
+		}
+	}
+}
+
+// prismaDefault sets a field's default (or identity) from its @default attribute.
+// Defaults Prisma generates in the client (uuid(), cuid()) have no database default.
+func prismaDefault(b *ddlBuilder, f *schema.Field, a prismaAttr, isEnum bool, label string) {
+	arg := prismaNamedArg(a.args, "")
+	if len(arg) == 0 {
+		return
+	}
+	v := arg[0]
+	switch {
+	case v.kind == stIdent && len(arg) > 1 && arg[1].isPunct("("):
+		inner, _, _ := bracketed(arg, 1)
+		switch v.text {
+		case "autoincrement", "sequence":
+			f.Identity = schema.IdentityByDefault
+		case "now":
+			f.Default = "current_timestamp"
+		case "dbgenerated":
+			if len(inner) > 0 && inner[0].kind == stString {
+				f.Default = inner[0].text
+			}
+		case "uuid", "cuid", "nanoid", "ulid", "auto":
+		default:
+			b.warn(a.line, "default %s() on %s not imported", v.text, label)
+		}
+	case v.kind == stString:
+		f.Default = quoteSQLString(v.text)
+	case v.kind == stNumber, v.isWord("true"), v.isWord("false"):
+		f.Default = v.text
+	case v.kind == stIdent && isEnum:
+		f.Default = quoteSQLString(v.text)
+	default:
+		b.warn(a.line, "default on %s not imported", label)
+	}
+}
+
+// enumCheck returns a CHECK expression limiting column to the enum values.
+func enumCheck(column string, values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quoteSQLString(v)
+	}
+	col := column
+	if !isPlainIdent(col) {
+		col = `"` + strings.ReplaceAll(col, `"`, `""`) + `"`
+	}
+	return col + " in (" + strings.Join(quoted, ", ") + ")"
+}
+
+func quoteSQLString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,157 @@
+package importers
+
+import (
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+func TestParsePrisma(t *testing.T) {
+	src := `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+generator client {
+  provider = "prisma-client-js"
+}
+
+/// A customer account.
+model User {
+  id        Int      @id @default(autoincrement())
+  email     String   @unique @db.VarChar(255)
+  /// Shown on the profile page.
+  fullName  String?  @map("full_name")
+  role      Role     @default(USER)
+  createdAt DateTime @default(now()) @map("created_at")
+  posts     Post[]
+
+  @@map("users")
+}
+
+model Post {
+  id       String  @id @default(uuid()) @db.Uuid
+  title    String  @default("Untitled")
+  authorId Int     @map("author_id")
+  author   User    @relation(fields: [authorId], references: [id], map: "posts_author_fk")
+  tags     String[]
+
+  @@index([authorId, title(sort: Desc)])
+  @@schema("blog")
+}
+
+model PostTag {
+  postId String
+  tag    String
+
+  post Post @relation(fields: [postId], references: [id])
+
+  @@id([postId, tag])
+  @@unique([tag, postId], map: "post_tags_tag_post")
+}
+
+enum Role {
+  USER
+  ADMIN @map("admin")
+}
+
+type Address {
+  street String
+}
+`
+	catalog, err := ParsePrisma(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Tables) != 3 {
+		t.Fatalf("expected 3 tables, got %d", len(catalog.Tables))
+	}
+	users, posts, tags := catalog.Tables[0], catalog.Tables[1], catalog.Tables[2]
+	if users.Name != "users" || users.Description != "A customer account." {
+		t.Errorf("users = %s %q", users.Name, users.Description)
+	}
+	id, email, name, role, created := users.Fields[0], users.Fields[1], users.Fields[2], users.Fields[3], users.Fields[4]
+	if !id.PrimaryKey || id.Identity != schema.IdentityByDefault || id.Type != "integer" || id.Nullable {
+		t.Errorf("id = %+v", id)
+	}
+	if email.Type != "string" || *email.Length != 255 || !email.Unique || email.TypeOverrides["postgres"].Type != "varchar(255)" {
+		t.Errorf("email = %+v", email)
+	}
+	if name.Name != "full_name" || !name.Nullable || name.Comment != "Shown on the profile page." || name.TypeOverrides != nil {
+		t.Errorf("full_name = %+v", name)
+	}
+	if role.Type != "string" || role.Check != "role in ('USER', 'admin')" || role.Default != "'USER'" {
+		t.Errorf("role = %+v", role)
+	}
+	if created.Name != "created_at" || created.Type != "timestamp" || created.Default != "current_timestamp" {
+		t.Errorf("created_at = %+v", created)
+	}
+	if len(users.Fields) != 5 {
+		t.Errorf("list relation field should not be a column: %+v", users.Fields)
+	}
+
+	if posts.Name != "Post" || posts.Schema != "blog" {
+		t.Errorf("posts = %s.%s", posts.Schema, posts.Name)
+	}
+	if f := posts.Fields[0]; f.Type != "uuid" || f.Default != "" {
+		t.Errorf("post id = %+v", f)
+	}
+	if f := posts.Fields[1]; f.Default != "'Untitled'" {
+		t.Errorf("title default = %q", f.Default)
+	}
+	if f := posts.Fields[3]; f.Name != "tags" || f.Type != "other" {
+		t.Errorf("tags = %+v", f)
+	}
+	if len(posts.Indexes) != 1 || posts.Indexes[0].Name != "Post_author_id_title_idx" ||
+		posts.Indexes[0].Columns[0].FieldID != posts.Fields[2].ID || !posts.Indexes[0].Columns[1].Desc {
+		t.Errorf("post indexes = %+v", posts.Indexes)
+	}
+
+	if !tags.Fields[0].PrimaryKey || !tags.Fields[1].PrimaryKey {
+		t.Errorf("composite primary key not set: %+v", tags.Fields)
+	}
+	if len(tags.Indexes) != 1 || !tags.Indexes[0].Unique || tags.Indexes[0].Name != "post_tags_tag_post" {
+		t.Errorf("post tag indexes = %+v", tags.Indexes)
+	}
+
+	if len(catalog.Relationships) != 2 {
+		t.Fatalf("expected 2 relationships, got %+v", catalog.Relationships)
+	}
+	r := catalog.Relationships[0]
+	if r.SourceTableID != users.ID || r.SourceFieldID != id.ID || r.TargetTableID != posts.ID ||
+		r.TargetFieldID != posts.Fields[2].ID || r.Name != "posts_author_fk" {
+		t.Errorf("author relationship = %+v", r)
+	}
+	if len(catalog.Warnings) != 1 {
+		t.Errorf("expected a warning for the type block, got %v", catalog.Warnings)
+	}
+}
+
+func TestParsePrisma_Views(t *testing.T) {
+	src := `
+datasource db {
+  provider = "mysql"
+}
+
+view UserInfo {
+  id    Int    @unique
+  email String @db.VarChar(100)
+  score Unsupported("point")?
+}
+`
+	catalog, err := ParsePrisma(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := catalog.Tables[0]
+	if !v.IsView() || len(v.Fields) != 3 {
+		t.Fatalf("view = %+v", v)
+	}
+	if v.Fields[1].TypeOverrides["mysql"].Type != "varchar(100)" {
+		t.Errorf("email overrides = %+v", v.Fields[1].TypeOverrides)
+	}
+	if f := v.Fields[2]; f.TypeOverrides["mysql"].Type != "point" || !f.Nullable {
+		t.Errorf("unsupported field = %+v", f)
+	}
+}
@@ -0,0 +1,267 @@
+package importers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// schemaTokKind classifies tokens of the schema definition languages (Prisma, DBML).
+type schemaTokKind int
+
+const (
+	stIdent   schemaTokKind = iota // name or keyword
+	stString                       // "..." or '...' ('''...''' in DBML); text is unquoted
+	stExpr                         // `...` expression (DBML); text is the expression
+	stNumber                       // number, possibly signed
+	stPunct                        // { } [ ] ( ) , : . = ? @ @@ > < - <> and others
+	stNewline                      // end of line; statements in both languages are line based
+	stDoc                          // /// documentation comment (Prisma); text is the comment
+)
+
+type schemaToken struct {
+	kind  schemaTokKind
+	text  string
+	quote byte // opening quote of a stString
+	line  int
+}
+
+func (t schemaToken) isPunct(p string) bool { return t.kind == stPunct && t.text == p }
+
+// isWord reports whether the token is the identifier w (case-insensitive).
+func (t schemaToken) isWord(w string) bool {
+	return t.kind == stIdent && strings.EqualFold(t.text, w)
+}
+
+// tokenizeSchema splits Prisma or DBML source into tokens. Comments are dropped, except
+// /// comments, which Prisma attaches to the following model or field. Newlines inside
+// brackets or parentheses are dropped too, so a bracketed list may span lines.
+func tokenizeSchema(src string) ([]schemaToken, error) {
+	var toks []schemaToken
+	line, depth := 1, 0
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			if depth == 0 {
+				toks = append(toks, schemaToken{kind: stNewline, line: line})
+			}
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "///"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			toks = append(toks, schemaToken{kind: stDoc, text: strings.TrimSpace(src[i+3 : i+end]), line: line})
+			i += end
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case strings.HasPrefix(src[i:], "'''"):
+			end := strings.Index(src[i+3:], "'''")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			toks = append(toks, schemaToken{kind: stString, text: dedent(src[i+3 : i+3+end]), quote: '\'', line: line})
+			line += strings.Count(src[i:i+6+end], "\n")
+			i += end + 6
+		case c == '"' || c == '\'' || c == '`':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+					switch src[j] {
+					case 'n':
+						b.WriteByte('\n')
+						continue
+					case 't':
+						b.WriteByte('\t')
+						continue
+					}
+				}
+				if src[j] == '\n' {
+					line++
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated %c", line, c)
+			}
+			kind := stString
+			if c == '`' {
+				kind = stExpr
+			}
+			toks = append(toks, schemaToken{kind: kind, text: b.String(), quote: c, line: line})
+			i = j + 1
+		case isDigit(c) || (c == '-' && i+1 < len(src) && isDigit(src[i+1]) && !afterValue(toks)):
+			j := i + 1
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			toks = append(toks, schemaToken{kind: stNumber, text: src[i:j], line: line})
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(src) && isIdentPart(src[j]) {
+				j++
+			}
+			toks = append(toks, schemaToken{kind: stIdent, text: src[i:j], line: line})
+			i = j
+		default:
+			p := string(c)
+			if strings.HasPrefix(src[i:], "@@") || strings.HasPrefix(src[i:], "<>") {
+				p = src[i : i+2]
+			}
+			switch p {
+			case "[", "(":
+				depth++
+			case "]", ")":
+				if depth > 0 {
+					depth--
+				}
+			}
+			toks = append(toks, schemaToken{kind: stPunct, text: p, line: line})
+			i += len(p)
+		}
+	}
+	return toks, nil
+}
+
+// afterValue reports whether the last token ends a value, so a following '-' is an
+// operator (a DBML one-to-one ref) rather than a sign.
+func afterValue(toks []schemaToken) bool {
+	if len(toks) == 0 {
+		return false
+	}
+	last := toks[len(toks)-1]
+	return last.kind == stIdent || last.kind == stNumber || last.isPunct(")")
+}
+
+// dedent removes the indentation shared by the lines of a multi-line string and
+// the blank lines around it.
+func dedent(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// schemaLines splits tokens into lines, dropping empty ones. Braces start and end
+// lines of their own, so "Table t {" yields the line "Table t {" and "}" its own line.
+func schemaLines(toks []schemaToken) [][]schemaToken {
+	var lines [][]schemaToken
+	var cur []schemaToken
+	flush := func() {
+		if len(cur) > 0 {
+			lines = append(lines, cur)
+			cur = nil
+		}
+	}
+	for _, t := range toks {
+		switch {
+		case t.kind == stNewline:
+			flush()
+		case t.isPunct("{"):
+			cur = append(cur, t)
+			flush()
+		case t.isPunct("}"):
+			flush()
+			lines = append(lines, []schemaToken{t})
+		default:
+			cur = append(cur, t)
+		}
+	}
+	flush()
+	return lines
+}
+
+// bracketed returns the tokens inside the group that opens at toks[start] ("(" or "["),
+// and the index just after its closing token, or false if it is not closed.
+func bracketed(toks []schemaToken, start int) ([]schemaToken, int, bool) {
+	open := toks[start].text
+	close := map[string]string{"(": ")", "[": "]", "{": "}"}[open]
+	depth := 0
+	for i := start; i < len(toks); i++ {
+		switch {
+		case toks[i].isPunct(open):
+			depth++
+		case toks[i].isPunct(close):
+			depth--
+			if depth == 0 {
+				return toks[start+1 : i], i + 1, true
+			}
+		}
+	}
+	return nil, len(toks), false
+}
+
+// splitSchemaList splits tokens at top-level commas.
+func splitSchemaList(toks []schemaToken) [][]schemaToken {
+	var parts [][]schemaToken
+	depth, start := 0, 0
+	for i, t := range toks {
+		switch {
+		case t.isPunct("(") || t.isPunct("[") || t.isPunct("{"):
+			depth++
+		case t.isPunct(")") || t.isPunct("]") || t.isPunct("}"):
+			depth--
+		case t.isPunct(",") && depth == 0:
+			parts = append(parts, toks[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(toks) {
+		parts = append(parts, toks[start:])
+	}
+	return parts
+}
+
+// schemaText renders tokens back to text, for types and expressions: "varchar(255)",
+// "decimal(10, 2)".
+func schemaText(toks []schemaToken) string {
+	var b strings.Builder
+	for i, t := range toks {
+		if i > 0 {
+			prev := toks[i-1]
+			if !(prev.isPunct("(") || prev.isPunct(".") || prev.isPunct("[") ||
+				t.isPunct("(") || t.isPunct(")") || t.isPunct(".") || t.isPunct(",") || t.isPunct("[") || t.isPunct("]")) {
+				b.WriteByte(' ')
+			} else if prev.isPunct(",") {
+				b.WriteByte(' ')
+			}
+		}
+		switch t.kind {
+		case stString:
+			b.WriteByte(t.quote)
+			b.WriteString(t.text)
+			b.WriteByte(t.quote)
+		default:
+			b.WriteString(t.text)
+		}
+	}
+	return b.String()
+}
@@ -1,6 +1,12 @@
 package importers
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
+)
 
 func TestParseSQL_Simple(t *testing.T) {
 	sql := `
@@ -29,6 +35,31 @@ CREATE TABLE posts (
 	}
 }
 
+func TestParseSQL_InfersCardinality(t *testing.T) {
+	catalog, err := ParseSQL(`
+CREATE TABLE users (id INT PRIMARY KEY);
+CREATE TABLE groups (id INT PRIMARY KEY);
+CREATE TABLE posts (id INT PRIMARY KEY, author_id INT NOT NULL REFERENCES users (id), editor_id INT REFERENCES users (id));
+CREATE TABLE profiles (user_id INT PRIMARY KEY REFERENCES users (id));
+CREATE TABLE members (
+  user_id INT NOT NULL REFERENCES users (id),
+  group_id INT NOT NULL REFERENCES groups (id),
+  PRIMARY KEY (user_id, group_id)
+);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range catalog.Relationships {
+		got = append(got, r.Cardinality)
+	}
+	want := []string{"1-to-0/many", "0/1-to-0/many", "1-to-0/1", "many-to-many", "many-to-many"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("cardinalities = %v, want %v", got, want)
+	}
+}
+
 func TestParseSQL_Empty(t *testing.T) {
 	catalog, err := ParseSQL("")
 	if err != nil {
@@ -115,3 +146,826 @@ CREATE TABLE contacts (
 		t.Errorf("created_at.Type = %q, want 'timestamp'", tsF.Type)
 	}
 }
+
+func TestParseSQL_PostgresDump(t *testing.T) {
+	sql := `
+-- pg_dump style output
+SET statement_timeout = 0;
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+/* accounts and their line items */
+CREATE TABLE IF NOT EXISTS public."Accounts" (
+  tenant_id integer NOT NULL,
+  id bigint NOT NULL,
+  label character varying(80) DEFAULT 'it''s; fine'::character varying,
+  CONSTRAINT accounts_pk PRIMARY KEY (tenant_id, id)
+);
+
+CREATE TABLE public.line_items (
+  id serial PRIMARY KEY,
+  tenant_id integer NOT NULL,
+  account_id bigint NOT NULL,
+  owner_id integer REFERENCES public.users,
+  note text -- trailing comment, with a comma
+);
+
+CREATE TABLE public.users (
+  id integer NOT NULL
+);
+
+ALTER TABLE ONLY public.users
+  ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.line_items
+  ADD CONSTRAINT line_items_account_fk FOREIGN KEY (tenant_id, account_id) REFERENCES public."Accounts"(tenant_id, id) ON DELETE CASCADE;
+CREATE INDEX line_items_account_idx ON public.line_items USING btree (account_id);
+`
+	catalog, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Tables) != 3 {
+		t.Fatalf("expected 3 tables, got %d", len(catalog.Tables))
+	}
+	accounts, items, users := catalog.Tables[0], catalog.Tables[1], catalog.Tables[2]
+	if accounts.Name != "Accounts" || items.Name != "line_items" || users.Name != "users" {
+		t.Fatalf("unexpected table names %q, %q, %q", accounts.Name, items.Name, users.Name)
+	}
+	if !accounts.Fields[0].PrimaryKey || !accounts.Fields[1].PrimaryKey || accounts.Fields[2].PrimaryKey {
+		t.Errorf("expected composite primary key (tenant_id, id) on Accounts")
+	}
+	if ov := accounts.Fields[2].TypeOverrides["postgres"]; ov.Type != "character varying(80)" {
+		t.Errorf("label override = %q, want 'character varying(80)'", ov.Type)
+	}
+	if !users.Fields[0].PrimaryKey {
+		t.Errorf("users.id should be PK from ALTER TABLE ADD CONSTRAINT")
+	}
+	if len(items.Fields) != 5 {
+		t.Errorf("expected 5 line_items fields, got %d", len(items.Fields))
+	}
+
+	if len(catalog.Relationships) != 2 {
+		t.Fatalf("expected 2 relationships, got %d", len(catalog.Relationships))
+	}
+	// Inline REFERENCES without columns resolves to the (later declared) users primary key.
+	owner := catalog.Relationships[0]
+	if owner.SourceTableID != users.ID || owner.SourceFieldID != users.Fields[0].ID ||
+		owner.TargetTableID != items.ID || owner.TargetFieldID != items.Fields[3].ID {
+		t.Errorf("owner relationship = %+v", owner)
+	}
+	composite := catalog.Relationships[1]
+	if composite.Name != "line_items_account_fk" {
+		t.Errorf("composite.Name = %q", composite.Name)
+	}
+	if composite.SourceTableID != accounts.ID || composite.TargetTableID != items.ID {
+		t.Errorf("composite relationship should go from Accounts to line_items: %+v", composite)
+	}
+	wantSource := []string{accounts.Fields[0].ID, accounts.Fields[1].ID}
+	wantTarget := []string{items.Fields[1].ID, items.Fields[2].ID}
+	if len(composite.SourceFieldIDs) != 2 || composite.SourceFieldIDs[0] != wantSource[0] || composite.SourceFieldIDs[1] != wantSource[1] ||
+		len(composite.TargetFieldIDs) != 2 || composite.TargetFieldIDs[0] != wantTarget[0] || composite.TargetFieldIDs[1] != wantTarget[1] {
+		t.Errorf("composite field IDs = %v -> %v, want %v -> %v", composite.SourceFieldIDs, composite.TargetFieldIDs, wantSource, wantTarget)
+	}
+
+	if d := accounts.Fields[2].Default; d != "'it''s; fine'::character varying" {
+		t.Errorf("label default = %q", d)
+	}
+
+	// The btree method is the default and is not recorded.
+	if len(items.Indexes) != 1 {
+		t.Fatalf("expected 1 line_items index, got %+v", items.Indexes)
+	}
+	if ix := items.Indexes[0]; ix.Name != "line_items_account_idx" || ix.Method != "" || ix.Unique ||
+		len(ix.Columns) != 1 || ix.Columns[0].FieldID != items.Fields[2].ID {
+		t.Errorf("line_items index = %+v", ix)
+	}
+
+	// CREATE EXTENSION and the referential action are reported; SET is not.
+	if len(catalog.Warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", catalog.Warnings)
+	}
+	if w := catalog.Warnings[0]; w.Line != 4 || w.Message != "CREATE EXTENSION statement not imported" {
+		t.Errorf("warning[0] = %+v", w)
+	}
+	if w := catalog.Warnings[1]; w.Line != 29 || w.Message != "ON DELETE CASCADE on foreign key line_items_account_fk not imported" {
+		t.Errorf("warning[1] = %+v", w)
+	}
+}
+
+func TestParseSQL_UnresolvedReference(t *testing.T) {
+	catalog, err := ParseSQL(`CREATE TABLE posts (id int PRIMARY KEY, author_id int REFERENCES authors(id));`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Relationships) != 0 {
+		t.Errorf("expected no relationships, got %d", len(catalog.Relationships))
+	}
+	if len(catalog.Warnings) != 1 {
+		t.Errorf("expected 1 warning for the unknown table, got %v", catalog.Warnings)
+	}
+}
+
+func TestParseSQL_UnterminatedString(t *testing.T) {
+	if _, err := ParseSQL("CREATE TABLE t (c text DEFAULT 'oops);"); err == nil {
+		t.Error("expected an error for an unterminated string literal")
+	}
+}
+
+func TestParseSQLDialect_MySQL(t *testing.T) {
+	sql := "# mysqldump\n" +
+		"DROP TABLE IF EXISTS `orders`;\n" +
+		"CREATE TABLE `users` (\n" +
+		"  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `email` varchar(190) CHARACTER SET utf8mb4 NOT NULL COMMENT 'login, unique',\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `users_email` (`email`)\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4;\n" +
+		"CREATE TABLE `orders` (\n" +
+		"  `id` bigint NOT NULL,\n" +
+		"  `user_id` int(11) unsigned DEFAULT NULL,\n" +
+		"  KEY `orders_user` (`user_id`),\n" +
+		"  CONSTRAINT `orders_user_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE SET NULL\n" +
+		");\n"
+	catalog, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog.SourceDialect != "mysql" {
+		t.Fatalf("SourceDialect = %q, want mysql", catalog.SourceDialect)
+	}
+	if len(catalog.Tables) != 2 || len(catalog.Relationships) != 1 {
+		t.Fatalf("expected 2 tables and 1 relationship, got %d and %d", len(catalog.Tables), len(catalog.Relationships))
+	}
+	users := catalog.Tables[0]
+	id, email := users.Fields[0], users.Fields[1]
+	if !id.PrimaryKey || id.Type != "integer" {
+		t.Errorf("users.id = %+v", id)
+	}
+	if ov, ok := id.TypeOverrides["mysql"]; !ok || ov.Type != "int(11) unsigned" {
+		t.Errorf("users.id overrides = %v, want mysql 'int(11) unsigned'", id.TypeOverrides)
+	}
+	if _, ok := id.TypeOverrides["postgres"]; ok {
+		t.Errorf("MySQL import should not record a postgres override")
+	}
+	if email.Nullable || email.Length == nil || *email.Length != 190 {
+		t.Errorf("users.email = %+v", email)
+	}
+	if id.Identity != schema.IdentityByDefault {
+		t.Errorf("users.id identity = %q, want by_default from AUTO_INCREMENT", id.Identity)
+	}
+	if !email.Unique || email.Comment != "login, unique" {
+		t.Errorf("users.email unique = %v, comment = %q", email.Unique, email.Comment)
+	}
+	if rel := catalog.Relationships[0]; rel.Name != "orders_user_fk" || rel.SourceTableID != users.ID {
+		t.Errorf("relationship = %+v", rel)
+	}
+	if len(users.Indexes) != 0 {
+		t.Errorf("single-column UNIQUE KEY should not become an index: %+v", users.Indexes)
+	}
+	orders := catalog.Tables[1]
+	if len(orders.Indexes) != 1 || orders.Indexes[0].Name != "orders_user" || orders.Indexes[0].Columns[0].FieldID != orders.Fields[1].ID {
+		t.Errorf("orders indexes = %+v", orders.Indexes)
+	}
+	// DROP TABLE, the character sets and the referential action are reported; inline KEY
+	// elements are imported.
+	want := []string{
+		"DROP TABLE statement not imported",
+		"CHARACTER SET utf8mb4 on users.email not imported",
+		"table options of users not imported (CHARSET)",
+		"ON DELETE SET NULL on foreign key orders_user_fk not imported",
+	}
+	if len(catalog.Warnings) != len(want) {
+		t.Fatalf("expected %d warnings, got %v", len(want), catalog.Warnings)
+	}
+	for i, w := range want {
+		if catalog.Warnings[i].Message != w {
+			t.Errorf("warning %d = %q, want %q", i, catalog.Warnings[i].Message, w)
+		}
+	}
+}
+
+func TestParseSQLDialect_MSSQL(t *testing.T) {
+	sql := `SET ANSI_NULLS ON
+GO
+CREATE TABLE [dbo].[Customers](
+	[CustomerId] [int] IDENTITY(1,1) NOT NULL,
+	[Name] [nvarchar](100) NOT NULL,
+	[Notes] [nvarchar](max) NULL,
+ CONSTRAINT [PK_Customers] PRIMARY KEY CLUSTERED ([CustomerId] ASC) WITH (PAD_INDEX = OFF) ON [PRIMARY]
+) ON [PRIMARY]
+GO
+CREATE TABLE [dbo].[Invoices](
+	[InvoiceId] [int] NOT NULL PRIMARY KEY NONCLUSTERED,
+	[CustomerId] [int] NOT NULL
+)
+GO
+ALTER TABLE [dbo].[Invoices] WITH CHECK ADD CONSTRAINT [FK_Invoices_Customers] FOREIGN KEY([CustomerId])
+REFERENCES [dbo].[Customers] ([CustomerId])
+GO
+ALTER TABLE [dbo].[Invoices] CHECK CONSTRAINT [FK_Invoices_Customers]
+GO
+`
+	catalog, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog.SourceDialect != "mssql" {
+		t.Fatalf("SourceDialect = %q, want mssql", catalog.SourceDialect)
+	}
+	if len(catalog.Tables) != 2 || len(catalog.Relationships) != 1 {
+		t.Fatalf("expected 2 tables and 1 relationship, got %d and %d", len(catalog.Tables), len(catalog.Relationships))
+	}
+	customers := catalog.Tables[0]
+	if customers.Name != "Customers" || !customers.Fields[0].PrimaryKey {
+		t.Errorf("customers = %+v", customers)
+	}
+	if ov := customers.Fields[1].TypeOverrides["mssql"]; ov.Type != "nvarchar(100)" {
+		t.Errorf("Name override = %q, want 'nvarchar(100)'", ov.Type)
+	}
+	if !customers.Fields[2].Nullable {
+		t.Errorf("Notes should be nullable")
+	}
+	if !catalog.Tables[1].Fields[0].PrimaryKey {
+		t.Errorf("Invoices.InvoiceId should be PK")
+	}
+	if rel := catalog.Relationships[0]; rel.Name != "FK_Invoices_Customers" {
+		t.Errorf("relationship = %+v", rel)
+	}
+	if customers.Fields[0].Identity != schema.IdentityAlways {
+		t.Errorf("CustomerId identity = %q, want always", customers.Fields[0].Identity)
+	}
+	if len(catalog.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", catalog.Warnings)
+	}
+}
+
+func TestParseSQLDialect_BigQuery(t *testing.T) {
+	sql := "CREATE TABLE IF NOT EXISTS `proj.shop.orders` (\n" +
+		"  id INT64 NOT NULL,\n" +
+		"  tags ARRAY<STRING>,\n" +
+		"  shipping STRUCT<city STRING, zip STRING(10)> OPTIONS(description=\"where, exactly\"),\n" +
+		"  total NUMERIC(12, 2),\n" +
+		"  PRIMARY KEY (id) NOT ENFORCED\n" +
+		")\n" +
+		"PARTITION BY DATE(_PARTITIONTIME);\n"
+	catalog, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog.SourceDialect != "bigquery" {
+		t.Fatalf("SourceDialect = %q, want bigquery", catalog.SourceDialect)
+	}
+	if len(catalog.Tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(catalog.Tables))
+	}
+	orders := catalog.Tables[0]
+	if orders.Name != "orders" || len(orders.Fields) != 4 {
+		t.Fatalf("orders = %+v", orders)
+	}
+	if !orders.Fields[0].PrimaryKey {
+		t.Errorf("id should be PK")
+	}
+	shipping := orders.Fields[2]
+	if shipping.Type != schema.TypeStruct || len(shipping.TypeOverrides) != 0 || len(shipping.Fields) != 2 ||
+		shipping.Fields[1].Name != "zip" || shipping.Fields[1].Length == nil || *shipping.Fields[1].Length != 10 {
+		t.Errorf("shipping = %+v", shipping)
+	}
+	if tags := orders.Fields[1]; tags.Type != "string" || !tags.Repeated || len(tags.TypeOverrides) != 0 {
+		t.Errorf("tags = %+v", tags)
+	}
+	if shipping.Comment != "where, exactly" {
+		t.Errorf("shipping comment = %q", shipping.Comment)
+	}
+	// The dropped project and PARTITION BY are reported.
+	if len(catalog.Warnings) != 2 || catalog.Warnings[0].Message != "project qualifier proj on shop.orders not imported" {
+		t.Errorf("expected 2 warnings, got %v", catalog.Warnings)
+	}
+}
+
+func TestParseSQL_BigQueryNestedTypes(t *testing.T) {
+	sql := "CREATE TABLE `proj.shop.events` (\n" +
+		"  id INT64 NOT NULL,\n" +
+		"  scores ARRAY<FLOAT64>,\n" +
+		"  items ARRAY<STRUCT<sku STRING NOT NULL, qty INT64, price NUMERIC(10, 2)>>,\n" +
+		"  device STRUCT<os STRING OPTIONS(description=\"name, version\"), tags ARRAY<STRING>, geo STRUCT<lat FLOAT64, lng FLOAT64>>,\n" +
+		"  pairs STRUCT<INT64, STRING>\n" +
+		");\n"
+	catalog, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Tables) != 1 || len(catalog.Tables[0].Fields) != 5 {
+		t.Fatalf("tables = %+v", catalog.Tables)
+	}
+	fields := catalog.Tables[0].Fields
+
+	if scores := fields[1]; scores.Type != "float" || !scores.Repeated || scores.TypeOverrides["bigquery"].Type != "float64" {
+		t.Errorf("scores = %+v", scores)
+	}
+	items := fields[2]
+	if items.Type != schema.TypeStruct || !items.Repeated || len(items.Fields) != 3 {
+		t.Fatalf("items = %+v", items)
+	}
+	if sku := items.Fields[0]; sku.Name != "sku" || sku.Type != "string" || sku.Nullable {
+		t.Errorf("items.sku = %+v", sku)
+	}
+	if price := items.Fields[2]; price.Type != "numeric" || price.Precision == nil || *price.Precision != 10 ||
+		price.Scale == nil || *price.Scale != 2 || !price.Nullable {
+		t.Errorf("items.price = %+v", price)
+	}
+	device := fields[3]
+	if device.Type != schema.TypeStruct || device.Repeated || len(device.Fields) != 3 {
+		t.Fatalf("device = %+v", device)
+	}
+	if os := device.Fields[0]; os.Comment != "name, version" {
+		t.Errorf("device.os = %+v", os)
+	}
+	if tags := device.Fields[1]; tags.Type != "string" || !tags.Repeated {
+		t.Errorf("device.tags = %+v", tags)
+	}
+	if geo := device.Fields[2]; geo.Type != schema.TypeStruct || len(geo.Fields) != 2 || geo.Fields[1].Name != "lng" {
+		t.Errorf("device.geo = %+v", geo)
+	}
+	// Sub-fields get IDs like columns do.
+	ids := map[string]bool{}
+	for _, f := range append(append([]schema.Field{}, items.Fields...), device.Fields...) {
+		if f.ID == "" || ids[f.ID] {
+			t.Errorf("sub-field %s has ID %q", f.Name, f.ID)
+		}
+		ids[f.ID] = true
+	}
+	// Unnamed struct fields cannot become sub-fields; the type is kept as written.
+	if pairs := fields[4]; pairs.Type != "other" || pairs.TypeOverrides["bigquery"].Type != "struct<int64, string>" {
+		t.Errorf("pairs = %+v", pairs)
+	}
+	// Only the dropped project is reported.
+	if len(catalog.Warnings) != 1 || catalog.Warnings[0].Message != "project qualifier proj on shop.events not imported" {
+		t.Errorf("warnings = %v", catalog.Warnings)
+	}
+
+	// The nested types export as they were written.
+	for i, want := range []string{
+		"ARRAY<float64>",
+		"ARRAY<STRUCT<sku STRING not null, qty int64, price numeric(10,2)>>",
+	} {
+		if got := sqlx.FieldType("bigquery", fields[i+1]); got != want {
+			t.Errorf("FieldType(%s) = %q, want %q", fields[i+1].Name, got, want)
+		}
+	}
+}
+
+func TestParseSQL_ColumnAttributes(t *testing.T) {
+	sql := `CREATE TABLE orders (
+  id bigint GENERATED ALWAYS AS IDENTITY (START WITH 100) PRIMARY KEY,
+  seq integer DEFAULT nextval('orders_seq_seq'::regclass),
+  code text UNIQUE NOT NULL,
+  qty integer NOT NULL DEFAULT 1 CHECK (qty > 0),
+  discount numeric DEFAULT -0.5,
+  status text DEFAULT 'new',
+  lo integer,
+  hi integer,
+  CONSTRAINT orders_status_ck CHECK (status IN ('new', 'paid')),
+  CONSTRAINT orders_range_ck CHECK (lo < hi),
+  UNIQUE (lo, hi)
+);
+COMMENT ON COLUMN public.orders.status IS E'Workflow state\nsee docs';
+COMMENT ON TABLE orders IS 'Orders';
+`
+	catalog, err := ParseSQLDialect(sql, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := catalog.Tables[0].Fields
+	if f[0].Identity != schema.IdentityAlways || !f[0].PrimaryKey {
+		t.Errorf("id = %+v", f[0])
+	}
+	if f[1].Identity != schema.IdentityByDefault || f[1].Default != "" {
+		t.Errorf("seq: identity %q, default %q; want by_default from nextval", f[1].Identity, f[1].Default)
+	}
+	if !f[2].Unique || f[2].Nullable {
+		t.Errorf("code = %+v", f[2])
+	}
+	if f[3].Default != "1" || f[3].Check != "qty > 0" {
+		t.Errorf("qty: default %q, check %q", f[3].Default, f[3].Check)
+	}
+	if f[4].Default != "-0.5" {
+		t.Errorf("discount default = %q, want -0.5", f[4].Default)
+	}
+	if f[5].Default != "'new'" || f[5].Check != "status IN ('new', 'paid')" || f[5].Comment != "Workflow state\nsee docs" {
+		t.Errorf("status: default %q, check %q, comment %q", f[5].Default, f[5].Check, f[5].Comment)
+	}
+	if f[6].Unique || f[6].Check != "" {
+		t.Errorf("lo should not take the multi-column constraints: %+v", f[6])
+	}
+	// The two-column UNIQUE becomes a unique index.
+	if ix := catalog.Tables[0].Indexes; len(ix) != 1 || !ix[0].Unique || len(ix[0].Columns) != 2 ||
+		ix[0].Columns[0].FieldID != f[6].ID || ix[0].Columns[1].FieldID != f[7].ID {
+		t.Errorf("indexes = %+v", ix)
+	}
+	if d := catalog.Tables[0].Description; d != "Orders" {
+		t.Errorf("description = %q, want Orders", d)
+	}
+	// The two-column CHECK is reported.
+	if len(catalog.Warnings) != 1 {
+		t.Errorf("expected 1 warning, got %v", catalog.Warnings)
+	}
+}
+
+func TestParseSQL_SerialAndDefaultNull(t *testing.T) {
+	sql := `CREATE TABLE events (
+  id serial PRIMARY KEY,
+  seq BIGSERIAL,
+  small smallserial,
+  note text DEFAULT NULL
+);
+`
+	catalog, err := ParseSQLDialect(sql, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := catalog.Tables[0].Fields
+	for i, base := range []string{"", "bigint", "smallint"} {
+		if f[i].Type != "integer" || f[i].Identity != schema.IdentityByDefault || f[i].Nullable || f[i].TypeOverrides["postgres"].Type != base {
+			t.Errorf("%s = %+v, want a not-null by_default identity with override %q", f[i].Name, f[i], base)
+		}
+	}
+	if f[3].Default != "" || !f[3].Nullable {
+		t.Errorf("note = %+v, want nullable without a default", f[3])
+	}
+
+	mysql := "CREATE TABLE `t` (\n" +
+		"  `id` SERIAL,\n" +
+		"  `name` varchar(20) DEFAULT NULL,\n" +
+		"  `state` varchar(10) NOT NULL DEFAULT 'NULL'\n" +
+		");\n"
+	catalog, err = ParseSQLDialect(mysql, "mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f = catalog.Tables[0].Fields
+	if f[0].Identity != schema.IdentityByDefault || !f[0].Unique || f[0].TypeOverrides["mysql"].Type != "bigint unsigned" {
+		t.Errorf("id = %+v", f[0])
+	}
+	if f[1].Default != "" || !f[1].Nullable {
+		t.Errorf("name: default %q, want none", f[1].Default)
+	}
+	if f[2].Default != "'NULL'" {
+		t.Errorf("state: default %q, want the string 'NULL'", f[2].Default)
+	}
+}
+
+func TestParseSQL_CreateIndex(t *testing.T) {
+	sql := `CREATE TABLE users (id int PRIMARY KEY, email text, org_id int, tags text[], created_at timestamp);
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS users_email_lower ON ONLY public.users (lower(email)) WHERE org_id IS NOT NULL;
+CREATE INDEX ON users (org_id, created_at DESC NULLS LAST) INCLUDE (email) WITH (fillfactor = 90);
+CREATE INDEX users_tags_gin ON users USING gin (tags);
+CREATE INDEX users_email_pattern ON users (email text_pattern_ops);
+CREATE INDEX missing_idx ON missing (x);
+CREATE INDEX users_bad_idx ON users (nope);
+`
+	catalog, err := ParseSQLDialect(sql, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := catalog.Tables[0]
+	f := users.Fields
+	if len(users.Indexes) != 4 {
+		t.Fatalf("expected 4 indexes, got %+v", users.Indexes)
+	}
+	lower := users.Indexes[0]
+	if lower.Name != "users_email_lower" || !lower.Unique || lower.Where != "org_id IS NOT NULL" ||
+		lower.Columns[0].Expression != "lower(email)" || lower.Columns[0].FieldID != "" {
+		t.Errorf("expression index = %+v", lower)
+	}
+	composite := users.Indexes[1]
+	if composite.Name != "" || len(composite.Columns) != 2 || composite.Columns[0].FieldID != f[2].ID ||
+		composite.Columns[1].FieldID != f[4].ID || !composite.Columns[1].Desc {
+		t.Errorf("composite index = %+v", composite)
+	}
+	if gin := users.Indexes[2]; gin.Method != "gin" || gin.Columns[0].FieldID != f[3].ID {
+		t.Errorf("gin index = %+v", gin)
+	}
+	if pattern := users.Indexes[3]; pattern.Columns[0].FieldID != f[1].ID {
+		t.Errorf("operator class index = %+v", pattern)
+	}
+	if len(catalog.Warnings) != 4 {
+		t.Fatalf("expected 4 warnings for the index options and the unknown table and column, got %v", catalog.Warnings)
+	}
+	if w := catalog.Warnings[0]; w.Line != 3 || w.Message != "INCLUDE clause of index on users not imported" {
+		t.Errorf("warning[0] = %+v", w)
+	}
+	if w := catalog.Warnings[1]; w.Line != 3 || w.Message != "WITH clause of index on users not imported" {
+		t.Errorf("warning[1] = %+v", w)
+	}
+}
+
+func TestParseSQLDialect_IndexVariants(t *testing.T) {
+	mysql := "CREATE TABLE `posts` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `title` varchar(200),\n" +
+		"  `body` text,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `posts_title_id` (`title`(50), `id`),\n" +
+		"  FULLTEXT KEY `posts_body` (`body`),\n" +
+		"  INDEX `posts_title_hash` USING HASH (`title`)\n" +
+		");\n"
+	catalog, err := ParseSQLDialect(mysql, "mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ix := catalog.Tables[0].Indexes
+	if len(ix) != 3 || !ix[0].Unique || len(ix[0].Columns) != 2 || ix[1].Method != "fulltext" || ix[2].Method != "hash" {
+		t.Errorf("mysql indexes = %+v", ix)
+	}
+
+	mssql := `CREATE TABLE [dbo].[Orders]([OrderId] [int] NOT NULL, [Status] [nvarchar](20) NULL)
+GO
+CREATE UNIQUE NONCLUSTERED INDEX [IX_Orders_Status] ON [dbo].[Orders] ([Status] ASC) WHERE ([Status] IS NOT NULL) WITH (ONLINE = ON) ON [PRIMARY]
+GO
+CREATE CLUSTERED INDEX [CX_Orders] ON [dbo].[Orders] ([OrderId])
+GO
+`
+	catalog, err = ParseSQLDialect(mssql, "mssql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ix = catalog.Tables[0].Indexes
+	if len(ix) != 2 || !ix[0].Unique || ix[0].Method != "" || ix[0].Where != `"Status" IS NOT NULL` || ix[1].Method != "clustered" {
+		t.Errorf("mssql indexes = %+v", ix)
+	}
+	if len(catalog.Warnings) != 1 || catalog.Warnings[0].Message != "WITH clause of index IX_Orders_Status on Orders not imported" {
+		t.Errorf("expected a warning for the index options, got %v", catalog.Warnings)
+	}
+}
+
+func TestParseSQL_ForeignKeyClauses(t *testing.T) {
+	sql := `CREATE TABLE a (id int PRIMARY KEY, b int, c int);
+CREATE TABLE b (
+  id int PRIMARY KEY,
+  a_id int REFERENCES a (id) ON DELETE NO ACTION MATCH SIMPLE NOT DEFERRABLE INITIALLY IMMEDIATE,
+  a_b int,
+  a_c int,
+  CONSTRAINT b_a_fk FOREIGN KEY (a_b, a_c) REFERENCES a (b, c) MATCH FULL ON UPDATE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
+`
+	catalog, err := ParseSQLDialect(sql, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Relationships) != 2 {
+		t.Errorf("expected 2 relationships, got %+v", catalog.Relationships)
+	}
+	// The defaults on a_id lose nothing; each clause on b_a_fk is reported.
+	want := []string{
+		"MATCH FULL on foreign key b_a_fk not imported",
+		"ON UPDATE CASCADE on foreign key b_a_fk not imported",
+		"DEFERRABLE on foreign key b_a_fk not imported",
+		"INITIALLY DEFERRED on foreign key b_a_fk not imported",
+	}
+	if len(catalog.Warnings) != len(want) {
+		t.Fatalf("warnings = %v", catalog.Warnings)
+	}
+	for i, w := range want {
+		if got := catalog.Warnings[i]; got.Message != w || got.Line != 7 {
+			t.Errorf("warning[%d] = %+v, want %q on line 7", i, got, w)
+		}
+	}
+}
+
+func TestParseSQL_MSSQLDefaultsAndDescriptions(t *testing.T) {
+	sql := `CREATE TABLE [dbo].[Items](
+	[ItemId] [int] IDENTITY(1,1) NOT NULL,
+	[Price] [money] NOT NULL CONSTRAINT [DF_Items_Price] DEFAULT ((0)),
+	[Added] [datetime2] NOT NULL
+)
+GO
+ALTER TABLE [dbo].[Items] ADD CONSTRAINT [DF_Items_Added] DEFAULT (getdate()) FOR [Added]
+GO
+EXEC sys.sp_addextendedproperty @name=N'MS_Description', @value=N'Unit price' , @level0type=N'SCHEMA',@level0name=N'dbo', @level1type=N'TABLE',@level1name=N'Items', @level2type=N'COLUMN',@level2name=N'Price'
+GO
+`
+	catalog, err := ParseSQLDialect(sql, "mssql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := catalog.Tables[0].Fields
+	if f[1].Default != "0" || f[1].Comment != "Unit price" {
+		t.Errorf("Price: default %q, comment %q", f[1].Default, f[1].Comment)
+	}
+	if f[2].Default != "getdate()" {
+		t.Errorf("Added default = %q, want getdate()", f[2].Default)
+	}
+	if len(catalog.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", catalog.Warnings)
+	}
+}
+
+func TestParseSQLDialect_Explicit(t *testing.T) {
+	catalog, err := ParseSQLDialect("CREATE TABLE t (id INT PRIMARY KEY);", "mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog.SourceDialect != "mysql" {
+		t.Errorf("SourceDialect = %q, want mysql", catalog.SourceDialect)
+	}
+	if _, err := ParseSQLDialect("", "oracle"); err == nil {
+		t.Error("expected an error for an unsupported dialect")
+	}
+}
+
+func TestParseSQLDialect_TableMetadata(t *testing.T) {
+	cases := []struct {
+		dialect, sql string
+		schema, desc string
+		tags         []string
+		warning      string
+	}{
+		{"postgres", "CREATE TABLE sales.orders (id int);\nCOMMENT ON TABLE sales.orders IS 'Customer orders';",
+			"sales", "Customer orders", nil, ""},
+		{"mysql", "CREATE TABLE `shop`.`orders` (`id` int) ENGINE=InnoDB COMMENT='Customer orders';",
+			"shop", "Customer orders", nil, ""},
+		{"mssql", "CREATE TABLE [sales].[orders] ([id] int)\nGO\n" +
+			"EXEC sp_addextendedproperty N'MS_Description', N'Customer orders', N'SCHEMA', N'sales', N'TABLE', N'orders'\nGO\n",
+			"sales", "Customer orders", nil, ""},
+		{"bigquery", "CREATE TABLE `proj.sales.orders` (id INT64)\n" +
+			"OPTIONS(description=\"Customer orders\", labels=[(\"team\", \"billing\"), (\"pii\", \"\")]);",
+			"sales", "Customer orders", []string{"team:billing", "pii"}, "project qualifier proj on sales.orders not imported"},
+	}
+	for _, c := range cases {
+		catalog, err := ParseSQLDialect(c.sql, c.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(catalog.Tables) != 1 {
+			t.Fatalf("%s: expected 1 table, got %d", c.dialect, len(catalog.Tables))
+		}
+		tbl := catalog.Tables[0]
+		if tbl.Name != "orders" || tbl.Schema != c.schema || tbl.Description != c.desc {
+			t.Errorf("%s: name %q, schema %q, description %q", c.dialect, tbl.Name, tbl.Schema, tbl.Description)
+		}
+		if strings.Join(tbl.Tags, ",") != strings.Join(c.tags, ",") {
+			t.Errorf("%s: tags = %v, want %v", c.dialect, tbl.Tags, c.tags)
+		}
+		var warnings []string
+		for _, w := range catalog.Warnings {
+			warnings = append(warnings, w.Message)
+		}
+		if strings.Join(warnings, "\n") != c.warning {
+			t.Errorf("%s: warnings = %v, want %q", c.dialect, catalog.Warnings, c.warning)
+		}
+	}
+}
+
+func TestParseSQLDialect_SameNameInTwoSchemas(t *testing.T) {
+	sql := `
+CREATE TABLE sales.orders (id integer PRIMARY KEY);
+CREATE TABLE hr.orders (id integer PRIMARY KEY, code text);
+CREATE TABLE people (id integer PRIMARY KEY, order_id integer REFERENCES hr.orders (id));
+CREATE TABLE notes (id integer, person_id integer REFERENCES public.people, order_id integer REFERENCES orders);
+CREATE INDEX orders_code_idx ON hr.orders (code);
+COMMENT ON TABLE hr.orders IS 'HR orders';
+CREATE TABLE public.people (id integer);
+`
+	catalog, err := ParseSQLDialect(sql, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Tables) != 4 {
+		t.Fatalf("expected 4 tables, got %+v", catalog.Tables)
+	}
+	sales, hr, people, notes := catalog.Tables[0], catalog.Tables[1], catalog.Tables[2], catalog.Tables[3]
+	if sales.Schema != "sales" || hr.Schema != "hr" || sales.Description != "" || hr.Description != "HR orders" {
+		t.Errorf("orders tables = %+v, %+v", sales, hr)
+	}
+	if len(sales.Indexes) != 0 || len(hr.Indexes) != 1 {
+		t.Errorf("indexes: sales %+v, hr %+v", sales.Indexes, hr.Indexes)
+	}
+	if len(catalog.Relationships) != 2 {
+		t.Fatalf("relationships = %+v", catalog.Relationships)
+	}
+	if r := catalog.Relationships[0]; r.SourceTableID != hr.ID || r.TargetTableID != people.ID {
+		t.Errorf("people.order_id references %s, want hr.orders", r.SourceTableID)
+	}
+	// An unqualified name is in the default schema, public.
+	if r := catalog.Relationships[1]; r.SourceTableID != people.ID || r.TargetTableID != notes.ID {
+		t.Errorf("notes.person_id = %+v", r)
+	}
+	var messages []string
+	for _, w := range catalog.Warnings {
+		messages = append(messages, w.Message)
+	}
+	want := []string{
+		"table public.people is defined more than once; later definition ignored",
+		"foreign key notes(order_id) references unknown table orders; skipped",
+	}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings = %q, want %q", messages, want)
+	}
+}
+
+func TestParseSQL_Views(t *testing.T) {
+	sql := `
+CREATE TABLE customers (id integer PRIMARY KEY, name varchar(80) NOT NULL);
+CREATE TABLE orders (id integer PRIMARY KEY, customer_id integer REFERENCES customers (id), amount numeric(10,2));
+CREATE OR REPLACE VIEW reporting.customer_orders AS
+  SELECT c.id AS customer_id, c.name, o.amount, o.amount * 2 doubled
+  FROM customers c JOIN orders AS o ON o.customer_id = c.id
+  WITH CHECK OPTION;
+CREATE MATERIALIZED VIEW order_totals (customer, total) AS
+  SELECT customer_id, sum(amount) FROM orders GROUP BY customer_id
+  WITH NO DATA;
+COMMENT ON MATERIALIZED VIEW order_totals IS 'Totals per customer';
+CREATE VIEW everything AS SELECT * FROM orders;
+`
+	catalog, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Tables) != 5 {
+		t.Fatalf("expected 5 tables and views, got %d", len(catalog.Tables))
+	}
+	v := catalog.Tables[2]
+	if v.Name != "customer_orders" || v.Schema != "reporting" || v.Kind != schema.KindView {
+		t.Errorf("view: name %q, schema %q, kind %q", v.Name, v.Schema, v.Kind)
+	}
+	if !strings.HasPrefix(v.Definition, "SELECT c.id AS customer_id") || strings.Contains(v.Definition, "CHECK") {
+		t.Errorf("definition = %q", v.Definition)
+	}
+	want := []struct{ name, typ string }{{"customer_id", "integer"}, {"name", "string"}, {"amount", "numeric"}, {"doubled", "other"}}
+	if len(v.Fields) != len(want) {
+		t.Fatalf("view fields = %+v", v.Fields)
+	}
+	for i, w := range want {
+		if f := v.Fields[i]; f.Name != w.name || f.Type != w.typ {
+			t.Errorf("field %d = %s %s, want %s %s", i, f.Name, f.Type, w.name, w.typ)
+		}
+	}
+	if v.Fields[1].Length == nil || *v.Fields[1].Length != 80 || v.Fields[1].Nullable {
+		t.Errorf("name should copy varchar(80) not null: %+v", v.Fields[1])
+	}
+
+	m := catalog.Tables[3]
+	if m.Kind != schema.KindMaterializedView || m.Description != "Totals per customer" || strings.Contains(m.Definition, "DATA") {
+		t.Errorf("materialized view: kind %q, description %q, definition %q", m.Kind, m.Description, m.Definition)
+	}
+	if len(m.Fields) != 2 || m.Fields[0].Name != "customer" || m.Fields[0].Type != "integer" || m.Fields[1].Name != "total" {
+		t.Errorf("materialized view fields = %+v", m.Fields)
+	}
+
+	// SELECT * cannot be expanded.
+	if len(catalog.Warnings) != 1 || !strings.Contains(catalog.Warnings[0].Message, "everything") {
+		t.Errorf("warnings = %v", catalog.Warnings)
+	}
+}
+
+func TestParseSQLDialect_MySQLView(t *testing.T) {
+	sql := "CREATE TABLE `t` (`id` int NOT NULL);\n" +
+		"CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`localhost` SQL SECURITY DEFINER VIEW `v` AS select `t`.`id` AS `id` from `t`;\n"
+	catalog, err := ParseSQLDialect(sql, "mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Tables) != 2 || catalog.Tables[1].Kind != schema.KindView || len(catalog.Tables[1].Fields) != 1 ||
+		catalog.Tables[1].Fields[0].Type != "integer" {
+		t.Errorf("tables = %+v", catalog.Tables)
+	}
+	if len(catalog.Warnings) != 0 {
+		t.Errorf("warnings = %v", catalog.Warnings)
+	}
+}
+
+func TestParseSQL_DiffIndependentImports(t *testing.T) {
+	// Both scripts number their tables and columns from t1/f1, so IDs say nothing about
+	// which objects correspond: b and c share an ID, as do a.legacy and a.email.
+	from, err := ParseSQL("CREATE TABLE a (id INTEGER PRIMARY KEY, legacy TEXT);\nCREATE TABLE b (id INTEGER PRIMARY KEY);\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := ParseSQL("CREATE TABLE a (id INTEGER PRIMARY KEY, email TEXT);\nCREATE TABLE c (id INTEGER PRIMARY KEY);\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromD := schema.Diagram{Tables: from.Tables, Relationships: from.Relationships}
+	toD := schema.Diagram{Tables: to.Tables, Relationships: to.Relationships}
+	sd := schema.DiffDiagrams(fromD, toD)
+	if len(sd.DroppedTables) != 1 || sd.DroppedTables[0].Name != "b" || len(sd.AddedTables) != 1 || sd.AddedTables[0].Name != "c" {
+		t.Errorf("expected b dropped and c added, got dropped=%+v added=%+v", sd.DroppedTables, sd.AddedTables)
+	}
+	for _, td := range sd.ModifiedTables {
+		if td.OldName != "" || len(td.RenamedFields) != 0 {
+			t.Errorf("unexpected rename %+v", td)
+		}
+	}
+
+	out, err := sqlx.GenerateMigration("postgres", fromD, toD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{"drop table b;", "create table c", "alter table a drop column legacy;", "alter table a add column email"} {
+		if !strings.Contains(out, w) {
+			t.Errorf("expected %q in output: %s", w, out)
+		}
+	}
+	if strings.Contains(out, "rename") {
+		t.Errorf("unexpected rename in output: %s", out)
+	}
+}
@@ -0,0 +1,115 @@
+package importers
+
+import (
+	"regexp"
+	"sort"
+)
+
+// ddlDialect describes the syntax differences between the SQL dialects ParseSQLDialect reads.
+type ddlDialect struct {
+	lex           lexOptions
+	columnStop    map[string]bool // keywords ending a column type, in addition to columnStop
+	indexElements bool            // KEY/INDEX/FULLTEXT/SPATIAL elements in CREATE TABLE (MySQL)
+	goBatches     bool            // GO on its own line separates batches (SQL Server)
+	dottedQuoted  bool            // `project.dataset.table` is one quoted identifier (BigQuery)
+	defaultSchema string          // schema of unqualified table names (public, dbo)
+}
+
+// ddlDialects maps source dialect names (the same keys as the SQL exporters and
+// Field.TypeOverrides) to their syntax.
+var ddlDialects = map[string]ddlDialect{
+	"postgres": {
+		lex:           lexOptions{dollarQuotes: true},
+		defaultSchema: "public",
+	},
+	"mysql": {
+		lex: lexOptions{backtickIdents: true, hashComments: true, backslashEscape: true},
+		columnStop: map[string]bool{
+			"AUTO_INCREMENT": true, "COMMENT": true, "ON": true, "CHARSET": true,
+			"VISIBLE": true, "INVISIBLE": true,
+		},
+		indexElements: true,
+	},
+	"mssql": {
+		lex: lexOptions{bracketIdents: true},
+		columnStop: map[string]bool{
+			"IDENTITY": true, "ROWGUIDCOL": true, "SPARSE": true, "AS": true,
+		},
+		goBatches:     true,
+		defaultSchema: "dbo",
+	},
+	"bigquery": {
+		lex:          lexOptions{backtickIdents: true, hashComments: true, backslashEscape: true, doubleQuoteStr: true, angleTokens: true},
+		columnStop:   map[string]bool{"OPTIONS": true},
+		dottedQuoted: true,
+	},
+}
+
+// SQLImportDialects returns the dialect names accepted by ParseSQLDialect, sorted.
+func SQLImportDialects() []string {
+	names := make([]string, 0, len(ddlDialects))
+	for name := range ddlDialects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dialectHints are patterns characteristic of each dialect's DDL; each match scores a point.
+var dialectHints = map[string][]*regexp.Regexp{
+	"postgres": {
+		regexp.MustCompile(`(?i)\b(BIG|SMALL)?SERIAL\b`),
+		regexp.MustCompile(`::`),
+		regexp.MustCompile(`\$\$`),
+		regexp.MustCompile(`(?i)\b(JSONB|TIMESTAMPTZ|BYTEA)\b`),
+		regexp.MustCompile(`(?i)\bOWNER\s+TO\b`),
+		regexp.MustCompile(`(?i)\bCREATE\s+EXTENSION\b`),
+	},
+	"mysql": {
+		regexp.MustCompile(`(?i)\bAUTO_INCREMENT\b`),
+		regexp.MustCompile(`(?i)\bENGINE\s*=`),
+		regexp.MustCompile(`(?i)\b(DEFAULT\s+)?CHARSET\s*=`),
+		regexp.MustCompile(`(?i)\bUNSIGNED\b`),
+		regexp.MustCompile(`(?i)\b(TINY|MEDIUM|LONG)(INT|TEXT|BLOB)\b`),
+		regexp.MustCompile("`[^`.]+`"),
+	},
+	"mssql": {
+		regexp.MustCompile(`(?i)\[dbo\]`),
+		regexp.MustCompile(`\[[^\]]+\]\.\[[^\]]+\]`),
+		regexp.MustCompile(`(?i)\bIDENTITY\s*\(`),
+		regexp.MustCompile(`(?im)^\s*GO\s*$`),
+		regexp.MustCompile(`(?i)\b(N?VARCHAR|VARBINARY)\s*\(\s*MAX\s*\)`),
+		regexp.MustCompile(`(?i)\b(NON)?CLUSTERED\b`),
+		regexp.MustCompile(`(?i)\b(DATETIME2|UNIQUEIDENTIFIER|NVARCHAR)\b`),
+	},
+	"bigquery": {
+		regexp.MustCompile(`(?i)\b(INT64|FLOAT64|BIGNUMERIC)\b`),
+		regexp.MustCompile(`(?i)\b(STRUCT|ARRAY)\s*<`),
+		regexp.MustCompile(`(?i)\bOPTIONS\s*\(`),
+		regexp.MustCompile("`[\\w-]+\\.[\\w-]+(\\.[\\w-]+)?`"),
+		regexp.MustCompile(`(?i)\bNOT\s+ENFORCED\b`),
+		regexp.MustCompile(`(?i)\bCLUSTER\s+BY\b`),
+	},
+}
+
+// DetectSQLDialect guesses the dialect of a DDL script from characteristic syntax.
+// It returns "postgres" when nothing points elsewhere, or on a tie with PostgreSQL.
+func DetectSQLDialect(sql string) string {
+	best, bestScore := "postgres", dialectScore("postgres", sql)
+	for _, name := range SQLImportDialects() {
+		if score := dialectScore(name, sql); score > bestScore {
+			best, bestScore = name, score
+		}
+	}
+	return best
+}
+
+func dialectScore(dialect, sql string) int {
+	score := 0
+	for _, re := range dialectHints[dialect] {
+		if re.MatchString(sql) {
+			score++
+		}
+	}
+	return score
+}
@@ -0,0 +1,21 @@
+package importers
+
+import "testing"
+
+func TestDetectSQLDialect(t *testing.T) {
+	cases := []struct {
+		sql  string
+		want string
+	}{
+		{"CREATE TABLE users (id INTEGER PRIMARY KEY);", "postgres"},
+		{"CREATE TABLE users (id bigserial PRIMARY KEY, data jsonb);", "postgres"},
+		{"CREATE TABLE `users` (`id` int NOT NULL AUTO_INCREMENT) ENGINE=InnoDB;", "mysql"},
+		{"CREATE TABLE [dbo].[Users] ([Id] INT IDENTITY(1,1) NOT NULL)\nGO\n", "mssql"},
+		{"CREATE TABLE `proj.ds.users` (id INT64, tags ARRAY<STRING>);", "bigquery"},
+	}
+	for _, c := range cases {
+		if got := DetectSQLDialect(c.sql); got != c.want {
+			t.Errorf("DetectSQLDialect(%q) = %q, want %q", c.sql, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package importers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind classifies SQL tokens.
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // keyword or unquoted identifier
+	tokQuoted                  // quoted identifier; text is unquoted
+	tokString                  // string literal; text is the raw literal
+	tokNumber
+	tokPunct // ( ) , ; . [ ] and operators
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// is reports whether the token is the given keyword (case-insensitive). Quoted
+// identifiers never match keywords.
+func (t token) is(keyword string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, keyword)
+}
+
+// isPunct reports whether the token is the given punctuation or operator.
+func (t token) isPunct(p string) bool {
+	return t.kind == tokPunct && t.text == p
+}
+
+// isIdent reports whether the token can name a table, column or constraint.
+func (t token) isIdent() bool {
+	return t.kind == tokWord || t.kind == tokQuoted
+}
+
+// lexOptions enables dialect-specific lexical syntax.
+type lexOptions struct {
+	backtickIdents  bool // `name` (MySQL, BigQuery)
+	bracketIdents   bool // [name] (SQL Server)
+	hashComments    bool // # comment (MySQL, BigQuery)
+	backslashEscape bool // backslash escapes in '...' (MySQL, BigQuery)
+	doubleQuoteStr  bool // "..." is a string, not an identifier (BigQuery)
+	angleTokens     bool // < and > are always single tokens, for ARRAY<STRUCT<...>> (BigQuery)
+	dollarQuotes    bool // $tag$...$tag$ strings (PostgreSQL)
+}
+
+// lexer splits SQL text into tokens, dropping whitespace and comments.
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	opts lexOptions
+}
+
+// tokenize returns the tokens of src, or an error for an unterminated string,
+// quoted identifier or comment.
+func tokenize(src string, opts lexOptions) ([]token, error) {
+	lx := &lexer{src: src, line: 1, opts: opts}
+	var toks []token
+	for {
+		tok, ok, err := lx.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return toks, nil
+		}
+		toks = append(toks, tok)
+	}
+}
+
+func (lx *lexer) next() (token, bool, error) {
+	if err := lx.skipSpaceAndComments(); err != nil {
+		return token{}, false, err
+	}
+	if lx.pos >= len(lx.src) {
+		return token{}, false, nil
+	}
+	start, line := lx.pos, lx.line
+	c := lx.src[lx.pos]
+	switch {
+	case c == '\'' || (c == '"' && lx.opts.doubleQuoteStr):
+		text, err := lx.quoted(c, c, lx.opts.backslashEscape)
+		return token{kind: tokString, text: text, line: line}, err == nil, err
+	case c == '"' || (c == '`' && lx.opts.backtickIdents):
+		text, err := lx.quoted(c, c, false)
+		if err != nil {
+			return token{}, false, err
+		}
+		return token{kind: tokQuoted, text: unquote(text, c), line: line}, true, nil
+	case c == '[' && lx.opts.bracketIdents:
+		text, err := lx.quoted('[', ']', false)
+		if err != nil {
+			return token{}, false, err
+		}
+		return token{kind: tokQuoted, text: unquote(text, ']'), line: line}, true, nil
+	case c == '$' && lx.opts.dollarQuotes && lx.dollarTag() != "":
+		text, err := lx.dollarQuoted()
+		return token{kind: tokString, text: text, line: line}, err == nil, err
+	case isDigit(c) || (c == '.' && lx.pos+1 < len(lx.src) && isDigit(lx.src[lx.pos+1])):
+		lx.pos++
+		for lx.pos < len(lx.src) && (isDigit(lx.src[lx.pos]) || lx.src[lx.pos] == '.' ||
+			lx.src[lx.pos] == 'e' || lx.src[lx.pos] == 'E' ||
+			((lx.src[lx.pos] == '+' || lx.src[lx.pos] == '-') && (lx.src[lx.pos-1] == 'e' || lx.src[lx.pos-1] == 'E'))) {
+			lx.pos++
+		}
+		return token{kind: tokNumber, text: lx.src[start:lx.pos], line: line}, true, nil
+	case isIdentStart(c):
+		for lx.pos < len(lx.src) && isIdentPart(lx.src[lx.pos]) {
+			lx.pos++
+		}
+		word := lx.src[start:lx.pos]
+		// Prefixed string literals: E'...', B'...', X'...', N'...'.
+		if len(word) == 1 && strings.ContainsAny(word, "eEbBxXnN") && lx.pos < len(lx.src) && lx.src[lx.pos] == '\'' {
+			text, err := lx.quoted('\'', '\'', lx.opts.backslashEscape || word == "e" || word == "E")
+			return token{kind: tokString, text: word + text, line: line}, err == nil, err
+		}
+		return token{kind: tokWord, text: word, line: line}, true, nil
+	case strings.IndexByte("(),;[]", c) >= 0:
+		lx.pos++
+		return token{kind: tokPunct, text: string(c), line: line}, true, nil
+	case c == '.':
+		lx.pos++
+		return token{kind: tokPunct, text: ".", line: line}, true, nil
+	case (c == '<' || c == '>') && lx.opts.angleTokens:
+		lx.pos++
+		return token{kind: tokPunct, text: string(c), line: line}, true, nil
+	}
+	// Operators: a run of operator characters (e.g. ::, =, <>, ||). A backtick that
+	// quotes identifiers starts the next token, as in MySQL's `user`@`host`.
+	for lx.pos < len(lx.src) && strings.IndexByte("+-*/<>=~!@#%^&|`?:", lx.src[lx.pos]) >= 0 &&
+		!(lx.pos > start && lx.src[lx.pos] == '`' && lx.opts.backtickIdents) {
+		lx.pos++
+	}
+	if lx.pos == start {
+		lx.pos++
+	}
+	return token{kind: tokPunct, text: lx.src[start:lx.pos], line: line}, true, nil
+}
+
+func (lx *lexer) skipSpaceAndComments() error {
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		switch {
+		case c == '\n':
+			lx.line++
+			lx.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			lx.pos++
+		case strings.HasPrefix(lx.src[lx.pos:], "--") || (c == '#' && lx.opts.hashComments):
+			for lx.pos < len(lx.src) && lx.src[lx.pos] != '\n' {
+				lx.pos++
+			}
+		case strings.HasPrefix(lx.src[lx.pos:], "/*"):
+			// PostgreSQL block comments nest.
+			line, depth := lx.line, 0
+			for {
+				if lx.pos >= len(lx.src) {
+					return fmt.Errorf("line %d: unterminated comment", line)
+				}
+				switch {
+				case strings.HasPrefix(lx.src[lx.pos:], "/*"):
+					depth++
+					lx.pos += 2
+				case strings.HasPrefix(lx.src[lx.pos:], "*/"):
+					depth--
+					lx.pos += 2
+				default:
+					if lx.src[lx.pos] == '\n' {
+						lx.line++
+					}
+					lx.pos++
+				}
+				if depth == 0 {
+					break
+				}
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// quoted consumes a literal opened by open and closed by q, where a doubled q is an
+// escaped q. If backslash is true, a backslash escapes the next character. Returns the
+// raw text including the delimiters.
+func (lx *lexer) quoted(open, q byte, backslash bool) (string, error) {
+	start, line := lx.pos, lx.line
+	lx.pos++
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		switch {
+		case c == '\n':
+			lx.line++
+		case backslash && c == '\\':
+			lx.pos++
+		case c == q:
+			if lx.pos+1 < len(lx.src) && lx.src[lx.pos+1] == q {
+				lx.pos++
+			} else {
+				lx.pos++
+				return lx.src[start:lx.pos], nil
+			}
+		}
+		lx.pos++
+	}
+	if open == '\'' || (open == '"' && lx.opts.doubleQuoteStr) {
+		return "", fmt.Errorf("line %d: unterminated string literal", line)
+	}
+	return "", fmt.Errorf("line %d: unterminated quoted identifier", line)
+}
+
+// dollarTag returns the opening tag of a dollar-quoted string at the current
+// position (e.g. "$$" or "$body$"), or "" if there is none.
+func (lx *lexer) dollarTag() string {
+	i := lx.pos + 1
+	for i < len(lx.src) && lx.src[i] != '$' {
+		if !isIdentPart(lx.src[i]) || lx.src[i] == '$' || (i == lx.pos+1 && isDigit(lx.src[i])) {
+			return ""
+		}
+		i++
+	}
+	if i >= len(lx.src) {
+		return ""
+	}
+	return lx.src[lx.pos : i+1]
+}
+
+func (lx *lexer) dollarQuoted() (string, error) {
+	start, line := lx.pos, lx.line
+	tag := lx.dollarTag()
+	end := strings.Index(lx.src[lx.pos+len(tag):], tag)
+	if end < 0 {
+		return "", fmt.Errorf("line %d: unterminated dollar-quoted string", line)
+	}
+	lx.pos += len(tag) + end + len(tag)
+	text := lx.src[start:lx.pos]
+	lx.line += strings.Count(text, "\n")
+	return text, nil
+}
+
+// unquote strips the delimiters from a quoted identifier and collapses doubled quotes.
+func unquote(s string, q byte) string {
+	if len(s) >= 2 {
+		s = s[1 : len(s)-1]
+	}
+	return strings.ReplaceAll(s, string([]byte{q, q}), string(q))
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '$'
+}
+
+// stringValue returns the value of a string literal token's raw text, handling
+// doubled quotes, E'...' and N'...' prefixes and dollar quoting. backslash reports whether
+// the dialect treats backslash as an escape character in ordinary strings.
+func stringValue(raw string, backslash bool) string {
+	if strings.HasPrefix(raw, "$") {
+		tag := raw[:strings.IndexByte(raw[1:], '$')+2]
+		return strings.TrimSuffix(strings.TrimPrefix(raw, tag), tag)
+	}
+	if len(raw) > 0 && raw[0] != '\'' && raw[0] != '"' {
+		backslash = backslash || raw[0] == 'e' || raw[0] == 'E'
+		raw = raw[1:]
+	}
+	if len(raw) < 2 {
+		return raw
+	}
+	q, body := raw[0], raw[1:len(raw)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case backslash && c == '\\' && i+1 < len(body):
+			i++
+			switch body[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			default:
+				b.WriteByte(body[i])
+			}
+		case c == q && i+1 < len(body) && body[i+1] == q:
+			b.WriteByte(q)
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
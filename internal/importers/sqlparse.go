@@ -0,0 +1,1826 @@
+package importers
+
+import (
+	"fmt"
+	"strings"
+
+	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
+)
+
+// ddlTable is a table under construction, with its fields indexed by lower-case name.
+type ddlTable struct {
+	table  schema.Table
+	fields map[string]int
+}
+
+func (t *ddlTable) field(name string) *schema.Field {
+	if i, ok := t.fields[strings.ToLower(name)]; ok {
+		return &t.table.Fields[i]
+	}
+	return nil
+}
+
+// ddlForeignKey is a foreign key recorded while parsing and resolved once all tables
+// are known, so forward references work.
+type ddlForeignKey struct {
+	name      string
+	schema    string
+	table     string
+	cols      []string
+	refSchema string
+	refTable  string
+	refCols   []string // empty: the referenced table's primary key
+	card      string   // relationship cardinality, when the source states it
+	line      int
+}
+
+// ddlIndex is an index recorded while parsing and resolved once all tables are known,
+// since CREATE INDEX statements usually follow the table they index.
+type ddlIndex struct {
+	name   string
+	schema string
+	table  string
+	unique bool
+	method string
+	where  string
+	keys   []ddlIndexKey
+	line   int
+}
+
+// ddlIndexKey is one index key: a column name or, for expression indexes, SQL text.
+type ddlIndexKey struct {
+	column     string
+	expression string
+	desc       bool
+}
+
+// ddlBuilder accumulates tables, foreign keys, indexes and warnings from DDL statements.
+type ddlBuilder struct {
+	ids           *idGen
+	dialect       string // key for raw type overrides
+	defaultSchema string // schema of unqualified names, if the dialect has one
+	tables        []*ddlTable
+	byName        map[string]*ddlTable // keyed by tableKey
+	fks           []ddlForeignKey
+	indexes       []ddlIndex
+	warnings      []schema.ImportWarning
+}
+
+func newDDLBuilder(dialect string) *ddlBuilder {
+	return &ddlBuilder{
+		ids:           newIDGen(),
+		dialect:       dialect,
+		defaultSchema: ddlDialects[dialect].defaultSchema,
+		byName:        make(map[string]*ddlTable),
+	}
+}
+
+func (b *ddlBuilder) warn(line int, format string, args ...any) {
+	b.warnings = append(b.warnings, schema.ImportWarning{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// tableKey is the key of a table in ddlBuilder.byName: its lower-case schema-qualified
+// name, where a table without a schema has a key of its own.
+func tableKey(schemaName, name string) string {
+	return strings.ToLower(schemaName + "." + name)
+}
+
+// qualifiedName returns schemaName.name, or name alone if there is no schema.
+func qualifiedName(schemaName, name string) string {
+	if schemaName == "" {
+		return name
+	}
+	return schemaName + "." + name
+}
+
+// find returns the table with the given schema and name. Leaving out the default
+// schema names the same table as spelling it out.
+func (b *ddlBuilder) find(schemaName, name string) *ddlTable {
+	if t := b.byName[tableKey(schemaName, name)]; t != nil {
+		return t
+	}
+	switch {
+	case b.defaultSchema == "":
+	case schemaName == "":
+		return b.byName[tableKey(b.defaultSchema, name)]
+	case strings.EqualFold(schemaName, b.defaultSchema):
+		return b.byName[tableKey("", name)]
+	}
+	return nil
+}
+
+// lookup resolves a table reference like find. A reference without a schema that
+// matches no table in the default schema resolves to the only table of that name, if
+// there is just one.
+func (b *ddlBuilder) lookup(schemaName, name string) *ddlTable {
+	if t := b.find(schemaName, name); t != nil || schemaName != "" {
+		return t
+	}
+	var match *ddlTable
+	for _, t := range b.tables {
+		if strings.EqualFold(t.table.Name, name) {
+			if match != nil {
+				return nil
+			}
+			match = t
+		}
+	}
+	return match
+}
+
+// addTable registers a new table, or returns nil (with a warning) for a duplicate name.
+func (b *ddlBuilder) addTable(schemaName, name string, line int) *ddlTable {
+	if b.find(schemaName, name) != nil {
+		b.warn(line, "table %s is defined more than once; later definition ignored", qualifiedName(schemaName, name))
+		return nil
+	}
+	t := &ddlTable{
+		table:  schema.Table{ID: b.ids.table(), Name: name, Schema: schemaName, Fields: []schema.Field{}},
+		fields: make(map[string]int),
+	}
+	b.tables = append(b.tables, t)
+	b.byName[tableKey(schemaName, name)] = t
+	return t
+}
+
+// addField appends a column with the given raw SQL type, typed as setType does.
+func (b *ddlBuilder) addField(t *ddlTable, name, rawType string, line int) *schema.Field {
+	if t.field(name) != nil {
+		b.warn(line, "column %s.%s is defined more than once; later definition ignored", t.table.Name, name)
+		return nil
+	}
+	f := schema.Field{ID: b.ids.field(), Name: name, Nullable: true}
+	b.setType(&f, rawType)
+	t.table.Fields = append(t.table.Fields, f)
+	t.fields[strings.ToLower(name)] = len(t.table.Fields) - 1
+	return &t.table.Fields[len(t.table.Fields)-1]
+}
+
+// setType types f from a raw SQL type. The raw type is kept as an override for the
+// builder's dialect when it differs from the normalized type (and the dialect is known).
+func (b *ddlBuilder) setType(f *schema.Field, rawType string) {
+	f.Type, f.Length, f.Precision, f.Scale = sqlx.NormalizeType(rawType)
+	f.TypeOverrides = nil
+	if rawLower := strings.ToLower(rawType); rawLower != f.Type && b.dialect != "" {
+		f.TypeOverrides = map[string]schema.FieldTypeOverride{b.dialect: {Type: rawLower}}
+	}
+}
+
+// addViewField appends a view column, typed after the table column it selects or
+// "other" when it is computed.
+func (b *ddlBuilder) addViewField(t *ddlTable, c viewColumn, line int) {
+	if t.field(c.name) != nil {
+		b.warn(line, "column %s.%s is defined more than once; later definition ignored", t.table.Name, c.name)
+		return
+	}
+	f := schema.Field{ID: b.ids.field(), Name: c.name, Type: "other", Nullable: true}
+	if src := c.source; src != nil {
+		f.Type, f.Nullable = src.Type, src.Nullable
+		f.Length, f.Precision, f.Scale = src.Length, src.Precision, src.Scale
+		f.TypeOverrides = src.TypeOverrides
+		f.Repeated, f.Fields = src.Repeated, src.Fields
+	}
+	t.table.Fields = append(t.table.Fields, f)
+	t.fields[strings.ToLower(c.name)] = len(t.table.Fields) - 1
+}
+
+// setPrimaryKey marks the named columns as the primary key (and not nullable).
+func (b *ddlBuilder) setPrimaryKey(t *ddlTable, cols []string, line int) {
+	for _, c := range cols {
+		f := t.field(c)
+		if f == nil {
+			b.warn(line, "primary key of %s references unknown column %s", t.table.Name, c)
+			continue
+		}
+		f.PrimaryKey = true
+		f.Nullable = false
+	}
+}
+
+// catalog resolves foreign keys and indexes and lays tables out on a grid.
+func (b *ddlBuilder) catalog() schema.TableCatalog {
+	var catalog schema.TableCatalog
+	for _, fk := range b.fks {
+		if rel, ok := b.resolve(fk); ok {
+			catalog.Relationships = append(catalog.Relationships, rel)
+		}
+	}
+	for _, ix := range b.indexes {
+		b.resolveIndex(ix)
+	}
+	cols := 3
+	for i, t := range b.tables {
+		row, col := i/cols, i%cols
+		t.table.X = float64(col * 320)
+		t.table.Y = float64(row * 240)
+		catalog.Tables = append(catalog.Tables, t.table)
+	}
+	schema.InferCardinalities(catalog.Tables, catalog.Relationships)
+	catalog.Warnings = b.warnings
+	return catalog
+}
+
+// resolve turns a foreign key into a relationship from the referenced (parent) table
+// to the referencing (child) table.
+func (b *ddlBuilder) resolve(fk ddlForeignKey) (schema.Relationship, bool) {
+	child, parent := b.lookup(fk.schema, fk.table), b.lookup(fk.refSchema, fk.refTable)
+	if child == nil {
+		b.warn(fk.line, "foreign key on unknown table %s skipped", qualifiedName(fk.schema, fk.table))
+		return schema.Relationship{}, false
+	}
+	if parent == nil {
+		b.warn(fk.line, "foreign key %s references unknown table %s; skipped", fkLabel(fk), qualifiedName(fk.refSchema, fk.refTable))
+		return schema.Relationship{}, false
+	}
+	refCols := fk.refCols
+	if len(refCols) == 0 {
+		for _, f := range parent.table.Fields {
+			if f.PrimaryKey {
+				refCols = append(refCols, f.Name)
+			}
+		}
+	}
+	if len(refCols) != len(fk.cols) {
+		b.warn(fk.line, "foreign key %s has %d columns but references %d; skipped", fkLabel(fk), len(fk.cols), len(refCols))
+		return schema.Relationship{}, false
+	}
+	var sourceIDs, targetIDs []string
+	for i := range fk.cols {
+		cf, pf := child.field(fk.cols[i]), parent.field(refCols[i])
+		if cf == nil || pf == nil {
+			b.warn(fk.line, "foreign key %s references unknown column; skipped", fkLabel(fk))
+			return schema.Relationship{}, false
+		}
+		targetIDs = append(targetIDs, cf.ID)
+		sourceIDs = append(sourceIDs, pf.ID)
+	}
+	rel := schema.Relationship{
+		ID:            b.ids.rel(),
+		SourceTableID: parent.table.ID,
+		SourceFieldID: sourceIDs[0],
+		TargetTableID: child.table.ID,
+		TargetFieldID: targetIDs[0],
+		Name:          fk.name,
+		Cardinality:   fk.card,
+	}
+	if len(sourceIDs) > 1 {
+		rel.SourceFieldIDs, rel.TargetFieldIDs = sourceIDs, targetIDs
+	}
+	return rel, true
+}
+
+// resolveIndex attaches an index to its table, mapping key columns to field IDs.
+func (b *ddlBuilder) resolveIndex(ix ddlIndex) {
+	t := b.lookup(ix.schema, ix.table)
+	if t == nil {
+		b.warn(ix.line, "index %s on unknown table %s skipped", ix.name, qualifiedName(ix.schema, ix.table))
+		return
+	}
+	index := schema.Index{Name: ix.name, Unique: ix.unique, Where: ix.where, Method: ix.method}
+	for _, k := range ix.keys {
+		col := schema.IndexColumn{Expression: k.expression, Desc: k.desc}
+		if k.expression == "" {
+			f := t.field(k.column)
+			if f == nil {
+				b.warn(ix.line, "index on %s references unknown column %s; skipped", t.table.Name, k.column)
+				return
+			}
+			col.FieldID = f.ID
+		}
+		index.Columns = append(index.Columns, col)
+	}
+	t.table.Indexes = append(t.table.Indexes, index)
+}
+
+func fkLabel(fk ddlForeignKey) string {
+	if fk.name != "" {
+		return fk.name
+	}
+	return fmt.Sprintf("%s(%s)", fk.table, strings.Join(fk.cols, ", "))
+}
+
+func indexLabel(ix ddlIndex) string {
+	if ix.name != "" {
+		return "index " + ix.name + " on " + ix.table
+	}
+	return "index on " + ix.table
+}
+
+// tokStream is a cursor over the tokens of one statement or clause.
+type tokStream struct {
+	toks []token
+	pos  int
+}
+
+func (s *tokStream) done() bool { return s.pos >= len(s.toks) }
+
+func (s *tokStream) peek() token {
+	if s.done() {
+		return token{kind: tokPunct}
+	}
+	return s.toks[s.pos]
+}
+
+// peekAt returns the token n positions ahead without consuming anything.
+func (s *tokStream) peekAt(n int) token {
+	if s.pos+n >= len(s.toks) {
+		return token{kind: tokPunct}
+	}
+	return s.toks[s.pos+n]
+}
+
+func (s *tokStream) next() token {
+	t := s.peek()
+	if !s.done() {
+		s.pos++
+	}
+	return t
+}
+
+// accept consumes the given keywords in sequence if they all match.
+func (s *tokStream) accept(keywords ...string) bool {
+	for i, kw := range keywords {
+		if s.pos+i >= len(s.toks) || !s.toks[s.pos+i].is(kw) {
+			return false
+		}
+	}
+	s.pos += len(keywords)
+	return true
+}
+
+// group consumes a parenthesized group and returns the tokens inside it, or false if
+// the stream is not at "(".
+func (s *tokStream) group() ([]token, bool) {
+	if !s.peek().isPunct("(") {
+		return nil, false
+	}
+	start, depth := s.pos+1, 0
+	for !s.done() {
+		t := s.next()
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+			if depth == 0 {
+				return s.toks[start : s.pos-1], true
+			}
+		}
+	}
+	return s.toks[start:], true
+}
+
+// skipUntil consumes tokens up to (not including) the first top-level keyword in stop.
+func (s *tokStream) skipUntil(stop map[string]bool) {
+	for !s.done() {
+		t := s.peek()
+		if t.kind == tokWord && stop[strings.ToUpper(t.text)] {
+			return
+		}
+		if _, ok := s.group(); !ok {
+			s.pos++
+		}
+	}
+}
+
+// qualifiedName reads a dotted name such as public.users and returns its last part.
+func (s *tokStream) qualifiedName() (string, bool) {
+	parts := s.nameParts()
+	if len(parts) == 0 {
+		return "", false
+	}
+	return parts[len(parts)-1], true
+}
+
+// nameParts reads a dotted name such as public.users and returns its parts.
+func (s *tokStream) nameParts() []string {
+	if !s.peek().isIdent() {
+		return nil
+	}
+	parts := []string{s.next().text}
+	for s.peek().isPunct(".") && s.pos+1 < len(s.toks) && s.toks[s.pos+1].isIdent() {
+		s.pos++
+		parts = append(parts, s.next().text)
+	}
+	return parts
+}
+
+// nameList reads "(a, b, ...)" and returns the names. Sort options such as ASC are dropped.
+func (s *tokStream) nameList() ([]string, bool) {
+	inner, ok := s.group()
+	if !ok {
+		return nil, false
+	}
+	var names []string
+	for _, item := range splitTopLevel(inner, ",", false) {
+		if len(item) == 0 || !item[0].isIdent() {
+			return nil, false
+		}
+		names = append(names, item[0].text)
+	}
+	return names, len(names) > 0
+}
+
+// splitTopLevel splits tokens on the given punctuation outside parentheses and brackets and, if
+// angles is set, outside <...> (BigQuery ARRAY<...> and STRUCT<...> types).
+func splitTopLevel(toks []token, sep string, angles bool) [][]token {
+	var parts [][]token
+	depth, start := 0, 0
+	for i, t := range toks {
+		switch {
+		case t.isPunct("(") || t.isPunct("[") || (angles && t.isPunct("<")):
+			depth++
+		case t.isPunct(")") || t.isPunct("]") || (angles && t.isPunct(">")):
+			depth--
+		case depth == 0 && t.isPunct(sep):
+			parts = append(parts, toks[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(toks) {
+		parts = append(parts, toks[start:])
+	}
+	return parts
+}
+
+// splitStatements splits tokens into statements on ";" and, for SQL Server scripts,
+// on GO batch separators (GO alone on its line).
+func splitStatements(toks []token, goBatches bool) [][]token {
+	if !goBatches {
+		return splitTopLevel(toks, ";", false)
+	}
+	var parts [][]token
+	start := 0
+	for i, t := range toks {
+		isGo := t.is("GO") && (i == 0 || toks[i-1].line < t.line) && (i+1 == len(toks) || toks[i+1].line > t.line)
+		if isGo {
+			parts = append(parts, splitTopLevel(toks[start:i], ";", false)...)
+			start = i + 1
+		}
+	}
+	return append(parts, splitTopLevel(toks[start:], ";", false)...)
+}
+
+// renderTokens rebuilds SQL text from tokens, e.g. a column type such as
+// "character varying(255)", "numeric(10,2)" or "ARRAY<STRUCT<a INT64, b STRING>>".
+func renderTokens(toks []token) string {
+	var b strings.Builder
+	angles := 0
+	for i, t := range toks {
+		text := t.text
+		if t.kind == tokQuoted {
+			text = `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		}
+		if i > 0 {
+			prev := toks[i-1]
+			attached := t.isPunct("(") || t.isPunct(")") || t.isPunct(",") || t.isPunct("[") ||
+				t.isPunct("]") || t.isPunct(".") || prev.isPunct("(") || prev.isPunct("[") ||
+				prev.isPunct(".") || t.isPunct("::") || prev.isPunct("::") ||
+				t.isPunct("<") || t.isPunct(">") || prev.isPunct("<") ||
+				(prev.isPunct(",") && angles == 0)
+			if !attached {
+				b.WriteByte(' ')
+			}
+		}
+		switch {
+		case t.isPunct("<"):
+			angles++
+		case t.isPunct(">"):
+			angles--
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// operatorKeywords may be followed by a parenthesized operand: IN ('a', 'b').
+var operatorKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "EXISTS": true,
+	"ANY": true, "ALL": true, "SOME": true, "BETWEEN": true, "LIKE": true, "THEN": true, "ELSE": true,
+}
+
+// renderExpr rebuilds SQL text from the tokens of an expression, e.g. a DEFAULT value
+// or CHECK condition: "qty > 0", "status IN ('new', 'paid')", "now()", "-1".
+func renderExpr(toks []token) string {
+	var b strings.Builder
+	for i, t := range toks {
+		text := t.text
+		// Identifiers quoted with backticks or brackets are written bare when they can be,
+		// so the expression reads the same in every dialect.
+		if t.kind == tokQuoted && !isPlainIdent(text) {
+			text = `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		}
+		if i > 0 {
+			prev := toks[i-1]
+			unary := (prev.isPunct("-") || prev.isPunct("+")) &&
+				(i == 1 || (toks[i-2].kind == tokPunct && !toks[i-2].isPunct(")")))
+			call := t.isPunct("(") && prev.isIdent() && !operatorKeywords[strings.ToUpper(prev.text)]
+			attached := call || unary || t.isPunct(")") || t.isPunct(",") || t.isPunct(".") ||
+				t.isPunct("[") || t.isPunct("]") || t.isPunct("::") ||
+				prev.isPunct("(") || prev.isPunct("[") || prev.isPunct(".") || prev.isPunct("::")
+			if !attached {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// isPlainIdent reports whether s is a lower-case identifier that needs no quoting.
+func isPlainIdent(s string) bool {
+	if s == "" || !isIdentStart(s[0]) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || (c >= 'a' && c <= 'z') || isDigit(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// statementLabel names a statement by its leading keywords, e.g. "CREATE INDEX".
+func statementLabel(toks []token) string {
+	n := 2
+	if len(toks) > 1 && (toks[1].is("OR") || toks[1].is("UNIQUE") || toks[1].is("MATERIALIZED") ||
+		toks[1].is("TEMP") || toks[1].is("TEMPORARY")) {
+		n = 3
+		if toks[1].is("OR") {
+			n = 4
+		}
+	}
+	var words []string
+	for i := 0; i < n && i < len(toks) && toks[i].kind == tokWord; i++ {
+		words = append(words, strings.ToUpper(toks[i].text))
+	}
+	return strings.Join(words, " ")
+}
+
+// columnStop ends a column type or DEFAULT expression in every dialect.
+var columnStop = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "REFERENCES": true,
+	"UNIQUE": true, "CHECK": true, "CONSTRAINT": true, "GENERATED": true, "COLLATE": true,
+}
+
+// silentStatements are session and transaction statements that carry no schema.
+var silentStatements = map[string]bool{
+	"SET": true, "RESET": true, "BEGIN": true, "COMMIT": true, "START": true, "END": true,
+	"ROLLBACK": true, "USE": true, "LOCK": true, "UNLOCK": true,
+}
+
+// notableTableOptions are table options after the column list that carry schema
+// information (rather than storage settings) and are worth a warning.
+var notableTableOptions = map[string]bool{
+	"COMMENT": true, "OPTIONS": true, "PARTITION": true, "CLUSTER": true, "INHERITS": true,
+	"CHARACTER SET": true, "CHARSET": true, "COLLATE": true,
+}
+
+// ddlParser parses DDL in one dialect into a ddlBuilder.
+type ddlParser struct {
+	b *ddlBuilder
+	d ddlDialect
+}
+
+// parse splits tokens into statements and dispatches on their leading keywords.
+func (p *ddlParser) parse(toks []token) {
+	for _, stmt := range splitStatements(toks, p.d.goBatches) {
+		if len(stmt) == 0 {
+			continue
+		}
+		s := &tokStream{toks: stmt}
+		line := stmt[0].line
+		switch {
+		case stmt[0].kind == tokWord && silentStatements[strings.ToUpper(stmt[0].text)]:
+		case s.accept("CREATE"):
+			s.accept("OR", "REPLACE")
+			s.accept("OR", "ALTER")
+			s.accept("GLOBAL")
+			s.accept("LOCAL")
+			s.accept("TEMPORARY")
+			s.accept("TEMP")
+			s.accept("UNLOGGED")
+			if s.accept("TABLE") {
+				p.createTable(s, line)
+			} else if kind, ok := p.viewHead(s); ok {
+				p.createView(s, kind, line)
+			} else if ix, ok := p.indexHead(s); ok {
+				p.createIndex(s, ix, line)
+			} else {
+				p.b.warn(line, "%s statement not imported", statementLabel(stmt))
+			}
+		case s.accept("ALTER", "TABLE"):
+			p.alterTable(s, line)
+		case s.accept("COMMENT", "ON", "COLUMN"):
+			p.commentOnColumn(s, line)
+		case s.accept("COMMENT", "ON", "TABLE"), s.accept("COMMENT", "ON", "VIEW"),
+			s.accept("COMMENT", "ON", "MATERIALIZED", "VIEW"):
+			p.commentOnTable(s, line)
+		case (stmt[0].is("EXEC") || stmt[0].is("EXECUTE")) && isExtendedProperty(stmt):
+			p.extendedProperty(stmt, line)
+		default:
+			p.b.warn(line, "%s statement not imported", statementLabel(stmt))
+		}
+	}
+}
+
+// schemaTableName reads a possibly qualified table name and returns its schema (the part
+// before the name: a PostgreSQL/SQL Server schema, MySQL database or BigQuery dataset) and
+// the unqualified name. A database or project qualifier before the schema is dropped.
+func (p *ddlParser) schemaTableName(s *tokStream) (string, string, bool) {
+	_, schemaName, name, ok := p.tableNameParts(s)
+	return schemaName, name, ok
+}
+
+// tableNameParts is schemaTableName that also returns the qualifier before the schema (a
+// SQL Server database or BigQuery project), so a definition can warn that it is dropped.
+func (p *ddlParser) tableNameParts(s *tokStream) (qualifier, schemaName, name string, ok bool) {
+	parts := s.nameParts()
+	if p.d.dottedQuoted {
+		var split []string
+		for _, part := range parts {
+			split = append(split, strings.Split(part, ".")...)
+		}
+		parts = split
+	}
+	switch len(parts) {
+	case 0:
+		return "", "", "", false
+	case 1:
+		return "", "", parts[0], true
+	}
+	n := len(parts)
+	return strings.Join(parts[:n-2], "."), parts[n-2], parts[n-1], true
+}
+
+// definedTableName reads the name of a table or view being created, warning when a
+// qualifier before its schema is dropped.
+func (p *ddlParser) definedTableName(s *tokStream, line int) (string, string, bool) {
+	qualifier, schemaName, name, ok := p.tableNameParts(s)
+	if qualifier != "" {
+		kind := "database"
+		if p.d.dottedQuoted {
+			kind = "project"
+		}
+		p.b.warn(line, "%s qualifier %s on %s not imported", kind, qualifier, qualifiedName(schemaName, name))
+	}
+	return schemaName, name, ok
+}
+
+func (p *ddlParser) createTable(s *tokStream, line int) {
+	s.accept("IF", "NOT", "EXISTS")
+	schemaName, name, ok := p.definedTableName(s, line)
+	if !ok {
+		p.b.warn(line, "CREATE TABLE without a table name skipped")
+		return
+	}
+	body, ok := s.group()
+	if !ok {
+		p.b.warn(line, "CREATE TABLE %s without a column list (AS, LIKE, OF or PARTITION OF) not imported", name)
+		return
+	}
+	t := p.b.addTable(schemaName, name, line)
+	if t == nil {
+		return
+	}
+	for _, elem := range splitTopLevel(body, ",", p.d.lex.angleTokens) {
+		if len(elem) == 0 {
+			continue
+		}
+		es := &tokStream{toks: elem}
+		if p.isTableConstraint(es) {
+			p.tableConstraint(t, es)
+		} else if elem[0].is("LIKE") {
+			p.b.warn(elem[0].line, "LIKE clause in table %s not imported", name)
+		} else {
+			p.column(t, es)
+		}
+	}
+	p.tableOptions(t, s)
+}
+
+// viewHead reads the keywords before a view name, [MATERIALIZED] VIEW, along with the
+// ALGORITHM=, DEFINER= and SQL SECURITY attributes MySQL writes before them. It returns
+// the view kind, or false (consuming nothing) if the statement does not create a view.
+func (p *ddlParser) viewHead(s *tokStream) (string, bool) {
+	start := s.pos
+	for {
+		switch {
+		case s.accept("ALGORITHM"), s.accept("DEFINER"):
+			if s.peek().isPunct("=") {
+				s.next()
+			}
+			s.next()
+			for s.peek().isPunct("@") { // user@host
+				s.next()
+				s.next()
+			}
+		case s.accept("SQL", "SECURITY"):
+			s.next()
+		case s.accept("MATERIALIZED", "VIEW"):
+			return schema.KindMaterializedView, true
+		case s.accept("VIEW"):
+			return schema.KindView, true
+		default:
+			s.pos = start
+			return "", false
+		}
+	}
+}
+
+// createView handles "CREATE VIEW [IF NOT EXISTS] name [(columns)] [options] AS query".
+// The query becomes the view's definition. Its columns come from the column list or else
+// from the query's select list, typed after the table columns they select where those
+// can be found.
+func (p *ddlParser) createView(s *tokStream, kind string, line int) {
+	s.accept("IF", "NOT", "EXISTS")
+	schemaName, name, ok := p.definedTableName(s, line)
+	if !ok {
+		p.b.warn(line, "CREATE VIEW without a view name skipped")
+		return
+	}
+	var listed []string
+	if s.peek().isPunct("(") {
+		listed, _ = s.nameList()
+	}
+	var description string
+	for !s.done() && !s.peek().is("AS") {
+		if s.peek().is("OPTIONS") && s.peekAt(1).isPunct("(") {
+			s.next()
+			inner, _ := s.group()
+			for _, opt := range splitTopLevel(inner, ",", true) {
+				if len(opt) == 3 && opt[0].is("description") && opt[1].isPunct("=") && opt[2].kind == tokString {
+					description = stringValue(opt[2].text, p.d.lex.backslashEscape)
+				}
+			}
+			continue
+		}
+		if _, ok := s.group(); !ok {
+			s.pos++
+		}
+	}
+	if !s.accept("AS") || s.done() {
+		p.b.warn(line, "CREATE VIEW %s without a query not imported", name)
+		return
+	}
+	query := trimViewOptions(s.toks[s.pos:])
+	t := p.b.addTable(schemaName, name, line)
+	if t == nil {
+		return
+	}
+	t.table.Kind = kind
+	t.table.Definition = renderExpr(query)
+	t.table.Description = description
+
+	columns, complete := p.viewColumns(query)
+	if len(listed) > 0 {
+		// The column list renames the select list positionally.
+		named := make([]viewColumn, len(listed))
+		for i, c := range listed {
+			named[i].name = c
+			if len(columns) == len(listed) {
+				named[i].source = columns[i].source
+			}
+		}
+		columns, complete = named, true
+	}
+	if !complete {
+		p.b.warn(line, "columns of view %s could not all be derived from its query", name)
+	}
+	for _, c := range columns {
+		if c.name != "" {
+			p.b.addViewField(t, c, line)
+		}
+	}
+}
+
+// trimViewOptions drops trailing WITH [NO] DATA and WITH [CASCADED|LOCAL] CHECK OPTION
+// clauses from a view query.
+func trimViewOptions(query []token) []token {
+	depth := 0
+	for i, t := range query {
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case depth == 0 && t.is("WITH") && i+1 < len(query):
+			next := query[i+1]
+			if next.is("DATA") || next.is("NO") || next.is("CHECK") || next.is("CASCADED") || next.is("LOCAL") {
+				return query[:i]
+			}
+		}
+	}
+	return query
+}
+
+// viewColumn is a column derived from a view's select list, with the table column it
+// selects (if any).
+type viewColumn struct {
+	name   string
+	source *schema.Field
+}
+
+// selectListEnd are the keywords that end a select list or FROM clause.
+var selectListEnd = map[string]bool{
+	"FROM": true, "WHERE": true, "GROUP": true, "HAVING": true, "ORDER": true, "LIMIT": true,
+	"UNION": true, "EXCEPT": true, "INTERSECT": true, "WINDOW": true, "QUALIFY": true, "OFFSET": true,
+}
+
+// fromClauseWords are keywords in a FROM clause that cannot be table aliases.
+var fromClauseWords = map[string]bool{
+	"ON": true, "USING": true, "JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true,
+	"OUTER": true, "CROSS": true, "NATURAL": true, "LATERAL": true,
+}
+
+// viewColumns derives a column from each item of the first top-level SELECT of a view
+// query, named by its alias or, for a plain column reference, the column name. It reports
+// false if some item (such as * or an unaliased expression) has no derivable name; that
+// item's column has an empty name.
+func (p *ddlParser) viewColumns(query []token) ([]viewColumn, bool) {
+	start := topLevelWord(query, map[string]bool{"SELECT": true}, 0)
+	if start < 0 {
+		return nil, false
+	}
+	start++
+	for start < len(query) && (query[start].is("DISTINCT") || query[start].is("ALL")) {
+		start++
+	}
+	if start+1 < len(query) && query[start].is("TOP") {
+		start += 2
+	}
+	end := topLevelWord(query, selectListEnd, start)
+	if end < 0 {
+		end = len(query)
+	}
+	sources := p.fromTables(query, end)
+
+	var columns []viewColumn
+	complete := true
+	for _, item := range splitTopLevel(query[start:end], ",", p.d.lex.angleTokens) {
+		n := len(item)
+		var name string
+		expr := item
+		switch {
+		case n >= 3 && item[0].isIdent() && item[1].isPunct("="): // SQL Server: alias = expr
+			name, expr = item[0].text, item[2:]
+		case n >= 3 && item[n-2].is("AS") && item[n-1].isIdent():
+			name, expr = item[n-1].text, item[:n-2]
+		case n >= 2 && item[n-1].isIdent() && !item[n-2].isPunct(".") && !item[n-1].is("END"):
+			name, expr = item[n-1].text, item[:n-1]
+		}
+		var qualifier, column string
+		if isColumnRef(expr) {
+			column = expr[len(expr)-1].text
+			if len(expr) >= 3 {
+				qualifier = expr[len(expr)-3].text
+			}
+			if name == "" {
+				name = column
+			}
+		}
+		if name == "" {
+			complete = false
+		}
+		var src *schema.Field
+		if column != "" {
+			src = sources.find(qualifier, column)
+		}
+		columns = append(columns, viewColumn{name: name, source: src})
+	}
+	return columns, complete
+}
+
+// isColumnRef reports whether toks is a possibly qualified column name such as t.col.
+func isColumnRef(toks []token) bool {
+	if len(toks) == 0 || len(toks)%2 == 0 {
+		return false
+	}
+	for i, t := range toks {
+		if (i%2 == 0 && !t.isIdent()) || (i%2 == 1 && !t.isPunct(".")) {
+			return false
+		}
+	}
+	return true
+}
+
+// topLevelWord returns the index of the first token at or after from that is one of
+// the keywords outside parentheses, or -1.
+func topLevelWord(toks []token, keywords map[string]bool, from int) int {
+	depth := 0
+	for i := from; i < len(toks); i++ {
+		t := toks[i]
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case depth == 0 && t.kind == tokWord && keywords[strings.ToUpper(t.text)]:
+			return i
+		}
+	}
+	return -1
+}
+
+// viewSources are the known tables named in a query's FROM clause, keyed by lower-case
+// alias (or table name when unaliased), in order.
+type viewSources struct {
+	keys   []string
+	tables []*ddlTable
+}
+
+// find returns the column selected as qualifier.column, or column from the first
+// source table that has it when unqualified.
+func (vs viewSources) find(qualifier, column string) *schema.Field {
+	for i, t := range vs.tables {
+		if qualifier != "" && vs.keys[i] != strings.ToLower(qualifier) {
+			continue
+		}
+		if f := t.field(column); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// fromTables reads the tables of the FROM clause that starts at query[from], if any.
+func (p *ddlParser) fromTables(query []token, from int) viewSources {
+	var vs viewSources
+	if from >= len(query) || !query[from].is("FROM") {
+		return vs
+	}
+	end := topLevelWord(query, selectListEnd, from+1)
+	if end < 0 {
+		end = len(query)
+	}
+	s := &tokStream{toks: query[from+1 : end]}
+	expectTable := true
+	for !s.done() {
+		if !expectTable {
+			tok := s.next()
+			expectTable = tok.isPunct(",") || tok.is("JOIN")
+			if tok.isPunct("(") {
+				s.pos--
+				s.group()
+			}
+			continue
+		}
+		expectTable = false
+		schemaName, name, ok := p.schemaTableName(s)
+		if !ok {
+			continue
+		}
+		key := strings.ToLower(name)
+		s.accept("AS")
+		if tok := s.peek(); tok.isIdent() && !(tok.kind == tokWord && fromClauseWords[strings.ToUpper(tok.text)]) {
+			key = strings.ToLower(s.next().text)
+		}
+		if t := p.b.lookup(schemaName, name); t != nil {
+			vs.keys = append(vs.keys, key)
+			vs.tables = append(vs.tables, t)
+		}
+	}
+	return vs
+}
+
+// tableOptions reads the table description from options after the column list (MySQL
+// COMMENT=, BigQuery OPTIONS(description=..., labels=...)) and warns about other options
+// that carry schema information, such as a MySQL character set or collation; storage
+// settings such as ENGINE=, WITH (...) or ON [PRIMARY] are ignored.
+func (p *ddlParser) tableOptions(t *ddlTable, s *tokStream) {
+	var notable []string
+	seen := make(map[string]bool)
+	line := s.peek().line
+	for !s.done() {
+		tok := s.peek()
+		consumed := false
+		if tok.is("COMMENT") {
+			s.next()
+			consumed = true
+			if s.peek().isPunct("=") {
+				s.next()
+			}
+			if s.peek().kind == tokString {
+				t.table.Description = stringValue(s.next().text, p.d.lex.backslashEscape)
+				continue
+			}
+		}
+		if tok.is("OPTIONS") && s.peekAt(1).isPunct("(") {
+			s.next()
+			consumed = true
+			inner, _ := s.group()
+			if p.bigQueryTableOptions(t, inner) {
+				continue
+			}
+		}
+		kw := strings.ToUpper(tok.text)
+		if kw == "CHARACTER" && s.peekAt(1).is("SET") {
+			kw = "CHARACTER SET"
+		}
+		if tok.kind == tokWord && notableTableOptions[kw] && !seen[kw] {
+			seen[kw] = true
+			notable = append(notable, kw)
+		}
+		if consumed {
+			continue
+		}
+		if _, ok := s.group(); !ok {
+			s.pos++
+		}
+	}
+	if len(notable) > 0 {
+		p.b.warn(line, "table options of %s not imported (%s)", t.table.Name, strings.Join(notable, ", "))
+	}
+}
+
+// bigQueryTableOptions imports the description and labels of a BigQuery OPTIONS list;
+// labels become "key:value" tags. It reports false if any other option is present.
+func (p *ddlParser) bigQueryTableOptions(t *ddlTable, inner []token) bool {
+	complete := true
+	for _, opt := range splitTopLevel(inner, ",", true) {
+		switch {
+		case len(opt) == 3 && opt[0].is("description") && opt[1].isPunct("=") && opt[2].kind == tokString:
+			t.table.Description = stringValue(opt[2].text, p.d.lex.backslashEscape)
+		case len(opt) > 2 && opt[0].is("labels") && opt[1].isPunct("="):
+			var parts []string
+			for _, tok := range opt[2:] {
+				if tok.kind == tokString {
+					parts = append(parts, stringValue(tok.text, p.d.lex.backslashEscape))
+				}
+			}
+			for i := 0; i+1 < len(parts); i += 2 {
+				tag := parts[i]
+				if parts[i+1] != "" {
+					tag += ":" + parts[i+1]
+				}
+				t.table.Tags = append(t.table.Tags, tag)
+			}
+		case len(opt) > 0:
+			complete = false
+		}
+	}
+	return complete
+}
+
+// isTableConstraint reports whether a table element starts a table-level constraint
+// (or, in MySQL, an inline index definition).
+func (p *ddlParser) isTableConstraint(s *tokStream) bool {
+	t := s.peek()
+	if p.d.indexElements && (t.is("KEY") || t.is("INDEX") || t.is("FULLTEXT") || t.is("SPATIAL")) {
+		return true
+	}
+	return t.is("CONSTRAINT") || t.is("PRIMARY") || t.is("FOREIGN") || t.is("UNIQUE") ||
+		t.is("CHECK") || t.is("EXCLUDE")
+}
+
+// columnType reads a column type up to the first column constraint keyword, returning
+// its text and tokens.
+func (p *ddlParser) columnType(s *tokStream) (string, []token) {
+	start, angles := s.pos, 0
+	for !s.done() {
+		t := s.peek()
+		kw := strings.ToUpper(t.text)
+		if angles == 0 && t.kind == tokWord &&
+			(columnStop[kw] || p.d.columnStop[kw] || (kw == "CHARACTER" && s.peekAt(1).is("SET"))) {
+			break
+		}
+		switch {
+		case t.isPunct("<"):
+			angles++
+		case t.isPunct(">"):
+			angles--
+		}
+		if _, ok := s.group(); !ok {
+			s.pos++
+		}
+	}
+	// Type names may be quoted (SQL Server scripts emit [nvarchar](50)); render them bare.
+	typeToks := make([]token, s.pos-start)
+	for i, t := range s.toks[start:s.pos] {
+		if t.kind == tokQuoted {
+			t.kind = tokWord
+		}
+		typeToks[i] = t
+	}
+	return renderTokens(typeToks), typeToks
+}
+
+// angleArgs returns the tokens between the angle brackets of a BigQuery type such as
+// ARRAY<...> or STRUCT<...>, named by keyword.
+func angleArgs(toks []token, keyword string) ([]token, bool) {
+	if len(toks) < 3 || !toks[0].is(keyword) || !toks[1].isPunct("<") || !toks[len(toks)-1].isPunct(">") {
+		return nil, false
+	}
+	return toks[2 : len(toks)-1], true
+}
+
+// nestedType types f from a BigQuery ARRAY<...> or STRUCT<...> column type, as
+// inspecting the table would: an array makes f a repeated field of its element type,
+// and the columns of a struct become f.Fields. It reports false, leaving f as it is,
+// for any other type and for struct types it cannot read.
+func (p *ddlParser) nestedType(f *schema.Field, typeToks []token, col string) bool {
+	if elem, ok := angleArgs(typeToks, "ARRAY"); ok {
+		if _, nested := angleArgs(elem, "ARRAY"); nested {
+			return false // BigQuery has no arrays of arrays.
+		}
+		if !p.nestedType(f, elem, col) {
+			p.b.setType(f, renderTokens(elem))
+		}
+		f.Repeated = true
+		return true
+	}
+	cols, ok := angleArgs(typeToks, "STRUCT")
+	if !ok {
+		return false
+	}
+	var fields []schema.Field
+	for _, part := range splitTopLevel(cols, ",", true) {
+		s := &tokStream{toks: part}
+		nameTok := s.next()
+		if !nameTok.isIdent() || s.done() {
+			return false // STRUCT<INT64, STRING> has unnamed fields.
+		}
+		sub := schema.Field{ID: p.b.ids.field(), Name: nameTok.text, Nullable: true}
+		subCol := col + "." + nameTok.text
+		rawType, subToks := p.columnType(s)
+		p.b.setType(&sub, rawType)
+		p.nestedType(&sub, subToks, subCol)
+		for !s.done() {
+			tok := s.peek()
+			switch {
+			case s.accept("NOT", "NULL"):
+				sub.Nullable = false
+			case s.accept("OPTIONS"):
+				inner, _ := s.group()
+				p.columnOptions(&sub, subCol, inner, tok.line)
+			default:
+				p.b.warn(tok.line, "unrecognized clause %q on %s skipped", tok.text, subCol)
+				s.pos = len(s.toks)
+			}
+		}
+		fields = append(fields, sub)
+	}
+	f.Type, f.Length, f.Precision, f.Scale = schema.TypeStruct, nil, nil, nil
+	f.TypeOverrides, f.Fields = nil, fields
+	return true
+}
+
+// column parses "name type [constraint ...]".
+func (p *ddlParser) column(t *ddlTable, s *tokStream) {
+	nameTok := s.next()
+	if !nameTok.isIdent() {
+		p.b.warn(nameTok.line, "unrecognized element in table %s skipped", t.table.Name)
+		return
+	}
+	rawType, typeToks := p.columnType(s)
+	f := p.b.addField(t, nameTok.text, rawType, nameTok.line)
+	if f == nil {
+		return
+	}
+	col := t.table.Name + "." + nameTok.text
+	p.serial(f, rawType)
+	p.nestedType(f, typeToks, col)
+	for !s.done() {
+		tok := s.peek()
+		constraintName := ""
+		if s.accept("CONSTRAINT") {
+			constraintName = s.next().text
+		}
+		switch {
+		case s.accept("NOT", "NULL"):
+			f.Nullable = false
+		case s.accept("NULL"):
+		case s.accept("PRIMARY", "KEY"):
+			f.PrimaryKey = true
+			f.Nullable = false
+			p.skipKeyOptions(s)
+		case s.accept("REFERENCES"):
+			p.references(s, t, []string{nameTok.text}, constraintName, tok.line)
+		case s.accept("DEFAULT"):
+			p.setDefault(f, p.expression(s))
+		case s.accept("UNIQUE"):
+			s.accept("KEY")
+			p.skipKeyOptions(s)
+			f.Unique = !f.PrimaryKey
+		case s.accept("CHECK"):
+			inner, _ := s.group()
+			addCheck(f, renderExpr(inner))
+		case s.accept("GENERATED", "ALWAYS", "AS", "IDENTITY"):
+			f.Identity = schema.IdentityAlways
+			s.group() // sequence options
+		case s.accept("GENERATED", "BY", "DEFAULT", "AS", "IDENTITY"):
+			f.Identity = schema.IdentityByDefault
+			s.group()
+		case s.accept("GENERATED"):
+			p.skipClause(s)
+			p.b.warn(tok.line, "generated column expression on %s not imported", col)
+		case s.accept("AUTO_INCREMENT"):
+			f.Identity = schema.IdentityByDefault
+		case s.accept("IDENTITY"):
+			f.Identity = schema.IdentityAlways
+			s.group() // (seed, increment)
+		case s.accept("COMMENT"):
+			if c := s.next(); c.kind == tokString {
+				f.Comment = stringValue(c.text, p.d.lex.backslashEscape)
+			}
+		case s.accept("OPTIONS"):
+			inner, _ := s.group()
+			p.columnOptions(f, col, inner, tok.line)
+		case s.accept("ON", "UPDATE"):
+			p.skipExpression(s)
+			p.b.warn(tok.line, "ON UPDATE on %s not imported", col)
+		case s.accept("COLLATE"), s.accept("CHARACTER", "SET"), s.accept("CHARSET"):
+			clause := "CHARACTER SET"
+			if tok.is("COLLATE") {
+				clause = "COLLATE"
+			}
+			name, _ := s.qualifiedName()
+			p.b.warn(tok.line, "%s %s on %s not imported", clause, name, col)
+		case s.accept("UNSIGNED"), s.accept("ZEROFILL"), s.accept("ROWGUIDCOL"), s.accept("SPARSE"),
+			s.accept("VISIBLE"), s.accept("INVISIBLE"), s.accept("NOT", "FOR", "REPLICATION"):
+		default:
+			p.b.warn(s.peek().line, "unrecognized clause %q on %s skipped", s.peek().text, col)
+			s.next()
+			p.skipClause(s)
+		}
+	}
+}
+
+// serialBaseTypes maps the serial pseudo-types to the integer type of the column they
+// declare.
+var serialBaseTypes = map[string]string{
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"serial":      "integer",
+	"serial4":     "integer",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+}
+
+// serial turns a column declared with a serial pseudo-type into a not-null by-default
+// identity of the underlying integer type. MySQL's SERIAL is BIGINT UNSIGNED NOT NULL
+// AUTO_INCREMENT UNIQUE.
+func (p *ddlParser) serial(f *schema.Field, rawType string) {
+	base, ok := serialBaseTypes[strings.ToLower(rawType)]
+	if !ok {
+		return
+	}
+	if p.b.dialect == "mysql" {
+		base = "bigint unsigned"
+		f.Unique = true
+	}
+	f.Type = "integer"
+	f.Identity = schema.IdentityByDefault
+	f.Nullable = false
+	f.TypeOverrides = nil
+	if base != f.Type && p.b.dialect != "" {
+		f.TypeOverrides = map[string]schema.FieldTypeOverride{p.b.dialect: {Type: base}}
+	}
+}
+
+// setDefault records a DEFAULT expression. PostgreSQL serial-style defaults that draw
+// from a sequence become a by-default identity instead, and DEFAULT NULL, which MySQL
+// dumps write for every nullable column, is no default at all.
+func (p *ddlParser) setDefault(f *schema.Field, expr string) {
+	switch {
+	case strings.HasPrefix(strings.ToLower(expr), "nextval("):
+		f.Identity = schema.IdentityByDefault
+		return
+	case strings.EqualFold(expr, "null"):
+		f.Default = ""
+		return
+	}
+	f.Default = expr
+}
+
+// addCheck adds a CHECK expression to a column, combining it with any existing one.
+func addCheck(f *schema.Field, expr string) {
+	if f.Check == "" {
+		f.Check = expr
+	} else {
+		f.Check = "(" + f.Check + ") and (" + expr + ")"
+	}
+}
+
+// columnOptions reads BigQuery column OPTIONS(...); description becomes the column comment.
+func (p *ddlParser) columnOptions(f *schema.Field, col string, inner []token, line int) {
+	for _, opt := range splitTopLevel(inner, ",", true) {
+		if len(opt) == 3 && opt[0].is("description") && opt[1].isPunct("=") && opt[2].kind == tokString {
+			f.Comment = stringValue(opt[2].text, p.d.lex.backslashEscape)
+		} else if len(opt) > 0 {
+			p.b.warn(line, "option %s on %s not imported", opt[0].text, col)
+		}
+	}
+}
+
+// expression reads one expression (e.g. a DEFAULT value) up to the next column constraint
+// and returns it as SQL text, without parentheses enclosing the whole expression.
+func (p *ddlParser) expression(s *tokStream) string {
+	start := s.pos
+	p.skipExpression(s)
+	return renderExpr(trimParens(s.toks[start:s.pos]))
+}
+
+// trimParens drops parentheses enclosing all of toks, repeatedly: SQL Server scripts
+// write defaults as ((0)).
+func trimParens(toks []token) []token {
+	for len(toks) >= 2 && toks[0].isPunct("(") && toks[len(toks)-1].isPunct(")") {
+		depth := 0
+		for _, t := range toks[:len(toks)-1] {
+			switch {
+			case t.isPunct("("):
+				depth++
+			case t.isPunct(")"):
+				depth--
+			}
+			if depth == 0 {
+				return toks
+			}
+		}
+		toks = toks[1 : len(toks)-1]
+	}
+	return toks
+}
+
+// skipExpression consumes one expression (e.g. a DEFAULT value) up to the next column constraint.
+func (p *ddlParser) skipExpression(s *tokStream) {
+	if _, ok := s.group(); !ok {
+		s.next()
+	}
+	p.skipClause(s)
+}
+
+// skipClause consumes tokens up to the next column constraint keyword.
+func (p *ddlParser) skipClause(s *tokStream) {
+	for !s.done() {
+		kw := strings.ToUpper(s.peek().text)
+		if s.peek().kind == tokWord && (columnStop[kw] || p.d.columnStop[kw]) {
+			return
+		}
+		if _, ok := s.group(); !ok {
+			s.pos++
+		}
+	}
+}
+
+// skipKeyOptions consumes index options that may follow PRIMARY KEY or UNIQUE
+// (SQL Server CLUSTERED, BigQuery NOT ENFORCED).
+func (p *ddlParser) skipKeyOptions(s *tokStream) {
+	for s.accept("CLUSTERED") || s.accept("NONCLUSTERED") || s.accept("NOT", "ENFORCED") {
+	}
+}
+
+// references parses "REFERENCES table [(cols)] [MATCH ...] [ON DELETE ...] ..." and
+// records a foreign key from cols of t. Referential actions, MATCH and deferrability have
+// no place in the model; a warning names each one that differs from the default.
+func (p *ddlParser) references(s *tokStream, t *ddlTable, cols []string, name string, line int) {
+	refSchema, refTable, ok := p.schemaTableName(s)
+	if !ok {
+		p.b.warn(line, "REFERENCES without a table on %s skipped", t.table.Name)
+		return
+	}
+	var refCols []string
+	if s.peek().isPunct("(") {
+		refCols, ok = s.nameList()
+		if !ok {
+			p.b.warn(line, "REFERENCES %s on %s has an invalid column list; skipped", refTable, t.table.Name)
+			return
+		}
+	}
+	fk := ddlForeignKey{
+		name: name, schema: t.table.Schema, table: t.table.Name, cols: cols,
+		refSchema: refSchema, refTable: refTable, refCols: refCols, line: line,
+	}
+	p.b.fks = append(p.b.fks, fk)
+	for !s.done() {
+		start := s.pos
+		switch {
+		case s.accept("MATCH"):
+			if s.next().is("SIMPLE") {
+				continue
+			}
+		case s.accept("ON", "DELETE"), s.accept("ON", "UPDATE"):
+			if s.accept("NO", "ACTION") {
+				continue
+			}
+			if !s.accept("SET", "NULL") && !s.accept("SET", "DEFAULT") {
+				s.next()
+			}
+			if s.peek().isPunct("(") {
+				s.group()
+			}
+		case s.accept("NOT", "DEFERRABLE"), s.accept("INITIALLY", "IMMEDIATE"), s.accept("NOT", "ENFORCED"):
+			// The defaults, and the only kind of foreign key BigQuery has.
+			continue
+		case s.accept("DEFERRABLE"), s.accept("INITIALLY", "DEFERRED"), s.accept("NOT", "VALID"),
+			s.accept("NOT", "FOR", "REPLICATION"):
+		default:
+			return
+		}
+		p.b.warn(line, "%s on foreign key %s not imported", strings.ToUpper(renderExpr(s.toks[start:s.pos])), fkLabel(fk))
+	}
+}
+
+// tableConstraint parses "[CONSTRAINT name] PRIMARY KEY|FOREIGN KEY|UNIQUE|CHECK|EXCLUDE ..."
+// and MySQL index elements.
+func (p *ddlParser) tableConstraint(t *ddlTable, s *tokStream) {
+	line := s.peek().line
+	name := ""
+	if s.accept("CONSTRAINT") {
+		name = s.next().text
+	}
+	switch {
+	case s.accept("PRIMARY", "KEY"):
+		p.skipKeyOptions(s)
+		cols, ok := s.nameList()
+		if !ok {
+			p.b.warn(line, "primary key of %s has an invalid column list; skipped", t.table.Name)
+			return
+		}
+		p.b.setPrimaryKey(t, cols, line)
+	case s.accept("FOREIGN", "KEY"):
+		if s.peek().isIdent() {
+			name = s.next().text // MySQL: FOREIGN KEY name (cols)
+		}
+		cols, ok := s.nameList()
+		if !ok || !s.accept("REFERENCES") {
+			p.b.warn(line, "foreign key on %s is malformed; skipped", t.table.Name)
+			return
+		}
+		p.references(s, t, cols, name, line)
+	case s.accept("UNIQUE"):
+		_ = s.accept("KEY") || s.accept("INDEX")
+		p.skipKeyOptions(s)
+		if s.peek().isIdent() && !s.peek().is("USING") {
+			name = s.next().text // MySQL: UNIQUE KEY name (cols)
+		}
+		inner, _ := s.group()
+		keys, ok := p.indexKeys(inner)
+		if !ok {
+			p.b.warn(line, "UNIQUE constraint on %s has an invalid column list; skipped", t.table.Name)
+			return
+		}
+		// A single plain column is a column attribute; anything else becomes a unique index.
+		if len(keys) == 1 && keys[0].column != "" {
+			if f := t.field(keys[0].column); f != nil && !f.PrimaryKey {
+				f.Unique = true
+			}
+			return
+		}
+		p.b.indexes = append(p.b.indexes, ddlIndex{name: name, schema: t.table.Schema, table: t.table.Name, unique: true, keys: keys, line: line})
+	case s.accept("CHECK"):
+		inner, _ := s.group()
+		f := checkedColumn(t, inner)
+		if f == nil {
+			p.b.warn(line, "CHECK constraint on %s not imported; it does not refer to exactly one column", t.table.Name)
+			return
+		}
+		addCheck(f, renderExpr(inner))
+	case s.accept("EXCLUDE"):
+		p.b.warn(line, "EXCLUDE constraint on %s not imported", t.table.Name)
+	case s.accept("DEFAULT"):
+		// SQL Server: [CONSTRAINT name] DEFAULT expr FOR column.
+		start := s.pos
+		for !s.done() && !s.peek().is("FOR") {
+			if _, ok := s.group(); !ok {
+				s.pos++
+			}
+		}
+		expr := renderExpr(trimParens(s.toks[start:s.pos]))
+		var f *schema.Field
+		if s.accept("FOR") && s.peek().isIdent() {
+			f = t.field(s.next().text)
+		}
+		if f == nil || expr == "" {
+			p.b.warn(line, "DEFAULT constraint on %s not imported", t.table.Name)
+			return
+		}
+		p.setDefault(f, expr)
+	case s.peek().is("KEY") || s.peek().is("INDEX") || s.peek().is("FULLTEXT") || s.peek().is("SPATIAL"):
+		// MySQL: {INDEX|KEY} [name] [USING type] (keys) [USING type].
+		ix := ddlIndex{schema: t.table.Schema, table: t.table.Name, line: line}
+		if s.peek().is("FULLTEXT") || s.peek().is("SPATIAL") {
+			ix.method = strings.ToLower(s.next().text)
+		}
+		_ = s.accept("KEY") || s.accept("INDEX")
+		if s.peek().isIdent() && !s.peek().is("USING") {
+			ix.name = s.next().text
+		}
+		p.indexTail(s, &ix)
+		if len(ix.keys) == 0 {
+			p.b.warn(line, "index on %s has an invalid key list; skipped", t.table.Name)
+			return
+		}
+		p.b.indexes = append(p.b.indexes, ix)
+	default:
+		p.b.warn(line, "unrecognized constraint on %s skipped", t.table.Name)
+	}
+}
+
+// indexHead consumes "[UNIQUE] [CLUSTERED|NONCLUSTERED|FULLTEXT|SPATIAL] INDEX" after
+// CREATE. If the statement does not create an index, nothing is consumed.
+func (p *ddlParser) indexHead(s *tokStream) (ddlIndex, bool) {
+	start := s.pos
+	ix := ddlIndex{unique: s.accept("UNIQUE")}
+	for _, kind := range []string{"CLUSTERED", "NONCLUSTERED", "FULLTEXT", "SPATIAL"} {
+		if s.accept(kind) {
+			ix.method = normalizeIndexMethod(kind)
+			break
+		}
+	}
+	if !s.accept("INDEX") {
+		s.pos = start
+		return ddlIndex{}, false
+	}
+	return ix, true
+}
+
+// createIndex parses the rest of "CREATE ... INDEX [CONCURRENTLY] [IF NOT EXISTS] [name]
+// [USING type] ON [ONLY] table [USING method] (keys) ...".
+func (p *ddlParser) createIndex(s *tokStream, ix ddlIndex, line int) {
+	ix.line = line
+	s.accept("CONCURRENTLY")
+	s.accept("IF", "NOT", "EXISTS")
+	if !s.peek().is("ON") {
+		if name, ok := s.qualifiedName(); ok {
+			ix.name = name
+		}
+	}
+	if s.accept("USING") {
+		ix.method = normalizeIndexMethod(s.next().text)
+	}
+	if !s.accept("ON") {
+		p.b.warn(line, "CREATE INDEX %s without a table skipped", ix.name)
+		return
+	}
+	s.accept("ONLY")
+	schemaName, table, ok := p.schemaTableName(s)
+	if !ok {
+		p.b.warn(line, "CREATE INDEX %s without a table skipped", ix.name)
+		return
+	}
+	ix.schema, ix.table = schemaName, table
+	p.indexTail(s, &ix)
+	if len(ix.keys) == 0 {
+		p.b.warn(line, "index on %s has an invalid key list; skipped", table)
+		return
+	}
+	p.b.indexes = append(p.b.indexes, ix)
+}
+
+// indexTail reads "[USING method] (keys)" and the options that may follow: USING,
+// INCLUDE, WITH, TABLESPACE and WHERE; only the method and predicate are kept, and the
+// INCLUDE columns and WITH options are reported as not imported.
+func (p *ddlParser) indexTail(s *tokStream, ix *ddlIndex) {
+	if s.accept("USING") {
+		ix.method = normalizeIndexMethod(s.next().text)
+	}
+	inner, ok := s.group()
+	if !ok {
+		return
+	}
+	keys, ok := p.indexKeys(inner)
+	if !ok {
+		return
+	}
+	ix.keys = keys
+	for !s.done() {
+		switch {
+		case s.accept("USING"):
+			ix.method = normalizeIndexMethod(s.next().text)
+		case s.accept("WHERE"):
+			start := s.pos
+			s.skipUntil(map[string]bool{"WITH": true, "TABLESPACE": true, "ON": true, "OPTION": true})
+			ix.where = renderExpr(trimParens(s.toks[start:s.pos]))
+		case s.accept("INCLUDE"), s.accept("WITH"):
+			clause := strings.ToUpper(s.toks[s.pos-1].text)
+			if _, ok := s.group(); !ok {
+				s.next()
+			}
+			p.b.warn(ix.line, "%s clause of %s not imported", clause, indexLabel(*ix))
+		default:
+			if _, ok := s.group(); !ok {
+				s.next()
+			}
+		}
+	}
+}
+
+// indexKeys parses the comma-separated keys inside an index's parentheses. A key is a
+// column (with an optional MySQL prefix length, operator class or collation, all dropped)
+// or an expression, followed by ASC or DESC and NULLS FIRST/LAST.
+func (p *ddlParser) indexKeys(inner []token) ([]ddlIndexKey, bool) {
+	var keys []ddlIndexKey
+	for _, item := range splitTopLevel(inner, ",", false) {
+		var key ddlIndexKey
+		for n := len(item); n > 0; n = len(item) {
+			last := item[n-1]
+			switch {
+			case last.is("DESC"):
+				key.desc = true
+				item = item[:n-1]
+				continue
+			case last.is("ASC"):
+				item = item[:n-1]
+				continue
+			case n >= 2 && item[n-2].is("NULLS") && (last.is("FIRST") || last.is("LAST")):
+				item = item[:n-2]
+				continue
+			}
+			break
+		}
+		switch {
+		case len(item) == 0:
+			return nil, false
+		case item[0].isIdent() && (len(item) == 1 || item[1].kind == tokWord || item[1].kind == tokQuoted):
+			key.column = item[0].text
+		case item[0].isIdent() && len(item) == 4 && item[1].isPunct("(") && item[2].kind == tokNumber && item[3].isPunct(")") &&
+			p.d.indexElements:
+			key.column = item[0].text // MySQL prefix index: name(10)
+		default:
+			key.expression = renderExpr(trimParens(item))
+		}
+		keys = append(keys, key)
+	}
+	return keys, len(keys) > 0
+}
+
+// normalizeIndexMethod lower-cases an index method, mapping each dialect's default
+// (btree, SQL Server NONCLUSTERED) to "".
+func normalizeIndexMethod(method string) string {
+	method = strings.ToLower(method)
+	if method == "btree" || method == "nonclustered" {
+		return ""
+	}
+	return method
+}
+
+// checkedColumn returns the one column of t that a table-level CHECK expression refers
+// to, or nil if it refers to none or several.
+func checkedColumn(t *ddlTable, expr []token) *schema.Field {
+	var found *schema.Field
+	for _, tok := range expr {
+		if !tok.isIdent() {
+			continue
+		}
+		f := t.field(tok.text)
+		if f == nil {
+			continue
+		}
+		if found != nil && found != f {
+			return nil
+		}
+		found = f
+	}
+	return found
+}
+
+// commentOnColumn handles PostgreSQL "COMMENT ON COLUMN [schema.]table.column IS '...'".
+func (p *ddlParser) commentOnColumn(s *tokStream, line int) {
+	var parts []string
+	for s.peek().isIdent() {
+		parts = append(parts, s.next().text)
+		if !s.peek().isPunct(".") {
+			break
+		}
+		s.next()
+	}
+	if len(parts) < 2 || !s.accept("IS") {
+		p.b.warn(line, "COMMENT ON COLUMN statement not imported")
+		return
+	}
+	table, column := parts[len(parts)-2], parts[len(parts)-1]
+	schemaName := ""
+	if len(parts) > 2 {
+		schemaName = parts[len(parts)-3]
+	}
+	var f *schema.Field
+	if t := p.b.lookup(schemaName, table); t != nil {
+		f = t.field(column)
+	}
+	if f == nil {
+		p.b.warn(line, "COMMENT ON unknown column %s.%s skipped", table, column)
+		return
+	}
+	if tok := s.next(); tok.kind == tokString {
+		f.Comment = stringValue(tok.text, p.d.lex.backslashEscape)
+	} else {
+		f.Comment = "" // IS NULL
+	}
+}
+
+// commentOnTable handles PostgreSQL "COMMENT ON TABLE [schema.]table IS '...'" and its
+// COMMENT ON [MATERIALIZED] VIEW form.
+func (p *ddlParser) commentOnTable(s *tokStream, line int) {
+	schemaName, name, ok := p.schemaTableName(s)
+	if !ok || !s.accept("IS") {
+		p.b.warn(line, "COMMENT ON TABLE statement not imported")
+		return
+	}
+	t := p.b.lookup(schemaName, name)
+	if t == nil {
+		p.b.warn(line, "COMMENT ON unknown table %s skipped", name)
+		return
+	}
+	if tok := s.next(); tok.kind == tokString {
+		t.table.Description = stringValue(tok.text, p.d.lex.backslashEscape)
+	} else {
+		t.table.Description = "" // IS NULL
+	}
+}
+
+// isExtendedProperty reports whether an EXEC statement calls sp_addextendedproperty.
+func isExtendedProperty(stmt []token) bool {
+	for _, t := range stmt[1:] {
+		if t.is("sp_addextendedproperty") {
+			return true
+		}
+		if !t.isIdent() && !t.isPunct(".") {
+			return false
+		}
+	}
+	return false
+}
+
+// extendedProperty imports SQL Server table and column descriptions, which scripts record as
+// EXEC sp_addextendedproperty 'MS_Description', 'text', 'SCHEMA', 'dbo', 'TABLE', 't'
+// [, 'COLUMN', 'c'] (optionally with @name= style argument names).
+func (p *ddlParser) extendedProperty(stmt []token, line int) {
+	var args []string
+	for _, t := range stmt {
+		if t.kind == tokString {
+			args = append(args, stringValue(t.text, false))
+		}
+	}
+	level0 := ""
+	if len(args) >= 4 && strings.EqualFold(args[2], "SCHEMA") {
+		level0 = args[3]
+	}
+	if len(args) == 6 && strings.EqualFold(args[0], "MS_Description") &&
+		(strings.EqualFold(args[4], "TABLE") || strings.EqualFold(args[4], "VIEW")) {
+		t := p.b.lookup(level0, args[5])
+		if t == nil {
+			p.b.warn(line, "description of unknown table %s skipped", args[5])
+			return
+		}
+		t.table.Description = args[1]
+		return
+	}
+	if len(args) != 8 || !strings.EqualFold(args[0], "MS_Description") || !strings.EqualFold(args[6], "COLUMN") {
+		p.b.warn(line, "sp_addextendedproperty call not imported")
+		return
+	}
+	var f *schema.Field
+	if t := p.b.lookup(level0, args[5]); t != nil {
+		f = t.field(args[7])
+	}
+	if f == nil {
+		p.b.warn(line, "description of unknown column %s.%s skipped", args[5], args[7])
+		return
+	}
+	f.Comment = args[1]
+}
+
+// alterTable handles "ALTER TABLE [IF EXISTS] [ONLY] name action [, ...]", importing
+// ADD COLUMN and ADD [CONSTRAINT] actions.
+func (p *ddlParser) alterTable(s *tokStream, line int) {
+	s.accept("IF", "EXISTS")
+	s.accept("ONLY")
+	schemaName, name, ok := p.schemaTableName(s)
+	if !ok {
+		p.b.warn(line, "ALTER TABLE without a table name skipped")
+		return
+	}
+	t := p.b.lookup(schemaName, name)
+	if t == nil {
+		p.b.warn(line, "ALTER TABLE on unknown table %s skipped", name)
+		return
+	}
+	// SQL Server: ALTER TABLE t WITH [NO]CHECK ADD CONSTRAINT ...
+	_ = s.accept("WITH", "CHECK") || s.accept("WITH", "NOCHECK")
+	for _, action := range splitTopLevel(s.toks[s.pos:], ",", p.d.lex.angleTokens) {
+		as := &tokStream{toks: action}
+		if len(action) == 0 {
+			continue
+		}
+		aline := action[0].line
+		switch {
+		case as.accept("ADD"):
+		case as.accept("CHECK", "CONSTRAINT"), as.accept("NOCHECK", "CONSTRAINT"):
+			// SQL Server scripts enable constraints after adding them.
+			continue
+		default:
+			p.b.warn(aline, "ALTER TABLE %s %s not imported", t.table.Name, statementLabel(action))
+			continue
+		}
+		if p.isTableConstraint(as) {
+			p.tableConstraint(t, as)
+			continue
+		}
+		as.accept("COLUMN")
+		as.accept("IF", "NOT", "EXISTS")
+		p.column(t, as)
+	}
+}
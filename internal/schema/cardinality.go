@@ -0,0 +1,143 @@
+package schema
+
+// InferCardinalities sets the cardinality of every relationship in rels that has none,
+// from the keys of the tables it joins. The parent (source) end is "0/1" when any foreign
+// key column is nullable and "1" otherwise. The child (target) end is "0/1" when the
+// foreign key columns are unique (a unique column, the whole primary key or a unique
+// index) and "0/many" otherwise. The two relationships of a junction table, whose primary
+// key is made up of exactly two foreign keys, are "many-to-many": together they stand for
+// the many-to-many relationship between the two parents.
+func InferCardinalities(tables []Table, rels []Relationship) {
+	tableByID := make(map[string]*Table, len(tables))
+	for i := range tables {
+		tableByID[tables[i].ID] = &tables[i]
+	}
+	junction := junctionRelationships(tableByID, rels)
+	for i := range rels {
+		r := &rels[i]
+		if r.Cardinality != "" {
+			continue
+		}
+		child := tableByID[r.TargetTableID]
+		if child == nil {
+			continue
+		}
+		if junction[i] {
+			r.Cardinality = "many-to-many"
+			continue
+		}
+		_, tgtIDs := r.FieldIDPairs()
+		parent, many := "1", "0/many"
+		for _, id := range tgtIDs {
+			if f := fieldByID(child, id); f != nil && f.Nullable {
+				parent = "0/1"
+			}
+		}
+		if uniqueFields(child, tgtIDs) {
+			many = "0/1"
+		}
+		r.Cardinality = parent + "-to-" + many
+	}
+}
+
+// junctionRelationships returns the indexes in rels of the relationships into junction
+// tables: tables with exactly two relationships whose foreign key columns lie in the
+// primary key and together make up all of it.
+func junctionRelationships(tableByID map[string]*Table, rels []Relationship) map[int]bool {
+	byChild := make(map[string][]int)
+	for i, r := range rels {
+		child := tableByID[r.TargetTableID]
+		if child == nil {
+			continue
+		}
+		_, tgtIDs := r.FieldIDPairs()
+		inPK := true
+		for _, id := range tgtIDs {
+			if f := fieldByID(child, id); f == nil || !f.PrimaryKey {
+				inPK = false
+			}
+		}
+		if inPK {
+			byChild[child.ID] = append(byChild[child.ID], i)
+		}
+	}
+
+	junction := make(map[int]bool)
+	for id, idx := range byChild {
+		if len(idx) != 2 {
+			continue
+		}
+		covered := make(map[string]bool)
+		for _, i := range idx {
+			_, tgtIDs := rels[i].FieldIDPairs()
+			for _, fid := range tgtIDs {
+				covered[fid] = true
+			}
+		}
+		all := true
+		for _, f := range tableByID[id].Fields {
+			if f.PrimaryKey && !covered[f.ID] {
+				all = false
+			}
+		}
+		// Two foreign keys on the same columns do not make a junction table.
+		if all && len(covered) >= 2 {
+			junction[idx[0]], junction[idx[1]] = true, true
+		}
+	}
+	return junction
+}
+
+// uniqueFields reports whether no two rows of t can share values in the fields ids: a
+// single unique field, the whole primary key, or the columns of a full unique index.
+func uniqueFields(t *Table, ids []string) bool {
+	if len(ids) == 0 {
+		return false
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	if len(ids) == 1 {
+		if f := fieldByID(t, ids[0]); f != nil && f.Unique {
+			return true
+		}
+	}
+	var pk int
+	for _, f := range t.Fields {
+		if f.PrimaryKey {
+			if !set[f.ID] {
+				pk = -1
+				break
+			}
+			pk++
+		}
+	}
+	if pk == len(set) {
+		return true
+	}
+	for _, ix := range t.Indexes {
+		if !ix.Unique || ix.Where != "" || len(ix.Columns) != len(set) {
+			continue
+		}
+		covers := true
+		for _, c := range ix.Columns {
+			if c.Expression != "" || !set[c.FieldID] {
+				covers = false
+			}
+		}
+		if covers {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldByID(t *Table, id string) *Field {
+	for i := range t.Fields {
+		if t.Fields[i].ID == id {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
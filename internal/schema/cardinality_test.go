@@ -0,0 +1,79 @@
+package schema
+
+import "testing"
+
+func TestInferCardinalities(t *testing.T) {
+	tables := []Table{
+		{ID: "users", Fields: []Field{{ID: "u.id", PrimaryKey: true}}},
+		{ID: "groups", Fields: []Field{{ID: "g.id", PrimaryKey: true}}},
+		{ID: "posts", Fields: []Field{
+			{ID: "p.id", PrimaryKey: true},
+			{ID: "p.author", Nullable: false},
+			{ID: "p.editor", Nullable: true},
+		}},
+		{ID: "profiles", Fields: []Field{
+			{ID: "pr.user", PrimaryKey: true},
+			{ID: "pr.backup", Nullable: true, Unique: true},
+		}},
+		{ID: "members", Fields: []Field{
+			{ID: "m.user", PrimaryKey: true},
+			{ID: "m.group", PrimaryKey: true},
+		}},
+		{ID: "badges", Fields: []Field{
+			{ID: "b.user", PrimaryKey: true},
+			{ID: "b.group", PrimaryKey: true},
+			{ID: "b.seq", PrimaryKey: true},
+			{ID: "b.owner"},
+		}, Indexes: []Index{{Name: "badges_owner", Unique: true, Columns: []IndexColumn{{FieldID: "b.owner"}}}}},
+	}
+	rels := []Relationship{
+		{SourceTableID: "users", SourceFieldID: "u.id", TargetTableID: "posts", TargetFieldID: "p.author"},
+		{SourceTableID: "users", SourceFieldID: "u.id", TargetTableID: "posts", TargetFieldID: "p.editor"},
+		{SourceTableID: "users", SourceFieldID: "u.id", TargetTableID: "profiles", TargetFieldID: "pr.user"},
+		{SourceTableID: "users", SourceFieldID: "u.id", TargetTableID: "profiles", TargetFieldID: "pr.backup"},
+		{SourceTableID: "users", SourceFieldID: "u.id", TargetTableID: "members", TargetFieldID: "m.user"},
+		{SourceTableID: "groups", SourceFieldID: "g.id", TargetTableID: "members", TargetFieldID: "m.group"},
+		// badges has a third key column, so it is not a junction table.
+		{SourceTableID: "users", SourceFieldID: "u.id", TargetTableID: "badges", TargetFieldID: "b.user"},
+		{SourceTableID: "groups", SourceFieldID: "g.id", TargetTableID: "badges", TargetFieldID: "b.group"},
+		{SourceTableID: "users", SourceFieldID: "u.id", TargetTableID: "badges", TargetFieldID: "b.owner"},
+		{SourceTableID: "users", SourceFieldID: "u.id", TargetTableID: "posts", TargetFieldID: "p.author", Cardinality: "1-to-many"},
+	}
+	InferCardinalities(tables, rels)
+	want := []string{
+		"1-to-0/many",
+		"0/1-to-0/many",
+		"1-to-0/1",
+		"0/1-to-0/1",
+		"many-to-many",
+		"many-to-many",
+		"1-to-0/many",
+		"1-to-0/many",
+		"1-to-0/1",
+		"1-to-many", // set already
+	}
+	for i, r := range rels {
+		if r.Cardinality != want[i] {
+			t.Errorf("relationship %d (%s.%s): cardinality %q, want %q", i, r.TargetTableID, r.TargetFieldID, r.Cardinality, want[i])
+		}
+	}
+}
+
+func TestInferCardinalities_CompositeKey(t *testing.T) {
+	tables := []Table{
+		{ID: "orders", Fields: []Field{{ID: "o.region", PrimaryKey: true}, {ID: "o.no", PrimaryKey: true}}},
+		{ID: "invoices", Fields: []Field{
+			{ID: "i.id", PrimaryKey: true},
+			{ID: "i.region"},
+			{ID: "i.no", Nullable: true},
+		}, Indexes: []Index{{Name: "invoices_order", Unique: true, Columns: []IndexColumn{{FieldID: "i.no"}, {FieldID: "i.region"}}}}},
+	}
+	rels := []Relationship{{
+		SourceTableID: "orders", SourceFieldID: "o.region", TargetTableID: "invoices", TargetFieldID: "i.region",
+		SourceFieldIDs: []string{"o.region", "o.no"}, TargetFieldIDs: []string{"i.region", "i.no"},
+	}}
+	InferCardinalities(tables, rels)
+	if rels[0].Cardinality != "0/1-to-0/1" {
+		t.Errorf("cardinality %q, want 0/1-to-0/1", rels[0].Cardinality)
+	}
+}
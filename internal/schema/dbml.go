@@ -0,0 +1,272 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	dbmlIdent      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	dbmlNumber     = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	dbmlSQLLiteral = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+)
+
+// ToDBML outputs DBML (as used by dbdiagram.io) from the diagram: a Table per table with
+// column settings, composite primary keys and indexes in an indexes block, a Ref per
+// relationship and a TableGroup per table tag. A table is grouped under its first tag,
+// since DBML allows a table in only one group.
+func ToDBML(d Diagram) string {
+	var b strings.Builder
+	tblByID := make(map[string]*Table, len(d.Tables))
+	for i := range d.Tables {
+		tblByID[d.Tables[i].ID] = &d.Tables[i]
+	}
+	for i, t := range d.Tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeDBMLTable(&b, t)
+	}
+
+	var refs []string
+	for _, r := range d.Relationships {
+		if ref := dbmlRef(r, tblByID); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) > 0 {
+		b.WriteString("\n")
+		for _, ref := range refs {
+			b.WriteString(ref + "\n")
+		}
+	}
+
+	var groups []string
+	members := make(map[string][]string)
+	for _, t := range d.Tables {
+		if len(t.Tags) == 0 {
+			continue
+		}
+		g := t.Tags[0]
+		if _, ok := members[g]; !ok {
+			groups = append(groups, g)
+		}
+		members[g] = append(members[g], dbmlTableName(t))
+	}
+	for _, g := range groups {
+		fmt.Fprintf(&b, "\nTableGroup %s {\n", dbmlName(g))
+		for _, m := range members[g] {
+			b.WriteString("  " + m + "\n")
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func writeDBMLTable(b *strings.Builder, t Table) {
+	header := "Table " + dbmlTableName(t)
+	if t.Description != "" {
+		header += " [note: " + dbmlString(t.Description) + "]"
+	}
+	b.WriteString(header + " {\n")
+
+	var pk []string
+	for _, f := range t.Fields {
+		if f.PrimaryKey {
+			pk = append(pk, dbmlName(f.Name))
+		}
+	}
+	for _, f := range t.Fields {
+		var settings []string
+		if f.PrimaryKey && len(pk) == 1 {
+			settings = append(settings, "pk")
+		}
+		if f.Identity != "" {
+			settings = append(settings, "increment")
+		}
+		if !f.Nullable && !(f.PrimaryKey && len(pk) == 1) {
+			settings = append(settings, "not null")
+		}
+		if f.Unique && !f.PrimaryKey {
+			settings = append(settings, "unique")
+		}
+		if f.Default != "" {
+			settings = append(settings, "default: "+dbmlDefault(f.Default))
+		}
+		if f.Check != "" {
+			settings = append(settings, "check: `"+f.Check+"`")
+		}
+		if f.Comment != "" {
+			settings = append(settings, "note: "+dbmlString(f.Comment))
+		}
+		line := "  " + dbmlName(f.Name) + " " + dbmlType(f)
+		if len(settings) > 0 {
+			line += " [" + strings.Join(settings, ", ") + "]"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	var indexes []string
+	if len(pk) > 1 {
+		indexes = append(indexes, "("+strings.Join(pk, ", ")+") [pk]")
+	}
+	for _, ix := range t.Indexes {
+		var keys []string
+		for _, c := range ix.Columns {
+			if c.Expression != "" {
+				keys = append(keys, "`"+c.Expression+"`")
+				continue
+			}
+			for _, f := range t.Fields {
+				if f.ID == c.FieldID {
+					keys = append(keys, dbmlName(f.Name))
+				}
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		key := keys[0]
+		if len(keys) > 1 {
+			key = "(" + strings.Join(keys, ", ") + ")"
+		}
+		var settings []string
+		if ix.Unique {
+			settings = append(settings, "unique")
+		}
+		if ix.Name != "" {
+			settings = append(settings, "name: "+dbmlString(ix.Name))
+		}
+		if ix.Method != "" {
+			settings = append(settings, "type: "+ix.Method)
+		}
+		if len(settings) > 0 {
+			key += " [" + strings.Join(settings, ", ") + "]"
+		}
+		indexes = append(indexes, key)
+	}
+	if len(indexes) > 0 {
+		b.WriteString("\n  indexes {\n")
+		for _, ix := range indexes {
+			b.WriteString("    " + ix + "\n")
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+}
+
+// dbmlRef renders a relationship as a Ref with the source (parent) on the left, or ""
+// if its tables or fields are missing.
+func dbmlRef(r Relationship, tblByID map[string]*Table) string {
+	src, tgt := tblByID[r.SourceTableID], tblByID[r.TargetTableID]
+	if src == nil || tgt == nil {
+		return ""
+	}
+	srcIDs, tgtIDs := r.SourceFieldIDs, r.TargetFieldIDs
+	if len(srcIDs) == 0 || len(srcIDs) != len(tgtIDs) {
+		srcIDs, tgtIDs = []string{r.SourceFieldID}, []string{r.TargetFieldID}
+	}
+	srcCols, tgtCols := dbmlColumns(*src, srcIDs), dbmlColumns(*tgt, tgtIDs)
+	if srcCols == "" || tgtCols == "" {
+		return ""
+	}
+	ref := "Ref"
+	if r.Name != "" {
+		ref += " " + dbmlName(r.Name)
+	}
+	return fmt.Sprintf("%s: %s.%s %s %s.%s", ref, dbmlTableName(*src), srcCols, dbmlCardinality(r.Cardinality), dbmlTableName(*tgt), tgtCols)
+}
+
+// dbmlColumns returns "col" or "(a, b)" for field IDs of t, or "" if one is missing.
+func dbmlColumns(t Table, ids []string) string {
+	var names []string
+	for _, id := range ids {
+		found := false
+		for _, f := range t.Fields {
+			if f.ID == id {
+				names = append(names, dbmlName(f.Name))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ""
+		}
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// dbmlCardinality maps a cardinality string to a DBML relationship operator, read with
+// the source on the left: "<" one-to-many, ">" many-to-one, "-" one-to-one and "<>"
+// many-to-many. DBML has no optional ends, so 0/1 reads as 1 and 0/many as many. An
+// unset cardinality is one-to-many.
+func dbmlCardinality(c string) string {
+	from, to := splitCardinality(c)
+	fromMany, toMany := strings.HasSuffix(from, "many"), strings.HasSuffix(to, "many")
+	switch {
+	case from == "" || to == "":
+		return "<"
+	case fromMany && toMany:
+		return "<>"
+	case fromMany:
+		return ">"
+	case toMany:
+		return "<"
+	default:
+		return "-"
+	}
+}
+
+func dbmlTableName(t Table) string {
+	if t.Schema != "" {
+		return dbmlName(t.Schema) + "." + dbmlName(t.Name)
+	}
+	return dbmlName(t.Name)
+}
+
+// dbmlName returns name as it is, or double-quoted when it is not a plain identifier.
+func dbmlName(name string) string {
+	if dbmlIdent.MatchString(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+}
+
+// dbmlType renders a field's type with its length or precision, quoted if it has spaces.
+func dbmlType(f Field) string {
+	typ := f.Type
+	switch {
+	case f.Length != nil:
+		typ += fmt.Sprintf("(%d)", *f.Length)
+	case f.Precision != nil && f.Scale != nil:
+		typ += fmt.Sprintf("(%d,%d)", *f.Precision, *f.Scale)
+	case f.Precision != nil:
+		typ += fmt.Sprintf("(%d)", *f.Precision)
+	}
+	if strings.ContainsAny(typ, " \"") {
+		return `"` + strings.ReplaceAll(typ, `"`, `\"`) + `"`
+	}
+	return typ
+}
+
+// dbmlString returns s as a single-quoted DBML string.
+func dbmlString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", `\n`).Replace(s) + "'"
+}
+
+// dbmlDefault renders an SQL default: numbers and true/false/null as they are, string
+// literals as DBML strings and anything else as a `backtick` expression.
+func dbmlDefault(def string) string {
+	switch lower := strings.ToLower(def); {
+	case dbmlNumber.MatchString(def), lower == "true", lower == "false", lower == "null":
+		return def
+	}
+	if m := dbmlSQLLiteral.FindStringSubmatch(def); m != nil {
+		return dbmlString(strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return "`" + def + "`"
+}
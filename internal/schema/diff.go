@@ -0,0 +1,641 @@
+package schema
+
+import "strings"
+
+// SchemaDiff describes the changes needed to turn one diagram (or catalog) into another.
+// Tables are matched by schema and name and columns by name (case-insensitive; see
+// MatchTables), so IDs generated independently by importers or inspectors do not
+// matter. With DiffOptions.MatchByID, unmatched tables and columns that share an ID are
+// reported as renames.
+type SchemaDiff struct {
+	AddedTables        []Table      `json:"addedTables,omitempty"`
+	DroppedTables      []Table      `json:"droppedTables,omitempty"`
+	ModifiedTables     []TableDiff  `json:"modifiedTables,omitempty"`
+	AddedForeignKeys   []ForeignKey `json:"addedForeignKeys,omitempty"`
+	DroppedForeignKeys []ForeignKey `json:"droppedForeignKeys,omitempty"`
+	// Views are matched like tables and compared by kind and definition.
+	AddedViews   []Table      `json:"addedViews,omitempty"`
+	DroppedViews []Table      `json:"droppedViews,omitempty"`
+	ChangedViews []ViewChange `json:"changedViews,omitempty"`
+}
+
+// ViewChange records a view whose kind or definition changed.
+type ViewChange struct {
+	From Table `json:"from"`
+	To   Table `json:"to"`
+}
+
+// TableDiff holds the column-level changes for a table present on both sides.
+type TableDiff struct {
+	Schema         string        `json:"schema,omitempty"`  // Schema of the table, if it has one.
+	Name           string        `json:"name"`              // Table name in the target schema.
+	OldName        string        `json:"oldName,omitempty"` // Set when the table was renamed.
+	AddedFields    []Field       `json:"addedFields,omitempty"`
+	DroppedFields  []Field       `json:"droppedFields,omitempty"`
+	RenamedFields  []FieldRename `json:"renamedFields,omitempty"`
+	ModifiedFields []FieldChange `json:"modifiedFields,omitempty"`
+	// OldPrimaryKey and NewPrimaryKey are set only when the primary key columns changed.
+	OldPrimaryKey []string `json:"oldPrimaryKey,omitempty"`
+	NewPrimaryKey []string `json:"newPrimaryKey,omitempty"`
+	// A changed index is reported as dropped and added.
+	AddedIndexes   []IndexDef `json:"addedIndexes,omitempty"`
+	DroppedIndexes []IndexDef `json:"droppedIndexes,omitempty"`
+}
+
+// PrimaryKeyChanged reports whether the table's primary key columns differ.
+func (td TableDiff) PrimaryKeyChanged() bool {
+	return len(td.OldPrimaryKey) > 0 || len(td.NewPrimaryKey) > 0
+}
+
+// FieldRename records a column whose name changed (matched by field ID).
+type FieldRename struct {
+	OldName string `json:"oldName"`
+	NewName string `json:"newName"`
+}
+
+// FieldChange records a column whose definition changed.
+type FieldChange struct {
+	Name            string `json:"name"`
+	From            Field  `json:"from"`
+	To              Field  `json:"to"`
+	TypeChanged     bool   `json:"typeChanged,omitempty"`
+	NullableChanged bool   `json:"nullableChanged,omitempty"`
+	DefaultChanged  bool   `json:"defaultChanged,omitempty"`
+}
+
+// ForeignKey is a relationship resolved to table and column names.
+// Table/Columns are the referencing (child) side; RefTable/RefColumns the referenced (parent) side.
+type ForeignKey struct {
+	Name       string   `json:"name,omitempty"`
+	Schema     string   `json:"schema,omitempty"`
+	Table      string   `json:"table"`
+	Columns    []string `json:"columns"`
+	RefSchema  string   `json:"refSchema,omitempty"`
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns"`
+	// Ordinal is the 1-based position of this foreign key among those of its table.
+	Ordinal int `json:"ordinal"`
+}
+
+// Signature identifies the foreign key by its tables (with their schemas) and columns
+// (case-insensitive), ignoring its name.
+func (fk ForeignKey) Signature() string {
+	lower := func(ss []string) string {
+		out := make([]string, len(ss))
+		for i, s := range ss {
+			out[i] = strings.ToLower(s)
+		}
+		return strings.Join(out, ",")
+	}
+	return TableKey(fk.Schema, fk.Table) + "(" + lower(fk.Columns) + ")->" + TableKey(fk.RefSchema, fk.RefTable) + "(" + lower(fk.RefColumns) + ")"
+}
+
+// TableKey identifies a table by its schema and name, case-insensitively. A table
+// without a schema has a key of its own.
+func TableKey(schemaName, name string) string {
+	return strings.ToLower(schemaName + "." + name)
+}
+
+// MatchTables pairs the tables of from with those of to by schema and name
+// (case-insensitive). A table left unpaired that has no schema is then paired with a
+// same-named table on the other side if each is the only one left with that name, so a
+// catalog that leaves schemas out still matches a database that reports them. It
+// returns, for each table of from, the index of its match in to, or -1.
+func MatchTables(from, to []Table) []int {
+	toByKey := make(map[string]int)
+	for j := range to {
+		if _, dup := toByKey[TableKey(to[j].Schema, to[j].Name)]; !dup {
+			toByKey[TableKey(to[j].Schema, to[j].Name)] = j
+		}
+	}
+	match := make([]int, len(from))
+	matchedTo := make([]bool, len(to))
+	for i := range from {
+		match[i] = -1
+		if j, ok := toByKey[TableKey(from[i].Schema, from[i].Name)]; ok && !matchedTo[j] {
+			match[i] = j
+			matchedTo[j] = true
+		}
+	}
+
+	fromLeft := make(map[string][]int)
+	for i := range from {
+		if match[i] < 0 {
+			fromLeft[strings.ToLower(from[i].Name)] = append(fromLeft[strings.ToLower(from[i].Name)], i)
+		}
+	}
+	toLeft := make(map[string][]int)
+	for j := range to {
+		if !matchedTo[j] {
+			toLeft[strings.ToLower(to[j].Name)] = append(toLeft[strings.ToLower(to[j].Name)], j)
+		}
+	}
+	for name, fs := range fromLeft {
+		ts := toLeft[name]
+		if len(fs) == 1 && len(ts) == 1 && (from[fs[0]].Schema == "" || to[ts[0]].Schema == "") {
+			match[fs[0]] = ts[0]
+		}
+	}
+	return match
+}
+
+// IndexDef is an index resolved to column names.
+type IndexDef struct {
+	Name   string     `json:"name"`
+	Table  string     `json:"table"`
+	Keys   []IndexKey `json:"keys"`
+	Unique bool       `json:"unique,omitempty"`
+	Where  string     `json:"where,omitempty"`
+	Method string     `json:"method,omitempty"`
+}
+
+// IndexKey is an index column resolved to a column name, or an expression.
+type IndexKey struct {
+	Column     string `json:"column,omitempty"`
+	Expression string `json:"expression,omitempty"`
+	Desc       bool   `json:"desc,omitempty"`
+}
+
+// ResolveIndex resolves ix's field IDs against t. Keys whose field cannot be found are
+// skipped. An unnamed index is given the PostgreSQL default name, table_col1_col2_idx.
+func ResolveIndex(t Table, ix Index) IndexDef {
+	def := IndexDef{Name: ix.Name, Table: t.Name, Unique: ix.Unique, Where: ix.Where, Method: ix.Method}
+	names := []string{t.Name}
+	for _, c := range ix.Columns {
+		if c.Expression != "" {
+			def.Keys = append(def.Keys, IndexKey{Expression: c.Expression, Desc: c.Desc})
+			names = append(names, "expr")
+			continue
+		}
+		if name := fieldNameByID(&t, c.FieldID); name != "" {
+			def.Keys = append(def.Keys, IndexKey{Column: name, Desc: c.Desc})
+			names = append(names, name)
+		}
+	}
+	if def.Name == "" {
+		suffix := "idx"
+		if ix.Unique {
+			suffix = "key"
+		}
+		def.Name = strings.Join(append(names, suffix), "_")
+	}
+	return def
+}
+
+// ResolvedIndexes resolves all of the table's indexes, skipping any left without keys.
+func (t Table) ResolvedIndexes() []IndexDef {
+	var defs []IndexDef
+	for _, ix := range t.Indexes {
+		if def := ResolveIndex(t, ix); len(def.Keys) > 0 {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+// Signature identifies the index by its table, keys and options (case-insensitive), ignoring its name.
+func (def IndexDef) Signature() string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(def.Table))
+	b.WriteString("(")
+	for i, k := range def.Keys {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		if k.Expression != "" {
+			b.WriteString("(" + k.Expression + ")")
+		} else {
+			b.WriteString(strings.ToLower(k.Column))
+		}
+		if k.Desc {
+			b.WriteString(" desc")
+		}
+	}
+	b.WriteString(")")
+	if def.Unique {
+		b.WriteString(" unique")
+	}
+	if def.Method != "" {
+		b.WriteString(" using " + strings.ToLower(def.Method))
+	}
+	if def.Where != "" {
+		b.WriteString(" where " + def.Where)
+	}
+	return b.String()
+}
+
+// IsEmpty reports whether the diff contains no changes.
+func (sd SchemaDiff) IsEmpty() bool {
+	return len(sd.AddedTables) == 0 && len(sd.DroppedTables) == 0 && len(sd.ModifiedTables) == 0 &&
+		len(sd.AddedForeignKeys) == 0 && len(sd.DroppedForeignKeys) == 0 &&
+		len(sd.AddedViews) == 0 && len(sd.DroppedViews) == 0 && len(sd.ChangedViews) == 0
+}
+
+// DiffOptions configures DiffDiagramsWithOptions.
+type DiffOptions struct {
+	// MatchByID pairs tables and columns left unmatched by name that share an ID, and
+	// reports them as renames. Set it only when both sides come from the same source of
+	// stable IDs, such as two versions of one workspace catalog; importers and inspectors
+	// number their IDs afresh, so unrelated objects would share them.
+	MatchByID bool
+}
+
+// DiffDiagrams compares from and to by name and returns the changes that turn from into
+// to. A table or column whose name changed is reported as dropped and added.
+func DiffDiagrams(from, to Diagram) SchemaDiff {
+	return DiffDiagramsWithOptions(from, to, DiffOptions{})
+}
+
+// DiffDiagramsWithOptions is DiffDiagrams with options; see DiffOptions.
+func DiffDiagramsWithOptions(from, to Diagram, opts DiffOptions) SchemaDiff {
+	var sd SchemaDiff
+	from, fromViews := splitViews(from)
+	to, toViews := splitViews(to)
+	diffViews(&sd, fromViews, toViews)
+
+	fromByKey := make(map[string]*Table)
+	for i := range from.Tables {
+		fromByKey[TableKey(from.Tables[i].Schema, from.Tables[i].Name)] = &from.Tables[i]
+	}
+
+	// Pair tables by schema and name, then (with MatchByID) pair leftovers by ID as renames.
+	pairs := make(map[*Table]*Table) // from -> to
+	matchedTo := make(map[*Table]bool)
+	for i, j := range MatchTables(from.Tables, to.Tables) {
+		if j >= 0 {
+			pairs[&from.Tables[i]] = &to.Tables[j]
+			matchedTo[&to.Tables[j]] = true
+		}
+	}
+	if opts.MatchByID {
+		toByID := make(map[string]*Table)
+		for i := range to.Tables {
+			if tt := &to.Tables[i]; !matchedTo[tt] && tt.ID != "" {
+				toByID[tt.ID] = tt
+			}
+		}
+		for i := range from.Tables {
+			ft := &from.Tables[i]
+			if pairs[ft] != nil {
+				continue
+			}
+			if tt := toByID[ft.ID]; tt != nil && fromByKey[TableKey(tt.Schema, tt.Name)] == nil {
+				pairs[ft] = tt
+				matchedTo[tt] = true
+				delete(toByID, ft.ID)
+			}
+		}
+	}
+
+	// Name translation for foreign key comparison: from-side tables (by TableKey) -> to-side
+	// tables, and their columns.
+	tableRename := make(map[string]*Table)
+	colRename := make(map[string]map[string]string) // from-table key -> from-col (lower) -> to-col
+
+	for i := range from.Tables {
+		ft := &from.Tables[i]
+		tt := pairs[ft]
+		if tt == nil {
+			sd.DroppedTables = append(sd.DroppedTables, *ft)
+			continue
+		}
+		key := TableKey(ft.Schema, ft.Name)
+		td, renames := diffTable(*ft, *tt, opts.MatchByID)
+		tableRename[key], colRename[key] = tt, renames
+		if !td.isEmpty() {
+			sd.ModifiedTables = append(sd.ModifiedTables, td)
+		}
+	}
+	for i := range to.Tables {
+		if !matchedTo[&to.Tables[i]] {
+			sd.AddedTables = append(sd.AddedTables, to.Tables[i])
+		}
+	}
+
+	// Foreign keys: translate from-side names, then compare by signature.
+	fromFKs := ForeignKeys(from)
+	toFKs := ForeignKeys(to)
+	toSigs := make(map[string]bool)
+	for _, fk := range toFKs {
+		toSigs[fk.Signature()] = true
+	}
+	fromSigs := make(map[string]bool)
+	for _, fk := range fromFKs {
+		translated := fk
+		if key := TableKey(fk.Schema, fk.Table); tableRename[key] != nil {
+			translated.Schema, translated.Table = tableRename[key].Schema, tableRename[key].Name
+			translated.Columns = renameColumns(fk.Columns, colRename[key])
+		}
+		if key := TableKey(fk.RefSchema, fk.RefTable); tableRename[key] != nil {
+			translated.RefSchema, translated.RefTable = tableRename[key].Schema, tableRename[key].Name
+			translated.RefColumns = renameColumns(fk.RefColumns, colRename[key])
+		}
+		sig := translated.Signature()
+		fromSigs[sig] = true
+		if !toSigs[sig] {
+			sd.DroppedForeignKeys = append(sd.DroppedForeignKeys, fk)
+		}
+	}
+	for _, fk := range toFKs {
+		if !fromSigs[fk.Signature()] {
+			sd.AddedForeignKeys = append(sd.AddedForeignKeys, fk)
+		}
+	}
+	return sd
+}
+
+// splitViews returns d without its views, and the views.
+func splitViews(d Diagram) (Diagram, []Table) {
+	var tables, views []Table
+	for _, t := range d.Tables {
+		if t.IsView() {
+			views = append(views, t)
+		} else {
+			tables = append(tables, t)
+		}
+	}
+	d.Tables = tables
+	return d, views
+}
+
+// diffViews matches views like MatchTables and records added, dropped and changed ones
+// in sd. Definitions are compared with whitespace collapsed.
+func diffViews(sd *SchemaDiff, from, to []Table) {
+	match := MatchTables(from, to)
+	matchedTo := make([]bool, len(to))
+	for i, j := range match {
+		if j < 0 {
+			continue
+		}
+		matchedTo[j] = true
+		fv, tv := from[i], to[j]
+		if fv.Kind != tv.Kind || strings.Join(strings.Fields(fv.Definition), " ") != strings.Join(strings.Fields(tv.Definition), " ") {
+			sd.ChangedViews = append(sd.ChangedViews, ViewChange{From: fv, To: tv})
+		}
+	}
+	for j, tv := range to {
+		if !matchedTo[j] {
+			sd.AddedViews = append(sd.AddedViews, tv)
+		}
+	}
+	for i, fv := range from {
+		if match[i] < 0 {
+			sd.DroppedViews = append(sd.DroppedViews, fv)
+		}
+	}
+}
+
+// diffTable compares two matched tables. It returns the diff and a map of
+// renamed columns (from-name lowercased -> to-name). Columns are paired by ID as
+// renames only when matchByID is set.
+func diffTable(ft, tt Table, matchByID bool) (TableDiff, map[string]string) {
+	td := TableDiff{Schema: tt.Schema, Name: tt.Name}
+	if td.Schema == "" {
+		td.Schema = ft.Schema
+	}
+	if ft.Name != tt.Name {
+		td.OldName = ft.Name
+	}
+	renames := make(map[string]string)
+
+	toByName := make(map[string]*Field)
+	for i := range tt.Fields {
+		toByName[strings.ToLower(tt.Fields[i].Name)] = &tt.Fields[i]
+	}
+	fromByName := make(map[string]*Field)
+	for i := range ft.Fields {
+		fromByName[strings.ToLower(ft.Fields[i].Name)] = &ft.Fields[i]
+	}
+	matchedTo := make(map[*Field]bool)
+	pairs := make(map[*Field]*Field)
+	for i := range ft.Fields {
+		ff := &ft.Fields[i]
+		if tf := toByName[strings.ToLower(ff.Name)]; tf != nil {
+			pairs[ff] = tf
+			matchedTo[tf] = true
+		}
+	}
+	if matchByID {
+		toByID := make(map[string]*Field)
+		for i := range tt.Fields {
+			if tf := &tt.Fields[i]; !matchedTo[tf] && tf.ID != "" {
+				toByID[tf.ID] = tf
+			}
+		}
+		for i := range ft.Fields {
+			ff := &ft.Fields[i]
+			if pairs[ff] != nil {
+				continue
+			}
+			if tf := toByID[ff.ID]; tf != nil && fromByName[strings.ToLower(tf.Name)] == nil {
+				pairs[ff] = tf
+				matchedTo[tf] = true
+				delete(toByID, ff.ID)
+				td.RenamedFields = append(td.RenamedFields, FieldRename{OldName: ff.Name, NewName: tf.Name})
+			}
+		}
+	}
+
+	for i := range ft.Fields {
+		ff := &ft.Fields[i]
+		tf := pairs[ff]
+		if tf == nil {
+			td.DroppedFields = append(td.DroppedFields, *ff)
+			continue
+		}
+		renames[strings.ToLower(ff.Name)] = tf.Name
+		typeChanged := !sameType(*ff, *tf)
+		nullChanged := ff.Nullable != tf.Nullable
+		defaultChanged := ff.Default != tf.Default
+		if typeChanged || nullChanged || defaultChanged {
+			td.ModifiedFields = append(td.ModifiedFields, FieldChange{
+				Name:            tf.Name,
+				From:            *ff,
+				To:              *tf,
+				TypeChanged:     typeChanged,
+				NullableChanged: nullChanged,
+				DefaultChanged:  defaultChanged,
+			})
+		}
+	}
+	for i := range tt.Fields {
+		if !matchedTo[&tt.Fields[i]] {
+			td.AddedFields = append(td.AddedFields, tt.Fields[i])
+		}
+	}
+
+	oldPK := primaryKeyColumns(ft)
+	newPK := primaryKeyColumns(tt)
+	if !sameStrings(renameColumns(oldPK, renames), newPK) {
+		td.OldPrimaryKey = oldPK
+		td.NewPrimaryKey = newPK
+	}
+
+	// Indexes: translate from-side column names, then compare by signature.
+	toIdx := tt.ResolvedIndexes()
+	toSigs := make(map[string]bool)
+	for _, def := range toIdx {
+		toSigs[def.Signature()] = true
+	}
+	fromSigs := make(map[string]bool)
+	for _, def := range ft.ResolvedIndexes() {
+		translated := def
+		translated.Table = tt.Name
+		translated.Keys = make([]IndexKey, len(def.Keys))
+		for i, k := range def.Keys {
+			if n, ok := renames[strings.ToLower(k.Column)]; ok {
+				k.Column = n
+			}
+			translated.Keys[i] = k
+		}
+		sig := translated.Signature()
+		fromSigs[sig] = true
+		if !toSigs[sig] {
+			td.DroppedIndexes = append(td.DroppedIndexes, def)
+		}
+	}
+	for _, def := range toIdx {
+		if !fromSigs[def.Signature()] {
+			td.AddedIndexes = append(td.AddedIndexes, def)
+		}
+	}
+	return td, renames
+}
+
+func (td TableDiff) isEmpty() bool {
+	return td.OldName == "" && len(td.AddedFields) == 0 && len(td.DroppedFields) == 0 &&
+		len(td.RenamedFields) == 0 && len(td.ModifiedFields) == 0 && !td.PrimaryKeyChanged() &&
+		len(td.AddedIndexes) == 0 && len(td.DroppedIndexes) == 0
+}
+
+// ForeignKeys resolves the diagram's relationships to name-based foreign keys.
+// Relationships whose tables or fields cannot be resolved are skipped.
+func ForeignKeys(d Diagram) []ForeignKey {
+	tableByID := make(map[string]*Table)
+	for i := range d.Tables {
+		tableByID[d.Tables[i].ID] = &d.Tables[i]
+	}
+	ordinals := make(map[string]int)
+	var fks []ForeignKey
+	for _, r := range d.Relationships {
+		parent := tableByID[r.SourceTableID]
+		child := tableByID[r.TargetTableID]
+		if parent == nil || child == nil {
+			continue
+		}
+		srcIDs, tgtIDs := r.FieldIDPairs()
+		fk := ForeignKey{Name: r.Name, Schema: child.Schema, Table: child.Name, RefSchema: parent.Schema, RefTable: parent.Name}
+		for i := range srcIDs {
+			pc := fieldNameByID(parent, srcIDs[i])
+			cc := fieldNameByID(child, tgtIDs[i])
+			if pc == "" || cc == "" {
+				continue
+			}
+			fk.Columns = append(fk.Columns, cc)
+			fk.RefColumns = append(fk.RefColumns, pc)
+		}
+		if len(fk.Columns) == 0 {
+			continue
+		}
+		ordinals[child.ID]++
+		fk.Ordinal = ordinals[child.ID]
+		fks = append(fks, fk)
+	}
+	return fks
+}
+
+// FieldIDPairs returns the relationship's source and target field IDs as
+// equal-length slices, using SourceFieldIDs/TargetFieldIDs for composite keys
+// and SourceFieldID/TargetFieldID otherwise.
+func (r Relationship) FieldIDPairs() (sourceIDs, targetIDs []string) {
+	sourceIDs = r.SourceFieldIDs
+	targetIDs = r.TargetFieldIDs
+	if len(sourceIDs) == 0 {
+		sourceIDs = []string{r.SourceFieldID}
+	}
+	if len(targetIDs) == 0 {
+		targetIDs = []string{r.TargetFieldID}
+	}
+	n := len(sourceIDs)
+	if len(targetIDs) < n {
+		n = len(targetIDs)
+	}
+	return sourceIDs[:n], targetIDs[:n]
+}
+
+func fieldNameByID(t *Table, id string) string {
+	for _, f := range t.Fields {
+		if f.ID == id {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+func primaryKeyColumns(t Table) []string {
+	var pk []string
+	for _, f := range t.Fields {
+		if f.PrimaryKey {
+			pk = append(pk, f.Name)
+		}
+	}
+	return pk
+}
+
+func renameColumns(cols []string, renames map[string]string) []string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		if n, ok := renames[strings.ToLower(c)]; ok {
+			out[i] = n
+		} else {
+			out[i] = c
+		}
+	}
+	return out
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameType(a, b Field) bool {
+	if !strings.EqualFold(a.Type, b.Type) || !sameIntPtr(a.Length, b.Length) ||
+		!sameIntPtr(a.Precision, b.Precision) || !sameIntPtr(a.Scale, b.Scale) {
+		return false
+	}
+	if len(a.TypeOverrides) != len(b.TypeOverrides) {
+		return false
+	}
+	for k, ao := range a.TypeOverrides {
+		bo, ok := b.TypeOverrides[k]
+		if !ok || !strings.EqualFold(ao.Type, bo.Type) {
+			return false
+		}
+	}
+	if a.Repeated != b.Repeated || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	// Sub-fields are part of a struct's type; reordering them changes it too.
+	for i := range a.Fields {
+		af, bf := a.Fields[i], b.Fields[i]
+		if !strings.EqualFold(af.Name, bf.Name) || af.Nullable != bf.Nullable || !sameType(af, bf) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,258 @@
+package schema
+
+import "testing"
+
+func diffFixture() Diagram {
+	return Diagram{
+		Version: 1,
+		Tables: []Table{
+			{ID: "t1", Name: "users", Fields: []Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "name", Type: "string", Nullable: true},
+				{ID: "f3", Name: "legacy", Type: "string", Nullable: true},
+			}},
+			{ID: "t2", Name: "posts", Fields: []Field{
+				{ID: "f4", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f5", Name: "user_id", Type: "integer"},
+			}},
+		},
+		Relationships: []Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f5"},
+		},
+	}
+}
+
+func TestDiffDiagrams_NoChanges(t *testing.T) {
+	sd := DiffDiagrams(diffFixture(), diffFixture())
+	if !sd.IsEmpty() {
+		t.Errorf("expected empty diff, got %+v", sd)
+	}
+}
+
+func TestDiffDiagrams_MatchesByNameNotID(t *testing.T) {
+	from := diffFixture()
+	to := diffFixture()
+	// Same schema imported again with different generated IDs.
+	to.Tables[0].ID, to.Tables[1].ID = "x1", "x2"
+	to.Tables[0].Fields[0].ID = "y1"
+	to.Tables[1].Fields[1].ID = "y5"
+	to.Relationships[0] = Relationship{ID: "z1", SourceTableID: "x1", SourceFieldID: "y1", TargetTableID: "x2", TargetFieldID: "y5"}
+	sd := DiffDiagrams(from, to)
+	if !sd.IsEmpty() {
+		t.Errorf("expected empty diff, got %+v", sd)
+	}
+}
+
+func TestDiffDiagrams_SameNameInTwoSchemas(t *testing.T) {
+	from := diffFixture()
+	from.Tables[0].Schema, from.Tables[1].Schema = "app", "app"
+	archived := from.Tables[1]
+	archived.ID, archived.Schema = "t3", "archive"
+	archived.Fields = []Field{{ID: "f6", Name: "id", Type: "integer", PrimaryKey: true}}
+	from.Tables = append(from.Tables, archived)
+
+	// archive.posts gains a column; app.posts is unchanged and keeps its foreign key.
+	to := diffFixture()
+	to.Tables[0].Schema, to.Tables[1].Schema = "app", "app"
+	toArchived := archived
+	toArchived.Fields = append(append([]Field{}, archived.Fields...), Field{ID: "f7", Name: "note", Type: "string", Nullable: true})
+	to.Tables = append([]Table{toArchived}, to.Tables...)
+
+	sd := DiffDiagrams(from, to)
+	if len(sd.AddedTables) != 0 || len(sd.DroppedTables) != 0 || len(sd.AddedForeignKeys) != 0 || len(sd.DroppedForeignKeys) != 0 {
+		t.Fatalf("diff = %+v", sd)
+	}
+	if len(sd.ModifiedTables) != 1 || sd.ModifiedTables[0].Schema != "archive" || len(sd.ModifiedTables[0].AddedFields) != 1 {
+		t.Errorf("modified tables = %+v", sd.ModifiedTables)
+	}
+
+	// A foreign key moving to the other posts table is a change.
+	to.Relationships[0].TargetTableID, to.Relationships[0].TargetFieldID = "t3", "f6"
+	sd = DiffDiagrams(from, to)
+	if len(sd.DroppedForeignKeys) != 1 || sd.DroppedForeignKeys[0].Schema != "app" ||
+		len(sd.AddedForeignKeys) != 1 || sd.AddedForeignKeys[0].Schema != "archive" {
+		t.Errorf("foreign keys: dropped %+v, added %+v", sd.DroppedForeignKeys, sd.AddedForeignKeys)
+	}
+
+	// Without schemas on one side, the only table of a name still matches; with two
+	// candidates neither does.
+	plain, app := diffFixture(), diffFixture()
+	app.Tables[0].Schema, app.Tables[1].Schema = "app", "app"
+	if sd := DiffDiagrams(plain, app); !sd.IsEmpty() {
+		t.Errorf("diff against a catalog without schemas = %+v", sd)
+	}
+	if sd := DiffDiagrams(plain, to); len(sd.DroppedTables) != 1 || len(sd.AddedTables) != 2 {
+		t.Errorf("ambiguous match: dropped %+v, added %+v", sd.DroppedTables, sd.AddedTables)
+	}
+}
+
+func TestDiffDiagrams_ColumnAndTableChanges(t *testing.T) {
+	from := diffFixture()
+	to := diffFixture()
+	users := &to.Tables[0]
+	// name: type and nullability change; legacy dropped; email added.
+	users.Fields[1].Nullable = false
+	users.Fields[1].Length = intPtr(100)
+	users.Fields = append(users.Fields[:2], Field{ID: "f9", Name: "email", Type: "string"})
+	// posts renamed (matched by ID).
+	to.Tables[1].Name = "articles"
+	to.Tables = append(to.Tables, Table{ID: "t3", Name: "tags", Fields: []Field{{ID: "f10", Name: "id", Type: "integer"}}})
+
+	sd := DiffDiagramsWithOptions(from, to, DiffOptions{MatchByID: true})
+	if len(sd.AddedTables) != 1 || sd.AddedTables[0].Name != "tags" {
+		t.Errorf("expected tags added, got %+v", sd.AddedTables)
+	}
+	if len(sd.DroppedTables) != 0 {
+		t.Errorf("expected no dropped tables, got %+v", sd.DroppedTables)
+	}
+	if len(sd.ModifiedTables) != 2 {
+		t.Fatalf("expected 2 modified tables, got %+v", sd.ModifiedTables)
+	}
+	ut := sd.ModifiedTables[0]
+	if len(ut.AddedFields) != 1 || ut.AddedFields[0].Name != "email" {
+		t.Errorf("expected email added, got %+v", ut.AddedFields)
+	}
+	if len(ut.DroppedFields) != 1 || ut.DroppedFields[0].Name != "legacy" {
+		t.Errorf("expected legacy dropped, got %+v", ut.DroppedFields)
+	}
+	if len(ut.ModifiedFields) != 1 || !ut.ModifiedFields[0].TypeChanged || !ut.ModifiedFields[0].NullableChanged {
+		t.Errorf("expected name type+nullability change, got %+v", ut.ModifiedFields)
+	}
+	pt := sd.ModifiedTables[1]
+	if pt.Name != "articles" || pt.OldName != "posts" {
+		t.Errorf("expected posts renamed to articles, got %+v", pt)
+	}
+	// The FK moves with the renamed table and must not be reported as changed.
+	if len(sd.AddedForeignKeys) != 0 || len(sd.DroppedForeignKeys) != 0 {
+		t.Errorf("expected unchanged foreign keys, got added=%+v dropped=%+v", sd.AddedForeignKeys, sd.DroppedForeignKeys)
+	}
+}
+
+func TestDiffDiagrams_RenamesNeedMatchByID(t *testing.T) {
+	from := diffFixture()
+	to := diffFixture()
+	// Generated IDs collide across unrelated imports: posts and legacy are gone, and the
+	// new tables and columns happen to reuse their IDs.
+	to.Tables[0].Fields[2] = Field{ID: "f3", Name: "email", Type: "string", Nullable: true}
+	to.Tables[1].Name = "audit_log"
+	sd := DiffDiagrams(from, to)
+	if len(sd.DroppedTables) != 1 || sd.DroppedTables[0].Name != "posts" || len(sd.AddedTables) != 1 || sd.AddedTables[0].Name != "audit_log" {
+		t.Errorf("expected posts dropped and audit_log added, got dropped=%+v added=%+v", sd.DroppedTables, sd.AddedTables)
+	}
+	if len(sd.ModifiedTables) != 1 {
+		t.Fatalf("expected users modified, got %+v", sd.ModifiedTables)
+	}
+	ut := sd.ModifiedTables[0]
+	if len(ut.RenamedFields) != 0 || len(ut.DroppedFields) != 1 || len(ut.AddedFields) != 1 {
+		t.Errorf("expected legacy dropped and email added, got %+v", ut)
+	}
+
+	sd = DiffDiagramsWithOptions(from, to, DiffOptions{MatchByID: true})
+	if len(sd.DroppedTables) != 0 || len(sd.ModifiedTables) != 2 || sd.ModifiedTables[1].OldName != "posts" {
+		t.Errorf("MatchByID: expected posts renamed, got %+v", sd)
+	}
+	if rf := sd.ModifiedTables[0].RenamedFields; len(rf) != 1 || rf[0].OldName != "legacy" || rf[0].NewName != "email" {
+		t.Errorf("MatchByID: expected legacy renamed to email, got %+v", rf)
+	}
+}
+
+func TestDiffDiagrams_PrimaryKeyAndForeignKeys(t *testing.T) {
+	from := diffFixture()
+	to := diffFixture()
+	to.Tables[1].Fields[1].PrimaryKey = true
+	to.Relationships = nil
+
+	sd := DiffDiagrams(from, to)
+	if len(sd.ModifiedTables) != 1 || !sd.ModifiedTables[0].PrimaryKeyChanged() {
+		t.Fatalf("expected posts primary key change, got %+v", sd.ModifiedTables)
+	}
+	if got := sd.ModifiedTables[0].NewPrimaryKey; len(got) != 2 {
+		t.Errorf("expected 2-column new primary key, got %v", got)
+	}
+	if len(sd.DroppedForeignKeys) != 1 {
+		t.Fatalf("expected 1 dropped foreign key, got %+v", sd.DroppedForeignKeys)
+	}
+	fk := sd.DroppedForeignKeys[0]
+	if fk.Table != "posts" || fk.RefTable != "users" || fk.Columns[0] != "user_id" || fk.RefColumns[0] != "id" || fk.Ordinal != 1 {
+		t.Errorf("unexpected dropped foreign key %+v", fk)
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestDiffDiagrams_Indexes(t *testing.T) {
+	from := diffFixture()
+	from.Tables[0].Indexes = []Index{{Columns: []IndexColumn{{FieldID: "f2"}}}}
+	to := diffFixture()
+	// Renaming the indexed column keeps the index; a changed key order replaces it.
+	to.Tables[0].Fields[1].Name = "full_name"
+	to.Tables[0].Indexes = []Index{{Columns: []IndexColumn{{FieldID: "f2"}}}}
+	byID := DiffOptions{MatchByID: true}
+	if td := DiffDiagramsWithOptions(from, to, byID).ModifiedTables; len(td) != 1 || len(td[0].AddedIndexes) != 0 || len(td[0].DroppedIndexes) != 0 {
+		t.Errorf("rename should not touch the index: %+v", td)
+	}
+
+	to.Tables[0].Indexes = []Index{{Columns: []IndexColumn{{FieldID: "f2", Desc: true}}}}
+	td := DiffDiagramsWithOptions(from, to, byID).ModifiedTables
+	if len(td) != 1 || len(td[0].DroppedIndexes) != 1 || len(td[0].AddedIndexes) != 1 {
+		t.Fatalf("expected one dropped and one added index, got %+v", td)
+	}
+	if got := td[0].DroppedIndexes[0].Name; got != "users_name_idx" {
+		t.Errorf("dropped index name = %q, want users_name_idx", got)
+	}
+	if k := td[0].AddedIndexes[0].Keys[0]; k.Column != "full_name" || !k.Desc {
+		t.Errorf("added index key = %+v", k)
+	}
+}
+
+func TestDiffDiagrams_Views(t *testing.T) {
+	from := diffFixture()
+	from.Tables = append(from.Tables,
+		Table{ID: "v1", Name: "active_users", Kind: KindView, Definition: "SELECT id FROM users"},
+		Table{ID: "v2", Name: "post_counts", Kind: KindView, Definition: "SELECT user_id, count(*) FROM posts GROUP BY user_id"},
+	)
+	to := diffFixture()
+	to.Tables = append(to.Tables,
+		// Whitespace-only change: not reported.
+		Table{ID: "v1", Name: "active_users", Kind: KindView, Definition: "SELECT id\n  FROM users"},
+		Table{ID: "v2", Name: "post_counts", Kind: KindMaterializedView, Definition: "SELECT user_id, count(*) FROM posts GROUP BY user_id"},
+		Table{ID: "v3", Name: "recent_posts", Kind: KindView, Definition: "SELECT id FROM posts"},
+	)
+	sd := DiffDiagrams(from, to)
+	if len(sd.AddedTables) != 0 || len(sd.DroppedTables) != 0 || len(sd.ModifiedTables) != 0 {
+		t.Errorf("views should not be diffed as tables: %+v", sd)
+	}
+	if len(sd.AddedViews) != 1 || sd.AddedViews[0].Name != "recent_posts" {
+		t.Errorf("added views = %+v", sd.AddedViews)
+	}
+	if len(sd.ChangedViews) != 1 || sd.ChangedViews[0].To.Name != "post_counts" {
+		t.Errorf("changed views = %+v", sd.ChangedViews)
+	}
+	sd = DiffDiagrams(to, from)
+	if len(sd.DroppedViews) != 1 || sd.DroppedViews[0].Name != "recent_posts" {
+		t.Errorf("dropped views = %+v", sd.DroppedViews)
+	}
+}
+
+func TestDiffDiagrams_NestedFields(t *testing.T) {
+	table := func(sub ...Field) Diagram {
+		return Diagram{Tables: []Table{{ID: "t1", Name: "events", Fields: []Field{
+			{ID: "f1", Name: "device", Type: TypeStruct, Fields: sub},
+			{ID: "f2", Name: "tags", Type: "string", Repeated: true},
+		}}}}
+	}
+	from := table(Field{ID: "a", Name: "os", Type: "string"})
+	// Sub-field IDs do not matter.
+	if sd := DiffDiagrams(from, table(Field{ID: "b", Name: "os", Type: "string"})); !sd.IsEmpty() {
+		t.Errorf("expected no changes, got %+v", sd)
+	}
+	sd := DiffDiagrams(from, table(Field{ID: "a", Name: "os", Type: "string"}, Field{ID: "c", Name: "model", Type: "string"}))
+	if len(sd.ModifiedTables) != 1 || len(sd.ModifiedTables[0].ModifiedFields) != 1 || !sd.ModifiedTables[0].ModifiedFields[0].TypeChanged {
+		t.Errorf("expected device type change, got %+v", sd.ModifiedTables)
+	}
+	to := table(Field{ID: "a", Name: "os", Type: "string"})
+	to.Tables[0].Fields[1].Repeated = false
+	if sd := DiffDiagrams(from, to); len(sd.ModifiedTables) != 1 || sd.ModifiedTables[0].ModifiedFields[0].Name != "tags" {
+		t.Errorf("expected tags type change, got %+v", sd.ModifiedTables)
+	}
+}
@@ -0,0 +1,162 @@
+package schema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	mermaidEntityName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	mermaidWordChars  = regexp.MustCompile(`[^A-Za-z0-9_\-\[\]()]+`)
+)
+
+// Mermaid crow's foot markers for each end of a relationship, keyed by the cardinality
+// of that end. Left markers are written before the line, right markers after it.
+var (
+	mermaidLeftMarkers  = map[string]string{"1": "||", "0/1": "|o", "many": "}|", "0/many": "}o"}
+	mermaidRightMarkers = map[string]string{"1": "||", "0/1": "o|", "many": "|{", "0/many": "o{"}
+)
+
+// ToMermaid outputs Mermaid ERD syntax from the diagram. Columns carry PK, FK and UK keys
+// and their comments. Relationships are written from source to target with the markers
+// for their cardinality (one to zero-or-more when unset), a solid line when identifying
+// (the target's columns are part of its primary key) and a dashed one otherwise, and the
+// relationship label or name.
+func ToMermaid(d Diagram) string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	tblByID := make(map[string]*Table, len(d.Tables))
+	for i := range d.Tables {
+		tblByID[d.Tables[i].ID] = &d.Tables[i]
+	}
+	foreignKeys := make(map[string]bool) // target field IDs
+	for _, r := range d.Relationships {
+		_, tgtIDs := r.FieldIDPairs()
+		for _, id := range tgtIDs {
+			foreignKeys[id] = true
+		}
+	}
+
+	for _, t := range d.Tables {
+		b.WriteString("    " + mermaidName(t.Name) + " {\n")
+		for _, f := range t.Fields {
+			line := "        " + mermaidWord(mermaidType(f)) + " " + mermaidWord(f.Name)
+			var keys []string
+			if f.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if foreignKeys[f.ID] {
+				keys = append(keys, "FK")
+			}
+			if f.Unique && !f.PrimaryKey {
+				keys = append(keys, "UK")
+			}
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			if f.Comment != "" {
+				line += " " + mermaidString(f.Comment)
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, r := range d.Relationships {
+		src, tgt := r.SourceTableID, r.TargetTableID
+		if t := tblByID[src]; t != nil {
+			src = t.Name
+		}
+		if t := tblByID[tgt]; t != nil {
+			tgt = t.Name
+		}
+		from, to := splitCardinality(r.Cardinality)
+		left, ok := mermaidLeftMarkers[from]
+		if !ok {
+			left = "||"
+		}
+		right, ok := mermaidRightMarkers[to]
+		if !ok {
+			right = "o{"
+		}
+		line := ".."
+		if identifying(r, tblByID[r.TargetTableID]) {
+			line = "--"
+		}
+		label := r.Label
+		if label == "" {
+			label = r.Name
+		}
+		b.WriteString("    " + mermaidName(src) + " " + left + line + right + " " + mermaidName(tgt) + " : " + mermaidString(label) + "\n")
+	}
+	return b.String()
+}
+
+// splitCardinality splits a cardinality such as "1-to-0/many" into its source and target
+// ends, or returns two empty strings if it is unset or malformed.
+func splitCardinality(c string) (string, string) {
+	from, to, ok := strings.Cut(c, "-to-")
+	if !ok {
+		return "", ""
+	}
+	return from, to
+}
+
+// identifying reports whether a relationship's target columns are all part of the target
+// table's primary key.
+func identifying(r Relationship, target *Table) bool {
+	if target == nil {
+		return false
+	}
+	_, tgtIDs := r.FieldIDPairs()
+	for _, id := range tgtIDs {
+		pk := false
+		for _, f := range target.Fields {
+			if f.ID == id {
+				pk = f.PrimaryKey
+			}
+		}
+		if !pk {
+			return false
+		}
+	}
+	return len(tgtIDs) > 0
+}
+
+// mermaidType returns a field's type with its length or precision. Mermaid attribute
+// types cannot contain commas, so a scale is left out.
+func mermaidType(f Field) string {
+	switch {
+	case f.Length != nil:
+		return f.Type + "(" + strconv.Itoa(*f.Length) + ")"
+	case f.Precision != nil:
+		return f.Type + "(" + strconv.Itoa(*f.Precision) + ")"
+	}
+	return f.Type
+}
+
+// mermaidName returns an entity name as it is, or double-quoted when Mermaid would not
+// accept it bare.
+func mermaidName(name string) string {
+	if mermaidEntityName.MatchString(name) {
+		return name
+	}
+	return mermaidString(name)
+}
+
+// mermaidWord makes an attribute type or name a single Mermaid word, replacing the
+// characters Mermaid does not allow with underscores.
+func mermaidWord(s string) string {
+	s = mermaidWordChars.ReplaceAllString(s, "_")
+	if s == "" || !(s[0] == '_' || (s[0] >= 'A' && s[0] <= 'Z') || (s[0] >= 'a' && s[0] <= 'z')) {
+		s = "_" + s
+	}
+	return s
+}
+
+// mermaidString returns s double-quoted. Mermaid strings have no escapes, so double quotes
+// become single quotes and line breaks spaces.
+func mermaidString(s string) string {
+	return `"` + strings.NewReplacer(`"`, "'", "\r\n", " ", "\n", " ").Replace(s) + `"`
+}
@@ -19,15 +19,66 @@ type TableCatalog struct {
 	ImportSource   string         `json:"importSource"` // File name the catalog was imported from.
 	Tables         []Table        `json:"tables"`
 	Relationships   []Relationship `json:"relationships"`
+	Warnings       []ImportWarning `json:"warnings,omitempty"` // Constructs the importer skipped or only partly imported.
+	SourceDialect  string          `json:"sourceDialect,omitempty"` // SQL dialect the catalog was parsed as (SQL imports only).
+}
+
+// ImportWarning reports a statement or clause an importer could not fully represent.
+type ImportWarning struct {
+	Line    int    `json:"line,omitempty"` // 1-based line in the source, 0 if unknown.
+	Message string `json:"message"`
 }
 
 // Table represents a table on the canvas.
 type Table struct {
-	ID     string  `json:"id"`
-	Name   string  `json:"name"`
-	X      float64 `json:"x"`
-	Y      float64 `json:"y"`
-	Fields []Field `json:"fields"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Schema      string   `json:"schema,omitempty"` // Namespace: PostgreSQL/SQL Server schema, MySQL database or BigQuery dataset.
+	X           float64  `json:"x"`
+	Y           float64  `json:"y"`
+	Fields      []Field  `json:"fields"`
+	Indexes     []Index  `json:"indexes,omitempty"`
+	Description string   `json:"description,omitempty"` // Exported as the table comment.
+	Owner       string   `json:"owner,omitempty"`       // Team or person responsible; not exported.
+	Tags        []string `json:"tags,omitempty"`
+	Kind        string   `json:"kind,omitempty"`       // "" for a base table, KindView or KindMaterializedView.
+	Definition  string   `json:"definition,omitempty"` // View query (the SELECT after AS); views only.
+}
+
+// Table kinds for Table.Kind. Views keep their columns in Fields, derived from the query.
+const (
+	KindView             = "view"
+	KindMaterializedView = "materialized_view"
+)
+
+// IsView reports whether the table is a view or materialized view.
+func (t Table) IsView() bool {
+	return t.Kind == KindView || t.Kind == KindMaterializedView
+}
+
+// QualifiedName returns schema.name, or name when the table has no schema.
+func (t Table) QualifiedName() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// Index is a secondary index on a table. The primary key is modeled by Field.PrimaryKey
+// and single-column unique constraints by Field.Unique.
+type Index struct {
+	Name    string        `json:"name"`
+	Columns []IndexColumn `json:"columns"`
+	Unique  bool          `json:"unique,omitempty"`
+	Where   string        `json:"where,omitempty"`  // Partial index predicate, without WHERE.
+	Method  string        `json:"method,omitempty"` // Access method, e.g. "btree", "gin" or "hash"; "fulltext"/"spatial" (MySQL); "clustered" (SQL Server).
+}
+
+// IndexColumn is one key of an index: a field, or an SQL expression for expression indexes.
+type IndexColumn struct {
+	FieldID    string `json:"fieldId,omitempty"`
+	Expression string `json:"expression,omitempty"`
+	Desc       bool   `json:"desc,omitempty"`
 }
 
 // FieldTypeOverride holds a per-database type override for a field.
@@ -46,8 +97,26 @@ type Field struct {
 	Precision     *int                         `json:"precision,omitempty"`
 	Scale         *int                         `json:"scale,omitempty"`
 	TypeOverrides map[string]FieldTypeOverride `json:"typeOverrides,omitempty"`
+	Default       string                       `json:"default,omitempty"`  // SQL expression, e.g. "0", "'draft'" or "now()".
+	Identity      string                       `json:"identity,omitempty"` // IdentityAlways or IdentityByDefault for auto-numbered columns.
+	Unique        bool                         `json:"unique,omitempty"`
+	Check         string                       `json:"check,omitempty"` // CHECK expression without the surrounding parentheses.
+	Comment       string                       `json:"comment,omitempty"`
+	Repeated      bool                         `json:"repeated,omitempty"` // An array of Type: BigQuery REPEATED mode.
+	Fields        []Field                      `json:"fields,omitempty"`   // Sub-fields of a TypeStruct field, in order.
 }
 
+// TypeStruct is the generic type of a field made of named sub-fields (a BigQuery RECORD
+// or STRUCT), held in Field.Fields.
+const TypeStruct = "struct"
+
+// Identity generation modes for Field.Identity. MySQL AUTO_INCREMENT and PostgreSQL
+// serial columns are "by_default"; SQL Server IDENTITY columns are "always".
+const (
+	IdentityAlways    = "always"
+	IdentityByDefault = "by_default"
+)
+
 // Relationship links source field(s) to target field(s).
 type Relationship struct {
 	ID              string   `json:"id"`
@@ -91,35 +160,6 @@ func (d Diagram) MarshalJSONString() (string, error) {
 	return string(b), nil
 }
 
-// ToMermaid outputs Mermaid ERD syntax from the diagram.
-func ToMermaid(d Diagram) string {
-	var out string
-	out = "erDiagram\n"
-	tblByID := make(map[string]string)
-	for _, t := range d.Tables {
-		tblByID[t.ID] = t.Name
-	}
-	for _, t := range d.Tables {
-		out += "    " + t.Name + " {\n"
-		for _, f := range t.Fields {
-			out += "        " + f.Type + " " + f.Name + "\n"
-		}
-		out += "    }\n"
-	}
-	for _, r := range d.Relationships {
-		src := tblByID[r.SourceTableID]
-		tgt := tblByID[r.TargetTableID]
-		if src == "" {
-			src = r.SourceTableID
-		}
-		if tgt == "" {
-			tgt = r.TargetTableID
-		}
-		out += "    " + src + " ||--o{ " + tgt + " : \"\"\n"
-	}
-	return out
-}
-
 // ToPlantUML outputs PlantUML entity-relationship diagram syntax from the diagram.
 func ToPlantUML(d Diagram) string {
 	out := "@startuml\n"
@@ -188,26 +228,14 @@ func ToPlantUML(d Diagram) string {
 	return out
 }
 
-// plantUMLCardinality maps a cardinality string to PlantUML relationship notation.
+// plantUMLCardinality maps a cardinality string to PlantUML relationship notation, with
+// the same crow's foot markers as Mermaid; one to zero-or-more when it is unset.
 func plantUMLCardinality(c string) string {
-	switch c {
-	case "1-to-1":
-		return "||--||"
-	case "1-to-many":
-		return "||--|{"
-	case "1-to-0/many":
-		return "||--o{"
-	case "many-to-1":
-		return "}|--||"
-	case "many-to-many":
-		return "}|--|{"
-	case "0/1-to-0/1":
-		return "|o--o|"
-	case "0/1-to-many":
-		return "|o--|{"
-	case "many-to-0/many":
-		return "}|--o{"
-	default:
+	from, to := splitCardinality(c)
+	left, lok := mermaidLeftMarkers[from]
+	right, rok := mermaidRightMarkers[to]
+	if !lok || !rok {
 		return "||--o{"
 	}
+	return left + "--" + right
 }
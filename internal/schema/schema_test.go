@@ -2,6 +2,7 @@ package schema
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -43,6 +44,59 @@ func TestToMermaid(t *testing.T) {
 	if out == "" {
 		t.Fatal("expected non-empty Mermaid output")
 	}
+	for _, want := range []string{"        int user_id FK\n", `    users ||..o{ posts : ""`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestToDBML(t *testing.T) {
+	length := 255
+	d := Diagram{
+		Version: 1,
+		Tables: []Table{
+			{ID: "t1", Name: "users", Schema: "app", Description: "People's accounts", Tags: []string{"core", "auth"}, Fields: []Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true, Identity: IdentityByDefault},
+				{ID: "f2", Name: "email", Type: "string", Length: &length, Unique: true, Comment: "Login name"},
+				{ID: "f3", Name: "status", Type: "string", Nullable: true, Default: "'new'", Check: "status in ('new', 'active')"},
+			}, Indexes: []Index{{Name: "users_lower_email", Unique: true, Columns: []IndexColumn{{Expression: "lower(email)"}}}}},
+			{ID: "t2", Name: "order lines", Tags: []string{"core"}, Fields: []Field{
+				{ID: "f4", Name: "order_id", Type: "integer", PrimaryKey: true},
+				{ID: "f5", Name: "line_no", Type: "integer", PrimaryKey: true},
+				{ID: "f6", Name: "user_id", Type: "integer", Default: "0"},
+				{ID: "f7", Name: "created_at", Type: "timestamp", Default: "now()"},
+			}},
+			{ID: "t3", Name: "shipments", Fields: []Field{
+				{ID: "f8", Name: "order_id", Type: "integer"},
+				{ID: "f9", Name: "line_no", Type: "integer"},
+			}},
+		},
+		Relationships: []Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f6", Name: "fk_lines_user"},
+			{ID: "r2", SourceTableID: "t2", SourceFieldID: "f4", TargetTableID: "t3", TargetFieldID: "f8",
+				SourceFieldIDs: []string{"f4", "f5"}, TargetFieldIDs: []string{"f8", "f9"}, Cardinality: "1-to-1"},
+		},
+	}
+	out := ToDBML(d)
+	for _, want := range []string{
+		"Table app.users [note: 'People\\'s accounts'] {\n",
+		"  id integer [pk, increment]\n",
+		"  email string(255) [not null, unique, note: 'Login name']\n",
+		"  status string [default: 'new', check: `status in ('new', 'active')`]\n",
+		"  indexes {\n    `lower(email)` [unique, name: 'users_lower_email']\n  }\n",
+		"Table \"order lines\" {\n",
+		"  user_id integer [not null, default: 0]\n",
+		"  created_at timestamp [not null, default: `now()`]\n",
+		"    (order_id, line_no) [pk]\n",
+		"Ref fk_lines_user: app.users.id < \"order lines\".user_id\n",
+		"Ref: \"order lines\".(order_id, line_no) - shipments.(order_id, line_no)\n",
+		"TableGroup core {\n  app.users\n  \"order lines\"\n}\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
 }
 
 func TestField_BackwardCompat_OldJSON(t *testing.T) {
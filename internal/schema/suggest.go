@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultSuggestPatterns are the foreign key naming conventions SuggestRelationships
+// looks for when SuggestOptions.Patterns is empty.
+var DefaultSuggestPatterns = []string{"{table}_{column}", "{table}_fk", "fk_{table}"}
+
+// SuggestOptions configures SuggestRelationships.
+type SuggestOptions struct {
+	// Patterns name a foreign key column after the table it references: {table} stands for
+	// the parent table's name, singular or plural, and {column} for its primary key column.
+	// Names are compared ignoring case and underscores, so "{table}_{column}" matches
+	// customer_id, customerId and CustomerID.
+	Patterns []string `json:"patterns,omitempty"`
+	// MinConfidence drops suggestions scored below it (0 to 1).
+	MinConfidence float64 `json:"minConfidence,omitempty"`
+}
+
+// RelationshipSuggestion is a relationship inferred from naming conventions rather than
+// a declared foreign key. Relationship has no ID until it is accepted.
+type RelationshipSuggestion struct {
+	Relationship Relationship `json:"relationship"`
+	Confidence   float64      `json:"confidence"` // 0 to 1.
+	Reason       string       `json:"reason"`
+}
+
+// Confidence scores, in hundredths. A column named after a table by one of the patterns
+// scores higher than one that merely shares the name of another table's key.
+const (
+	suggestPatternScore  = 60
+	suggestSameKeyScore  = 50
+	suggestTypeBonus     = 30
+	suggestIndexedBonus  = 10
+	suggestMaxConfidence = 100
+)
+
+// SuggestRelationships proposes relationships for catalogs without declared foreign keys.
+// A column is suggested as a foreign key to a table with a single-column primary key when
+// its name follows one of the patterns for that table, or equals the primary key's name
+// when that is more specific than "id". Candidates whose generic types differ are ruled
+// out; matching types and an index on the column raise the confidence. Columns already
+// used by a relationship are skipped, and each column keeps only its best-scoring
+// candidates. Suggestions are ordered by confidence, highest first, and carry inferred
+// cardinalities.
+func SuggestRelationships(catalog TableCatalog, opts SuggestOptions) ([]RelationshipSuggestion, error) {
+	patterns := opts.Patterns
+	if len(patterns) == 0 {
+		patterns = DefaultSuggestPatterns
+	}
+	for _, p := range patterns {
+		if !strings.Contains(p, "{table}") {
+			return nil, fmt.Errorf("pattern %q has no {table} placeholder", p)
+		}
+	}
+
+	type parent struct {
+		table *Table
+		key   *Field
+	}
+	var parents []parent
+	for i := range catalog.Tables {
+		t := &catalog.Tables[i]
+		if t.Kind != "" {
+			continue
+		}
+		var key *Field
+		n := 0
+		for j := range t.Fields {
+			if t.Fields[j].PrimaryKey {
+				key = &t.Fields[j]
+				n++
+			}
+		}
+		if n == 1 {
+			parents = append(parents, parent{t, key})
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, r := range catalog.Relationships {
+		_, tgtIDs := r.FieldIDPairs()
+		for _, id := range tgtIDs {
+			used[r.TargetTableID+"\x00"+id] = true
+		}
+	}
+
+	var suggestions []RelationshipSuggestion
+	for i := range catalog.Tables {
+		child := &catalog.Tables[i]
+		if child.Kind != "" {
+			continue
+		}
+		for j := range child.Fields {
+			f := &child.Fields[j]
+			if used[child.ID+"\x00"+f.ID] {
+				continue
+			}
+			var best []RelationshipSuggestion
+			bestScore := 0
+			for _, p := range parents {
+				if p.key == f {
+					continue
+				}
+				score, reason := suggestScore(f, p.table, p.key, patterns)
+				if score == 0 {
+					continue
+				}
+				if f.Type == p.key.Type {
+					score += suggestTypeBonus
+					reason += "; types match"
+				} else if f.Type != "other" && p.key.Type != "other" {
+					continue
+				}
+				if indexedField(child, f.ID) {
+					score += suggestIndexedBonus
+					reason += "; indexed"
+				}
+				if score > suggestMaxConfidence {
+					score = suggestMaxConfidence
+				}
+				if score < bestScore {
+					continue
+				}
+				if score > bestScore {
+					best, bestScore = nil, score
+				}
+				best = append(best, RelationshipSuggestion{
+					Relationship: Relationship{
+						SourceTableID: p.table.ID,
+						SourceFieldID: p.key.ID,
+						TargetTableID: child.ID,
+						TargetFieldID: f.ID,
+					},
+					Confidence: float64(score) / 100,
+					Reason:     reason,
+				})
+			}
+			for _, s := range best {
+				if s.Confidence >= opts.MinConfidence {
+					suggestions = append(suggestions, s)
+				}
+			}
+		}
+	}
+	sort.SliceStable(suggestions, func(a, b int) bool {
+		return suggestions[a].Confidence > suggestions[b].Confidence
+	})
+
+	// Infer cardinalities alongside the declared relationships, so a junction table with
+	// one declared and one suggested key is still recognised.
+	rels := append(append([]Relationship(nil), catalog.Relationships...), make([]Relationship, len(suggestions))...)
+	for i, s := range suggestions {
+		rels[len(catalog.Relationships)+i] = s.Relationship
+	}
+	InferCardinalities(catalog.Tables, rels)
+	for i := range suggestions {
+		suggestions[i].Relationship.Cardinality = rels[len(catalog.Relationships)+i].Cardinality
+	}
+	return suggestions, nil
+}
+
+// AcceptSuggestions returns catalog with the suggested relationships added, each given an
+// unused "r<n>" ID. Suggestions for a column that already has a relationship, including
+// an earlier accepted suggestion, are skipped.
+func AcceptSuggestions(catalog TableCatalog, suggestions []RelationshipSuggestion) TableCatalog {
+	ids := make(map[string]bool, len(catalog.Relationships))
+	used := make(map[string]bool)
+	for _, r := range catalog.Relationships {
+		ids[r.ID] = true
+		_, tgtIDs := r.FieldIDPairs()
+		for _, id := range tgtIDs {
+			used[r.TargetTableID+"\x00"+id] = true
+		}
+	}
+	rels := append([]Relationship(nil), catalog.Relationships...)
+	n := 0
+	for _, s := range suggestions {
+		r := s.Relationship
+		key := r.TargetTableID + "\x00" + r.TargetFieldID
+		if used[key] {
+			continue
+		}
+		used[key] = true
+		for {
+			n++
+			r.ID = fmt.Sprintf("r%d", n)
+			if !ids[r.ID] {
+				break
+			}
+		}
+		ids[r.ID] = true
+		rels = append(rels, r)
+	}
+	catalog.Relationships = rels
+	return catalog
+}
+
+// suggestScore scores field f as a foreign key to key, the primary key of t, by name
+// alone. It returns 0 when the name does not match.
+func suggestScore(f *Field, t *Table, key *Field, patterns []string) (int, string) {
+	name := suggestNormalize(f.Name)
+	for _, p := range patterns {
+		for _, tableName := range []string{t.Name, singular(t.Name)} {
+			want := strings.NewReplacer("{table}", tableName, "{column}", key.Name).Replace(p)
+			if name == suggestNormalize(want) {
+				return suggestPatternScore, fmt.Sprintf("%s matches %s for %s.%s", f.Name, p, t.Name, key.Name)
+			}
+		}
+	}
+	if keyName := suggestNormalize(key.Name); keyName != "id" && name == keyName {
+		return suggestSameKeyScore, fmt.Sprintf("%s has the name of the primary key of %s", f.Name, t.Name)
+	}
+	return 0, ""
+}
+
+// suggestNormalize lowercases name and drops underscores, so snake_case, camelCase and
+// PascalCase spellings compare equal.
+func suggestNormalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// singular returns the English singular of a plural table name, or name unchanged.
+func singular(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"):
+		return name
+	case strings.HasSuffix(lower, "s") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// indexedField reports whether field id is unique or leads an index of t.
+func indexedField(t *Table, id string) bool {
+	if f := fieldByID(t, id); f != nil && f.Unique {
+		return true
+	}
+	for _, ix := range t.Indexes {
+		if len(ix.Columns) > 0 && ix.Columns[0].FieldID == id {
+			return true
+		}
+	}
+	return false
+}
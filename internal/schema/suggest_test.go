@@ -0,0 +1,114 @@
+package schema
+
+import "testing"
+
+func TestSuggestRelationships(t *testing.T) {
+	catalog := TableCatalog{
+		Tables: []Table{
+			{ID: "customers", Name: "customers", Fields: []Field{
+				{ID: "c.id", Name: "id", Type: "integer", PrimaryKey: true},
+			}},
+			{ID: "categories", Name: "categories", Fields: []Field{
+				{ID: "cat.code", Name: "category_code", Type: "string", PrimaryKey: true},
+			}},
+			{ID: "orders", Name: "orders", Fields: []Field{
+				{ID: "o.id", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "o.customer", Name: "customer_id", Type: "integer"},
+				{ID: "o.category", Name: "category_code", Type: "string", Nullable: true},
+				{ID: "o.status", Name: "status_id", Type: "integer"},
+			}, Indexes: []Index{{Name: "orders_customer", Columns: []IndexColumn{{FieldID: "o.customer"}}}}},
+			{ID: "invoices", Name: "invoices", Fields: []Field{
+				{ID: "i.id", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "i.order", Name: "OrderID", Type: "integer", Unique: true},
+				{ID: "i.customer", Name: "customerId", Type: "string"},
+			}},
+			{ID: "shipments", Name: "shipments", Fields: []Field{
+				{ID: "s.id", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "s.order", Name: "order_id", Type: "integer"},
+			}},
+		},
+		Relationships: []Relationship{
+			{ID: "r1", SourceTableID: "orders", SourceFieldID: "o.id", TargetTableID: "shipments", TargetFieldID: "s.order"},
+		},
+	}
+
+	got, err := SuggestRelationships(catalog, SuggestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		child, field, parent, cardinality string
+		confidence                        float64
+	}{
+		{"orders", "o.customer", "customers", "1-to-0/many", 1},
+		{"invoices", "i.order", "orders", "1-to-0/1", 1},
+		{"orders", "o.category", "categories", "0/1-to-0/many", 0.8},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("SuggestRelationships() = %+v", got)
+	}
+	for i, w := range want {
+		r := got[i].Relationship
+		if r.TargetTableID != w.child || r.TargetFieldID != w.field || r.SourceTableID != w.parent ||
+			r.Cardinality != w.cardinality || got[i].Confidence != w.confidence || r.ID != "" {
+			t.Errorf("suggestion %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+	if got[0].Reason != "customer_id matches {table}_{column} for customers.id; types match; indexed" {
+		t.Errorf("Reason = %q", got[0].Reason)
+	}
+
+	// customerId is a string, so invoices.customerId is not a key to customers.id.
+	for _, s := range got {
+		if s.Relationship.TargetFieldID == "i.customer" || s.Relationship.TargetFieldID == "s.order" {
+			t.Errorf("unexpected suggestion %+v", s)
+		}
+	}
+
+	high, _ := SuggestRelationships(catalog, SuggestOptions{MinConfidence: 0.9})
+	if len(high) != 2 {
+		t.Errorf("MinConfidence 0.9: %+v", high)
+	}
+
+	custom, _ := SuggestRelationships(catalog, SuggestOptions{Patterns: []string{"{table}_ref"}})
+	if len(custom) != 1 || custom[0].Relationship.TargetFieldID != "o.category" {
+		t.Errorf("custom patterns: %+v", custom)
+	}
+	if _, err := SuggestRelationships(catalog, SuggestOptions{Patterns: []string{"{column}"}}); err == nil {
+		t.Error("expected an error for a pattern without {table}")
+	}
+}
+
+func TestAcceptSuggestions(t *testing.T) {
+	catalog := TableCatalog{Relationships: []Relationship{{ID: "r1", TargetTableID: "a", TargetFieldID: "a.x"}}}
+	suggestions := []RelationshipSuggestion{
+		{Relationship: Relationship{SourceTableID: "b", TargetTableID: "a", TargetFieldID: "a.x"}},
+		{Relationship: Relationship{SourceTableID: "b", TargetTableID: "a", TargetFieldID: "a.y"}},
+		{Relationship: Relationship{SourceTableID: "c", TargetTableID: "a", TargetFieldID: "a.y"}},
+		{Relationship: Relationship{SourceTableID: "c", TargetTableID: "a", TargetFieldID: "a.z"}},
+	}
+	got := AcceptSuggestions(catalog, suggestions)
+	if len(got.Relationships) != 3 {
+		t.Fatalf("Relationships = %+v", got.Relationships)
+	}
+	if r := got.Relationships[1]; r.ID != "r2" || r.SourceTableID != "b" || r.TargetFieldID != "a.y" {
+		t.Errorf("first accepted = %+v", r)
+	}
+	if r := got.Relationships[2]; r.ID != "r3" || r.TargetFieldID != "a.z" {
+		t.Errorf("second accepted = %+v", r)
+	}
+	if len(catalog.Relationships) != 1 {
+		t.Error("AcceptSuggestions modified its argument")
+	}
+}
+
+func TestSingular(t *testing.T) {
+	for in, want := range map[string]string{
+		"customers": "customer", "categories": "category", "addresses": "address",
+		"boxes": "box", "status": "status", "person": "person", "Orders": "Order",
+	} {
+		if got := singular(in); got != want {
+			t.Errorf("singular(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -17,27 +17,35 @@ func (b *BigQueryExporter) Export(d schema.Diagram) (string, error) {
 }
 
 // ExportBigQueryWithTarget generates BigQuery DDL with optional fully qualified table names.
-// If project and dataset are both non-empty, table names are output as `project.dataset.tablename`.
+// A table's own schema is used as its dataset in place of dataset. If project and the
+// dataset are both non-empty, table names are output as `project.dataset.tablename`;
+// a table schema without a project gives dataset.tablename.
 // creationMode: "if_not_exists" -> CREATE TABLE IF NOT EXISTS; "create_or_replace" -> CREATE OR REPLACE TABLE; else -> CREATE TABLE.
+// Views are created after all tables, each after the views it selects from, with the same creation mode.
 func ExportBigQueryWithTarget(d schema.Diagram, project, dataset, creationMode string) (string, error) {
 	var buf bytes.Buffer
-	qualify := project != "" && dataset != ""
-	createClause := "create table "
-	switch creationMode {
-	case "if_not_exists":
-		createClause = "create table if not exists "
-	case "create_or_replace":
-		createClause = "create or replace table "
+	tableName := func(t *schema.Table) string {
+		switch ds := tableSchema(t, dataset); {
+		case project != "" && ds != "":
+			return quoteIdentBQ(project) + "." + quoteIdentBQ(ds) + "." + quoteIdentBQ(t.Name)
+		case t.Schema != "":
+			return quoteIdentBQ(t.Schema) + "." + quoteIdentBQ(t.Name)
+		}
+		return quoteIdentBQ(t.Name)
 	}
-	for _, t := range d.Tables {
-		buf.WriteString(createClause)
-		if qualify {
-			buf.WriteString(quoteIdentBQ(project))
-			buf.WriteString(".")
-			buf.WriteString(quoteIdentBQ(dataset))
-			buf.WriteString(".")
+	createClause := func(kind string) string {
+		switch creationMode {
+		case "if_not_exists":
+			return "create " + kind + " if not exists "
+		case "create_or_replace":
+			return "create or replace " + kind + " "
 		}
-		buf.WriteString(quoteIdentBQ(t.Name))
+		return "create " + kind + " "
+	}
+	co := dependencyOrder(d, false)
+	for _, t := range co.tables {
+		buf.WriteString(createClause("table"))
+		buf.WriteString(tableName(t))
 		buf.WriteString(" (\n")
 		for i, f := range t.Fields {
 			if i > 0 {
@@ -46,12 +54,32 @@ func ExportBigQueryWithTarget(d schema.Diagram, project, dataset, creationMode s
 			buf.WriteString("  ")
 			buf.WriteString(quoteIdentBQ(f.Name))
 			buf.WriteString(" ")
-			buf.WriteString(DefaultExportType("bigquery", f.Type, f.Length, f.Precision, f.Scale, f.TypeOverrides))
-			if !f.Nullable {
-				buf.WriteString(" not null")
-			}
+			buf.WriteString(FieldType("bigquery", f))
+			buf.WriteString(columnAttributes("bigquery", f))
+		}
+		buf.WriteString("\n)")
+		if t.Description != "" {
+			buf.WriteString(" options(description=")
+			buf.WriteString(quoteStringBQ(t.Description))
+			buf.WriteString(")")
+		}
+		buf.WriteString(";\n\n")
+	}
+	for _, t := range co.views {
+		kind := "view"
+		if t.Kind == schema.KindMaterializedView {
+			kind = "materialized view"
+		}
+		buf.WriteString(createClause(kind))
+		buf.WriteString(tableName(t))
+		if t.Description != "" {
+			buf.WriteString(" options(description=")
+			buf.WriteString(quoteStringBQ(t.Description))
+			buf.WriteString(")")
 		}
-		buf.WriteString("\n);\n\n")
+		buf.WriteString(" as\n")
+		buf.WriteString(viewDefinition(t))
+		buf.WriteString(";\n\n")
 	}
 	return buf.String(), nil
 }
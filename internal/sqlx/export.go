@@ -16,6 +16,8 @@ type Exporter interface {
 var registry = map[string]Exporter{
 	"postgres": &PostgresExporter{},
 	"bigquery": &BigQueryExporter{},
+	"mysql":    &MySQLExporter{},
+	"mssql":    &MSSQLExporter{},
 }
 
 // Register adds an exporter for a dialect name.
@@ -34,7 +36,7 @@ func Export(dialect string, d schema.Diagram) (string, error) {
 
 // ExportPostgres returns PostgreSQL DDL. If schema is non-empty, table names are schema-qualified.
 func ExportPostgres(d schema.Diagram, schema string) (string, error) {
-	return ExportPostgresWithSchema(d, schema)
+	return ExportPostgresWithSchema(d, schema, false)
 }
 
 // Dialects returns the list of registered dialect names.
@@ -45,3 +47,226 @@ func Dialects() []string {
 	}
 	return names
 }
+
+// relationshipColumns resolves a relationship's field IDs to column names.
+// parentCols are the referenced columns in the source (parent) table and
+// childCols the referencing columns in the target (child) table. Composite
+// relationships use SourceFieldIDs/TargetFieldIDs; otherwise the single
+// SourceFieldID/TargetFieldID pair is used. Pairs that cannot be resolved are skipped.
+func relationshipColumns(r schema.Relationship, parent, child *schema.Table) (parentCols, childCols []string) {
+	srcFieldIDs, tgtFieldIDs := r.FieldIDPairs()
+	for i := range srcFieldIDs {
+		srcF := fieldName(parent, srcFieldIDs[i])
+		tgtF := fieldName(child, tgtFieldIDs[i])
+		if srcF != "" && tgtF != "" {
+			parentCols = append(parentCols, srcF)
+			childCols = append(childCols, tgtF)
+		}
+	}
+	return parentCols, childCols
+}
+
+func fieldName(t *schema.Table, fieldID string) string {
+	for _, f := range t.Fields {
+		if f.ID == fieldID {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// columnAttributes renders the clauses that follow the type in a column definition:
+// identity, NOT NULL, DEFAULT, UNIQUE, CHECK and, for MySQL and BigQuery, the inline
+// comment, in each dialect's syntax. PostgreSQL and SQL Server column comments are
+// separate statements. BigQuery has no identity, UNIQUE or CHECK columns, so those
+// attributes are not emitted for it.
+func columnAttributes(dialect string, f schema.Field) string {
+	var b strings.Builder
+	notNull := func() {
+		if !f.Nullable {
+			b.WriteString(" not null")
+		}
+	}
+	defaultValue := func() {
+		if f.Default != "" && f.Identity == "" {
+			b.WriteString(" default ")
+			b.WriteString(f.Default)
+		}
+	}
+	uniqueAndCheck := func() {
+		if f.Unique && !f.PrimaryKey {
+			b.WriteString(" unique")
+		}
+		if f.Check != "" {
+			b.WriteString(" check (")
+			b.WriteString(f.Check)
+			b.WriteString(")")
+		}
+	}
+	switch dialect {
+	case "mysql":
+		notNull()
+		defaultValue()
+		if f.Identity != "" {
+			b.WriteString(" auto_increment")
+		}
+		uniqueAndCheck()
+		if f.Comment != "" {
+			b.WriteString(" comment ")
+			b.WriteString(quoteStringMySQL(f.Comment))
+		}
+	case "mssql":
+		if f.Identity != "" {
+			b.WriteString(" identity(1,1)")
+		}
+		notNull()
+		defaultValue()
+		uniqueAndCheck()
+	case "bigquery":
+		// ARRAY columns cannot be NOT NULL; an empty array stands for no values.
+		if !f.Repeated {
+			notNull()
+		}
+		defaultValue()
+		if f.Comment != "" {
+			b.WriteString(" options(description=")
+			b.WriteString(quoteStringBQ(f.Comment))
+			b.WriteString(")")
+		}
+	default:
+		switch f.Identity {
+		case schema.IdentityAlways:
+			b.WriteString(" generated always as identity")
+		case schema.IdentityByDefault:
+			b.WriteString(" generated by default as identity")
+		}
+		notNull()
+		defaultValue()
+		uniqueAndCheck()
+	}
+	return b.String()
+}
+
+// createView renders CREATE VIEW name AS definition for the dialect, without a
+// terminator. orReplace selects the dialect's replacing form (CREATE OR REPLACE, or
+// CREATE OR ALTER for SQL Server). MySQL and SQL Server have no materialized views, so
+// those are created as plain views.
+func createView(dialect string, t *schema.Table, name string, orReplace bool) string {
+	var b strings.Builder
+	b.WriteString("create ")
+	if orReplace {
+		if dialect == "mssql" {
+			b.WriteString("or alter ")
+		} else {
+			b.WriteString("or replace ")
+		}
+	}
+	if t.Kind == schema.KindMaterializedView && (dialect == "postgres" || dialect == "bigquery") {
+		b.WriteString("materialized ")
+	}
+	b.WriteString("view ")
+	b.WriteString(name)
+	b.WriteString(" as\n")
+	b.WriteString(viewDefinition(t))
+	return b.String()
+}
+
+// dropView renders DROP VIEW (or DROP MATERIALIZED VIEW) for the dialect, without a terminator.
+func dropView(dialect string, t *schema.Table, name string) string {
+	if t.Kind == schema.KindMaterializedView && (dialect == "postgres" || dialect == "bigquery") {
+		return "drop materialized view " + name
+	}
+	return "drop view " + name
+}
+
+// viewDefinition returns the view query without surrounding whitespace or a trailing semicolon.
+func viewDefinition(t *schema.Table) string {
+	return strings.TrimRight(strings.TrimSpace(t.Definition), "; \t\r\n")
+}
+
+// tableSchema returns the table's own schema, or def for tables without one.
+func tableSchema(t *schema.Table, def string) string {
+	if t.Schema != "" {
+		return t.Schema
+	}
+	return def
+}
+
+// quoteString returns s as a standard SQL string literal.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteStringMySQL returns s as a MySQL string literal; backslash is an escape character there.
+func quoteStringMySQL(s string) string {
+	return quoteString(strings.ReplaceAll(s, `\`, `\\`))
+}
+
+// quoteStringBQ returns s as a BigQuery double-quoted string literal.
+func quoteStringBQ(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// createIndex renders a CREATE INDEX statement, without terminator, for def on the
+// already quoted and qualified table name. It returns "" when the dialect cannot express
+// the index: BigQuery has no indexes and SQL Server cannot index expressions. MySQL
+// has no partial indexes, so the predicate is dropped there.
+func createIndex(dialect string, def schema.IndexDef, table string) string {
+	var quote func(string) string
+	switch dialect {
+	case "mysql":
+		quote = quoteIdentMySQL
+	case "mssql":
+		quote = quoteIdentMSSQL
+	case "bigquery":
+		return ""
+	default:
+		quote = quoteIdent
+	}
+	method := strings.ToLower(def.Method)
+	keys := make([]string, len(def.Keys))
+	for i, k := range def.Keys {
+		if k.Expression != "" {
+			if dialect == "mssql" {
+				return ""
+			}
+			keys[i] = "(" + k.Expression + ")"
+		} else {
+			keys[i] = quote(k.Column)
+		}
+		if k.Desc {
+			keys[i] += " desc"
+		}
+	}
+	var b strings.Builder
+	b.WriteString("create ")
+	if def.Unique {
+		b.WriteString("unique ")
+	}
+	switch {
+	case dialect == "mysql" && (method == "fulltext" || method == "spatial"):
+		b.WriteString(method + " ")
+	case dialect == "mssql" && (method == "clustered" || method == "nonclustered"):
+		b.WriteString(method + " ")
+	}
+	b.WriteString("index ")
+	b.WriteString(quote(def.Name))
+	b.WriteString(" on ")
+	b.WriteString(table)
+	if dialect == "postgres" && method != "" && method != "btree" {
+		b.WriteString(" using ")
+		b.WriteString(method)
+	}
+	b.WriteString(" (")
+	b.WriteString(strings.Join(keys, ", "))
+	b.WriteString(")")
+	if dialect == "mysql" && (method == "btree" || method == "hash") {
+		b.WriteString(" using ")
+		b.WriteString(method)
+	}
+	if def.Where != "" && dialect != "mysql" {
+		b.WriteString(" where ")
+		b.WriteString(def.Where)
+	}
+	return b.String()
+}
@@ -201,3 +201,621 @@ func TestExport_BackwardCompat_OldTypes(t *testing.T) {
 	}
 }
 
+func TestExport_MySQL(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "users", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "name", Type: "string", Length: intP(100), Nullable: true},
+				{ID: "f3", Name: "active", Type: "boolean"},
+			}},
+			{ID: "t2", Name: "posts", Fields: []schema.Field{
+				{ID: "f4", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f5", Name: "user_id", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f5"},
+		},
+	}
+	out, err := Export("mysql", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"create table `users` (",
+		"`id` int not null",
+		"`name` varchar(100),",
+		"`active` tinyint(1) not null",
+		"primary key (`id`)",
+		"constraint `posts_ibfk_1` foreign key (`user_id`) references `users` (`id`)",
+		") engine=InnoDB default charset=utf8mb4;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output: %s", want, out)
+		}
+	}
+}
+
+func TestExport_MySQL_CompositeFKAndOverride(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "orders", Fields: []schema.Field{
+				{ID: "f1", Name: "region", Type: "string", Length: intP(2), PrimaryKey: true},
+				{ID: "f2", Name: "order_no", Type: "integer", PrimaryKey: true},
+				{ID: "f3", Name: "notes", Type: "string", Nullable: true,
+					TypeOverrides: map[string]schema.FieldTypeOverride{"mysql": {Type: "longtext"}}},
+			}},
+			{ID: "t2", Name: "order_lines", Fields: []schema.Field{
+				{ID: "f4", Name: "order_region", Type: "string", Length: intP(2)},
+				{ID: "f5", Name: "order_no", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", TargetTableID: "t2",
+				SourceFieldIDs: []string{"f1", "f2"}, TargetFieldIDs: []string{"f4", "f5"}},
+		},
+	}
+	out, err := ExportMySQLWithOptions(d, "MyISAM", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "primary key (`region`, `order_no`)") {
+		t.Errorf("expected composite primary key in output: %s", out)
+	}
+	if !strings.Contains(out, "foreign key (`order_region`, `order_no`) references `orders` (`region`, `order_no`)") {
+		t.Errorf("expected composite foreign key in output: %s", out)
+	}
+	if !strings.Contains(out, "`notes` longtext") {
+		t.Errorf("expected override 'longtext' in output: %s", out)
+	}
+	if !strings.Contains(out, ") engine=MyISAM;") || strings.Contains(out, "charset") {
+		t.Errorf("expected engine option without charset in output: %s", out)
+	}
+}
+
+func TestExport_Postgres_CompositeFK(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "orders", Fields: []schema.Field{
+				{ID: "f1", Name: "region", Type: "string", Length: intP(2), PrimaryKey: true},
+				{ID: "f2", Name: "order_no", Type: "integer", PrimaryKey: true},
+			}},
+			{ID: "t2", Name: "order_lines", Fields: []schema.Field{
+				{ID: "f4", Name: "order_region", Type: "string", Length: intP(2)},
+				{ID: "f5", Name: "order_no", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", Name: "order_lines_order_fk", SourceTableID: "t1", TargetTableID: "t2",
+				SourceFieldIDs: []string{"f1", "f2"}, TargetFieldIDs: []string{"f4", "f5"}},
+		},
+	}
+	out, err := Export("postgres", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "constraint order_lines_order_fk foreign key (order_region, order_no) references orders (region, order_no)"
+	if !strings.Contains(out, want) || strings.Count(out, "foreign key") != 1 {
+		t.Errorf("expected one composite foreign key %q in output: %s", want, out)
+	}
+
+	d.Relationships[0].Name = ""
+	out, err = ExportPostgresWithSchema(d, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "alter table order_lines add constraint order_lines_order_region_order_no_fkey foreign key (order_region, order_no) references orders (region, order_no);"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in output: %s", want, out)
+	}
+}
+
+func TestExport_MSSQL(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "users", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "name", Type: "string", Length: intP(50), Nullable: true},
+			}},
+			{ID: "t2", Name: "posts", Fields: []schema.Field{
+				{ID: "f3", Name: "id", Type: "uuid", PrimaryKey: true},
+				{ID: "f4", Name: "user_id", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f4"},
+		},
+	}
+	out, err := Export("mssql", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"create table [dbo].[users] (",
+		"[id] int not null",
+		"[name] nvarchar(50),",
+		"[id] uniqueidentifier not null",
+		"constraint [PK_users] primary key ([id])",
+		"constraint [FK_posts_users] foreign key ([user_id]) references [dbo].[users] ([id])",
+		");\nGO\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output: %s", want, out)
+		}
+	}
+}
+
+func TestExportMSSQLWithOptions(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "orders", Fields: []schema.Field{
+				{ID: "f1", Name: "region", Type: "string", Length: intP(2), PrimaryKey: true},
+				{ID: "f2", Name: "order_no", Type: "integer", PrimaryKey: true},
+			}},
+			{ID: "t2", Name: "order_lines", Fields: []schema.Field{
+				{ID: "f3", Name: "order_region", Type: "string", Length: intP(2)},
+				{ID: "f4", Name: "order_no", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", Name: "fk_lines_order", SourceTableID: "t1", TargetTableID: "t2",
+				SourceFieldIDs: []string{"f1", "f2"}, TargetFieldIDs: []string{"f3", "f4"}},
+		},
+	}
+	out, err := ExportMSSQLWithOptions(d, "sales", "if_not_exists", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "if not exists (select 1 from sys.tables t join sys.schemas s on t.schema_id = s.schema_id where s.name = N'sales' and t.name = N'orders')\ncreate table [sales].[orders]") {
+		t.Errorf("expected if not exists guard in output: %s", out)
+	}
+	if !strings.Contains(out, "constraint [fk_lines_order] foreign key ([order_region], [order_no]) references [sales].[orders] ([region], [order_no])") {
+		t.Errorf("expected named composite foreign key in output: %s", out)
+	}
+	if strings.Contains(out, "GO") {
+		t.Errorf("expected no GO separators in output: %s", out)
+	}
+	out2, err := ExportMSSQLWithOptions(d, "", "create_or_replace", true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out2, "drop table if exists [dbo].[orders];\nGO\n") {
+		t.Errorf("expected drop table if exists in output: %s", out2)
+	}
+}
+
+func TestExportMSSQLWithOptions_Rerun(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "teams", Description: "Teams", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "captain_id", Type: "integer", Nullable: true, Comment: "Team captain"},
+			}, Indexes: []schema.Index{{Name: "teams_captain", Columns: []schema.IndexColumn{{FieldID: "f2"}}}}},
+			{ID: "t2", Name: "members", Fields: []schema.Field{
+				{ID: "f3", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f4", Name: "team_id", Type: "integer"},
+			}},
+			{ID: "t3", Name: "notes", Fields: []schema.Field{
+				{ID: "f5", Name: "member_id", Type: "integer"},
+			}},
+			{ID: "v1", Name: "team_sizes", Kind: schema.KindView, Description: "Members per team",
+				Definition: "select team_id, count(*) as n from members group by team_id"},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f4"},
+			{ID: "r2", SourceTableID: "t2", SourceFieldID: "f3", TargetTableID: "t1", TargetFieldID: "f2"},
+			{ID: "r3", SourceTableID: "t2", SourceFieldID: "f3", TargetTableID: "t3", TargetFieldID: "f5"},
+		},
+	}
+
+	// Rerunning an if_not_exists script must not fail: every statement is guarded.
+	out, err := ExportMSSQLWithOptions(d, "", "if_not_exists", true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements := strings.Split(strings.TrimSpace(out), ";\nGO\n")
+	if len(statements) != 9 {
+		t.Errorf("expected 9 statements (3 tables, 2 descriptions, index, foreign key, view, view description), got %d: %s", len(statements), out)
+	}
+	for _, stmt := range statements {
+		if stmt = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt), ";\nGO")); !strings.HasPrefix(stmt, "if ") {
+			t.Errorf("unguarded statement %q", stmt)
+		}
+	}
+	for _, want := range []string{
+		"if not exists (select 1 from sys.extended_properties where class = 1 and major_id = object_id(N'[dbo].[teams]') and minor_id = columnproperty(object_id(N'[dbo].[teams]'), N'captain_id', 'ColumnId') and name = N'MS_Description')\nexec sp_addextendedproperty",
+		"if not exists (select 1 from sys.indexes where object_id = object_id(N'[dbo].[teams]') and name = N'teams_captain')\ncreate index [teams_captain] on [dbo].[teams]",
+		"if object_id(N'[dbo].[FK_teams_members]', N'F') is null\nalter table [dbo].[teams] add constraint [FK_teams_members]",
+		"if object_id(N'[dbo].[team_sizes]', N'V') is null\nexec (N'create view [dbo].[team_sizes] as\nselect team_id, count(*) as n from members group by team_id')",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output: %s", want, out)
+		}
+	}
+
+	// create_or_replace drops every table before creating any, dependents first, after
+	// removing the foreign key that closes the teams/members cycle.
+	out, err = ExportMSSQLWithOptions(d, "", "create_or_replace", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "if object_id(N'[dbo].[FK_teams_members]', N'F') is not null alter table [dbo].[teams] drop constraint [FK_teams_members];\n\n" +
+		"drop table if exists [dbo].[notes];\n\n" +
+		"drop table if exists [dbo].[members];\n\n" +
+		"drop table if exists [dbo].[teams];\n\n" +
+		"create table [dbo].[teams]"
+	if !strings.HasPrefix(out, want) {
+		t.Errorf("expected output to start with %q: %s", want, out)
+	}
+	// Without GO separators the view would not start its batch, so it runs through exec.
+	if want := "exec (N'create or alter view [dbo].[team_sizes] as\nselect team_id, count(*) as n from members group by team_id');"; !strings.Contains(out, want) {
+		t.Errorf("expected %q in output: %s", want, out)
+	}
+}
+
+func TestExport_ColumnAttributes(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "orders", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true, Identity: schema.IdentityAlways},
+				{ID: "f2", Name: "code", Type: "string", Length: intP(10), Unique: true},
+				{ID: "f3", Name: "qty", Type: "integer", Default: "1", Check: "qty > 0"},
+				{ID: "f4", Name: "note", Type: "string", Length: intP(200), Nullable: true, Comment: "Customer's note"},
+			}},
+		},
+	}
+	cases := map[string][]string{
+		"postgres": {
+			"id integer generated always as identity not null,",
+			"code varchar(10) not null unique,",
+			"qty integer not null default 1 check (qty > 0),",
+			"comment on column orders.note is 'Customer''s note';",
+		},
+		"mysql": {
+			"`id` int not null auto_increment,",
+			"`code` varchar(10) not null unique,",
+			"`qty` int not null default 1 check (qty > 0),",
+			"`note` varchar(200) comment 'Customer''s note'",
+		},
+		"mssql": {
+			"[id] int identity(1,1) not null,",
+			"[code] nvarchar(10) not null unique,",
+			"[qty] int not null default 1 check (qty > 0),",
+			"exec sp_addextendedproperty N'MS_Description', N'Customer''s note', N'SCHEMA', N'dbo', N'TABLE', N'orders', N'COLUMN', N'note';",
+		},
+		"bigquery": {
+			"id INT64 not null,",
+			"code STRING not null,",
+			"qty INT64 not null default 1,",
+			`note STRING options(description="Customer's note")`,
+		},
+	}
+	for dialect, wants := range cases {
+		out, err := Export(dialect, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: expected %q in output: %s", dialect, want, out)
+			}
+		}
+	}
+}
+
+func TestExport_Indexes(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "users", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "email", Type: "string", Length: intP(100)},
+				{ID: "f3", Name: "created_at", Type: "timestamp"},
+			}, Indexes: []schema.Index{
+				{Columns: []schema.IndexColumn{{FieldID: "f2"}, {FieldID: "f3", Desc: true}}},
+				{Name: "users_email_lower", Unique: true, Where: "email is not null",
+					Columns: []schema.IndexColumn{{Expression: "lower(email)"}}},
+				{Name: "users_email_hash", Method: "hash", Columns: []schema.IndexColumn{{FieldID: "f2"}}},
+			}},
+		},
+	}
+	cases := map[string][]string{
+		"postgres": {
+			"create index users_email_created_at_idx on users (email, created_at desc);",
+			"create unique index users_email_lower on users ((lower(email))) where email is not null;",
+			"create index users_email_hash on users using hash (email);",
+		},
+		"mysql": {
+			"create index `users_email_created_at_idx` on `users` (`email`, `created_at` desc);",
+			"create unique index `users_email_lower` on `users` ((lower(email)));",
+			"create index `users_email_hash` on `users` (`email`) using hash;",
+		},
+		"mssql": {
+			"create index [users_email_created_at_idx] on [dbo].[users] ([email], [created_at] desc);",
+			"create index [users_email_hash] on [dbo].[users] ([email]);",
+		},
+	}
+	for dialect, wants := range cases {
+		out, err := Export(dialect, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: expected %q in output: %s", dialect, want, out)
+			}
+		}
+		if dialect == "mssql" && strings.Contains(out, "lower(email)") {
+			t.Errorf("mssql: expression indexes should be skipped: %s", out)
+		}
+	}
+
+	out, err := Export("bigquery", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "index") {
+		t.Errorf("bigquery: indexes should not be emitted: %s", out)
+	}
+}
+
+func TestExport_TableSchemaAndDescription(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "customers", Schema: "sales", Description: "People who buy", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+			}},
+			{ID: "t2", Name: "orders", Fields: []schema.Field{
+				{ID: "f2", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f3", Name: "customer_id", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f3"},
+		},
+	}
+	cases := map[string][]string{
+		"postgres": {
+			"create table sales.customers (",
+			"create table orders (",
+			"references sales.customers (id)",
+			"comment on table sales.customers is 'People who buy';",
+		},
+		"mysql": {
+			"create table `sales`.`customers` (",
+			"references `sales`.`customers` (`id`)",
+			") engine=InnoDB default charset=utf8mb4 comment='People who buy';",
+		},
+		"mssql": {
+			"create table [sales].[customers] (",
+			"create table [dbo].[orders] (",
+			"references [sales].[customers] ([id])",
+			"exec sp_addextendedproperty N'MS_Description', N'People who buy', N'SCHEMA', N'sales', N'TABLE', N'customers';",
+		},
+		"bigquery": {
+			"create table sales.customers (",
+			`) options(description="People who buy");`,
+			"create table orders (",
+		},
+	}
+	for dialect, wants := range cases {
+		out, err := Export(dialect, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: expected %q in output: %s", dialect, want, out)
+			}
+		}
+	}
+
+	// The export-wide schema only applies to tables without their own.
+	out, err := ExportPostgresWithSchema(d, "public", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "create table sales.customers (") || !strings.Contains(out, "create table public.orders (") {
+		t.Errorf("unexpected qualification: %s", out)
+	}
+}
+
+func TestExport_Views(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "v1", Name: "order_totals", Kind: schema.KindMaterializedView, Description: "Totals",
+				Definition: "select customer_id, sum(amount) as total from orders group by customer_id;\n",
+				Fields: []schema.Field{
+					{ID: "f9", Name: "customer_id", Type: "integer"},
+					{ID: "f10", Name: "total", Type: "decimal"},
+				}},
+			{ID: "t1", Name: "orders", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "customer_id", Type: "integer"},
+				{ID: "f3", Name: "amount", Type: "decimal"},
+			}},
+		},
+	}
+	cases := map[string][]string{
+		"postgres": {
+			"create materialized view order_totals as\nselect customer_id, sum(amount) as total from orders group by customer_id;\n",
+			"comment on materialized view order_totals is 'Totals';",
+		},
+		"mysql": {
+			"create view `order_totals` as\nselect customer_id",
+		},
+		"mssql": {
+			"create view [dbo].[order_totals] as\nselect customer_id",
+			"N'VIEW', N'order_totals'",
+		},
+		"bigquery": {
+			"create materialized view order_totals options(description=\"Totals\") as\nselect customer_id",
+		},
+	}
+	for dialect, wants := range cases {
+		out, err := Export(dialect, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: expected %q in output: %s", dialect, want, out)
+			}
+		}
+		// The view comes after the table it depends on and is not created as a table.
+		if strings.Index(out, "order_totals") < strings.Index(out, "orders") {
+			t.Errorf("%s: view emitted before tables: %s", dialect, out)
+		}
+		if strings.Contains(out, "table order_totals") || strings.Contains(out, "table `order_totals`") || strings.Contains(out, "[order_totals] (") {
+			t.Errorf("%s: view exported as a table: %s", dialect, out)
+		}
+	}
+}
+
+func TestExport_DependencyOrder(t *testing.T) {
+	// orders references customers but is listed first; teams and members reference each other.
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "o", Name: "orders", Fields: []schema.Field{
+				{ID: "o1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "o2", Name: "customer_id", Type: "integer"},
+			}},
+			{ID: "c", Name: "customers", Fields: []schema.Field{
+				{ID: "c1", Name: "id", Type: "integer", PrimaryKey: true},
+			}},
+			{ID: "t", Name: "teams", Fields: []schema.Field{
+				{ID: "t1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "t2", Name: "lead_id", Type: "integer", Nullable: true},
+			}},
+			{ID: "m", Name: "members", Fields: []schema.Field{
+				{ID: "m1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "m2", Name: "team_id", Type: "integer"},
+				{ID: "m3", Name: "mentor_id", Type: "integer", Nullable: true},
+			}},
+			{ID: "v2", Name: "big_spenders", Kind: schema.KindView, Definition: "select * from order_totals where total > 1000"},
+			{ID: "v1", Name: "order_totals", Kind: schema.KindView, Definition: "select customer_id, count(*) as total from orders group by customer_id"},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "c", SourceFieldID: "c1", TargetTableID: "o", TargetFieldID: "o2"},
+			{ID: "r2", SourceTableID: "t", SourceFieldID: "t1", TargetTableID: "m", TargetFieldID: "m2"},
+			{ID: "r3", SourceTableID: "m", SourceFieldID: "m1", TargetTableID: "t", TargetFieldID: "t2", Name: "fk_team_lead"},
+			{ID: "r4", SourceTableID: "m", SourceFieldID: "m1", TargetTableID: "m", TargetFieldID: "m3"},
+		},
+	}
+	before := func(t *testing.T, out, a, b string) {
+		t.Helper()
+		i, j := strings.Index(out, a), strings.Index(out, b)
+		if i < 0 || j < 0 || i > j {
+			t.Errorf("expected %q before %q:\n%s", a, b, out)
+		}
+	}
+
+	pg, err := ExportPostgresWithSchema(d, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before(t, pg, "create table customers", "create table orders")
+	before(t, pg, "create table teams", "create table members")
+	before(t, pg, "create table members", "alter table teams add constraint fk_team_lead foreign key (lead_id) references members (id);")
+	before(t, pg, "alter table teams", "create view order_totals")
+	before(t, pg, "create view order_totals", "create view big_spenders")
+	if !strings.Contains(pg, "foreign key (customer_id) references customers (id)") ||
+		!strings.Contains(pg, "foreign key (team_id) references teams (id)") ||
+		!strings.Contains(pg, "foreign key (mentor_id) references members (id)") {
+		t.Errorf("expected acyclic foreign keys inline:\n%s", pg)
+	}
+	if strings.Count(pg, "alter table") != 1 {
+		t.Errorf("expected only the cyclic foreign key as ALTER TABLE:\n%s", pg)
+	}
+
+	all, err := ExportPostgresWithSchema(d, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(all, ",\n  foreign key") || strings.Count(all, "alter table") != 4 {
+		t.Errorf("expected every foreign key as ALTER TABLE:\n%s", all)
+	}
+	if !strings.Contains(all, "alter table orders add constraint orders_customer_id_fkey foreign key (customer_id) references customers (id);") {
+		t.Errorf("expected default constraint name:\n%s", all)
+	}
+
+	my, err := ExportMySQLWithOptions(d, "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before(t, my, "create table `customers`", "create table `orders`")
+	if !strings.Contains(my, "alter table `teams` add constraint `fk_team_lead` foreign key (`lead_id`) references `members` (`id`);") {
+		t.Errorf("expected cyclic foreign key as ALTER TABLE:\n%s", my)
+	}
+
+	ms, err := ExportMSSQLWithOptions(d, "", "", true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before(t, ms, "create table [dbo].[customers]", "create table [dbo].[orders]")
+	if !strings.Contains(ms, "alter table [dbo].[teams] add constraint [fk_team_lead] foreign key ([lead_id]) references [dbo].[members] ([id]);\nGO\n") {
+		t.Errorf("expected cyclic foreign key as ALTER TABLE:\n%s", ms)
+	}
+
+	bq, err := Export("bigquery", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before(t, bq, "create table customers", "create table orders")
+	before(t, bq, "create view order_totals", "create view big_spenders")
+}
+
+func TestExport_NestedFields(t *testing.T) {
+	d := schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "events", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "string"},
+				{ID: "f2", Name: "tags", Type: "string", Repeated: true, Nullable: true},
+				{ID: "f3", Name: "device", Type: schema.TypeStruct, Nullable: true, Fields: []schema.Field{
+					{ID: "f4", Name: "os", Type: "string"},
+					{ID: "f5", Name: "version", Type: "integer", Nullable: true, Comment: "major version"},
+				}},
+				{ID: "f6", Name: "items", Type: schema.TypeStruct, Repeated: true, Fields: []schema.Field{
+					{ID: "f7", Name: "sku", Type: "string"},
+					{ID: "f8", Name: "codes", Type: "integer", Repeated: true},
+				}},
+			}},
+		},
+	}
+	out, err := Export("bigquery", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"  id STRING not null,\n",
+		"  tags ARRAY<STRING>,\n",
+		"  device STRUCT<os STRING not null, version INT64 options(description=\"major version\")>,\n",
+		"  items ARRAY<STRUCT<sku STRING not null, codes ARRAY<INT64>>>\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("bigquery: expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out, err = Export("postgres", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"tags varchar[]", "device jsonb", "items jsonb not null"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("postgres: expected %q in output:\n%s", want, out)
+		}
+	}
+}
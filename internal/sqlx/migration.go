@@ -0,0 +1,323 @@
+package sqlx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// migrationDialect holds the dialect-specific pieces used to render ALTER statements.
+type migrationDialect struct {
+	name  string
+	quote func(string) string
+	table func(schemaName, name string) string // qualified, quoted table name
+}
+
+var migrationDialects = map[string]migrationDialect{
+	"postgres": {name: "postgres", quote: quoteIdent, table: qualifiedTableName},
+	"mysql": {name: "mysql", quote: quoteIdentMySQL, table: func(s, t string) string {
+		return qualifiedTableNameMySQL(&schema.Table{Schema: s, Name: t})
+	}},
+	"mssql": {name: "mssql", quote: quoteIdentMSSQL, table: func(s, t string) string {
+		return quoteIdentMSSQL(mssqlSchema(s)) + "." + quoteIdentMSSQL(t)
+	}},
+	"bigquery": {name: "bigquery", quote: quoteIdentBQ, table: func(s, t string) string {
+		if s == "" {
+			return quoteIdentBQ(t)
+		}
+		return quoteIdentBQ(s) + "." + quoteIdentBQ(t)
+	}},
+}
+
+// mssqlSchema returns schemaName, or dbo for tables without one.
+func mssqlSchema(schemaName string) string {
+	if schemaName == "" {
+		return "dbo"
+	}
+	return schemaName
+}
+
+// GenerateMigration compares from and to by name and returns a DDL script for the
+// dialect that migrates a database matching from into one matching to.
+func GenerateMigration(dialect string, from, to schema.Diagram) (string, error) {
+	return GenerateMigrationWithOptions(dialect, from, to, schema.DiffOptions{})
+}
+
+// GenerateMigrationWithOptions is GenerateMigration with diff options; see schema.DiffOptions.
+func GenerateMigrationWithOptions(dialect string, from, to schema.Diagram, opts schema.DiffOptions) (string, error) {
+	return MigrationScript(dialect, schema.DiffDiagramsWithOptions(from, to, opts))
+}
+
+// MigrationScript renders a SchemaDiff as ALTER/CREATE/DROP statements for the dialect.
+// Statements are ordered so the script applies cleanly: dropped and changed views go first
+// (they may depend on the tables being altered), then foreign keys are dropped,
+// tables and columns are renamed, altered, created and dropped (indexes are dropped
+// before and created after their table's column changes), new foreign keys are added,
+// and finally changed and new views are created. BigQuery has no enforced keys, so key changes are skipped for it.
+func MigrationScript(dialect string, sd schema.SchemaDiff) (string, error) {
+	md, ok := migrationDialects[strings.ToLower(dialect)]
+	if !ok {
+		return "", fmt.Errorf("unknown dialect: %s", dialect)
+	}
+	var b bytes.Buffer
+	stmt := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format, args...)
+		b.WriteString(";\n")
+	}
+	keys := md.name != "bigquery"
+
+	// 0. Drop views that are gone or will be recreated.
+	for _, v := range sd.DroppedViews {
+		stmt("%s", dropView(md.name, &v, md.table(v.Schema, v.Name)))
+	}
+	for _, vc := range sd.ChangedViews {
+		stmt("%s", dropView(md.name, &vc.From, md.table(vc.From.Schema, vc.From.Name)))
+	}
+
+	// 1. Drop foreign keys that no longer exist (before their columns or tables go away).
+	// Only a recorded name can be dropped; the one the database assigned an unnamed key
+	// is not known here, so the script says what to drop instead of guessing.
+	if keys {
+		for _, fk := range sd.DroppedForeignKeys {
+			if fk.Name == "" {
+				fmt.Fprintf(&b, "-- drop the foreign key %s (%s) references %s (%s) by hand: its constraint name is unknown\n",
+					fk.Table, strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", "))
+				continue
+			}
+			name := md.quote(fk.Name)
+			if md.name == "mysql" {
+				stmt("alter table %s drop foreign key %s", md.table(fk.Schema, fk.Table), name)
+			} else {
+				stmt("alter table %s drop constraint %s", md.table(fk.Schema, fk.Table), name)
+			}
+		}
+	}
+
+	// 2. Table and column changes.
+	for _, td := range sd.ModifiedTables {
+		tbl := md.table(td.Schema, td.Name)
+		// Indexes are dropped before the columns they cover, under the old table name.
+		if md.name != "bigquery" {
+			for _, def := range td.DroppedIndexes {
+				switch md.name {
+				case "postgres":
+					stmt("drop index %s", md.table(td.Schema, def.Name))
+				default:
+					stmt("drop index %s on %s", md.quote(def.Name), md.table(td.Schema, def.Table))
+				}
+			}
+		}
+		if td.OldName != "" {
+			switch md.name {
+			case "mysql":
+				stmt("rename table %s to %s", md.table(td.Schema, td.OldName), tbl)
+			case "mssql":
+				stmt("exec sp_rename %s, %s", quoteStringMSSQL(mssqlSchema(td.Schema)+"."+td.OldName), quoteStringMSSQL(td.Name))
+			default:
+				stmt("alter table %s rename to %s", md.table(td.Schema, td.OldName), md.quote(td.Name))
+			}
+		}
+		for _, rn := range td.RenamedFields {
+			if md.name == "mssql" {
+				stmt("exec sp_rename %s, %s, 'COLUMN'", quoteStringMSSQL(mssqlSchema(td.Schema)+"."+td.Name+"."+rn.OldName), quoteStringMSSQL(rn.NewName))
+			} else {
+				stmt("alter table %s rename column %s to %s", tbl, md.quote(rn.OldName), md.quote(rn.NewName))
+			}
+		}
+		// MySQL drops the primary key without naming it; elsewhere its constraint name
+		// is not recorded, so as with foreign keys the script says what to drop.
+		if keys && td.PrimaryKeyChanged() && len(td.OldPrimaryKey) > 0 {
+			if md.name == "mysql" {
+				stmt("alter table %s drop primary key", tbl)
+			} else {
+				fmt.Fprintf(&b, "-- drop the primary key %s (%s) by hand: its constraint name is unknown\n",
+					td.Name, strings.Join(td.OldPrimaryKey, ", "))
+			}
+		}
+		for _, f := range td.AddedFields {
+			col := md.quote(f.Name) + " " + FieldType(md.name, f)
+			// BigQuery cannot add REQUIRED columns to an existing table.
+			if md.name != "bigquery" {
+				col += columnAttributes(md.name, f)
+			}
+			if md.name == "mssql" {
+				stmt("alter table %s add %s", tbl, col)
+			} else {
+				stmt("alter table %s add column %s", tbl, col)
+			}
+		}
+		for _, fc := range td.ModifiedFields {
+			renderAlterColumn(md, tbl, fc, stmt)
+		}
+		for _, f := range td.DroppedFields {
+			stmt("alter table %s drop column %s", tbl, md.quote(f.Name))
+		}
+		if keys && td.PrimaryKeyChanged() && len(td.NewPrimaryKey) > 0 {
+			cols := make([]string, len(td.NewPrimaryKey))
+			for i, c := range td.NewPrimaryKey {
+				cols[i] = md.quote(c)
+			}
+			if md.name == "mssql" {
+				stmt("alter table %s add constraint %s primary key (%s)", tbl, md.quote("PK_"+td.Name), strings.Join(cols, ", "))
+			} else {
+				stmt("alter table %s add primary key (%s)", tbl, strings.Join(cols, ", "))
+			}
+		}
+		for _, def := range td.AddedIndexes {
+			if ddl := createIndex(md.name, def, tbl); ddl != "" {
+				stmt("%s", ddl)
+			}
+		}
+	}
+
+	// 3. New tables (without foreign keys; those are added below). SQL Server gets no GO
+	// separators, which the rest of the script does not use either.
+	if len(sd.AddedTables) > 0 {
+		added := schema.Diagram{Version: schema.CurrentVersion, Tables: sd.AddedTables}
+		var ddl string
+		var err error
+		if md.name == "mssql" {
+			ddl, err = ExportMSSQLWithOptions(added, "", "", false, false)
+		} else {
+			ddl, err = Export(md.name, added)
+		}
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(ddl)
+	}
+
+	// 4. Dropped tables.
+	for _, t := range sd.DroppedTables {
+		stmt("drop table %s", md.table(t.Schema, t.Name))
+	}
+
+	// 5. New foreign keys.
+	if keys {
+		for _, fk := range sd.AddedForeignKeys {
+			cols := make([]string, len(fk.Columns))
+			for i, c := range fk.Columns {
+				cols[i] = md.quote(c)
+			}
+			refCols := make([]string, len(fk.RefColumns))
+			for i, c := range fk.RefColumns {
+				refCols[i] = md.quote(c)
+			}
+			stmt("alter table %s add constraint %s foreign key (%s) references %s (%s)",
+				md.table(fk.Schema, fk.Table), md.quote(foreignKeyName(md.name, fk)),
+				strings.Join(cols, ", "), md.table(fk.RefSchema, fk.RefTable), strings.Join(refCols, ", "))
+		}
+	}
+
+	// 6. Changed and new views.
+	for _, vc := range sd.ChangedViews {
+		stmt("%s", createView(md.name, &vc.To, md.table(vc.To.Schema, vc.To.Name), false))
+	}
+	for _, v := range sd.AddedViews {
+		stmt("%s", createView(md.name, &v, md.table(v.Schema, v.Name), false))
+	}
+	return b.String(), nil
+}
+
+// renderAlterColumn emits the statements that change a column's type and/or nullability.
+func renderAlterColumn(md migrationDialect, tbl string, fc schema.FieldChange, stmt func(string, ...interface{})) {
+	f := fc.To
+	newType := FieldType(md.name, f)
+	oldType := FieldType(md.name, fc.From)
+	typeChanged := fc.TypeChanged && !strings.EqualFold(newType, oldType)
+	if !typeChanged && !fc.NullableChanged && !fc.DefaultChanged {
+		return
+	}
+	col := md.quote(f.Name)
+	switch md.name {
+	case "mysql":
+		// MODIFY COLUMN restates the column definition, including the default.
+		if typeChanged || fc.NullableChanged {
+			def := ""
+			if !f.Nullable {
+				def = " not null"
+			}
+			if f.Default != "" {
+				def += " default " + f.Default
+			}
+			stmt("alter table %s modify column %s %s%s", tbl, col, newType, def)
+		} else if f.Default != "" {
+			stmt("alter table %s alter column %s set default %s", tbl, col, f.Default)
+		} else {
+			stmt("alter table %s alter column %s drop default", tbl, col)
+		}
+	case "mssql":
+		if typeChanged || fc.NullableChanged {
+			null := " null"
+			if !f.Nullable {
+				null = " not null"
+			}
+			stmt("alter table %s alter column %s %s%s", tbl, col, newType, null)
+		}
+		if fc.DefaultChanged {
+			// Defaults are constraints with generated names; look the old one up to drop it.
+			if fc.From.Default != "" {
+				obj := quoteStringMSSQL(tbl)
+				stmt("declare @df sysname = (select name from sys.default_constraints where parent_object_id = object_id(%s) "+
+					"and parent_column_id = columnproperty(object_id(%s), %s, 'ColumnId')); "+
+					"if @df is not null exec (N'alter table %s drop constraint ' + quotename(@df))",
+					obj, obj, quoteStringMSSQL(f.Name), strings.ReplaceAll(tbl, "'", "''"))
+			}
+			if f.Default != "" {
+				stmt("alter table %s add default %s for %s", tbl, f.Default, col)
+			}
+		}
+	case "bigquery":
+		if typeChanged {
+			stmt("alter table %s alter column %s set data type %s", tbl, col, newType)
+		}
+		// BigQuery can relax a column to nullable but cannot add NOT NULL to an existing column.
+		if fc.NullableChanged && f.Nullable {
+			stmt("alter table %s alter column %s drop not null", tbl, col)
+		}
+		renderAlterDefault(tbl, col, fc, stmt)
+	default:
+		if typeChanged {
+			stmt("alter table %s alter column %s type %s", tbl, col, newType)
+		}
+		if fc.NullableChanged {
+			if f.Nullable {
+				stmt("alter table %s alter column %s drop not null", tbl, col)
+			} else {
+				stmt("alter table %s alter column %s set not null", tbl, col)
+			}
+		}
+		renderAlterDefault(tbl, col, fc, stmt)
+	}
+}
+
+// renderAlterDefault emits SET DEFAULT or DROP DEFAULT for a changed column default
+// (PostgreSQL and BigQuery syntax).
+func renderAlterDefault(tbl, col string, fc schema.FieldChange, stmt func(string, ...interface{})) {
+	if !fc.DefaultChanged {
+		return
+	}
+	if fc.To.Default != "" {
+		stmt("alter table %s alter column %s set default %s", tbl, col, fc.To.Default)
+	} else {
+		stmt("alter table %s alter column %s drop default", tbl, col)
+	}
+}
+
+// foreignKeyName returns the constraint name to create the foreign key under: its own
+// name, or for an unnamed relationship one following the dialect's naming convention.
+func foreignKeyName(dialect string, fk schema.ForeignKey) string {
+	if fk.Name != "" {
+		return fk.Name
+	}
+	switch dialect {
+	case "mysql":
+		return fmt.Sprintf("%s_ibfk_%d", fk.Table, fk.Ordinal)
+	case "mssql":
+		return "FK_" + fk.Table + "_" + fk.RefTable
+	default:
+		return fk.Table + "_" + strings.Join(fk.Columns, "_") + "_fkey"
+	}
+}
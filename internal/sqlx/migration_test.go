@@ -0,0 +1,323 @@
+package sqlx
+
+import (
+	"strings"
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+func migrationFixture() schema.Diagram {
+	return schema.Diagram{
+		Version: 1,
+		Tables: []schema.Table{
+			{ID: "t1", Name: "users", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "name", Type: "string", Length: intP(50), Nullable: true},
+				{ID: "f3", Name: "legacy", Type: "string", Nullable: true},
+			}},
+			{ID: "t2", Name: "posts", Fields: []schema.Field{
+				{ID: "f4", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f5", Name: "user_id", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f5"},
+		},
+	}
+}
+
+func TestGenerateMigration_NoChanges(t *testing.T) {
+	out, err := GenerateMigration("postgres", migrationFixture(), migrationFixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "" {
+		t.Errorf("expected empty migration, got: %s", out)
+	}
+}
+
+func TestGenerateMigration_Postgres(t *testing.T) {
+	from := migrationFixture()
+	to := migrationFixture()
+	to.Tables[0].Fields[1].Length = intP(100)
+	to.Tables[0].Fields[1].Nullable = false
+	to.Tables[0].Fields[2].Name = "notes" // renamed by ID
+	to.Tables[0].Fields = append(to.Tables[0].Fields, schema.Field{ID: "f9", Name: "email", Type: "string", Nullable: true})
+	to.Tables[1].Fields = to.Tables[1].Fields[:1] // user_id dropped, FK with it
+	to.Relationships = nil
+	from.Relationships[0].Name = "posts_author_fk"
+
+	out, err := GenerateMigrationWithOptions("postgres", from, to, schema.DiffOptions{MatchByID: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"alter table posts drop constraint posts_author_fk;",
+		"alter table users rename column legacy to notes;",
+		"alter table users add column email varchar;",
+		"alter table users alter column name type varchar(100);",
+		"alter table users alter column name set not null;",
+		"alter table posts drop column user_id;",
+	}
+	last := -1
+	for _, w := range want {
+		i := strings.Index(out, w)
+		if i < 0 {
+			t.Errorf("expected %q in output: %s", w, out)
+			continue
+		}
+		if i < last {
+			t.Errorf("expected %q to come later in output: %s", w, out)
+		}
+		last = i
+	}
+}
+
+func TestGenerateMigration_AddTableAndForeignKey(t *testing.T) {
+	from := migrationFixture()
+	to := migrationFixture()
+	to.Tables = append(to.Tables, schema.Table{ID: "t3", Name: "comments", Fields: []schema.Field{
+		{ID: "f10", Name: "id", Type: "integer", PrimaryKey: true},
+		{ID: "f11", Name: "post_id", Type: "integer"},
+	}})
+	to.Relationships = append(to.Relationships, schema.Relationship{
+		ID: "r2", SourceTableID: "t2", SourceFieldID: "f4", TargetTableID: "t3", TargetFieldID: "f11",
+	})
+
+	out, err := GenerateMigration("mysql", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := strings.Index(out, "create table `comments`")
+	fk := strings.Index(out, "alter table `comments` add constraint `comments_ibfk_1` foreign key (`post_id`) references `posts` (`id`);")
+	if create < 0 || fk < 0 || fk < create {
+		t.Errorf("expected create table followed by add foreign key in output: %s", out)
+	}
+
+	// The script has no GO separators, so the new tables get none either.
+	out, err = GenerateMigration("mssql", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "create table [dbo].[comments]") || strings.Contains(out, "GO\n") {
+		t.Errorf("expected create table without GO separators in output: %s", out)
+	}
+}
+
+func TestGenerateMigration_DropForeignKeyByName(t *testing.T) {
+	from := migrationFixture()
+	to := migrationFixture()
+	to.Relationships = nil
+	for dialect, want := range map[string]string{
+		"postgres": "alter table posts drop constraint posts_author_fk;",
+		"mysql":    "alter table `posts` drop foreign key `posts_author_fk`;",
+		"mssql":    "alter table [dbo].[posts] drop constraint [posts_author_fk];",
+	} {
+		out, err := GenerateMigration(dialect, from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The name the database gave an unnamed foreign key is unknown: no guessing.
+		if strings.Contains(out, "alter table") || !strings.Contains(out, "-- drop the foreign key posts (user_id) references users (id) by hand") {
+			t.Errorf("%s: expected a comment instead of a guessed name in output: %s", dialect, out)
+		}
+		named := migrationFixture()
+		named.Relationships[0].Name = "posts_author_fk"
+		out, err = GenerateMigration(dialect, named, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, want) || strings.Contains(out, "--") {
+			t.Errorf("%s: expected %q in output: %s", dialect, want, out)
+		}
+	}
+}
+
+func TestGenerateMigration_MSSQLRenameAndPrimaryKey(t *testing.T) {
+	from := migrationFixture()
+	to := migrationFixture()
+	to.Tables[1].Name = "articles"
+	to.Tables[1].Fields[1].PrimaryKey = true
+
+	out, err := GenerateMigrationWithOptions("mssql", from, to, schema.DiffOptions{MatchByID: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{
+		"exec sp_rename N'dbo.posts', N'articles';",
+		"-- drop the primary key articles (id) by hand: its constraint name is unknown\n",
+		"alter table [dbo].[articles] add constraint [PK_articles] primary key ([id], [user_id]);",
+	} {
+		if !strings.Contains(out, w) {
+			t.Errorf("expected %q in output: %s", w, out)
+		}
+	}
+}
+
+func TestGenerateMigration_SchemaQualified(t *testing.T) {
+	from := migrationFixture()
+	for i := range from.Tables {
+		from.Tables[i].Schema = "sales"
+	}
+	to := migrationFixture()
+	for i := range to.Tables {
+		to.Tables[i].Schema = "sales"
+	}
+	to.Tables[0].Fields[1].Name = "full_name"
+	to.Tables[1].Name = "articles"
+	from.Relationships = nil
+
+	tests := map[string][]string{
+		"postgres": {
+			"alter table sales.posts rename to articles;",
+			"alter table sales.users rename column name to full_name;",
+			"alter table sales.articles add constraint articles_user_id_fkey foreign key (user_id) references sales.users (id);",
+		},
+		"mysql": {
+			"rename table `sales`.`posts` to `sales`.`articles`;",
+		},
+		"mssql": {
+			"exec sp_rename N'sales.posts', N'articles';",
+			"exec sp_rename N'sales.users.name', N'full_name', 'COLUMN';",
+			"references [sales].[users] ([id]);",
+		},
+		"bigquery": {
+			"alter table sales.users rename column name to full_name;",
+		},
+	}
+	for dialect, want := range tests {
+		out, err := GenerateMigrationWithOptions(dialect, from, to, schema.DiffOptions{MatchByID: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !strings.Contains(out, w) {
+				t.Errorf("%s: expected %q in output: %s", dialect, w, out)
+			}
+		}
+	}
+}
+
+func TestGenerateMigration_BigQuerySkipsKeys(t *testing.T) {
+	from := migrationFixture()
+	to := migrationFixture()
+	to.Relationships = nil
+	to.Tables[0].Fields[1].Nullable = false
+
+	out, err := GenerateMigration("bigquery", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "" {
+		t.Errorf("expected no statements for key and NOT NULL changes in BigQuery, got: %s", out)
+	}
+}
+
+func TestGenerateMigration_Defaults(t *testing.T) {
+	from := migrationFixture()
+	from.Tables[0].Fields[2].Default = "'n/a'"
+	to := migrationFixture()
+	to.Tables[0].Fields[1].Default = "'anonymous'"
+	to.Tables[0].Fields = append(to.Tables[0].Fields, schema.Field{ID: "f9", Name: "score", Type: "integer", Default: "0", Check: "score >= 0"})
+
+	cases := map[string][]string{
+		"postgres": {
+			"alter table users add column score integer not null default 0 check (score >= 0);",
+			"alter table users alter column name set default 'anonymous';",
+			"alter table users alter column legacy drop default;",
+		},
+		"mysql": {
+			"alter table `users` alter column `name` set default 'anonymous';",
+			"alter table `users` alter column `legacy` drop default;",
+		},
+		"mssql": {
+			"alter table [dbo].[users] add default 'anonymous' for [name];",
+			"if @df is not null exec (N'alter table [dbo].[users] drop constraint ' + quotename(@df));",
+		},
+	}
+	for dialect, want := range cases {
+		out, err := GenerateMigration(dialect, from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !strings.Contains(out, w) {
+				t.Errorf("%s: expected %q in output: %s", dialect, w, out)
+			}
+		}
+	}
+}
+
+func TestGenerateMigration_UnknownDialect(t *testing.T) {
+	if _, err := GenerateMigration("oracle", schema.Diagram{}, schema.Diagram{}); err == nil {
+		t.Fatal("expected error for unknown dialect")
+	}
+}
+
+func TestGenerateMigration_Indexes(t *testing.T) {
+	from := migrationFixture()
+	from.Tables[1].Indexes = []schema.Index{{Name: "posts_user_idx", Columns: []schema.IndexColumn{{FieldID: "f5"}}}}
+	to := migrationFixture()
+	to.Tables[0].Indexes = []schema.Index{{Unique: true, Columns: []schema.IndexColumn{{FieldID: "f2"}}}}
+
+	cases := map[string][]string{
+		"postgres": {
+			"drop index posts_user_idx;",
+			"create unique index users_name_key on users (name);",
+		},
+		"mysql": {
+			"drop index `posts_user_idx` on `posts`;",
+			"create unique index `users_name_key` on `users` (`name`);",
+		},
+		"mssql": {
+			"drop index [posts_user_idx] on [dbo].[posts];",
+			"create unique index [users_name_key] on [dbo].[users] ([name]);",
+		},
+	}
+	for dialect, want := range cases {
+		out, err := GenerateMigration(dialect, from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !strings.Contains(out, w) {
+				t.Errorf("%s: expected %q in output: %s", dialect, w, out)
+			}
+		}
+	}
+}
+
+func TestGenerateMigration_Views(t *testing.T) {
+	from := migrationFixture()
+	from.Tables = append(from.Tables,
+		schema.Table{ID: "v1", Name: "user_names", Kind: schema.KindMaterializedView, Definition: "select name from users"},
+		schema.Table{ID: "v2", Name: "old_view", Kind: schema.KindView, Definition: "select 1"},
+	)
+	to := migrationFixture()
+	to.Tables[0].Fields = to.Tables[0].Fields[:2] // drop users.legacy
+	to.Tables = append(to.Tables,
+		schema.Table{ID: "v1", Name: "user_names", Kind: schema.KindMaterializedView, Definition: "select id, name from users"},
+	)
+	out, err := GenerateMigration("postgres", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := []string{
+		"drop view old_view;",
+		"drop materialized view user_names;",
+		"alter table users drop column legacy;",
+		"create materialized view user_names as\nselect id, name from users;",
+	}
+	last := -1
+	for _, w := range order {
+		i := strings.Index(out, w)
+		if i < 0 {
+			t.Fatalf("expected %q in output: %s", w, out)
+		}
+		if i < last {
+			t.Errorf("%q is out of order: %s", w, out)
+		}
+		last = i
+	}
+}
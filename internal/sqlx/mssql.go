@@ -0,0 +1,258 @@
+package sqlx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// MSSQLExporter generates SQL Server DDL with named PRIMARY KEY and FOREIGN KEY constraints.
+type MSSQLExporter struct{}
+
+func (m *MSSQLExporter) Dialect() string { return "mssql" }
+
+func (m *MSSQLExporter) Export(d schema.Diagram) (string, error) {
+	return ExportMSSQLWithOptions(d, "", "", true, false)
+}
+
+// ExportMSSQLWithOptions returns SQL Server DDL with table names qualified as [schema].[table].
+// schemaName applies to tables without a schema of their own and defaults to "dbo" when empty.
+// creationMode: "if_not_exists" -> guard each CREATE TABLE with IF NOT EXISTS (SELECT ... FROM sys.tables),
+// and the descriptions, indexes, foreign keys and views that follow it likewise, so the script can be rerun;
+// "create_or_replace" -> DROP TABLE IF EXISTS for every table, dependents first, then CREATE TABLE; else -> CREATE TABLE.
+// If goBatches is true, each statement is followed by a GO batch separator. Tables are
+// created after the tables they reference. Foreign keys that close a cycle, or all of them
+// if alterForeignKeys is true, are added with ALTER TABLE once every table exists. Views
+// are created after all tables; create_or_replace creates them with CREATE OR ALTER VIEW.
+// Without GO batches, each view is created through exec, as CREATE VIEW must start a batch.
+func ExportMSSQLWithOptions(d schema.Diagram, schemaName, creationMode string, goBatches, alterForeignKeys bool) (string, error) {
+	var b bytes.Buffer
+	if schemaName == "" {
+		schemaName = "dbo"
+	}
+	tableByID := make(map[string]*schema.Table)
+	for i := range d.Tables {
+		tableByID[d.Tables[i].ID] = &d.Tables[i]
+	}
+	endStatement := func() {
+		b.WriteString(";\n")
+		if goBatches {
+			b.WriteString("GO\n")
+		}
+		b.WriteString("\n")
+	}
+	co := dependencyOrder(d, alterForeignKeys)
+	pkNames, fkNames := mssqlConstraintNames(d, co, tableByID)
+	qualified := func(t *schema.Table) string {
+		return quoteIdentMSSQL(tableSchema(t, schemaName)) + "." + quoteIdentMSSQL(t.Name)
+	}
+	// guard makes the next statement conditional in if_not_exists mode, so the script
+	// can be run again against a database it already created.
+	guard := func(format string, args ...interface{}) {
+		if creationMode == "if_not_exists" {
+			fmt.Fprintf(&b, "if "+format+"\n", args...)
+		}
+	}
+
+	// create_or_replace drops every table up front, referencing tables after the tables
+	// that reference them. Foreign keys added after the tables may close a cycle, so
+	// they go first.
+	if creationMode == "create_or_replace" {
+		for ri, r := range d.Relationships {
+			if !co.deferred[ri] || fkNames[ri] == "" {
+				continue
+			}
+			child := tableByID[r.TargetTableID]
+			fmt.Fprintf(&b, "if object_id(%s, N'F') is not null alter table %s drop constraint %s",
+				quoteStringMSSQL(quoteIdentMSSQL(tableSchema(child, schemaName))+"."+quoteIdentMSSQL(fkNames[ri])),
+				qualified(child), quoteIdentMSSQL(fkNames[ri]))
+			endStatement()
+		}
+		for i := len(co.tables) - 1; i >= 0; i-- {
+			b.WriteString("drop table if exists ")
+			b.WriteString(qualified(co.tables[i]))
+			endStatement()
+		}
+	}
+	for _, t := range co.tables {
+		tblSchema := tableSchema(t, schemaName)
+		tblName := qualified(t)
+		guard("not exists (select 1 from sys.tables t join sys.schemas s on t.schema_id = s.schema_id where s.name = %s and t.name = %s)",
+			quoteStringMSSQL(tblSchema), quoteStringMSSQL(t.Name))
+		b.WriteString("create table ")
+		b.WriteString(tblName)
+		b.WriteString(" (\n")
+		var pk []string
+		for j, f := range t.Fields {
+			if j > 0 {
+				b.WriteString(",\n")
+			}
+			b.WriteString("  ")
+			b.WriteString(quoteIdentMSSQL(f.Name))
+			b.WriteString(" ")
+			b.WriteString(FieldType("mssql", f))
+			b.WriteString(columnAttributes("mssql", f))
+			if f.PrimaryKey {
+				pk = append(pk, f.Name)
+			}
+		}
+		if len(pk) > 0 {
+			b.WriteString(",\n  constraint ")
+			b.WriteString(quoteIdentMSSQL(pkNames[t]))
+			b.WriteString(" primary key (")
+			b.WriteString(joinIdentsMSSQL(pk))
+			b.WriteString(")")
+		}
+		for ri, r := range d.Relationships {
+			if r.TargetTableID != t.ID || co.deferred[ri] || fkNames[ri] == "" {
+				continue
+			}
+			srcT := tableByID[r.SourceTableID]
+			parentCols, childCols := relationshipColumns(r, srcT, t)
+			b.WriteString(",\n  constraint ")
+			b.WriteString(quoteIdentMSSQL(fkNames[ri]))
+			b.WriteString(" foreign key (")
+			b.WriteString(joinIdentsMSSQL(childCols))
+			b.WriteString(") references ")
+			b.WriteString(qualified(srcT))
+			b.WriteString(" (")
+			b.WriteString(joinIdentsMSSQL(parentCols))
+			b.WriteString(")")
+		}
+		b.WriteString("\n)")
+		endStatement()
+		if t.Description != "" {
+			guard("not exists (select 1 from sys.extended_properties where class = 1 and major_id = object_id(%s) and minor_id = 0 and name = N'MS_Description')",
+				quoteStringMSSQL(tblName))
+			fmt.Fprintf(&b, "exec sp_addextendedproperty N'MS_Description', %s, N'SCHEMA', %s, N'TABLE', %s",
+				quoteStringMSSQL(t.Description), quoteStringMSSQL(tblSchema), quoteStringMSSQL(t.Name))
+			endStatement()
+		}
+		for _, f := range t.Fields {
+			if f.Comment == "" {
+				continue
+			}
+			guard("not exists (select 1 from sys.extended_properties where class = 1 and major_id = object_id(%s) and minor_id = columnproperty(object_id(%s), %s, 'ColumnId') and name = N'MS_Description')",
+				quoteStringMSSQL(tblName), quoteStringMSSQL(tblName), quoteStringMSSQL(f.Name))
+			fmt.Fprintf(&b, "exec sp_addextendedproperty N'MS_Description', %s, N'SCHEMA', %s, N'TABLE', %s, N'COLUMN', %s",
+				quoteStringMSSQL(f.Comment), quoteStringMSSQL(tblSchema), quoteStringMSSQL(t.Name), quoteStringMSSQL(f.Name))
+			endStatement()
+		}
+		for _, def := range t.ResolvedIndexes() {
+			if stmt := createIndex("mssql", def, tblName); stmt != "" {
+				guard("not exists (select 1 from sys.indexes where object_id = object_id(%s) and name = %s)",
+					quoteStringMSSQL(tblName), quoteStringMSSQL(def.Name))
+				b.WriteString(stmt)
+				endStatement()
+			}
+		}
+	}
+	for ri, r := range d.Relationships {
+		if !co.deferred[ri] || fkNames[ri] == "" {
+			continue
+		}
+		parent, child := tableByID[r.SourceTableID], tableByID[r.TargetTableID]
+		parentCols, childCols := relationshipColumns(r, parent, child)
+		guard("object_id(%s, N'F') is null",
+			quoteStringMSSQL(quoteIdentMSSQL(tableSchema(child, schemaName))+"."+quoteIdentMSSQL(fkNames[ri])))
+		b.WriteString(addForeignKey(quoteIdentMSSQL, qualified(child), fkNames[ri], childCols, qualified(parent), parentCols))
+		endStatement()
+	}
+	for _, t := range co.views {
+		viewSchema := tableSchema(t, schemaName)
+		viewName := quoteIdentMSSQL(viewSchema) + "." + quoteIdentMSSQL(t.Name)
+		view := createView("mssql", t, viewName, creationMode == "create_or_replace")
+		// CREATE VIEW must start a batch, so a guarded statement, or one without a GO
+		// before it, runs through exec.
+		switch {
+		case creationMode == "if_not_exists":
+			view = "if object_id(" + quoteStringMSSQL(viewName) + ", N'V') is null\nexec (" + quoteStringMSSQL(view) + ")"
+		case !goBatches:
+			view = "exec (" + quoteStringMSSQL(view) + ")"
+		}
+		b.WriteString(view)
+		endStatement()
+		if t.Description != "" {
+			guard("not exists (select 1 from sys.extended_properties where class = 1 and major_id = object_id(%s) and minor_id = 0 and name = N'MS_Description')",
+				quoteStringMSSQL(viewName))
+			fmt.Fprintf(&b, "exec sp_addextendedproperty N'MS_Description', %s, N'SCHEMA', %s, N'VIEW', %s",
+				quoteStringMSSQL(t.Description), quoteStringMSSQL(viewSchema), quoteStringMSSQL(t.Name))
+			endStatement()
+		}
+	}
+	return b.String(), nil
+}
+
+// mssqlConstraintNames names the primary key of each table and the foreign key of each
+// exportable relationship (by index into d.Relationships), in the order the constraints
+// are created, so names made unique with a suffix do not depend on whether a foreign key
+// is declared inline or added later.
+func mssqlConstraintNames(d schema.Diagram, co creationOrder, tableByID map[string]*schema.Table) (map[*schema.Table]string, map[int]string) {
+	used := make(map[string]int)
+	pkNames := make(map[*schema.Table]string)
+	fkNames := make(map[int]string)
+	fkName := func(ri int, child *schema.Table) {
+		r := d.Relationships[ri]
+		parent := tableByID[r.SourceTableID]
+		if parent == nil || parent.IsView() {
+			return
+		}
+		if _, childCols := relationshipColumns(r, parent, child); len(childCols) == 0 {
+			return
+		}
+		name := r.Name
+		if name == "" {
+			name = "FK_" + child.Name + "_" + parent.Name
+		}
+		fkNames[ri] = uniqueConstraintName(used, name)
+	}
+	for _, t := range co.tables {
+		for _, f := range t.Fields {
+			if f.PrimaryKey {
+				pkNames[t] = uniqueConstraintName(used, "PK_"+t.Name)
+				break
+			}
+		}
+		for ri, r := range d.Relationships {
+			if r.TargetTableID == t.ID && !co.deferred[ri] {
+				fkName(ri, t)
+			}
+		}
+	}
+	for ri, r := range d.Relationships {
+		if co.deferred[ri] {
+			if child := tableByID[r.TargetTableID]; child != nil {
+				fkName(ri, child)
+			}
+		}
+	}
+	return pkNames, fkNames
+}
+
+// uniqueConstraintName returns name, or name with a numeric suffix if it was already used.
+// SQL Server constraint names must be unique within a schema.
+func uniqueConstraintName(used map[string]int, name string) string {
+	used[name]++
+	if n := used[name]; n > 1 {
+		return fmt.Sprintf("%s_%d", name, n)
+	}
+	return name
+}
+
+func quoteIdentMSSQL(s string) string {
+	return "[" + strings.ReplaceAll(s, "]", "]]") + "]"
+}
+
+func quoteStringMSSQL(s string) string {
+	return "N'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func joinIdentsMSSQL(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdentMSSQL(n)
+	}
+	return strings.Join(quoted, ", ")
+}
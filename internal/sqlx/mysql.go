@@ -0,0 +1,139 @@
+package sqlx
+
+import (
+	"bytes"
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// MySQLExporter generates MySQL DDL with PRIMARY KEY and FOREIGN KEY.
+type MySQLExporter struct{}
+
+func (m *MySQLExporter) Dialect() string { return "mysql" }
+
+func (m *MySQLExporter) Export(d schema.Diagram) (string, error) {
+	return ExportMySQLWithOptions(d, "InnoDB", "utf8mb4", false)
+}
+
+// ExportMySQLWithOptions returns MySQL DDL with backtick-quoted identifiers. Tables with
+// a schema are qualified with it as the database name.
+// engine and charset are emitted as table options (e.g. "engine=InnoDB default charset=utf8mb4");
+// either may be empty to omit it. Tables are created after the tables they reference and
+// views after all tables. Foreign keys that close a cycle, or all of them if
+// alterForeignKeys is true, are added with ALTER TABLE once every table exists.
+func ExportMySQLWithOptions(d schema.Diagram, engine, charset string, alterForeignKeys bool) (string, error) {
+	var b bytes.Buffer
+	tableByID := make(map[string]*schema.Table)
+	for i := range d.Tables {
+		tableByID[d.Tables[i].ID] = &d.Tables[i]
+	}
+	co := dependencyOrder(d, alterForeignKeys)
+	for _, t := range co.tables {
+		tblName := qualifiedTableNameMySQL(t)
+		b.WriteString("create table ")
+		b.WriteString(tblName)
+		b.WriteString(" (\n")
+		var pk []string
+		for j, f := range t.Fields {
+			if j > 0 {
+				b.WriteString(",\n")
+			}
+			b.WriteString("  ")
+			b.WriteString(quoteIdentMySQL(f.Name))
+			b.WriteString(" ")
+			b.WriteString(FieldType("mysql", f))
+			b.WriteString(columnAttributes("mysql", f))
+			if f.PrimaryKey {
+				pk = append(pk, f.Name)
+			}
+		}
+		if len(pk) > 0 {
+			b.WriteString(",\n  primary key (")
+			b.WriteString(joinIdentsMySQL(pk))
+			b.WriteString(")")
+		}
+		for ri, r := range d.Relationships {
+			if r.TargetTableID != t.ID || co.deferred[ri] {
+				continue
+			}
+			srcT := tableByID[r.SourceTableID]
+			if srcT == nil || srcT.IsView() {
+				continue
+			}
+			parentCols, childCols := relationshipColumns(r, srcT, t)
+			if len(childCols) == 0 {
+				continue
+			}
+			b.WriteString(",\n  constraint ")
+			b.WriteString(quoteIdentMySQL(exportedForeignKeyName("mysql", d, ri, t, srcT, childCols)))
+			b.WriteString(" foreign key (")
+			b.WriteString(joinIdentsMySQL(childCols))
+			b.WriteString(") references ")
+			b.WriteString(qualifiedTableNameMySQL(srcT))
+			b.WriteString(" (")
+			b.WriteString(joinIdentsMySQL(parentCols))
+			b.WriteString(")")
+		}
+		b.WriteString("\n)")
+		if engine != "" {
+			b.WriteString(" engine=")
+			b.WriteString(engine)
+		}
+		if charset != "" {
+			b.WriteString(" default charset=")
+			b.WriteString(charset)
+		}
+		if t.Description != "" {
+			b.WriteString(" comment=")
+			b.WriteString(quoteStringMySQL(t.Description))
+		}
+		b.WriteString(";\n")
+		for _, def := range t.ResolvedIndexes() {
+			b.WriteString(createIndex("mysql", def, tblName))
+			b.WriteString(";\n")
+		}
+		b.WriteString("\n")
+	}
+	for ri, r := range d.Relationships {
+		if !co.deferred[ri] {
+			continue
+		}
+		parent, child := tableByID[r.SourceTableID], tableByID[r.TargetTableID]
+		parentCols, childCols := relationshipColumns(r, parent, child)
+		if len(childCols) == 0 {
+			continue
+		}
+		b.WriteString(addForeignKey(quoteIdentMySQL, qualifiedTableNameMySQL(child),
+			exportedForeignKeyName("mysql", d, ri, child, parent, childCols), childCols,
+			qualifiedTableNameMySQL(parent), parentCols))
+		b.WriteString(";\n")
+	}
+	if len(co.deferred) > 0 {
+		b.WriteString("\n")
+	}
+	for _, t := range co.views {
+		b.WriteString(createView("mysql", t, qualifiedTableNameMySQL(t), false))
+		b.WriteString(";\n\n")
+	}
+	return b.String(), nil
+}
+
+func quoteIdentMySQL(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+func qualifiedTableNameMySQL(t *schema.Table) string {
+	if t.Schema == "" {
+		return quoteIdentMySQL(t.Name)
+	}
+	return quoteIdentMySQL(t.Schema) + "." + quoteIdentMySQL(t.Name)
+}
+
+func joinIdentsMySQL(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdentMySQL(n)
+	}
+	return strings.Join(quoted, ", ")
+}
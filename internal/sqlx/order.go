@@ -0,0 +1,174 @@
+package sqlx
+
+import (
+	"regexp"
+	"strings"
+
+	"schemastudio/internal/schema"
+)
+
+// creationOrder is the order in which an exporter creates a diagram's tables and views.
+type creationOrder struct {
+	tables []*schema.Table
+	views  []*schema.Table
+	// deferred holds the indexes into d.Relationships of the foreign keys that cannot be
+	// declared inline because their referenced table is created later (a cycle), or that
+	// were asked to be emitted separately. They are added with ALTER TABLE after all tables.
+	deferred map[int]bool
+}
+
+// dependencyOrder orders the base tables so that each comes after the tables its foreign
+// keys reference, and the views so that each comes after the views its definition names.
+// Independent tables keep diagram order. When relationships form a cycle, the first
+// table in diagram order that lies on it is created first and the foreign keys that
+// reference tables not yet created are deferred. If alterForeignKeys is true, every
+// foreign key is deferred. Self-references are declared inline.
+func dependencyOrder(d schema.Diagram, alterForeignKeys bool) creationOrder {
+	var co creationOrder
+	var tables, views []*schema.Table
+	for i := range d.Tables {
+		if t := &d.Tables[i]; t.IsView() {
+			views = append(views, t)
+		} else {
+			tables = append(tables, t)
+		}
+	}
+
+	tableIdx := make(map[string]int, len(tables))
+	for i, t := range tables {
+		tableIdx[t.ID] = i
+	}
+	deps := make([][]int, len(tables))
+	for _, r := range d.Relationships {
+		child, ok := tableIdx[r.TargetTableID]
+		parent, ok2 := tableIdx[r.SourceTableID]
+		if ok && ok2 && child != parent {
+			deps[child] = append(deps[child], parent)
+		}
+	}
+	pos := make(map[string]int, len(tables))
+	for n, i := range topoOrder(deps) {
+		co.tables = append(co.tables, tables[i])
+		pos[tables[i].ID] = n
+	}
+	co.deferred = make(map[int]bool)
+	for ri, r := range d.Relationships {
+		child, ok := pos[r.TargetTableID]
+		parent, ok2 := pos[r.SourceTableID]
+		if ok && ok2 && (alterForeignKeys || parent > child) {
+			co.deferred[ri] = true
+		}
+	}
+
+	viewDeps := make([][]int, len(views))
+	for i, v := range views {
+		for j, other := range views {
+			if i != j && namesTable(v.Definition, other.Name) {
+				viewDeps[i] = append(viewDeps[i], j)
+			}
+		}
+	}
+	for _, i := range topoOrder(viewDeps) {
+		co.views = append(co.views, views[i])
+	}
+	return co
+}
+
+// topoOrder returns the indexes 0..len(deps)-1 ordered so that each comes after the
+// indexes in its deps, keeping index order where there is a choice. When every remaining
+// index waits on another, the first one that lies on a cycle is taken next.
+func topoOrder(deps [][]int) []int {
+	n := len(deps)
+	done := make([]bool, n)
+	order := make([]int, 0, n)
+	ready := func(i int) bool {
+		for _, d := range deps[i] {
+			if !done[d] {
+				return false
+			}
+		}
+		return true
+	}
+	// onCycle reports whether i can reach itself through dependencies not yet done.
+	onCycle := func(i int) bool {
+		seen := make([]bool, n)
+		stack := []int{i}
+		for len(stack) > 0 {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, d := range deps[j] {
+				if d == i {
+					return true
+				}
+				if !done[d] && !seen[d] {
+					seen[d] = true
+					stack = append(stack, d)
+				}
+			}
+		}
+		return false
+	}
+	for len(order) < n {
+		next := -1
+		for i := 0; i < n && next < 0; i++ {
+			if !done[i] && ready(i) {
+				next = i
+			}
+		}
+		for i := 0; i < n && next < 0; i++ {
+			if !done[i] && onCycle(i) {
+				next = i
+			}
+		}
+		done[next] = true
+		order = append(order, next)
+	}
+	return order
+}
+
+// namesTable reports whether the SQL text mentions name as a whole identifier
+// (case-insensitive, bare or quoted).
+func namesTable(sql, name string) bool {
+	if name == "" {
+		return false
+	}
+	re, err := regexp.Compile(`(?i)(^|[^\w$])` + regexp.QuoteMeta(name) + `($|[^\w$])`)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(sql)
+}
+
+// addForeignKey renders ALTER TABLE ... ADD CONSTRAINT ... FOREIGN KEY, without a
+// terminator, for already quoted and qualified table names.
+func addForeignKey(quote func(string) string, table, name string, cols []string, refTable string, refCols []string) string {
+	quoteAll := func(names []string) string {
+		quoted := make([]string, len(names))
+		for i, n := range names {
+			quoted[i] = quote(n)
+		}
+		return strings.Join(quoted, ", ")
+	}
+	return "alter table " + table + " add constraint " + quote(name) +
+		" foreign key (" + quoteAll(cols) + ") references " + refTable + " (" + quoteAll(refCols) + ")"
+}
+
+// exportedForeignKeyName returns the constraint name the exporters give relationship ri:
+// its own name, or one following the dialect's naming convention. Exporters always state
+// the name, so a database created from their DDL has foreign keys migrations can drop.
+func exportedForeignKeyName(dialect string, d schema.Diagram, ri int, child, parent *schema.Table, cols []string) string {
+	r := d.Relationships[ri]
+	ordinal := 0
+	for _, other := range d.Relationships[:ri+1] {
+		if other.TargetTableID == r.TargetTableID {
+			ordinal++
+		}
+	}
+	return foreignKeyName(dialect, schema.ForeignKey{
+		Name:     r.Name,
+		Table:    child.Name,
+		Columns:  cols,
+		RefTable: parent.Name,
+		Ordinal:  ordinal,
+	})
+}
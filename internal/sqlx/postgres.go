@@ -13,19 +13,23 @@ type PostgresExporter struct{}
 func (p *PostgresExporter) Dialect() string { return "postgres" }
 
 func (p *PostgresExporter) Export(d schema.Diagram) (string, error) {
-	return ExportPostgresWithSchema(d, "")
+	return ExportPostgresWithSchema(d, "", false)
 }
 
-// ExportPostgresWithSchema returns PostgreSQL DDL. If schemaName is non-empty,
-// table names are qualified as "schema"."table".
-func ExportPostgresWithSchema(d schema.Diagram, schemaName string) (string, error) {
+// ExportPostgresWithSchema returns PostgreSQL DDL. Tables with a schema of their own are
+// qualified with it; otherwise, if schemaName is non-empty, as "schema"."table". Tables
+// are created after the tables they reference and views after all tables. Foreign keys
+// that close a cycle, or all of them if alterForeignKeys is true, are added with
+// ALTER TABLE once every table exists.
+func ExportPostgresWithSchema(d schema.Diagram, schemaName string, alterForeignKeys bool) (string, error) {
 	var b bytes.Buffer
 	tableByID := make(map[string]*schema.Table)
 	for i := range d.Tables {
 		tableByID[d.Tables[i].ID] = &d.Tables[i]
 	}
-	for _, t := range d.Tables {
-		tblName := qualifiedTableName(schemaName, t.Name)
+	co := dependencyOrder(d, alterForeignKeys)
+	for _, t := range co.tables {
+		tblName := qualifiedTableName(tableSchema(t, schemaName), t.Name)
 		b.WriteString("create table ")
 		b.WriteString(tblName)
 		b.WriteString(" (\n")
@@ -37,10 +41,8 @@ func ExportPostgresWithSchema(d schema.Diagram, schemaName string) (string, erro
 			b.WriteString("  ")
 			b.WriteString(quoteIdent(f.Name))
 			b.WriteString(" ")
-			b.WriteString(DefaultExportType("postgres", f.Type, f.Length, f.Precision, f.Scale, f.TypeOverrides))
-			if !f.Nullable {
-				b.WriteString(" not null")
-			}
+			b.WriteString(FieldType("postgres", f))
+			b.WriteString(columnAttributes("postgres", f))
 			if f.PrimaryKey {
 				pk = append(pk, f.Name)
 			}
@@ -55,53 +57,94 @@ func ExportPostgresWithSchema(d schema.Diagram, schemaName string) (string, erro
 			}
 			b.WriteString(")")
 		}
-		for _, r := range d.Relationships {
-			if r.TargetTableID != t.ID {
+		for ri, r := range d.Relationships {
+			if r.TargetTableID != t.ID || co.deferred[ri] {
 				continue
 			}
 			srcT := tableByID[r.SourceTableID]
-			if srcT == nil {
+			if srcT == nil || srcT.IsView() {
 				continue
 			}
-			srcFieldIDs := r.SourceFieldIDs
-			tgtFieldIDs := r.TargetFieldIDs
-			if len(srcFieldIDs) == 0 {
-				srcFieldIDs = []string{r.SourceFieldID}
+			parentCols, childCols := relationshipColumns(r, srcT, t)
+			if len(childCols) == 0 {
+				continue
 			}
-			if len(tgtFieldIDs) == 0 {
-				tgtFieldIDs = []string{r.TargetFieldID}
+			b.WriteString(",\n  constraint ")
+			b.WriteString(quoteIdent(exportedForeignKeyName("postgres", d, ri, t, srcT, childCols)))
+			b.WriteString(" foreign key (")
+			b.WriteString(joinIdents(childCols))
+			b.WriteString(") references ")
+			b.WriteString(qualifiedTableName(tableSchema(srcT, schemaName), srcT.Name))
+			b.WriteString(" (")
+			b.WriteString(joinIdents(parentCols))
+			b.WriteString(")")
+		}
+		b.WriteString("\n);\n")
+		if t.Description != "" {
+			b.WriteString("comment on table ")
+			b.WriteString(tblName)
+			b.WriteString(" is ")
+			b.WriteString(quoteString(t.Description))
+			b.WriteString(";\n")
+		}
+		for _, f := range t.Fields {
+			if f.Comment == "" {
+				continue
 			}
-			n := len(srcFieldIDs)
-			if len(tgtFieldIDs) < n {
-				n = len(tgtFieldIDs)
+			b.WriteString("comment on column ")
+			b.WriteString(tblName)
+			b.WriteString(".")
+			b.WriteString(quoteIdent(f.Name))
+			b.WriteString(" is ")
+			b.WriteString(quoteString(f.Comment))
+			b.WriteString(";\n")
+		}
+		for _, def := range t.ResolvedIndexes() {
+			b.WriteString(createIndex("postgres", def, tblName))
+			b.WriteString(";\n")
+		}
+		b.WriteString("\n")
+	}
+	for ri, r := range d.Relationships {
+		if !co.deferred[ri] {
+			continue
+		}
+		parent, child := tableByID[r.SourceTableID], tableByID[r.TargetTableID]
+		parentCols, childCols := relationshipColumns(r, parent, child)
+		if len(childCols) == 0 {
+			continue
+		}
+		b.WriteString(addForeignKey(quoteIdent,
+			qualifiedTableName(tableSchema(child, schemaName), child.Name),
+			exportedForeignKeyName("postgres", d, ri, child, parent, childCols), childCols,
+			qualifiedTableName(tableSchema(parent, schemaName), parent.Name), parentCols))
+		b.WriteString(";\n")
+	}
+	if len(co.deferred) > 0 {
+		b.WriteString("\n")
+	}
+	for _, t := range co.views {
+		viewName := qualifiedTableName(tableSchema(t, schemaName), t.Name)
+		b.WriteString(createView("postgres", t, viewName, false))
+		b.WriteString(";\n")
+		if t.Description != "" {
+			b.WriteString("comment on ")
+			if t.Kind == schema.KindMaterializedView {
+				b.WriteString("materialized ")
 			}
-			for i := 0; i < n; i++ {
-				var srcF, tgtF string
-				for _, f := range srcT.Fields {
-					if f.ID == srcFieldIDs[i] {
-						srcF = f.Name
-						break
-					}
-				}
-				for _, f := range t.Fields {
-					if f.ID == tgtFieldIDs[i] {
-						tgtF = f.Name
-						break
-					}
-				}
-				if srcF != "" && tgtF != "" {
-					srcTblName := qualifiedTableName(schemaName, srcT.Name)
-					b.WriteString(",\n  foreign key (")
-					b.WriteString(quoteIdent(tgtF))
-					b.WriteString(") references ")
-					b.WriteString(srcTblName)
-					b.WriteString(" (")
-					b.WriteString(quoteIdent(srcF))
-					b.WriteString(")")
-				}
+			b.WriteString("view ")
+			b.WriteString(viewName)
+			b.WriteString(" is ")
+			b.WriteString(quoteString(t.Description))
+			b.WriteString(";\n")
+		}
+		if t.Kind == schema.KindMaterializedView {
+			for _, def := range t.ResolvedIndexes() {
+				b.WriteString(createIndex("postgres", def, viewName))
+				b.WriteString(";\n")
 			}
 		}
-		b.WriteString("\n);\n\n")
+		b.WriteString("\n")
 	}
 	return b.String(), nil
 }
@@ -113,6 +156,14 @@ func quoteIdent(s string) string {
 	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
 }
 
+func joinIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdent(n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
 func qualifiedTableName(schemaName, tableName string) string {
 	q := quoteIdent(tableName)
 	if schemaName == "" {
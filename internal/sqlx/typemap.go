@@ -19,6 +19,12 @@ func NormalizeType(rawType string) (genericType string, length, precision, scale
 
 	upper := strings.ToUpper(raw)
 
+	// BigQuery ARRAY<...> and STRUCT<...> have no scalar equivalent; the raw type
+	// is kept as an override by importers.
+	if strings.HasPrefix(upper, "ARRAY<") || strings.HasPrefix(upper, "STRUCT<") {
+		return "other", nil, nil, nil
+	}
+
 	// Extract parenthesised dimensions: "varchar(255)" -> base="VARCHAR", args="255"
 	base := upper
 	var args string
@@ -93,6 +99,10 @@ func NormalizeType(rawType string) (genericType string, length, precision, scale
 	case "INTERVAL":
 		return "string", nil, nil, nil
 
+	// --- Struct (BigQuery RECORD); sub-fields live in Field.Fields ---
+	case "RECORD", "STRUCT":
+		return schema.TypeStruct, nil, nil, nil
+
 	default:
 		// Fall back: if it looks like it could be a known family with qualifiers, try again
 		if strings.Contains(base, "INT") {
@@ -135,6 +145,37 @@ func DefaultExportType(dialect, genericType string, length, precision, scale *in
 	}
 }
 
+// FieldType returns the dialect-specific SQL type for f like DefaultExportType, and also
+// renders its sub-fields and repetition. A type override names the element type, so it
+// replaces the scalar or STRUCT<...> type but is still wrapped for a repeated field.
+// BigQuery gets STRUCT<...> and ARRAY<...>; PostgreSQL stores a repeated scalar as an
+// array, and other dialects store structs and repeated fields as JSON.
+func FieldType(dialect string, f schema.Field) string {
+	typ := DefaultExportType(dialect, f.Type, f.Length, f.Precision, f.Scale, f.TypeOverrides)
+	if f.Type != schema.TypeStruct && !f.Repeated {
+		return typ
+	}
+	if dialect == "bigquery" {
+		if f.Type == schema.TypeStruct && f.TypeOverrides[dialect].Type == "" {
+			fields := make([]string, len(f.Fields))
+			for i, sub := range f.Fields {
+				fields[i] = quoteIdentBQ(sub.Name) + " " + FieldType(dialect, sub) + columnAttributes(dialect, schema.Field{
+					Nullable: sub.Nullable, Repeated: sub.Repeated, Comment: sub.Comment,
+				})
+			}
+			typ = "STRUCT<" + strings.Join(fields, ", ") + ">"
+		}
+		if f.Repeated {
+			typ = "ARRAY<" + typ + ">"
+		}
+		return typ
+	}
+	if f.Type != schema.TypeStruct && dialect == "postgres" {
+		return typ + "[]"
+	}
+	return DefaultExportType(dialect, "json", nil, nil, nil, nil)
+}
+
 // --- PostgreSQL defaults ---
 
 func pgDefaultType(gt string, length, precision, scale *int) string {
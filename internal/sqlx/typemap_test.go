@@ -125,6 +125,20 @@ func TestNormalizeType_Unknown(t *testing.T) {
 	}
 }
 
+func TestNormalizeType_BigQueryNested(t *testing.T) {
+	for _, raw := range []string{"ARRAY<STRING>", "STRUCT<id INT64, name STRING>", "array<struct<a int64>>"} {
+		if gt, _, _, _ := NormalizeType(raw); gt != "other" {
+			t.Errorf("NormalizeType(%q) = %q, want 'other'", raw, gt)
+		}
+	}
+	// A bare RECORD (inspected BigQuery columns) keeps its sub-fields in Field.Fields.
+	for _, raw := range []string{"RECORD", "struct"} {
+		if gt, _, _, _ := NormalizeType(raw); gt != schema.TypeStruct {
+			t.Errorf("NormalizeType(%q) = %q, want %q", raw, gt, schema.TypeStruct)
+		}
+	}
+}
+
 // --- DefaultExportType tests ---
 
 func TestDefaultExportType_Postgres(t *testing.T) {
@@ -225,6 +239,26 @@ func TestDefaultExportType_Override(t *testing.T) {
 	}
 }
 
+func TestFieldType_RepeatedOverride(t *testing.T) {
+	f := schema.Field{Type: "timestamp", Repeated: true, TypeOverrides: map[string]schema.FieldTypeOverride{
+		"bigquery": {Type: "DATETIME"},
+		"postgres": {Type: "timestamptz"},
+	}}
+	for dialect, want := range map[string]string{
+		"bigquery": "ARRAY<DATETIME>",
+		"postgres": "timestamptz[]",
+		"mysql":    "json",
+	} {
+		if got := FieldType(dialect, f); got != want {
+			t.Errorf("FieldType(%q) = %q, want %q", dialect, got, want)
+		}
+	}
+	f.Repeated = false
+	if got := FieldType("bigquery", f); got != "DATETIME" {
+		t.Errorf("FieldType(bigquery) without repetition = %q, want DATETIME", got)
+	}
+}
+
 // --- helpers ---
 
 func intPtr(v int) *int { return &v }
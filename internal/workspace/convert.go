@@ -0,0 +1,167 @@
+package workspace
+
+import (
+	"fmt"
+
+	"schemastudio/internal/schema"
+)
+
+// CatalogToDiagram converts catalog tables and relationships into a schema.Diagram
+// so the catalog can be exported, diffed or compared like any other diagram.
+// Tables keep their catalog IDs; positions are left at zero.
+func CatalogToDiagram(tables []CatalogTable, rels []CatalogRelationship) schema.Diagram {
+	d := schema.NewDiagram()
+	for _, ct := range tables {
+		d.Tables = append(d.Tables, catalogTableToSchema(ct))
+	}
+	for _, cr := range rels {
+		if r, ok := catalogRelationshipToSchema(cr); ok {
+			d.Relationships = append(d.Relationships, r)
+		}
+	}
+	return d
+}
+
+// CatalogDiagram loads the whole workspace catalog as a schema.Diagram.
+func (r *WorkspaceRepo) CatalogDiagram() (schema.Diagram, error) {
+	tables, err := r.ListCatalogTables()
+	if err != nil {
+		return schema.Diagram{}, err
+	}
+	rels, err := r.ListCatalogRelationships()
+	if err != nil {
+		return schema.Diagram{}, err
+	}
+	return CatalogToDiagram(tables, rels), nil
+}
+
+// DiagramToSchema converts a workspace diagram into a schema.Diagram containing the
+// tables placed on it (at their positions) and its placed relationships.
+func DiagramToSchema(d Diagram, tables []CatalogTable, rels []CatalogRelationship) schema.Diagram {
+	out := schema.NewDiagram()
+	out.Viewport = &schema.Viewport{Zoom: d.ViewportZoom, PanX: d.ViewportPanX, PanY: d.ViewportPanY}
+	tableByID := make(map[string]CatalogTable, len(tables))
+	for _, t := range tables {
+		tableByID[t.ID] = t
+	}
+	relByID := make(map[string]CatalogRelationship, len(rels))
+	for _, r := range rels {
+		relByID[r.ID] = r
+	}
+	placed := make(map[string]bool)
+	for _, tp := range d.Tables {
+		ct, ok := tableByID[tp.CatalogTableID]
+		if !ok {
+			continue
+		}
+		t := catalogTableToSchema(ct)
+		t.X, t.Y = tp.X, tp.Y
+		out.Tables = append(out.Tables, t)
+		placed[ct.ID] = true
+	}
+	for _, rp := range d.Relationships {
+		cr, ok := relByID[rp.CatalogRelationshipID]
+		if !ok || !placed[cr.SourceTableID] || !placed[cr.TargetTableID] {
+			continue
+		}
+		if r, ok := catalogRelationshipToSchema(cr); ok {
+			r.Label = rp.Label
+			out.Relationships = append(out.Relationships, r)
+		}
+	}
+	return out
+}
+
+// SchemaDiagram loads a workspace diagram as a schema.Diagram. Returns an error if
+// the diagram does not exist.
+func (r *WorkspaceRepo) SchemaDiagram(id string) (schema.Diagram, error) {
+	d, err := r.GetDiagram(id)
+	if err != nil {
+		return schema.Diagram{}, err
+	}
+	if d == nil {
+		return schema.Diagram{}, fmt.Errorf("diagram %s not found", id)
+	}
+	tables, err := r.ListCatalogTables()
+	if err != nil {
+		return schema.Diagram{}, err
+	}
+	rels, err := r.ListCatalogRelationships()
+	if err != nil {
+		return schema.Diagram{}, err
+	}
+	return DiagramToSchema(*d, tables, rels), nil
+}
+
+func catalogTableToSchema(ct CatalogTable) schema.Table {
+	t := schema.Table{
+		ID:          ct.ID,
+		Name:        ct.Name,
+		Schema:      ct.Schema,
+		Description: ct.Description,
+		Owner:       ct.Owner,
+		Tags:        ct.Tags,
+		Kind:        ct.Kind,
+		Definition:  ct.Definition,
+		Fields:      []schema.Field{},
+	}
+	for _, cf := range ct.Fields {
+		f := schema.Field{
+			ID:         cf.ID,
+			Name:       cf.Name,
+			Type:       cf.Type,
+			Nullable:   cf.Nullable,
+			PrimaryKey: cf.PrimaryKey,
+			Length:     cf.Length,
+			Precision:  cf.Precision,
+			Scale:      cf.Scale,
+			Default:    cf.Default,
+			Identity:   cf.Identity,
+			Unique:     cf.Unique,
+			Check:      cf.Check,
+			Comment:    cf.Comment,
+			Repeated:   cf.Repeated,
+			Fields:     cf.Fields,
+		}
+		if len(cf.TypeOverrides) > 0 {
+			f.TypeOverrides = make(map[string]schema.FieldTypeOverride, len(cf.TypeOverrides))
+			for _, o := range cf.TypeOverrides {
+				f.TypeOverrides[o.Dialect] = schema.FieldTypeOverride{Type: o.TypeOverride}
+			}
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	for _, ci := range ct.Indexes {
+		ix := schema.Index{Name: ci.Name, Unique: ci.Unique, Where: ci.Where, Method: ci.Method}
+		for _, c := range ci.Columns {
+			ix.Columns = append(ix.Columns, schema.IndexColumn{FieldID: c.FieldID, Expression: c.Expression, Desc: c.Desc})
+		}
+		t.Indexes = append(t.Indexes, ix)
+	}
+	return t
+}
+
+// catalogRelationshipToSchema converts a catalog relationship. Relationships
+// without field mappings cannot be represented and are skipped.
+func catalogRelationshipToSchema(cr CatalogRelationship) (schema.Relationship, bool) {
+	if len(cr.Fields) == 0 {
+		return schema.Relationship{}, false
+	}
+	r := schema.Relationship{
+		ID:            cr.ID,
+		SourceTableID: cr.SourceTableID,
+		SourceFieldID: cr.Fields[0].SourceFieldID,
+		TargetTableID: cr.TargetTableID,
+		TargetFieldID: cr.Fields[0].TargetFieldID,
+		Name:          cr.Name,
+		Note:          cr.Note,
+		Cardinality:   cr.Cardinality,
+	}
+	if len(cr.Fields) > 1 {
+		for _, f := range cr.Fields {
+			r.SourceFieldIDs = append(r.SourceFieldIDs, f.SourceFieldID)
+			r.TargetFieldIDs = append(r.TargetFieldIDs, f.TargetFieldID)
+		}
+	}
+	return r, true
+}
@@ -150,7 +150,88 @@ INSERT OR IGNORE INTO schema_version (version) VALUES (1);
 `
 
 // currentSchemaVersion is the latest schema version this code supports.
-const currentSchemaVersion = 1
+const currentSchemaVersion = 8
+
+// migrationV2SQL adds workspace snapshots (version history). A snapshot stores the
+// catalog and diagrams as a JSON document so it stays readable as the schema evolves.
+const migrationV2SQL = `
+CREATE TABLE IF NOT EXISTS snapshots (
+    id         TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    auto       INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT DEFAULT (datetime('now')),
+    data       TEXT NOT NULL
+);
+`
+
+// migrationV3SQL adds the undo/redo change journal. Each entry holds the state of the
+// entities a change touched, before and after it, as JSON documents.
+const migrationV3SQL = `
+CREATE TABLE IF NOT EXISTS change_journal (
+    seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+    label      TEXT NOT NULL,
+    undone     INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT DEFAULT (datetime('now')),
+    before     TEXT NOT NULL,
+    after      TEXT NOT NULL
+);
+`
+
+// migrationV4SQL adds column metadata to catalog fields: default expression, identity
+// mode, single-column UNIQUE, CHECK expression and comment.
+const migrationV4SQL = `
+ALTER TABLE catalog_fields ADD COLUMN default_value TEXT NOT NULL DEFAULT '';
+ALTER TABLE catalog_fields ADD COLUMN identity      TEXT NOT NULL DEFAULT '';
+ALTER TABLE catalog_fields ADD COLUMN is_unique     INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE catalog_fields ADD COLUMN check_expr    TEXT NOT NULL DEFAULT '';
+ALTER TABLE catalog_fields ADD COLUMN comment       TEXT NOT NULL DEFAULT '';
+`
+
+// migrationV5SQL adds secondary indexes on catalog tables. A key is either a field or,
+// for expression indexes, an SQL expression (field_id NULL).
+const migrationV5SQL = `
+CREATE TABLE IF NOT EXISTS catalog_indexes (
+    id           TEXT PRIMARY KEY,
+    table_id     TEXT NOT NULL REFERENCES catalog_tables(id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    is_unique    INTEGER NOT NULL DEFAULT 0,
+    where_clause TEXT NOT NULL DEFAULT '',
+    method       TEXT NOT NULL DEFAULT '',
+    sort_order   INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS catalog_index_columns (
+    index_id   TEXT NOT NULL REFERENCES catalog_indexes(id) ON DELETE CASCADE,
+    field_id   TEXT REFERENCES catalog_fields(id) ON DELETE CASCADE,
+    expression TEXT NOT NULL DEFAULT '',
+    is_desc    INTEGER NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (index_id, sort_order)
+);
+`
+
+// migrationV6SQL adds table-level metadata: schema (namespace), description, owner and
+// tags, the latter as a JSON array.
+const migrationV6SQL = `
+ALTER TABLE catalog_tables ADD COLUMN schema_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE catalog_tables ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE catalog_tables ADD COLUMN owner       TEXT NOT NULL DEFAULT '';
+ALTER TABLE catalog_tables ADD COLUMN tags        TEXT NOT NULL DEFAULT '[]';
+`
+
+// migrationV7SQL adds views: a catalog table's kind (empty for a base table, 'view' or
+// 'materialized_view') and, for views, the query that defines it.
+const migrationV7SQL = `
+ALTER TABLE catalog_tables ADD COLUMN kind       TEXT NOT NULL DEFAULT '';
+ALTER TABLE catalog_tables ADD COLUMN definition TEXT NOT NULL DEFAULT '';
+`
+
+// migrationV8SQL adds nested fields: whether a field is repeated (an array) and, for
+// struct fields, their sub-fields as a JSON array of schema.Field.
+const migrationV8SQL = `
+ALTER TABLE catalog_fields ADD COLUMN repeated   INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE catalog_fields ADD COLUMN sub_fields TEXT NOT NULL DEFAULT '[]';
+`
 
 // OpenDB opens (or creates) a SQLite database at filePath and returns the
 // connection. It enables foreign keys and WAL journal mode.
@@ -181,12 +262,13 @@ func OpenDB(filePath string) (*sql.DB, error) {
 	return db, nil
 }
 
-// InitSchema creates all tables if they do not already exist.
+// InitSchema creates all tables if they do not already exist and brings the
+// schema up to the current version.
 func InitSchema(db *sql.DB) error {
 	if _, err := db.Exec(schemaSQL); err != nil {
 		return fmt.Errorf("init schema: %w", err)
 	}
-	return nil
+	return MigrateSchema(db)
 }
 
 // MigrateSchema checks the current schema version and applies incremental
@@ -200,7 +282,57 @@ func MigrateSchema(db *sql.DB) error {
 	if version > currentSchemaVersion {
 		return fmt.Errorf("workspace file version %d is newer than supported version %d — please update Schema Studio", version, currentSchemaVersion)
 	}
-	// Future migrations go here, e.g.:
-	// if version < 2 { applyMigrationV2(db); }
+	if version < 2 {
+		if err := applyMigration(db, 2, migrationV2SQL); err != nil {
+			return err
+		}
+	}
+	if version < 3 {
+		if err := applyMigration(db, 3, migrationV3SQL); err != nil {
+			return err
+		}
+	}
+	if version < 4 {
+		if err := applyMigration(db, 4, migrationV4SQL); err != nil {
+			return err
+		}
+	}
+	if version < 5 {
+		if err := applyMigration(db, 5, migrationV5SQL); err != nil {
+			return err
+		}
+	}
+	if version < 6 {
+		if err := applyMigration(db, 6, migrationV6SQL); err != nil {
+			return err
+		}
+	}
+	if version < 7 {
+		if err := applyMigration(db, 7, migrationV7SQL); err != nil {
+			return err
+		}
+	}
+	if version < 8 {
+		if err := applyMigration(db, 8, migrationV8SQL); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// applyMigration runs ddl and records the new schema version in one transaction.
+func applyMigration(db *sql.DB, version int, ddl string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(ddl); err != nil {
+		return fmt.Errorf("migrate schema to version %d: %w", version, err)
+	}
+	if _, err := tx.Exec("INSERT OR IGNORE INTO schema_version (version) VALUES (?)", version); err != nil {
+		return fmt.Errorf("record schema version %d: %w", version, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,407 @@
+package workspace
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// maxJournalEntries is how many undoable changes are kept in the journal.
+const maxJournalEntries = 200
+
+// ChangeScope names the entities a change may touch. Their state is captured before
+// and after the change so it can be undone and redone. Relationships attached to the
+// listed tables are always captured, since rewriting a table's fields cascades to them.
+type ChangeScope struct {
+	TableIDs        []string
+	RelationshipIDs []string
+	DiagramIDs      []string
+	// Cascade also captures the diagrams placing the tables or relationships, for
+	// deletes that cascade to diagram placements.
+	Cascade bool
+	// All captures the entire catalog and every diagram.
+	All bool
+}
+
+// JournalEntry is one undoable change.
+type JournalEntry struct {
+	Seq       int64  `json:"seq"`
+	Label     string `json:"label"`
+	Undone    bool   `json:"undone,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// UndoState reports what can be undone and redone.
+type UndoState struct {
+	CanUndo   bool   `json:"canUndo"`
+	CanRedo   bool   `json:"canRedo"`
+	UndoLabel string `json:"undoLabel,omitempty"`
+	RedoLabel string `json:"redoLabel,omitempty"`
+}
+
+// journalState is the captured state of a set of entities. A nil value means the
+// entity does not exist in that state.
+type journalState struct {
+	Tables        map[string]*CatalogTable        `json:"tables,omitempty"`
+	Relationships map[string]*CatalogRelationship `json:"relationships,omitempty"`
+	Diagrams      map[string]*Diagram             `json:"diagrams,omitempty"`
+}
+
+// Journaled runs fn and records the change it makes to the entities in scope, so that
+// Undo can revert it. Recording a new change discards anything that was undone.
+// Changes that leave the captured state unchanged are not recorded.
+func (r *WorkspaceRepo) Journaled(label string, scope ChangeScope, fn func() error) error {
+	tables, rels, diagrams, err := r.expandScope(scope)
+	if err != nil {
+		return err
+	}
+	before, err := r.captureState(tables, rels, diagrams)
+	if err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	if scope.All {
+		// Pick up entities the change created; they did not exist before it, so undoing
+		// deletes them.
+		if tables, rels, diagrams, err = r.expandScope(scope); err != nil {
+			return err
+		}
+		tables, rels, diagrams = unionKeys(tables, before.Tables), unionKeys(rels, before.Relationships), unionKeys(diagrams, before.Diagrams)
+		addAbsent(before.Tables, tables)
+		addAbsent(before.Relationships, rels)
+		addAbsent(before.Diagrams, diagrams)
+	}
+	after, err := r.captureState(tables, rels, diagrams)
+	if err != nil {
+		return err
+	}
+	return r.recordChange(label, before, after)
+}
+
+// Undo reverts the most recent change that has not been undone. Returns nil if there
+// is nothing to undo.
+func (r *WorkspaceRepo) Undo() (*JournalEntry, error) {
+	return r.replay("SELECT seq, label, created_at, before FROM change_journal WHERE undone = 0 ORDER BY seq DESC LIMIT 1", true)
+}
+
+// Redo reapplies the earliest undone change. Returns nil if there is nothing to redo.
+func (r *WorkspaceRepo) Redo() (*JournalEntry, error) {
+	return r.replay("SELECT seq, label, created_at, after FROM change_journal WHERE undone = 1 ORDER BY seq ASC LIMIT 1", false)
+}
+
+// GetUndoState returns whether undo and redo are available, with the change labels.
+func (r *WorkspaceRepo) GetUndoState() (UndoState, error) {
+	var s UndoState
+	err := r.db.QueryRow("SELECT label FROM change_journal WHERE undone = 0 ORDER BY seq DESC LIMIT 1").Scan(&s.UndoLabel)
+	if err != nil && err != sql.ErrNoRows {
+		return s, err
+	}
+	s.CanUndo = err == nil
+	err = r.db.QueryRow("SELECT label FROM change_journal WHERE undone = 1 ORDER BY seq ASC LIMIT 1").Scan(&s.RedoLabel)
+	if err != nil && err != sql.ErrNoRows {
+		return s, err
+	}
+	s.CanRedo = err == nil
+	return s, nil
+}
+
+// ListJournal returns the journal, newest first.
+func (r *WorkspaceRepo) ListJournal() ([]JournalEntry, error) {
+	rows, err := r.db.Query("SELECT seq, label, undone, created_at FROM change_journal ORDER BY seq DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []JournalEntry
+	for rows.Next() {
+		var e JournalEntry
+		var undone int
+		if err := rows.Scan(&e.Seq, &e.Label, &undone, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Undone = undone != 0
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// FieldTableID returns the ID of the table a field belongs to, or "" if the field does not exist.
+func (r *WorkspaceRepo) FieldTableID(fieldID string) (string, error) {
+	var tableID string
+	err := r.db.QueryRow("SELECT table_id FROM catalog_fields WHERE id = ?", fieldID).Scan(&tableID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return tableID, err
+}
+
+func (r *WorkspaceRepo) replay(query string, undo bool) (*JournalEntry, error) {
+	var e JournalEntry
+	var data string
+	err := r.db.QueryRow(query).Scan(&e.Seq, &e.Label, &e.CreatedAt, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st journalState
+	if err := json.Unmarshal([]byte(data), &st); err != nil {
+		return nil, fmt.Errorf("decode journal entry %d: %w", e.Seq, err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := applyState(tx, st); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("UPDATE change_journal SET undone = ? WHERE seq = ?", boolToInt(undo), e.Seq); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	e.Undone = undo
+	return &e, nil
+}
+
+// applyState writes a captured state: absent entities are deleted (diagrams first, so
+// cascades do not matter), then tables, relationships and diagrams are saved in
+// dependency order.
+func applyState(tx *sql.Tx, st journalState) error {
+	for _, id := range sortedKeys(st.Diagrams) {
+		if st.Diagrams[id] == nil {
+			if _, err := tx.Exec("DELETE FROM diagrams WHERE id = ?", id); err != nil {
+				return err
+			}
+		}
+	}
+	for _, id := range sortedKeys(st.Relationships) {
+		if st.Relationships[id] == nil {
+			if _, err := tx.Exec("DELETE FROM catalog_relationships WHERE id = ?", id); err != nil {
+				return err
+			}
+		}
+	}
+	for _, id := range sortedKeys(st.Tables) {
+		if st.Tables[id] == nil {
+			if _, err := tx.Exec("DELETE FROM catalog_tables WHERE id = ?", id); err != nil {
+				return err
+			}
+		}
+	}
+	for _, id := range sortedKeys(st.Tables) {
+		if t := st.Tables[id]; t != nil {
+			if err := saveCatalogTableTx(tx, *t); err != nil {
+				return err
+			}
+		}
+	}
+	for _, id := range sortedKeys(st.Relationships) {
+		if rel := st.Relationships[id]; rel != nil {
+			if err := saveRelationshipTx(tx, *rel); err != nil {
+				return err
+			}
+		}
+	}
+	for _, id := range sortedKeys(st.Diagrams) {
+		if d := st.Diagrams[id]; d != nil {
+			if err := saveDiagramTx(tx, *d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *WorkspaceRepo) recordChange(label string, before, after journalState) error {
+	b, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	a, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+	if string(a) == string(b) {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM change_journal WHERE undone = 1"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO change_journal (label, before, after) VALUES (?, ?, ?)", label, string(b), string(a)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"DELETE FROM change_journal WHERE seq NOT IN (SELECT seq FROM change_journal ORDER BY seq DESC LIMIT ?)",
+		maxJournalEntries,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// expandScope resolves a scope into the IDs of every entity to capture.
+func (r *WorkspaceRepo) expandScope(scope ChangeScope) (tables, rels, diagrams map[string]bool, err error) {
+	tables, rels, diagrams = make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	if scope.All {
+		all, err := r.ListCatalogTables()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, t := range all {
+			tables[t.ID] = true
+		}
+		allRels, err := r.ListCatalogRelationships()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, rel := range allRels {
+			rels[rel.ID] = true
+		}
+		summaries, err := r.ListDiagrams()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, d := range summaries {
+			diagrams[d.ID] = true
+		}
+		return tables, rels, diagrams, nil
+	}
+
+	for _, id := range scope.TableIDs {
+		tables[id] = true
+	}
+	for _, id := range scope.RelationshipIDs {
+		rels[id] = true
+	}
+	for _, id := range scope.DiagramIDs {
+		diagrams[id] = true
+	}
+	if len(tables) > 0 {
+		rows, err := r.db.Query("SELECT id, source_table_id, target_table_id FROM catalog_relationships")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id, src, tgt string
+			if err := rows.Scan(&id, &src, &tgt); err != nil {
+				return nil, nil, nil, err
+			}
+			if tables[src] || tables[tgt] {
+				rels[id] = true
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if scope.Cascade {
+		for id := range tables {
+			if err := r.collectDiagrams(diagrams, "SELECT diagram_id FROM diagram_table_placements WHERE catalog_table_id = ?", id); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		for id := range rels {
+			if err := r.collectDiagrams(diagrams, "SELECT diagram_id FROM diagram_relationship_placements WHERE catalog_relationship_id = ?", id); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	return tables, rels, diagrams, nil
+}
+
+func (r *WorkspaceRepo) collectDiagrams(into map[string]bool, query, id string) error {
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var diagramID string
+		if err := rows.Scan(&diagramID); err != nil {
+			return err
+		}
+		into[diagramID] = true
+	}
+	return rows.Err()
+}
+
+// captureState loads the current state of the given entities.
+func (r *WorkspaceRepo) captureState(tables, rels, diagrams map[string]bool) (journalState, error) {
+	st := journalState{
+		Tables:        make(map[string]*CatalogTable),
+		Relationships: make(map[string]*CatalogRelationship),
+		Diagrams:      make(map[string]*Diagram),
+	}
+	for id := range tables {
+		t, err := r.GetCatalogTable(id)
+		if err != nil {
+			return st, err
+		}
+		st.Tables[id] = t
+	}
+	if len(rels) > 0 {
+		all, err := r.ListCatalogRelationships()
+		if err != nil {
+			return st, err
+		}
+		for id := range rels {
+			st.Relationships[id] = nil
+		}
+		for i := range all {
+			if rels[all[i].ID] {
+				st.Relationships[all[i].ID] = &all[i]
+			}
+		}
+	}
+	for id := range diagrams {
+		d, err := r.GetDiagram(id)
+		if err != nil {
+			return st, err
+		}
+		st.Diagrams[id] = d
+	}
+	return st, nil
+}
+
+func unionKeys[T any](ids map[string]bool, states map[string]*T) map[string]bool {
+	for id := range states {
+		ids[id] = true
+	}
+	return ids
+}
+
+// addAbsent records the given IDs missing from states as not existing.
+func addAbsent[T any](states map[string]*T, ids map[string]bool) {
+	for id := range ids {
+		if _, ok := states[id]; !ok {
+			states[id] = nil
+		}
+	}
+}
+
+func sortedKeys[T any](m map[string]*T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
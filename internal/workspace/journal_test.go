@@ -0,0 +1,272 @@
+package workspace
+
+import (
+	"fmt"
+	"testing"
+)
+
+// renameTable journals renaming a catalog table, saving it with all its fields as the
+// table editor does.
+func renameTable(t *testing.T, repo *WorkspaceRepo, id, name string) {
+	t.Helper()
+	err := repo.Journaled("Save table "+name, ChangeScope{TableIDs: []string{id}}, func() error {
+		ct, err := repo.GetCatalogTable(id)
+		if err != nil {
+			return err
+		}
+		ct.Name = name
+		return repo.SaveCatalogTable(*ct)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func undoState(t *testing.T, repo *WorkspaceRepo) UndoState {
+	t.Helper()
+	s, err := repo.GetUndoState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestJournal_UndoRedo(t *testing.T) {
+	repo := newTestRepo(t)
+	if _, err := repo.MergeImportedCatalog(mergeFixture(), false); err != nil {
+		t.Fatal(err)
+	}
+	if s := undoState(t, repo); s.CanUndo || s.CanRedo {
+		t.Fatalf("fresh workspace undo state = %+v", s)
+	}
+	tables, fields := catalogByName(t, repo)
+	customers := tables["customers"]
+
+	// Saving a table rewrites its fields, which cascades to its relationships; the
+	// journal captures those too.
+	renameTable(t, repo, customers.ID, "clients")
+	if s := undoState(t, repo); !s.CanUndo || s.UndoLabel != "Save table clients" || s.CanRedo {
+		t.Errorf("undo state after a change = %+v", s)
+	}
+
+	e, err := repo.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e == nil || e.Label != "Save table clients" || !e.Undone {
+		t.Fatalf("Undo = %+v", e)
+	}
+	after, afterFields := catalogByName(t, repo)
+	if after["customers"].ID != customers.ID || after["clients"].ID != "" {
+		t.Errorf("after undo tables = %+v", after)
+	}
+	rels, err := repo.ListCatalogRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 2 {
+		t.Fatalf("after undo relationships = %+v", rels)
+	}
+	for _, rel := range rels {
+		if rel.SourceTableID == customers.ID &&
+			(len(rel.Fields) != 1 || rel.Fields[0].SourceFieldID != fields["customers.id"].ID) {
+			t.Errorf("customer relationship fields = %+v", rel.Fields)
+		}
+	}
+	if afterFields["customers.id"].ID != fields["customers.id"].ID {
+		t.Errorf("customers.id = %+v", afterFields["customers.id"])
+	}
+	if s := undoState(t, repo); s.CanUndo || !s.CanRedo || s.RedoLabel != "Save table clients" {
+		t.Errorf("undo state after undo = %+v", s)
+	}
+	if e, err := repo.Undo(); err != nil || e != nil {
+		t.Errorf("Undo with nothing to undo = %+v, %v", e, err)
+	}
+
+	if e, err := repo.Redo(); err != nil || e == nil || e.Undone {
+		t.Fatalf("Redo = %+v, %v", e, err)
+	}
+	if after, _ := catalogByName(t, repo); after["clients"].ID != customers.ID {
+		t.Errorf("after redo tables = %+v", after)
+	}
+	if e, err := repo.Redo(); err != nil || e != nil {
+		t.Errorf("Redo with nothing to redo = %+v, %v", e, err)
+	}
+
+	// A change that leaves the captured state as it was is not recorded.
+	renameTable(t, repo, customers.ID, "clients")
+	if entries, err := repo.ListJournal(); err != nil || len(entries) != 1 {
+		t.Errorf("journal after a no-op change = %+v, %v", entries, err)
+	}
+}
+
+func TestJournal_NewChangeClearsRedo(t *testing.T) {
+	repo := newTestRepo(t)
+	if _, err := repo.MergeImportedCatalog(mergeFixture(), false); err != nil {
+		t.Fatal(err)
+	}
+	tables, _ := catalogByName(t, repo)
+	renameTable(t, repo, tables["customers"].ID, "clients")
+	renameTable(t, repo, tables["orders"].ID, "purchases")
+	if _, err := repo.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if s := undoState(t, repo); !s.CanRedo || s.RedoLabel != "Save table purchases" {
+		t.Fatalf("undo state after undo = %+v", s)
+	}
+
+	renameTable(t, repo, tables["legacy"].ID, "archive")
+	if s := undoState(t, repo); s.CanRedo || s.UndoLabel != "Save table archive" {
+		t.Errorf("undo state after a new change = %+v", s)
+	}
+	if e, err := repo.Redo(); err != nil || e != nil {
+		t.Errorf("Redo after a new change = %+v, %v", e, err)
+	}
+	entries, err := repo.ListJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Label != "Save table archive" || entries[1].Label != "Save table clients" {
+		t.Errorf("journal = %+v", entries)
+	}
+	if after, _ := catalogByName(t, repo); after["orders"].ID != tables["orders"].ID {
+		t.Errorf("tables = %+v, want orders left undone", after)
+	}
+}
+
+func TestJournal_ScopeAll(t *testing.T) {
+	repo := newTestRepo(t)
+	if _, err := repo.MergeImportedCatalog(mergeFixture(), false); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := catalogByName(t, repo)
+
+	// Entities the change creates are picked up after it runs.
+	err := repo.Journaled("Merge database import", ChangeScope{All: true}, func() error {
+		addTable(t, repo, "extra")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := catalogByName(t, repo)
+	if len(after) != len(before) || after["extra"].ID != "" {
+		t.Errorf("after undo tables = %+v", after)
+	}
+	if _, err := repo.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := catalogByName(t, repo); after["extra"].ID == "" {
+		t.Errorf("after redo tables = %+v", after)
+	}
+}
+
+func TestJournal_Cascade(t *testing.T) {
+	for _, cascade := range []bool{true, false} {
+		t.Run(fmt.Sprintf("cascade=%t", cascade), func(t *testing.T) {
+			repo := newTestRepo(t)
+			if _, err := repo.MergeImportedCatalog(mergeFixture(), false); err != nil {
+				t.Fatal(err)
+			}
+			tables, _ := catalogByName(t, repo)
+			rels, err := repo.ListCatalogRelationships()
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := Diagram{ID: "d1", Name: "Orders", Version: 1, ViewportZoom: 1}
+			for _, name := range []string{"customers", "orders"} {
+				d.Tables = append(d.Tables, DiagramTablePlacement{ID: "p-" + name, CatalogTableID: tables[name].ID})
+			}
+			for _, rel := range rels {
+				d.Relationships = append(d.Relationships, DiagramRelationshipPlacement{ID: "p-" + rel.ID, CatalogRelationshipID: rel.ID})
+			}
+			if err := repo.SaveDiagram(d); err != nil {
+				t.Fatal(err)
+			}
+
+			orders := tables["orders"].ID
+			err = repo.Journaled("Delete table", ChangeScope{TableIDs: []string{orders}, Cascade: cascade}, func() error {
+				return repo.DeleteCatalogTable(orders)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := repo.GetDiagram("d1"); len(got.Tables) != 1 || len(got.Relationships) != 0 {
+				t.Fatalf("diagram after delete = %+v", got)
+			}
+
+			if _, err := repo.Undo(); err != nil {
+				t.Fatal(err)
+			}
+			if after, _ := catalogByName(t, repo); after["orders"].ID != orders {
+				t.Errorf("orders not restored: %+v", after)
+			}
+			if rels, _ := repo.ListCatalogRelationships(); len(rels) != 2 {
+				t.Errorf("relationships not restored: %+v", rels)
+			}
+			// Only a cascading scope captures the diagram, so only then do the
+			// placements come back.
+			got, err := repo.GetDiagram("d1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 1
+			if cascade {
+				want = 2
+			}
+			if len(got.Tables) != want || len(got.Relationships) != 2*(want-1) {
+				t.Errorf("diagram after undo has %d tables and %d relationships, want %d and %d",
+					len(got.Tables), len(got.Relationships), want, 2*(want-1))
+			}
+		})
+	}
+}
+
+func TestJournal_Trim(t *testing.T) {
+	repo := newTestRepo(t)
+	if _, err := repo.MergeImportedCatalog(mergeFixture(), false); err != nil {
+		t.Fatal(err)
+	}
+	tables, _ := catalogByName(t, repo)
+	legacy := tables["legacy"].ID
+	const extra = 5
+	for i := 1; i <= maxJournalEntries+extra; i++ {
+		renameTable(t, repo, legacy, fmt.Sprintf("legacy_%d", i))
+	}
+
+	entries, err := repo.ListJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != maxJournalEntries {
+		t.Fatalf("journal has %d entries, want %d", len(entries), maxJournalEntries)
+	}
+	if want := fmt.Sprintf("Save table legacy_%d", maxJournalEntries+extra); entries[0].Label != want {
+		t.Errorf("newest entry = %q, want %q", entries[0].Label, want)
+	}
+	if want := fmt.Sprintf("Save table legacy_%d", extra+1); entries[len(entries)-1].Label != want {
+		t.Errorf("oldest entry = %q, want %q", entries[len(entries)-1].Label, want)
+	}
+
+	// Undoing everything stops at the oldest kept change.
+	undone := 0
+	for {
+		e, err := repo.Undo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e == nil {
+			break
+		}
+		undone++
+	}
+	if undone != maxJournalEntries {
+		t.Errorf("undid %d changes, want %d", undone, maxJournalEntries)
+	}
+	if ct, err := repo.GetCatalogTable(legacy); err != nil || ct.Name != fmt.Sprintf("legacy_%d", extra) {
+		t.Errorf("legacy after undoing everything = %+v, %v", ct, err)
+	}
+}
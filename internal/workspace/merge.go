@@ -0,0 +1,629 @@
+package workspace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"schemastudio/internal/schema"
+	"schemastudio/internal/sqlx"
+)
+
+// CatalogMergeReport describes what merging an imported schema into the catalog
+// changed (or, for a dry run, would change).
+type CatalogMergeReport struct {
+	DryRun               bool               `json:"dryRun"`
+	AddedTables          []string           `json:"addedTables"`
+	UpdatedTables        []TableMergeReport `json:"updatedTables"`
+	UnchangedTables      []string           `json:"unchangedTables"`
+	RemovedTables        []string           `json:"removedTables"` // catalog tables not in the import; kept, not deleted
+	AddedRelationships   []string           `json:"addedRelationships"`
+	RemovedRelationships []string           `json:"removedRelationships"`
+}
+
+// TableMergeReport lists the column changes made to an existing catalog table.
+type TableMergeReport struct {
+	Table          string              `json:"table"`
+	Changes        []string            `json:"changes,omitempty"` // table-level changes, e.g. "schema: - -> sales"
+	AddedColumns   []string            `json:"addedColumns,omitempty"`
+	ChangedColumns []ColumnMergeChange `json:"changedColumns,omitempty"`
+	RemovedColumns []string            `json:"removedColumns,omitempty"`
+	AddedIndexes   []string            `json:"addedIndexes,omitempty"`
+	ChangedIndexes []string            `json:"changedIndexes,omitempty"`
+	RemovedIndexes []string            `json:"removedIndexes,omitempty"`
+}
+
+// ColumnMergeChange describes how an existing column was updated, e.g. "nullable: false -> true".
+type ColumnMergeChange struct {
+	Column  string   `json:"column"`
+	Changes []string `json:"changes"`
+}
+
+// catalogMergePlan is the set of writes needed to apply a merge.
+type catalogMergePlan struct {
+	report         CatalogMergeReport
+	tables         []CatalogTable // table rows and the fields and indexes to upsert for them
+	removedFields  []string
+	removedRels    []string
+	removedIndexes []string
+	addedRels      []CatalogRelationship
+}
+
+// MergeImportedCatalog upserts an imported schema (e.g. from ImportFromDatabase) into the
+// catalog. Tables and columns are matched by name (case-insensitive), so existing catalog
+// IDs, diagram placements, type overrides and relationship metadata are preserved. A
+// table with a schema matches the catalog table of the same schema and name, or else an
+// unqualified catalog table of that name, which then takes the schema.
+// Columns missing from an imported table are removed along with the relationships and
+// indexes that use them; catalog tables not present in the import are left alone, since
+// an import may cover only some tables, and are reported as RemovedTables. Indexes are matched by name; existing ones the
+// import does not mention are kept. If dryRun is true, nothing is written.
+func (r *WorkspaceRepo) MergeImportedCatalog(imported schema.TableCatalog, dryRun bool) (CatalogMergeReport, error) {
+	tables, err := r.ListCatalogTables()
+	if err != nil {
+		return CatalogMergeReport{}, err
+	}
+	rels, err := r.ListCatalogRelationships()
+	if err != nil {
+		return CatalogMergeReport{}, err
+	}
+	plan := planCatalogMerge(tables, rels, imported)
+	plan.report.DryRun = dryRun
+	if dryRun {
+		return plan.report, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return CatalogMergeReport{}, err
+	}
+	defer tx.Rollback()
+
+	for _, t := range plan.tables {
+		if err := upsertTableRowTx(tx, t); err != nil {
+			return CatalogMergeReport{}, err
+		}
+		for _, f := range t.Fields {
+			if err := saveFieldTx(tx, f); err != nil {
+				return CatalogMergeReport{}, fmt.Errorf("save field %s: %w", f.ID, err)
+			}
+		}
+	}
+	// Indexes go before fields, so none is left behind with only some of its keys.
+	for _, id := range plan.removedIndexes {
+		if _, err := tx.Exec("DELETE FROM catalog_indexes WHERE id = ?", id); err != nil {
+			return CatalogMergeReport{}, err
+		}
+	}
+	for _, id := range plan.removedRels {
+		if _, err := tx.Exec("DELETE FROM catalog_relationships WHERE id = ?", id); err != nil {
+			return CatalogMergeReport{}, err
+		}
+	}
+	for _, id := range plan.removedFields {
+		if _, err := tx.Exec("DELETE FROM catalog_fields WHERE id = ?", id); err != nil {
+			return CatalogMergeReport{}, err
+		}
+	}
+	for _, t := range plan.tables {
+		for _, ix := range t.Indexes {
+			if err := saveIndexTx(tx, ix); err != nil {
+				return CatalogMergeReport{}, fmt.Errorf("save index %s: %w", ix.ID, err)
+			}
+		}
+	}
+	for _, rel := range plan.addedRels {
+		if err := saveRelationshipTx(tx, rel); err != nil {
+			return CatalogMergeReport{}, fmt.Errorf("save relationship %s: %w", rel.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return CatalogMergeReport{}, err
+	}
+	return plan.report, nil
+}
+
+// planCatalogMerge computes the writes and report for merging imported into the catalog.
+func planCatalogMerge(tables []CatalogTable, rels []CatalogRelationship, imported schema.TableCatalog) catalogMergePlan {
+	var plan catalogMergePlan
+
+	byName := make(map[string]*CatalogTable)      // lower-case name -> first table of that name
+	byQualified := make(map[string]*CatalogTable) // lower-case schema.name
+	fieldTable := make(map[string]string)         // catalog field ID -> table name
+	fieldName := make(map[string]string)          // catalog field ID -> field name
+	tableName := make(map[string]string)          // catalog table ID -> table name
+	nextTableOrder := 0
+	for i := range tables {
+		t := &tables[i]
+		key := strings.ToLower(t.Name)
+		if byName[key] == nil {
+			byName[key] = t
+		}
+		if qkey := strings.ToLower(t.Schema + "." + t.Name); byQualified[qkey] == nil {
+			byQualified[qkey] = t
+		}
+		tableName[t.ID] = t.Name
+		for _, f := range t.Fields {
+			fieldTable[f.ID] = t.Name
+			fieldName[f.ID] = f.Name
+		}
+		if t.SortOrder >= nextTableOrder {
+			nextTableOrder = t.SortOrder + 1
+		}
+	}
+
+	// Imported IDs are throwaway; map them to the catalog IDs they merge into.
+	tableIDs := make(map[string]string)
+	fieldIDs := make(map[string]string)
+	removed := make(map[string]bool)
+	matched := make(map[string]bool) // catalog table IDs the import matched
+
+	for _, it := range imported.Tables {
+		ct := byQualified[strings.ToLower(it.Schema+"."+it.Name)]
+		if ct == nil && it.Schema != "" {
+			ct = byQualified[strings.ToLower("."+it.Name)]
+		}
+		if ct == nil && it.Schema == "" {
+			ct = byName[strings.ToLower(it.Name)]
+		}
+		if ct == nil {
+			nt := CatalogTable{
+				ID:          newCatalogID("catalog"),
+				Name:        it.Name,
+				Schema:      it.Schema,
+				Description: it.Description,
+				Owner:       it.Owner,
+				Tags:        it.Tags,
+				Kind:        it.Kind,
+				Definition:  it.Definition,
+				SortOrder:   nextTableOrder,
+			}
+			nextTableOrder++
+			for j, f := range it.Fields {
+				cf := importedField(f, newCatalogID("f"), nt.ID, j)
+				fieldIDs[f.ID] = cf.ID
+				fieldName[cf.ID] = cf.Name
+				fieldTable[cf.ID] = nt.Name
+				nt.Fields = append(nt.Fields, cf)
+			}
+			for j, ix := range it.Indexes {
+				if ci, ok := importedIndex(it, ix, fieldIDs, newCatalogID("cidx"), nt.ID, j); ok {
+					nt.Indexes = append(nt.Indexes, ci)
+				}
+			}
+			tableIDs[it.ID] = nt.ID
+			tableName[nt.ID] = nt.Name
+			plan.tables = append(plan.tables, nt)
+			plan.report.AddedTables = append(plan.report.AddedTables, nt.Name)
+			continue
+		}
+
+		tableIDs[it.ID] = ct.ID
+		matched[ct.ID] = true
+		tr := TableMergeReport{Table: ct.Name, Changes: mergeTableMetadata(ct, it)}
+		existing := make(map[string]*CatalogField)
+		nextFieldOrder := 0
+		for j := range ct.Fields {
+			f := &ct.Fields[j]
+			if existing[strings.ToLower(f.Name)] == nil {
+				existing[strings.ToLower(f.Name)] = f
+			}
+			if f.SortOrder >= nextFieldOrder {
+				nextFieldOrder = f.SortOrder + 1
+			}
+		}
+		seen := make(map[string]bool)
+		var upserts []CatalogField
+		for _, f := range it.Fields {
+			cf := existing[strings.ToLower(f.Name)]
+			if cf == nil {
+				nf := importedField(f, newCatalogID("f"), ct.ID, nextFieldOrder)
+				nextFieldOrder++
+				fieldIDs[f.ID] = nf.ID
+				fieldName[nf.ID] = nf.Name
+				fieldTable[nf.ID] = ct.Name
+				upserts = append(upserts, nf)
+				tr.AddedColumns = append(tr.AddedColumns, nf.Name)
+				continue
+			}
+			seen[cf.ID] = true
+			fieldIDs[f.ID] = cf.ID
+			if changes := mergeField(cf, f); len(changes) > 0 {
+				upserts = append(upserts, *cf)
+				tr.ChangedColumns = append(tr.ChangedColumns, ColumnMergeChange{Column: cf.Name, Changes: changes})
+			}
+		}
+		for _, f := range ct.Fields {
+			if !seen[f.ID] {
+				removed[f.ID] = true
+				plan.removedFields = append(plan.removedFields, f.ID)
+				tr.RemovedColumns = append(tr.RemovedColumns, f.Name)
+			}
+		}
+		indexes, removedIndexes := mergeIndexes(ct, it, fieldIDs, removed, &tr)
+		plan.removedIndexes = append(plan.removedIndexes, removedIndexes...)
+		if len(tr.Changes) == 0 && len(tr.AddedColumns) == 0 && len(tr.ChangedColumns) == 0 && len(tr.RemovedColumns) == 0 &&
+			len(indexes) == 0 && len(removedIndexes) == 0 {
+			plan.report.UnchangedTables = append(plan.report.UnchangedTables, ct.Name)
+			continue
+		}
+		plan.tables = append(plan.tables, CatalogTable{
+			ID:          ct.ID,
+			Name:        ct.Name,
+			Schema:      ct.Schema,
+			Description: ct.Description,
+			Owner:       ct.Owner,
+			Tags:        ct.Tags,
+			Kind:        ct.Kind,
+			Definition:  ct.Definition,
+			SortOrder:   ct.SortOrder,
+			Fields:      upserts,
+			Indexes:     indexes,
+		})
+		plan.report.UpdatedTables = append(plan.report.UpdatedTables, tr)
+	}
+
+	for _, t := range tables {
+		if !matched[t.ID] {
+			plan.report.RemovedTables = append(plan.report.RemovedTables, t.Name)
+		}
+	}
+
+	// Relationships: keep existing ones (and their name/note/cardinality), drop those that
+	// lose a column, and add imported ones not already in the catalog.
+	label := func(rel CatalogRelationship) string {
+		var src, tgt []string
+		for _, f := range rel.Fields {
+			src = append(src, fieldName[f.SourceFieldID])
+			tgt = append(tgt, fieldName[f.TargetFieldID])
+		}
+		return fmt.Sprintf("%s(%s) -> %s(%s)", tableName[rel.TargetTableID], strings.Join(tgt, ", "),
+			tableName[rel.SourceTableID], strings.Join(src, ", "))
+	}
+	known := make(map[string]bool)
+	for _, rel := range rels {
+		lost := false
+		for _, f := range rel.Fields {
+			if removed[f.SourceFieldID] || removed[f.TargetFieldID] {
+				lost = true
+			}
+		}
+		if lost {
+			plan.removedRels = append(plan.removedRels, rel.ID)
+			plan.report.RemovedRelationships = append(plan.report.RemovedRelationships, label(rel))
+			continue
+		}
+		known[relationshipKey(rel)] = true
+	}
+	for _, ir := range imported.Relationships {
+		src, tgt := ir.FieldIDPairs()
+		rel := CatalogRelationship{
+			SourceTableID: tableIDs[ir.SourceTableID],
+			TargetTableID: tableIDs[ir.TargetTableID],
+			Name:          ir.Name,
+			Note:          ir.Note,
+			Cardinality:   ir.Cardinality,
+		}
+		if rel.SourceTableID == "" || rel.TargetTableID == "" || len(src) == 0 {
+			continue
+		}
+		complete := true
+		for i := range src {
+			s, t := fieldIDs[src[i]], fieldIDs[tgt[i]]
+			if s == "" || t == "" {
+				complete = false
+				break
+			}
+			rel.Fields = append(rel.Fields, CatalogRelationshipField{SourceFieldID: s, TargetFieldID: t, SortOrder: i})
+		}
+		if !complete || known[relationshipKey(rel)] {
+			continue
+		}
+		known[relationshipKey(rel)] = true
+		rel.ID = newCatalogID("crel")
+		for i := range rel.Fields {
+			rel.Fields[i].RelationshipID = rel.ID
+		}
+		plan.addedRels = append(plan.addedRels, rel)
+		plan.report.AddedRelationships = append(plan.report.AddedRelationships, label(rel))
+	}
+	return plan
+}
+
+// mergeField updates cf in place from the imported field and returns a description of
+// each change. Imported type overrides are added for dialects the catalog field does not
+// override yet. Existing ones are kept, unless the field's type changed: then an imported
+// override replaces the one for its dialect, and other dialects' overrides stay.
+func mergeField(cf *CatalogField, f schema.Field) []string {
+	var changes []string
+	oldType, _, _, _ := sqlx.NormalizeType(cf.Type)
+	newType, _, _, _ := sqlx.NormalizeType(f.Type)
+	typeChanged := oldType != newType
+	if typeChanged {
+		changes = append(changes, fmt.Sprintf("type: %s -> %s", cf.Type, f.Type))
+		cf.Type = f.Type
+	}
+	if cf.Nullable != f.Nullable {
+		changes = append(changes, fmt.Sprintf("nullable: %t -> %t", cf.Nullable, f.Nullable))
+		cf.Nullable = f.Nullable
+	}
+	if cf.PrimaryKey != f.PrimaryKey {
+		changes = append(changes, fmt.Sprintf("primaryKey: %t -> %t", cf.PrimaryKey, f.PrimaryKey))
+		cf.PrimaryKey = f.PrimaryKey
+	}
+	// Dimensions only matter for the types that use them; information_schema reports
+	// a precision for integers too, which would otherwise show up as noise.
+	switch newType {
+	case "string":
+		if !sameIntPtr(cf.Length, f.Length) {
+			changes = append(changes, fmt.Sprintf("length: %s -> %s", intPtrString(cf.Length), intPtrString(f.Length)))
+			cf.Length = f.Length
+		}
+	case "numeric":
+		if !sameIntPtr(cf.Precision, f.Precision) || !sameIntPtr(cf.Scale, f.Scale) {
+			changes = append(changes, fmt.Sprintf("precision: %s,%s -> %s,%s",
+				intPtrString(cf.Precision), intPtrString(cf.Scale), intPtrString(f.Precision), intPtrString(f.Scale)))
+			cf.Precision, cf.Scale = f.Precision, f.Scale
+		}
+	}
+	if cf.Default != f.Default {
+		changes = append(changes, fmt.Sprintf("default: %q -> %q", cf.Default, f.Default))
+		cf.Default = f.Default
+	}
+	if cf.Identity != f.Identity {
+		changes = append(changes, fmt.Sprintf("identity: %q -> %q", cf.Identity, f.Identity))
+		cf.Identity = f.Identity
+	}
+	if cf.Unique != f.Unique {
+		changes = append(changes, fmt.Sprintf("unique: %t -> %t", cf.Unique, f.Unique))
+		cf.Unique = f.Unique
+	}
+	if cf.Check != f.Check {
+		changes = append(changes, fmt.Sprintf("check: %q -> %q", cf.Check, f.Check))
+		cf.Check = f.Check
+	}
+	if cf.Repeated != f.Repeated {
+		changes = append(changes, fmt.Sprintf("repeated: %t -> %t", cf.Repeated, f.Repeated))
+		cf.Repeated = f.Repeated
+	}
+	// Sub-field IDs are regenerated on every import, so compare their definitions.
+	if oldSub, newSub := subFieldsString(cf.Fields), subFieldsString(f.Fields); oldSub != newSub {
+		changes = append(changes, fmt.Sprintf("fields: <%s> -> <%s>", oldSub, newSub))
+		cf.Fields = f.Fields
+	}
+	// Comments documented only in the catalog are kept when the source has none.
+	if f.Comment != "" && cf.Comment != f.Comment {
+		changes = append(changes, fmt.Sprintf("comment: %q -> %q", cf.Comment, f.Comment))
+		cf.Comment = f.Comment
+	}
+	have := make(map[string]int)
+	for i, o := range cf.TypeOverrides {
+		have[o.Dialect] = i
+	}
+	for _, o := range sortedOverrides(f, cf.ID) {
+		i, ok := have[o.Dialect]
+		switch {
+		case !ok:
+			cf.TypeOverrides = append(cf.TypeOverrides, o)
+			changes = append(changes, fmt.Sprintf("%s type: %s", o.Dialect, o.TypeOverride))
+		case typeChanged && cf.TypeOverrides[i].TypeOverride != o.TypeOverride:
+			changes = append(changes, fmt.Sprintf("%s type: %s -> %s", o.Dialect, cf.TypeOverrides[i].TypeOverride, o.TypeOverride))
+			cf.TypeOverrides[i].TypeOverride = o.TypeOverride
+		}
+	}
+	return changes
+}
+
+// mergeTableMetadata updates ct's schema and description from the imported table where
+// the import has them, and its kind and view definition. Owner and tags are
+// workspace-only and are kept.
+func mergeTableMetadata(ct *CatalogTable, it schema.Table) []string {
+	var changes []string
+	if it.Kind != ct.Kind {
+		changes = append(changes, fmt.Sprintf("kind: %s -> %s", tableKindLabel(ct.Kind), tableKindLabel(it.Kind)))
+		ct.Kind = it.Kind
+	}
+	if it.Definition != ct.Definition {
+		changes = append(changes, "definition")
+		ct.Definition = it.Definition
+	}
+	if it.Schema != "" && it.Schema != ct.Schema {
+		changes = append(changes, fmt.Sprintf("schema: %s -> %s", emptyDash(ct.Schema), it.Schema))
+		ct.Schema = it.Schema
+	}
+	if it.Description != "" && it.Description != ct.Description {
+		changes = append(changes, "description")
+		ct.Description = it.Description
+	}
+	return changes
+}
+
+func tableKindLabel(kind string) string {
+	if kind == "" {
+		return "table"
+	}
+	return kind
+}
+
+func emptyDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// mergeIndexes matches the imported table's indexes to ct's by name. It returns the
+// indexes to upsert and the IDs of catalog indexes that lost a column, recording both in tr.
+func mergeIndexes(ct *CatalogTable, it schema.Table, fieldIDs map[string]string, removed map[string]bool, tr *TableMergeReport) ([]CatalogIndex, []string) {
+	var upserts []CatalogIndex
+	var dropped []string
+	st := catalogTableToSchema(*ct)
+	existing := make(map[string]*CatalogIndex)
+	nextOrder := 0
+	for i := range ct.Indexes {
+		ci := &ct.Indexes[i]
+		lost := false
+		for _, c := range ci.Columns {
+			if removed[c.FieldID] {
+				lost = true
+			}
+		}
+		name := schema.ResolveIndex(st, st.Indexes[i]).Name
+		if lost {
+			dropped = append(dropped, ci.ID)
+			tr.RemovedIndexes = append(tr.RemovedIndexes, name)
+			continue
+		}
+		existing[strings.ToLower(name)] = ci
+		if ci.SortOrder >= nextOrder {
+			nextOrder = ci.SortOrder + 1
+		}
+	}
+	for _, ix := range it.Indexes {
+		name := schema.ResolveIndex(it, ix).Name
+		ci := existing[strings.ToLower(name)]
+		if ci == nil {
+			ni, ok := importedIndex(it, ix, fieldIDs, newCatalogID("cidx"), ct.ID, nextOrder)
+			if !ok {
+				continue
+			}
+			nextOrder++
+			upserts = append(upserts, ni)
+			tr.AddedIndexes = append(tr.AddedIndexes, name)
+			continue
+		}
+		ni, ok := importedIndex(it, ix, fieldIDs, ci.ID, ct.ID, ci.SortOrder)
+		if !ok {
+			continue
+		}
+		ni.Name = ci.Name
+		if !sameIndex(*ci, ni) {
+			upserts = append(upserts, ni)
+			tr.ChangedIndexes = append(tr.ChangedIndexes, name)
+		}
+	}
+	return upserts, dropped
+}
+
+// importedIndex converts an imported index into a catalog index, mapping its field IDs.
+// It reports false if a key's field is not in the catalog.
+func importedIndex(t schema.Table, ix schema.Index, fieldIDs map[string]string, id, tableID string, sortOrder int) (CatalogIndex, bool) {
+	ci := CatalogIndex{
+		ID:        id,
+		TableID:   tableID,
+		Name:      schema.ResolveIndex(t, ix).Name,
+		Unique:    ix.Unique,
+		Where:     ix.Where,
+		Method:    ix.Method,
+		SortOrder: sortOrder,
+	}
+	for i, c := range ix.Columns {
+		col := CatalogIndexColumn{IndexID: id, Expression: c.Expression, Desc: c.Desc, SortOrder: i}
+		if c.Expression == "" {
+			col.FieldID = fieldIDs[c.FieldID]
+			if col.FieldID == "" {
+				return CatalogIndex{}, false
+			}
+		}
+		ci.Columns = append(ci.Columns, col)
+	}
+	return ci, len(ci.Columns) > 0
+}
+
+func sameIndex(a, b CatalogIndex) bool {
+	if a.Unique != b.Unique || a.Where != b.Where || !strings.EqualFold(a.Method, b.Method) || len(a.Columns) != len(b.Columns) {
+		return false
+	}
+	for i := range a.Columns {
+		x, y := a.Columns[i], b.Columns[i]
+		if x.FieldID != y.FieldID || x.Expression != y.Expression || x.Desc != y.Desc {
+			return false
+		}
+	}
+	return true
+}
+
+// importedField converts an imported field into a new catalog field.
+func importedField(f schema.Field, id, tableID string, sortOrder int) CatalogField {
+	return CatalogField{
+		ID:            id,
+		TableID:       tableID,
+		Name:          f.Name,
+		Type:          f.Type,
+		Nullable:      f.Nullable,
+		PrimaryKey:    f.PrimaryKey,
+		Length:        f.Length,
+		Precision:     f.Precision,
+		Scale:         f.Scale,
+		SortOrder:     sortOrder,
+		TypeOverrides: sortedOverrides(f, id),
+		Default:       f.Default,
+		Identity:      f.Identity,
+		Unique:        f.Unique,
+		Check:         f.Check,
+		Comment:       f.Comment,
+		Repeated:      f.Repeated,
+		Fields:        f.Fields,
+	}
+}
+
+// subFieldsString renders struct sub-fields as "name type[, ...]", nested structs in
+// angle brackets, with "[]" after repeated types and " not null" after required ones.
+func subFieldsString(fields []schema.Field) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		typ := f.Type
+		if len(f.Fields) > 0 {
+			typ += "<" + subFieldsString(f.Fields) + ">"
+		}
+		if f.Repeated {
+			typ += "[]"
+		}
+		parts[i] = f.Name + " " + typ
+		if !f.Nullable {
+			parts[i] += " not null"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedOverrides(f schema.Field, fieldID string) []CatalogFieldTypeOverride {
+	var out []CatalogFieldTypeOverride
+	for dialect, o := range f.TypeOverrides {
+		if o.Type != "" {
+			out = append(out, CatalogFieldTypeOverride{FieldID: fieldID, Dialect: dialect, TypeOverride: o.Type})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Dialect < out[j].Dialect })
+	return out
+}
+
+// relationshipKey identifies a relationship by its tables and ordered field pairs.
+func relationshipKey(rel CatalogRelationship) string {
+	var b strings.Builder
+	b.WriteString(rel.SourceTableID + ">" + rel.TargetTableID)
+	for _, f := range rel.Fields {
+		b.WriteString("|" + f.SourceFieldID + ":" + f.TargetFieldID)
+	}
+	return b.String()
+}
+
+func newCatalogID(prefix string) string {
+	return prefix + "-" + uuid.New().String()
+}
+
+func sameIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func intPtrString(p *int) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprint(*p)
+}
@@ -0,0 +1,262 @@
+package workspace
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+// newTestRepo creates an empty workspace in a temporary directory.
+func newTestRepo(t *testing.T) *WorkspaceRepo {
+	t.Helper()
+	db, err := OpenDB(filepath.Join(t.TempDir(), "test.schemastudio"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitSchema(db); err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	repo := NewRepo(db, "")
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// mergeFixture is a first import: customers, orders with a key to customers and one to
+// legacy, and legacy. IDs are numbered like an importer's.
+func mergeFixture() schema.TableCatalog {
+	return schema.TableCatalog{
+		Tables: []schema.Table{
+			{ID: "t1", Name: "customers", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "name", Type: "string", Nullable: true},
+			}},
+			{ID: "t2", Name: "orders", Fields: []schema.Field{
+				{ID: "f3", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f4", Name: "customer_id", Type: "integer"},
+				{ID: "f5", Name: "legacy_id", Type: "integer", Nullable: true},
+			}},
+			{ID: "t3", Name: "legacy", Fields: []schema.Field{
+				{ID: "f6", Name: "id", Type: "integer", PrimaryKey: true},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t1", SourceFieldID: "f1", TargetTableID: "t2", TargetFieldID: "f4"},
+			{ID: "r2", SourceTableID: "t3", SourceFieldID: "f6", TargetTableID: "t2", TargetFieldID: "f5"},
+		},
+	}
+}
+
+// catalogByName returns the catalog tables keyed by name and their fields keyed by
+// "table.field".
+func catalogByName(t *testing.T, repo *WorkspaceRepo) (map[string]CatalogTable, map[string]CatalogField) {
+	t.Helper()
+	tables, err := repo.ListCatalogTables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]CatalogTable)
+	fields := make(map[string]CatalogField)
+	for _, ct := range tables {
+		byName[ct.Name] = ct
+		for _, f := range ct.Fields {
+			fields[ct.Name+"."+f.Name] = f
+		}
+	}
+	return byName, fields
+}
+
+func TestMergeImportedCatalog(t *testing.T) {
+	repo := newTestRepo(t)
+	report, err := repo.MergeImportedCatalog(mergeFixture(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.AddedTables, []string{"customers", "orders", "legacy"}) || len(report.AddedRelationships) != 2 {
+		t.Fatalf("first import report = %+v", report)
+	}
+	tables, fields := catalogByName(t, repo)
+
+	// Workspace-only metadata the next import must keep.
+	customers := tables["customers"]
+	customers.Owner, customers.Tags = "sales", []string{"core"}
+	tx, err := repo.db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := upsertTableRowTx(tx, customers); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	name := fields["customers.name"]
+	if err := repo.SetTypeOverride(name.ID, "postgres", "citext"); err != nil {
+		t.Fatal(err)
+	}
+	rels, err := repo.ListCatalogRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var customerRel CatalogRelationship
+	for _, rel := range rels {
+		if rel.SourceTableID == customers.ID {
+			customerRel = rel
+		}
+	}
+	customerRel.Note, customerRel.Cardinality = "every order has a customer", "1-to-0/many"
+	if err := repo.SaveCatalogRelationship(customerRel); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second, independent import: new IDs that collide with the first import's,
+	// differently cased names, a changed, an added and a removed column, legacy gone
+	// and invoices new.
+	second := schema.TableCatalog{
+		Tables: []schema.Table{
+			{ID: "t1", Name: "invoices", Fields: []schema.Field{
+				{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f2", Name: "order_id", Type: "integer"},
+			}},
+			{ID: "t2", Name: "Customers", Fields: []schema.Field{
+				{ID: "f3", Name: "ID", Type: "integer", PrimaryKey: true},
+				{ID: "f4", Name: "name", Type: "string", Length: intPtr(100)},
+				{ID: "f5", Name: "email", Type: "string", Nullable: true},
+			}},
+			{ID: "t3", Name: "orders", Fields: []schema.Field{
+				{ID: "f6", Name: "id", Type: "integer", PrimaryKey: true},
+				{ID: "f7", Name: "customer_id", Type: "integer"},
+			}},
+		},
+		Relationships: []schema.Relationship{
+			{ID: "r1", SourceTableID: "t2", SourceFieldID: "f3", TargetTableID: "t3", TargetFieldID: "f7"},
+			{ID: "r2", SourceTableID: "t3", SourceFieldID: "f6", TargetTableID: "t1", TargetFieldID: "f2"},
+		},
+	}
+
+	dry, err := repo.MergeImportedCatalog(second, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := catalogByName(t, repo); len(after) != 3 || after["invoices"].ID != "" {
+		t.Errorf("dry run wrote to the catalog: %+v", after)
+	}
+	report, err = repo.MergeImportedCatalog(second, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dry.DryRun = false
+	if !reflect.DeepEqual(dry, report) {
+		t.Errorf("dry run report %+v differs from merge report %+v", dry, report)
+	}
+
+	if !reflect.DeepEqual(report.AddedTables, []string{"invoices"}) || !reflect.DeepEqual(report.RemovedTables, []string{"legacy"}) ||
+		len(report.UnchangedTables) != 0 {
+		t.Errorf("tables: added %v, removed %v, unchanged %v", report.AddedTables, report.RemovedTables, report.UnchangedTables)
+	}
+	if len(report.UpdatedTables) != 2 {
+		t.Fatalf("UpdatedTables = %+v", report.UpdatedTables)
+	}
+	if tr := report.UpdatedTables[0]; tr.Table != "customers" || !reflect.DeepEqual(tr.AddedColumns, []string{"email"}) ||
+		len(tr.ChangedColumns) != 1 || tr.ChangedColumns[0].Column != "name" ||
+		!reflect.DeepEqual(tr.ChangedColumns[0].Changes, []string{"nullable: true -> false", "length: - -> 100"}) {
+		t.Errorf("customers report = %+v", tr)
+	}
+	if tr := report.UpdatedTables[1]; tr.Table != "orders" || !reflect.DeepEqual(tr.RemovedColumns, []string{"legacy_id"}) {
+		t.Errorf("orders report = %+v", tr)
+	}
+	if !reflect.DeepEqual(report.RemovedRelationships, []string{"orders(legacy_id) -> legacy(id)"}) ||
+		!reflect.DeepEqual(report.AddedRelationships, []string{"invoices(order_id) -> orders(id)"}) {
+		t.Errorf("relationships: removed %v, added %v", report.RemovedRelationships, report.AddedRelationships)
+	}
+
+	// Fields are matched by name, so catalog IDs survive; metadata is preserved.
+	tables, after := catalogByName(t, repo)
+	for _, key := range []string{"customers.id", "customers.name", "orders.id", "orders.customer_id"} {
+		if after[key].ID != fields[key].ID {
+			t.Errorf("%s: ID %q, want %q", key, after[key].ID, fields[key].ID)
+		}
+	}
+	if _, ok := after["orders.legacy_id"]; ok {
+		t.Error("orders.legacy_id was not removed")
+	}
+	if _, ok := tables["legacy"]; !ok {
+		t.Error("legacy was deleted; tables missing from an import are kept")
+	}
+	if c := tables["customers"]; c.Name != "customers" || c.Owner != "sales" || !reflect.DeepEqual(c.Tags, []string{"core"}) {
+		t.Errorf("customers = %+v, want name, owner and tags kept", c)
+	}
+	if f := after["customers.name"]; f.Nullable || f.Length == nil || *f.Length != 100 ||
+		len(f.TypeOverrides) != 1 || f.TypeOverrides[0].TypeOverride != "citext" {
+		t.Errorf("customers.name = %+v", f)
+	}
+
+	// Relationships are remapped from import IDs to catalog IDs.
+	rels, err = repo.ListCatalogRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 2 {
+		t.Fatalf("relationships = %+v", rels)
+	}
+	for _, rel := range rels {
+		switch rel.ID {
+		case customerRel.ID:
+			if rel.Note != customerRel.Note || rel.Cardinality != customerRel.Cardinality {
+				t.Errorf("customer relationship lost its metadata: %+v", rel)
+			}
+		default:
+			want := CatalogRelationshipField{RelationshipID: rel.ID, SourceFieldID: after["orders.id"].ID, TargetFieldID: after["invoices.order_id"].ID}
+			if rel.SourceTableID != tables["orders"].ID || rel.TargetTableID != tables["invoices"].ID ||
+				len(rel.Fields) != 1 || rel.Fields[0] != want {
+				t.Errorf("invoice relationship = %+v, want fields %+v", rel, want)
+			}
+		}
+	}
+}
+
+func TestMergeField_TypeOverrides(t *testing.T) {
+	overrides := func(types map[string]string) map[string]schema.FieldTypeOverride {
+		m := make(map[string]schema.FieldTypeOverride)
+		for d, typ := range types {
+			m[d] = schema.FieldTypeOverride{Type: typ}
+		}
+		return m
+	}
+	catalogField := func() CatalogField {
+		return CatalogField{ID: "c1", Name: "code", Type: "string", TypeOverrides: []CatalogFieldTypeOverride{
+			{FieldID: "c1", Dialect: "mysql", TypeOverride: "varchar(20)"},
+			{FieldID: "c1", Dialect: "postgres", TypeOverride: "citext"},
+		}}
+	}
+	got := func(cf CatalogField) map[string]string {
+		m := make(map[string]string)
+		for _, o := range cf.TypeOverrides {
+			m[o.Dialect] = o.TypeOverride
+		}
+		return m
+	}
+
+	// The same type: existing overrides win, new dialects are added.
+	cf := catalogField()
+	mergeField(&cf, schema.Field{Name: "code", Type: "string",
+		TypeOverrides: overrides(map[string]string{"postgres": "text", "mssql": "nvarchar(20)"})})
+	if want := map[string]string{"mysql": "varchar(20)", "postgres": "citext", "mssql": "nvarchar(20)"}; !reflect.DeepEqual(got(cf), want) {
+		t.Errorf("same type overrides = %v, want %v", got(cf), want)
+	}
+
+	// A changed type: the imported override replaces the same dialect's, others stay.
+	cf = catalogField()
+	changes := mergeField(&cf, schema.Field{Name: "code", Type: "integer",
+		TypeOverrides: overrides(map[string]string{"postgres": "int4"})})
+	if want := map[string]string{"mysql": "varchar(20)", "postgres": "int4"}; !reflect.DeepEqual(got(cf), want) {
+		t.Errorf("changed type overrides = %v, want %v", got(cf), want)
+	}
+	if want := []string{"type: string -> integer", "postgres type: citext -> int4"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %q, want %q", changes, want)
+	}
+}
+
+func intPtr(v int) *int { return &v }
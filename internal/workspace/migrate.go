@@ -132,7 +132,7 @@ var validGenericTypes = map[string]bool{
 	"string": true, "integer": true, "float": true, "numeric": true,
 	"boolean": true, "date": true, "time": true, "timestamp": true,
 	"timestamptz": true, "uuid": true, "json": true, "bytes": true,
-	"other": true,
+	"struct": true, "other": true,
 }
 
 // Valid dialect names.
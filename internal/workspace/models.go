@@ -1,18 +1,28 @@
 package workspace
 
+import "schemastudio/internal/schema"
+
 // WorkspaceSettings holds workspace-level configuration as key-value pairs.
 type WorkspaceSettings struct {
 	Name          string `json:"name"`
 	Description   string `json:"description,omitempty"`
 	NotationStyle string `json:"notationStyle,omitempty"`
+	AutoSnapshot  bool   `json:"autoSnapshot,omitempty"`
 }
 
 // CatalogTable is a table in the workspace table catalog.
 type CatalogTable struct {
-	ID        string         `json:"id"`
-	Name      string         `json:"name"`
-	SortOrder int            `json:"sortOrder"`
-	Fields    []CatalogField `json:"fields"`
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Schema      string         `json:"schema,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Owner       string         `json:"owner,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Kind        string         `json:"kind,omitempty"`       // "" for a base table, "view" or "materialized_view".
+	Definition  string         `json:"definition,omitempty"` // View query; views only.
+	SortOrder   int            `json:"sortOrder"`
+	Fields      []CatalogField `json:"fields"`
+	Indexes     []CatalogIndex `json:"indexes,omitempty"`
 }
 
 // CatalogField is a column definition within a catalog table.
@@ -28,6 +38,13 @@ type CatalogField struct {
 	Scale         *int                      `json:"scale,omitempty"`
 	SortOrder     int                       `json:"sortOrder"`
 	TypeOverrides []CatalogFieldTypeOverride `json:"typeOverrides,omitempty"`
+	Default       string                    `json:"default,omitempty"`
+	Identity      string                    `json:"identity,omitempty"` // schema.IdentityAlways or schema.IdentityByDefault
+	Unique        bool                      `json:"unique,omitempty"`
+	Check         string                    `json:"check,omitempty"`
+	Comment       string                    `json:"comment,omitempty"`
+	Repeated      bool                      `json:"repeated,omitempty"`
+	Fields        []schema.Field            `json:"fields,omitempty"` // Sub-fields of a struct field.
 }
 
 // CatalogFieldTypeOverride holds a per-dialect type override for a field.
@@ -37,6 +54,27 @@ type CatalogFieldTypeOverride struct {
 	TypeOverride string `json:"typeOverride"`
 }
 
+// CatalogIndex is a secondary index on a catalog table.
+type CatalogIndex struct {
+	ID        string               `json:"id"`
+	TableID   string               `json:"tableId"`
+	Name      string               `json:"name"`
+	Unique    bool                 `json:"unique,omitempty"`
+	Where     string               `json:"where,omitempty"`  // partial index predicate
+	Method    string               `json:"method,omitempty"` // e.g. btree, gin, hash
+	SortOrder int                  `json:"sortOrder"`
+	Columns   []CatalogIndexColumn `json:"columns"`
+}
+
+// CatalogIndexColumn is one key of an index: a field, or an SQL expression.
+type CatalogIndexColumn struct {
+	IndexID    string `json:"indexId"`
+	FieldID    string `json:"fieldId,omitempty"`
+	Expression string `json:"expression,omitempty"`
+	Desc       bool   `json:"desc,omitempty"`
+	SortOrder  int    `json:"sortOrder"`
+}
+
 // CatalogRelationship represents a foreign-key relationship between catalog tables.
 type CatalogRelationship struct {
 	ID            string                       `json:"id"`
@@ -127,6 +165,22 @@ type ConnectionProfile struct {
 	BigQueryAuthMode string `json:"bigqueryAuthMode,omitempty"`
 }
 
+// Snapshot is a named, saved copy of the workspace catalog and diagrams.
+type Snapshot struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Auto      bool   `json:"auto,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// SnapshotContent is a snapshot together with the catalog and diagrams it captured.
+type SnapshotContent struct {
+	Snapshot
+	Tables        []CatalogTable        `json:"tables"`
+	Relationships []CatalogRelationship `json:"relationships"`
+	Diagrams      []Diagram             `json:"diagrams"`
+}
+
 // UIState holds persisted UI state as key-value pairs.
 type UIState map[string]string
 
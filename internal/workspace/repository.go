@@ -2,7 +2,10 @@ package workspace
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+
+	"schemastudio/internal/schema"
 )
 
 // WorkspaceRepo provides CRUD operations against a workspace SQLite database.
@@ -66,6 +69,8 @@ func (r *WorkspaceRepo) GetAllSettings() (WorkspaceSettings, error) {
 			s.Description = v
 		case "notation_style":
 			s.NotationStyle = v
+		case "auto_snapshot":
+			s.AutoSnapshot = v == "true"
 		}
 	}
 	return s, rows.Err()
@@ -89,6 +94,9 @@ func (r *WorkspaceRepo) SaveAllSettings(s WorkspaceSettings) error {
 	if _, err := tx.Exec(upsert, "notation_style", s.NotationStyle); err != nil {
 		return err
 	}
+	if _, err := tx.Exec(upsert, "auto_snapshot", fmt.Sprint(s.AutoSnapshot)); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
@@ -98,7 +106,10 @@ func (r *WorkspaceRepo) SaveAllSettings(s WorkspaceSettings) error {
 
 // ListCatalogTables returns all catalog tables with their fields and type overrides.
 func (r *WorkspaceRepo) ListCatalogTables() ([]CatalogTable, error) {
-	rows, err := r.db.Query("SELECT id, name, sort_order FROM catalog_tables ORDER BY sort_order, name")
+	rows, err := r.db.Query(
+		`SELECT id, name, schema_name, description, owner, tags, kind, definition, sort_order
+		 FROM catalog_tables ORDER BY sort_order, name`,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -107,22 +118,29 @@ func (r *WorkspaceRepo) ListCatalogTables() ([]CatalogTable, error) {
 	var tables []CatalogTable
 	for rows.Next() {
 		var t CatalogTable
-		if err := rows.Scan(&t.ID, &t.Name, &t.SortOrder); err != nil {
+		var tags string
+		if err := rows.Scan(&t.ID, &t.Name, &t.Schema, &t.Description, &t.Owner, &tags, &t.Kind, &t.Definition, &t.SortOrder); err != nil {
 			return nil, err
 		}
+		t.Tags = decodeTags(tags)
 		tables = append(tables, t)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	// Load fields for each table.
+	// Load fields and indexes for each table.
 	for i := range tables {
 		fields, err := r.GetFieldsForTable(tables[i].ID)
 		if err != nil {
 			return nil, err
 		}
 		tables[i].Fields = fields
+		indexes, err := r.GetIndexesForTable(tables[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		tables[i].Indexes = indexes
 	}
 	return tables, nil
 }
@@ -130,8 +148,11 @@ func (r *WorkspaceRepo) ListCatalogTables() ([]CatalogTable, error) {
 // GetCatalogTable returns a single catalog table with its fields and type overrides.
 func (r *WorkspaceRepo) GetCatalogTable(id string) (*CatalogTable, error) {
 	var t CatalogTable
-	err := r.db.QueryRow("SELECT id, name, sort_order FROM catalog_tables WHERE id = ?", id).
-		Scan(&t.ID, &t.Name, &t.SortOrder)
+	var tags string
+	err := r.db.QueryRow(
+		"SELECT id, name, schema_name, description, owner, tags, kind, definition, sort_order FROM catalog_tables WHERE id = ?", id,
+	).Scan(&t.ID, &t.Name, &t.Schema, &t.Description, &t.Owner, &tags, &t.Kind, &t.Definition, &t.SortOrder)
+	t.Tags = decodeTags(tags)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -143,11 +164,16 @@ func (r *WorkspaceRepo) GetCatalogTable(id string) (*CatalogTable, error) {
 		return nil, err
 	}
 	t.Fields = fields
+	indexes, err := r.GetIndexesForTable(t.ID)
+	if err != nil {
+		return nil, err
+	}
+	t.Indexes = indexes
 	return &t, nil
 }
 
-// SaveCatalogTable upserts a catalog table and all its fields and type overrides.
-// This replaces all fields for the table (delete + re-insert).
+// SaveCatalogTable upserts a catalog table and all its fields, type overrides and indexes.
+// This replaces all fields and indexes for the table (delete + re-insert).
 func (r *WorkspaceRepo) SaveCatalogTable(t CatalogTable) error {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -155,15 +181,15 @@ func (r *WorkspaceRepo) SaveCatalogTable(t CatalogTable) error {
 	}
 	defer tx.Rollback()
 
-	// Upsert table row.
-	_, err = tx.Exec(
-		`INSERT INTO catalog_tables (id, name, sort_order, updated_at)
-		 VALUES (?, ?, ?, datetime('now'))
-		 ON CONFLICT(id) DO UPDATE SET name=excluded.name, sort_order=excluded.sort_order, updated_at=datetime('now')`,
-		t.ID, t.Name, t.SortOrder,
-	)
-	if err != nil {
-		return fmt.Errorf("upsert catalog_tables: %w", err)
+	if err := saveCatalogTableTx(tx, t); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveCatalogTableTx(tx *sql.Tx, t CatalogTable) error {
+	if err := upsertTableRowTx(tx, t); err != nil {
+		return err
 	}
 
 	// Delete existing fields (cascade deletes type overrides too).
@@ -174,11 +200,14 @@ func (r *WorkspaceRepo) SaveCatalogTable(t CatalogTable) error {
 	// Insert fields.
 	for _, f := range t.Fields {
 		_, err := tx.Exec(
-			`INSERT INTO catalog_fields (id, table_id, name, type, nullable, primary_key, length, precision, scale, sort_order)
-			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			`INSERT INTO catalog_fields (id, table_id, name, type, nullable, primary_key, length, precision, scale, sort_order,
+			   default_value, identity, is_unique, check_expr, comment, repeated, sub_fields)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			f.ID, t.ID, f.Name, f.Type,
 			boolToInt(f.Nullable), boolToInt(f.PrimaryKey),
 			f.Length, f.Precision, f.Scale, f.SortOrder,
+			f.Default, f.Identity, boolToInt(f.Unique), f.Check, f.Comment,
+			boolToInt(f.Repeated), encodeSubFields(f.Fields),
 		)
 		if err != nil {
 			return fmt.Errorf("insert field %s: %w", f.ID, err)
@@ -194,7 +223,35 @@ func (r *WorkspaceRepo) SaveCatalogTable(t CatalogTable) error {
 			}
 		}
 	}
-	return tx.Commit()
+
+	// Replace indexes; their field keys went with the old fields.
+	if _, err := tx.Exec("DELETE FROM catalog_indexes WHERE table_id = ?", t.ID); err != nil {
+		return fmt.Errorf("delete old indexes: %w", err)
+	}
+	for _, ix := range t.Indexes {
+		ix.TableID = t.ID
+		if err := saveIndexTx(tx, ix); err != nil {
+			return fmt.Errorf("insert index %s: %w", ix.ID, err)
+		}
+	}
+	return nil
+}
+
+// upsertTableRowTx upserts the catalog_tables row only; fields are left untouched.
+func upsertTableRowTx(tx *sql.Tx, t CatalogTable) error {
+	_, err := tx.Exec(
+		`INSERT INTO catalog_tables (id, name, schema_name, description, owner, tags, kind, definition, sort_order, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		 ON CONFLICT(id) DO UPDATE SET
+		   name=excluded.name, schema_name=excluded.schema_name, description=excluded.description,
+		   owner=excluded.owner, tags=excluded.tags, kind=excluded.kind, definition=excluded.definition,
+		   sort_order=excluded.sort_order, updated_at=datetime('now')`,
+		t.ID, t.Name, t.Schema, t.Description, t.Owner, encodeTags(t.Tags), t.Kind, t.Definition, t.SortOrder,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert catalog_tables: %w", err)
+	}
+	return nil
 }
 
 // DeleteCatalogTable removes a catalog table and all its fields (via CASCADE).
@@ -210,7 +267,8 @@ func (r *WorkspaceRepo) DeleteCatalogTable(id string) error {
 // GetFieldsForTable returns all fields for a given table, with their type overrides.
 func (r *WorkspaceRepo) GetFieldsForTable(tableID string) ([]CatalogField, error) {
 	rows, err := r.db.Query(
-		`SELECT id, table_id, name, type, nullable, primary_key, length, precision, scale, sort_order
+		`SELECT id, table_id, name, type, nullable, primary_key, length, precision, scale, sort_order,
+		   default_value, identity, is_unique, check_expr, comment, repeated, sub_fields
 		 FROM catalog_fields WHERE table_id = ? ORDER BY sort_order`,
 		tableID,
 	)
@@ -222,13 +280,18 @@ func (r *WorkspaceRepo) GetFieldsForTable(tableID string) ([]CatalogField, error
 	var fields []CatalogField
 	for rows.Next() {
 		var f CatalogField
-		var nullable, pk int
+		var nullable, pk, unique, repeated int
+		var subFields string
 		if err := rows.Scan(&f.ID, &f.TableID, &f.Name, &f.Type, &nullable, &pk,
-			&f.Length, &f.Precision, &f.Scale, &f.SortOrder); err != nil {
+			&f.Length, &f.Precision, &f.Scale, &f.SortOrder,
+			&f.Default, &f.Identity, &unique, &f.Check, &f.Comment, &repeated, &subFields); err != nil {
 			return nil, err
 		}
 		f.Nullable = nullable != 0
 		f.PrimaryKey = pk != 0
+		f.Unique = unique != 0
+		f.Repeated = repeated != 0
+		f.Fields = decodeSubFields(subFields)
 		fields = append(fields, f)
 	}
 	if err := rows.Err(); err != nil {
@@ -254,16 +317,31 @@ func (r *WorkspaceRepo) SaveField(f CatalogField) error {
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec(
-		`INSERT INTO catalog_fields (id, table_id, name, type, nullable, primary_key, length, precision, scale, sort_order)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	if err := saveFieldTx(tx, f); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// saveFieldTx upserts a field in place (keeping its ID, so relationship field
+// mappings survive) and replaces its type overrides.
+func saveFieldTx(tx *sql.Tx, f CatalogField) error {
+	_, err := tx.Exec(
+		`INSERT INTO catalog_fields (id, table_id, name, type, nullable, primary_key, length, precision, scale, sort_order,
+		   default_value, identity, is_unique, check_expr, comment, repeated, sub_fields)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(id) DO UPDATE SET
 		   name=excluded.name, type=excluded.type, nullable=excluded.nullable,
 		   primary_key=excluded.primary_key, length=excluded.length,
-		   precision=excluded.precision, scale=excluded.scale, sort_order=excluded.sort_order`,
+		   precision=excluded.precision, scale=excluded.scale, sort_order=excluded.sort_order,
+		   default_value=excluded.default_value, identity=excluded.identity, is_unique=excluded.is_unique,
+		   check_expr=excluded.check_expr, comment=excluded.comment,
+		   repeated=excluded.repeated, sub_fields=excluded.sub_fields`,
 		f.ID, f.TableID, f.Name, f.Type,
 		boolToInt(f.Nullable), boolToInt(f.PrimaryKey),
 		f.Length, f.Precision, f.Scale, f.SortOrder,
+		f.Default, f.Identity, boolToInt(f.Unique), f.Check, f.Comment,
+		boolToInt(f.Repeated), encodeSubFields(f.Fields),
 	)
 	if err != nil {
 		return err
@@ -281,7 +359,7 @@ func (r *WorkspaceRepo) SaveField(f CatalogField) error {
 			return err
 		}
 	}
-	return tx.Commit()
+	return nil
 }
 
 // DeleteField removes a catalog field by ID.
@@ -290,6 +368,119 @@ func (r *WorkspaceRepo) DeleteField(id string) error {
 	return err
 }
 
+// ---------------------------------------------------------------------------
+// Catalog Indexes
+// ---------------------------------------------------------------------------
+
+// GetIndexesForTable returns all indexes of a table with their keys.
+func (r *WorkspaceRepo) GetIndexesForTable(tableID string) ([]CatalogIndex, error) {
+	rows, err := r.db.Query(
+		`SELECT id, table_id, name, is_unique, where_clause, method, sort_order
+		 FROM catalog_indexes WHERE table_id = ? ORDER BY sort_order, name`,
+		tableID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []CatalogIndex
+	for rows.Next() {
+		var ix CatalogIndex
+		var unique int
+		if err := rows.Scan(&ix.ID, &ix.TableID, &ix.Name, &unique, &ix.Where, &ix.Method, &ix.SortOrder); err != nil {
+			return nil, err
+		}
+		ix.Unique = unique != 0
+		indexes = append(indexes, ix)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range indexes {
+		cols, err := r.getIndexColumns(indexes[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		indexes[i].Columns = cols
+	}
+	return indexes, nil
+}
+
+// SaveIndex upserts a single catalog index and replaces its keys.
+func (r *WorkspaceRepo) SaveIndex(ix CatalogIndex) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveIndexTx(tx, ix); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveIndexTx(tx *sql.Tx, ix CatalogIndex) error {
+	_, err := tx.Exec(
+		`INSERT INTO catalog_indexes (id, table_id, name, is_unique, where_clause, method, sort_order)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET
+		   table_id=excluded.table_id, name=excluded.name, is_unique=excluded.is_unique,
+		   where_clause=excluded.where_clause, method=excluded.method, sort_order=excluded.sort_order`,
+		ix.ID, ix.TableID, ix.Name, boolToInt(ix.Unique), ix.Where, ix.Method, ix.SortOrder,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Replace keys.
+	if _, err := tx.Exec("DELETE FROM catalog_index_columns WHERE index_id = ?", ix.ID); err != nil {
+		return err
+	}
+	for i, c := range ix.Columns {
+		if _, err := tx.Exec(
+			"INSERT INTO catalog_index_columns (index_id, field_id, expression, is_desc, sort_order) VALUES (?, ?, ?, ?, ?)",
+			ix.ID, nullIfEmpty(c.FieldID), c.Expression, boolToInt(c.Desc), i,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteIndex removes a catalog index by ID.
+func (r *WorkspaceRepo) DeleteIndex(id string) error {
+	_, err := r.db.Exec("DELETE FROM catalog_indexes WHERE id = ?", id)
+	return err
+}
+
+func (r *WorkspaceRepo) getIndexColumns(indexID string) ([]CatalogIndexColumn, error) {
+	rows, err := r.db.Query(
+		"SELECT index_id, field_id, expression, is_desc, sort_order FROM catalog_index_columns WHERE index_id = ? ORDER BY sort_order",
+		indexID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []CatalogIndexColumn
+	for rows.Next() {
+		var c CatalogIndexColumn
+		var fieldID sql.NullString
+		var desc int
+		if err := rows.Scan(&c.IndexID, &fieldID, &c.Expression, &desc, &c.SortOrder); err != nil {
+			return nil, err
+		}
+		c.FieldID = fieldID.String
+		c.Desc = desc != 0
+		cols = append(cols, c)
+	}
+	return cols, rows.Err()
+}
+
 // ---------------------------------------------------------------------------
 // Type Overrides
 // ---------------------------------------------------------------------------
@@ -374,7 +565,14 @@ func (r *WorkspaceRepo) SaveCatalogRelationship(rel CatalogRelationship) error {
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec(
+	if err := saveRelationshipTx(tx, rel); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveRelationshipTx(tx *sql.Tx, rel CatalogRelationship) error {
+	_, err := tx.Exec(
 		`INSERT INTO catalog_relationships (id, source_table_id, target_table_id, name, note, cardinality)
 		 VALUES (?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(id) DO UPDATE SET
@@ -400,7 +598,7 @@ func (r *WorkspaceRepo) SaveCatalogRelationship(rel CatalogRelationship) error {
 			return err
 		}
 	}
-	return tx.Commit()
+	return nil
 }
 
 // DeleteCatalogRelationship removes a catalog relationship by ID.
@@ -493,8 +691,15 @@ func (r *WorkspaceRepo) SaveDiagram(d Diagram) error {
 	}
 	defer tx.Rollback()
 
+	if err := saveDiagramTx(tx, d); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveDiagramTx(tx *sql.Tx, d Diagram) error {
 	// Upsert diagram header.
-	_, err = tx.Exec(
+	_, err := tx.Exec(
 		`INSERT INTO diagrams (id, name, version, viewport_zoom, viewport_pan_x, viewport_pan_y, updated_at)
 		 VALUES (?, ?, ?, ?, ?, ?, datetime('now'))
 		 ON CONFLICT(id) DO UPDATE SET
@@ -564,7 +769,7 @@ func (r *WorkspaceRepo) SaveDiagram(d Diagram) error {
 		}
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // DeleteDiagram removes a diagram and all its child elements (via CASCADE).
@@ -785,6 +990,42 @@ func boolToInt(b bool) int {
 	return 0
 }
 
+// encodeTags stores tags as a JSON array.
+func encodeTags(tags []string) string {
+	if len(tags) == 0 {
+		return "[]"
+	}
+	data, _ := json.Marshal(tags)
+	return string(data)
+}
+
+// decodeTags reads a JSON tag array; malformed values yield no tags.
+func decodeTags(s string) []string {
+	var tags []string
+	if err := json.Unmarshal([]byte(s), &tags); err != nil || len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// encodeSubFields stores a struct field's sub-fields as a JSON array.
+func encodeSubFields(fields []schema.Field) string {
+	if len(fields) == 0 {
+		return "[]"
+	}
+	data, _ := json.Marshal(fields)
+	return string(data)
+}
+
+// decodeSubFields reads a JSON sub-field array; malformed values yield no sub-fields.
+func decodeSubFields(s string) []schema.Field {
+	var fields []schema.Field
+	if err := json.Unmarshal([]byte(s), &fields); err != nil || len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
 func nullIfEmpty(s string) interface{} {
 	if s == "" {
 		return nil
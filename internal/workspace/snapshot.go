@@ -0,0 +1,338 @@
+package workspace
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"schemastudio/internal/schema"
+)
+
+const (
+	// autoSnapshotInterval is the minimum time between automatic snapshots (SQLite modifier).
+	autoSnapshotInterval = "-10 minutes"
+	// maxAutoSnapshots is how many automatic snapshots are kept; older ones are pruned.
+	maxAutoSnapshots = 20
+)
+
+// snapshotData is the JSON document stored in snapshots.data.
+type snapshotData struct {
+	Tables        []CatalogTable        `json:"tables"`
+	Relationships []CatalogRelationship `json:"relationships"`
+	Diagrams      []Diagram             `json:"diagrams"`
+}
+
+// CreateSnapshot saves the current catalog and diagrams under the given name.
+func (r *WorkspaceRepo) CreateSnapshot(name string) (Snapshot, error) {
+	return r.createSnapshot(name, false)
+}
+
+// AutoSnapshot takes an automatic snapshot if the workspace has auto_snapshot enabled,
+// the last automatic snapshot is older than autoSnapshotInterval and the workspace
+// changed since the latest snapshot. Returns whether a snapshot was taken.
+func (r *WorkspaceRepo) AutoSnapshot() (bool, error) {
+	enabled, err := r.GetSetting("auto_snapshot")
+	if err != nil || enabled != "true" {
+		return false, err
+	}
+	var recent int
+	if err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM snapshots WHERE auto = 1 AND created_at > datetime('now', ?)", autoSnapshotInterval,
+	).Scan(&recent); err != nil {
+		return false, err
+	}
+	if recent > 0 {
+		return false, nil
+	}
+	data, err := r.snapshotData()
+	if err != nil {
+		return false, err
+	}
+	var latest string
+	err = r.db.QueryRow("SELECT data FROM snapshots ORDER BY created_at DESC, rowid DESC LIMIT 1").Scan(&latest)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	if latest == string(data) {
+		return false, nil
+	}
+	if _, err := r.insertSnapshot("Auto-save", true, data); err != nil {
+		return false, err
+	}
+	_, err = r.db.Exec(
+		`DELETE FROM snapshots WHERE auto = 1 AND id NOT IN (
+		   SELECT id FROM snapshots WHERE auto = 1 ORDER BY created_at DESC, rowid DESC LIMIT ?)`,
+		maxAutoSnapshots,
+	)
+	return true, err
+}
+
+// ListSnapshots returns all snapshots, newest first (without their content).
+func (r *WorkspaceRepo) ListSnapshots() ([]Snapshot, error) {
+	rows, err := r.db.Query("SELECT id, name, auto, created_at FROM snapshots ORDER BY created_at DESC, rowid DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snaps []Snapshot
+	for rows.Next() {
+		var s Snapshot
+		var auto int
+		if err := rows.Scan(&s.ID, &s.Name, &auto, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		s.Auto = auto != 0
+		snaps = append(snaps, s)
+	}
+	return snaps, rows.Err()
+}
+
+// GetSnapshot returns a snapshot with its catalog and diagrams. Returns nil if not found.
+func (r *WorkspaceRepo) GetSnapshot(id string) (*SnapshotContent, error) {
+	var sc SnapshotContent
+	var auto int
+	var data string
+	err := r.db.QueryRow("SELECT id, name, auto, created_at, data FROM snapshots WHERE id = ?", id).
+		Scan(&sc.ID, &sc.Name, &auto, &sc.CreatedAt, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sc.Auto = auto != 0
+	var d snapshotData
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		return nil, fmt.Errorf("decode snapshot %s: %w", id, err)
+	}
+	sc.Tables, sc.Relationships, sc.Diagrams = d.Tables, d.Relationships, d.Diagrams
+	return &sc, nil
+}
+
+// DeleteSnapshot removes a snapshot by ID.
+func (r *WorkspaceRepo) DeleteSnapshot(id string) error {
+	_, err := r.db.Exec("DELETE FROM snapshots WHERE id = ?", id)
+	return err
+}
+
+// DiffSnapshot compares a snapshot's catalog (from) with the current catalog (to).
+// Catalog IDs survive renames, so renamed tables and columns are reported as such.
+func (r *WorkspaceRepo) DiffSnapshot(id string) (schema.SchemaDiff, error) {
+	sc, err := r.requireSnapshot(id)
+	if err != nil {
+		return schema.SchemaDiff{}, err
+	}
+	current, err := r.CatalogDiagram()
+	if err != nil {
+		return schema.SchemaDiff{}, err
+	}
+	return schema.DiffDiagramsWithOptions(CatalogToDiagram(sc.Tables, sc.Relationships), current, schema.DiffOptions{MatchByID: true}), nil
+}
+
+// RestoreSnapshot replaces the whole catalog and all diagrams with the snapshot's.
+// The current state is snapshotted first so the restore can itself be undone. That
+// snapshot is a manual one, so pruning automatic snapshots never removes it.
+func (r *WorkspaceRepo) RestoreSnapshot(id string) error {
+	sc, err := r.requireSnapshot(id)
+	if err != nil {
+		return err
+	}
+	if _, err := r.createSnapshot("Before restoring "+sc.Name, false); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Cascades remove fields, overrides, relationship fields and placements.
+	for _, stmt := range []string{"DELETE FROM diagrams", "DELETE FROM catalog_relationships", "DELETE FROM catalog_tables"} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	for _, t := range sc.Tables {
+		if err := saveCatalogTableTx(tx, t); err != nil {
+			return err
+		}
+	}
+	for _, rel := range sc.Relationships {
+		if err := saveRelationshipTx(tx, rel); err != nil {
+			return err
+		}
+	}
+	for _, d := range sc.Diagrams {
+		if err := saveDiagramTx(tx, d); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// RestoreSnapshotTable restores a single catalog table (by ID) from a snapshot, with its
+// fields and indexes. Fields keep their IDs, so relationships and diagram placements
+// pointing at them stay intact. Relationships captured with the table are restored when
+// the tables they connect exist.
+func (r *WorkspaceRepo) RestoreSnapshotTable(snapshotID, tableID string) error {
+	sc, err := r.requireSnapshot(snapshotID)
+	if err != nil {
+		return err
+	}
+	var table *CatalogTable
+	for i := range sc.Tables {
+		if sc.Tables[i].ID == tableID {
+			table = &sc.Tables[i]
+			break
+		}
+	}
+	if table == nil {
+		return fmt.Errorf("table %s not found in snapshot %s", tableID, snapshotID)
+	}
+	keep := make(map[string]bool, len(table.Fields))
+	for _, f := range table.Fields {
+		keep[f.ID] = true
+	}
+	current, err := r.GetFieldsForTable(tableID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := upsertTableRowTx(tx, *table); err != nil {
+		return err
+	}
+	for _, f := range current {
+		if keep[f.ID] {
+			continue
+		}
+		// Relationships using a field that goes away cannot be kept half-mapped.
+		if _, err := tx.Exec(
+			`DELETE FROM catalog_relationships WHERE id IN (
+			   SELECT relationship_id FROM catalog_relationship_fields WHERE source_field_id = ? OR target_field_id = ?)`,
+			f.ID, f.ID,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM catalog_fields WHERE id = ?", f.ID); err != nil {
+			return err
+		}
+	}
+	for _, f := range table.Fields {
+		f.TableID = table.ID
+		if err := saveFieldTx(tx, f); err != nil {
+			return err
+		}
+	}
+	// Indexes are replaced after the fields their keys point at are back.
+	if _, err := tx.Exec("DELETE FROM catalog_indexes WHERE table_id = ?", table.ID); err != nil {
+		return err
+	}
+	for _, ix := range table.Indexes {
+		ix.TableID = table.ID
+		if err := saveIndexTx(tx, ix); err != nil {
+			return err
+		}
+	}
+	for _, rel := range sc.Relationships {
+		if rel.SourceTableID != tableID && rel.TargetTableID != tableID {
+			continue
+		}
+		ok, err := relationshipRestorable(tx, rel)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err := saveRelationshipTx(tx, rel); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// relationshipRestorable reports whether every field a relationship maps still exists.
+func relationshipRestorable(tx *sql.Tx, rel CatalogRelationship) (bool, error) {
+	for _, f := range rel.Fields {
+		want := 2
+		if f.SourceFieldID == f.TargetFieldID {
+			want = 1
+		}
+		var n int
+		if err := tx.QueryRow(
+			"SELECT COUNT(*) FROM catalog_fields WHERE id IN (?, ?)", f.SourceFieldID, f.TargetFieldID,
+		).Scan(&n); err != nil {
+			return false, err
+		}
+		if n != want {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *WorkspaceRepo) requireSnapshot(id string) (*SnapshotContent, error) {
+	sc, err := r.GetSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+	if sc == nil {
+		return nil, fmt.Errorf("snapshot %s not found", id)
+	}
+	return sc, nil
+}
+
+func (r *WorkspaceRepo) createSnapshot(name string, auto bool) (Snapshot, error) {
+	data, err := r.snapshotData()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return r.insertSnapshot(name, auto, data)
+}
+
+func (r *WorkspaceRepo) insertSnapshot(name string, auto bool, data []byte) (Snapshot, error) {
+	s := Snapshot{ID: uuid.New().String(), Name: name, Auto: auto}
+	if _, err := r.db.Exec(
+		"INSERT INTO snapshots (id, name, auto, data) VALUES (?, ?, ?, ?)",
+		s.ID, s.Name, boolToInt(auto), string(data),
+	); err != nil {
+		return Snapshot{}, err
+	}
+	err := r.db.QueryRow("SELECT created_at FROM snapshots WHERE id = ?", s.ID).Scan(&s.CreatedAt)
+	return s, err
+}
+
+// snapshotData serializes the current catalog and all diagrams.
+func (r *WorkspaceRepo) snapshotData() ([]byte, error) {
+	var d snapshotData
+	var err error
+	if d.Tables, err = r.ListCatalogTables(); err != nil {
+		return nil, err
+	}
+	if d.Relationships, err = r.ListCatalogRelationships(); err != nil {
+		return nil, err
+	}
+	summaries, err := r.ListDiagrams()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range summaries {
+		diag, err := r.GetDiagram(s.ID)
+		if err != nil {
+			return nil, err
+		}
+		if diag != nil {
+			d.Diagrams = append(d.Diagrams, *diag)
+		}
+	}
+	return json.Marshal(d)
+}
@@ -0,0 +1,243 @@
+package workspace
+
+import (
+	"testing"
+
+	"schemastudio/internal/schema"
+)
+
+// backdateSnapshots moves every snapshot's creation time an hour into the past, out of
+// the auto-snapshot interval.
+func backdateSnapshots(t *testing.T, repo *WorkspaceRepo) {
+	t.Helper()
+	if _, err := repo.db.Exec("UPDATE snapshots SET created_at = datetime(created_at, '-1 hour')"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// countSnapshots returns the number of automatic and manual snapshots.
+func countSnapshots(t *testing.T, repo *WorkspaceRepo) (auto, manual int) {
+	t.Helper()
+	snaps, err := repo.ListSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range snaps {
+		if s.Auto {
+			auto++
+		} else {
+			manual++
+		}
+	}
+	return auto, manual
+}
+
+// addTable merges a one-column table into the catalog, changing the workspace.
+func addTable(t *testing.T, repo *WorkspaceRepo, name string) {
+	t.Helper()
+	imported := schema.TableCatalog{Tables: []schema.Table{
+		{ID: "t1", Name: name, Fields: []schema.Field{{ID: "f1", Name: "id", Type: "integer", PrimaryKey: true}}},
+	}}
+	if _, err := repo.MergeImportedCatalog(imported, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAutoSnapshot(t *testing.T) {
+	repo := newTestRepo(t)
+	addTable(t, repo, "a")
+	if took, err := repo.AutoSnapshot(); err != nil || took {
+		t.Fatalf("AutoSnapshot with the setting off = %t, %v", took, err)
+	}
+	if err := repo.SetSetting("auto_snapshot", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if took, err := repo.AutoSnapshot(); err != nil || !took {
+		t.Fatalf("first AutoSnapshot = %t, %v", took, err)
+	}
+
+	// Within the interval nothing is taken, even after a change.
+	addTable(t, repo, "b")
+	if took, err := repo.AutoSnapshot(); err != nil || took {
+		t.Errorf("AutoSnapshot within the interval = %t, %v", took, err)
+	}
+	backdateSnapshots(t, repo)
+	if took, err := repo.AutoSnapshot(); err != nil || !took {
+		t.Errorf("AutoSnapshot after the interval = %t, %v", took, err)
+	}
+	// Nothing changed since the latest snapshot.
+	backdateSnapshots(t, repo)
+	if took, err := repo.AutoSnapshot(); err != nil || took {
+		t.Errorf("AutoSnapshot without changes = %t, %v", took, err)
+	}
+
+	// Only the newest maxAutoSnapshots automatic snapshots are kept; manual ones stay.
+	if _, err := repo.CreateSnapshot("milestone"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < maxAutoSnapshots; i++ {
+		addTable(t, repo, string(rune('c'+i)))
+		backdateSnapshots(t, repo)
+		if took, err := repo.AutoSnapshot(); err != nil || !took {
+			t.Fatalf("AutoSnapshot %d = %t, %v", i, took, err)
+		}
+	}
+	if auto, manual := countSnapshots(t, repo); auto != maxAutoSnapshots || manual != 1 {
+		t.Errorf("snapshots: %d automatic, %d manual; want %d and 1", auto, manual, maxAutoSnapshots)
+	}
+	snaps, err := repo.ListSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err := repo.GetSnapshot(snaps[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(latest.Tables); n != 2+maxAutoSnapshots {
+		t.Errorf("newest snapshot has %d tables, want %d", n, 2+maxAutoSnapshots)
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	repo := newTestRepo(t)
+	if _, err := repo.MergeImportedCatalog(mergeFixture(), false); err != nil {
+		t.Fatal(err)
+	}
+	v1, err := repo.CreateSnapshot("v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := catalogByName(t, repo)
+	addTable(t, repo, "extra")
+
+	if err := repo.RestoreSnapshot(v1.ID); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := catalogByName(t, repo)
+	if len(after) != len(before) || after["extra"].ID != "" || after["orders"].ID != before["orders"].ID {
+		t.Errorf("restored catalog = %+v", after)
+	}
+	if rels, err := repo.ListCatalogRelationships(); err != nil || len(rels) != 2 {
+		t.Errorf("restored relationships = %+v, %v", rels, err)
+	}
+
+	// The safety snapshot is manual, so pruning automatic snapshots cannot remove it.
+	snaps, err := repo.ListSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 || snaps[0].Name != "Before restoring v1" || snaps[0].Auto {
+		t.Fatalf("snapshots = %+v", snaps)
+	}
+	if err := repo.SetSetting("auto_snapshot", "true"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= maxAutoSnapshots; i++ {
+		addTable(t, repo, string(rune('a'+i)))
+		backdateSnapshots(t, repo)
+		if _, err := repo.AutoSnapshot(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	safety, err := repo.GetSnapshot(snaps[0].ID)
+	if err != nil || safety == nil {
+		t.Fatalf("safety snapshot was pruned: %v", err)
+	}
+	if len(safety.Tables) != len(before)+1 {
+		t.Errorf("safety snapshot has %d tables, want %d", len(safety.Tables), len(before)+1)
+	}
+
+	if err := repo.RestoreSnapshot("missing"); err == nil {
+		t.Error("expected an error for a missing snapshot")
+	}
+}
+
+func TestRestoreSnapshotTable(t *testing.T) {
+	repo := newTestRepo(t)
+	if _, err := repo.MergeImportedCatalog(mergeFixture(), false); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := repo.CreateSnapshot("v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, fields := catalogByName(t, repo)
+
+	// orders loses legacy_id (and its relationship) and gains notes; customers gains email.
+	changed := mergeFixture()
+	changed.Tables[1].Fields[2] = schema.Field{ID: "f5", Name: "notes", Type: "string", Nullable: true}
+	changed.Tables[0].Fields = append(changed.Tables[0].Fields, schema.Field{ID: "f7", Name: "email", Type: "string"})
+	changed.Relationships = changed.Relationships[:1]
+	if _, err := repo.MergeImportedCatalog(changed, false); err != nil {
+		t.Fatal(err)
+	}
+	if rels, _ := repo.ListCatalogRelationships(); len(rels) != 1 {
+		t.Fatalf("relationships after the change = %+v", rels)
+	}
+
+	if err := repo.RestoreSnapshotTable(snap.ID, tables["orders"].ID); err != nil {
+		t.Fatal(err)
+	}
+	_, after := catalogByName(t, repo)
+	if after["orders.legacy_id"].ID != fields["orders.legacy_id"].ID {
+		t.Errorf("orders.legacy_id = %+v, want it back with ID %s", after["orders.legacy_id"], fields["orders.legacy_id"].ID)
+	}
+	if _, ok := after["orders.notes"]; ok {
+		t.Error("orders.notes was not removed")
+	}
+	if _, ok := after["customers.email"]; !ok {
+		t.Error("customers was restored too; only orders should be")
+	}
+	rels, err := repo.ListCatalogRelationships()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 2 {
+		t.Errorf("expected the legacy relationship restored, got %+v", rels)
+	}
+
+	if err := repo.RestoreSnapshotTable(snap.ID, "missing"); err == nil {
+		t.Error("expected an error for a table missing from the snapshot")
+	}
+}
+
+func TestRestoreSnapshotTable_Indexes(t *testing.T) {
+	repo := newTestRepo(t)
+	if _, err := repo.MergeImportedCatalog(mergeFixture(), false); err != nil {
+		t.Fatal(err)
+	}
+	tables, fields := catalogByName(t, repo)
+	orders := tables["orders"].ID
+	byCustomer := CatalogIndex{ID: "ix1", TableID: orders, Name: "orders_customer_idx",
+		Columns: []CatalogIndexColumn{{FieldID: fields["orders.customer_id"].ID}}}
+	if err := repo.SaveIndex(byCustomer); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := repo.CreateSnapshot("v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The index becomes unique and a second one is added.
+	byCustomer.Unique = true
+	if err := repo.SaveIndex(byCustomer); err != nil {
+		t.Fatal(err)
+	}
+	byLegacy := CatalogIndex{ID: "ix2", TableID: orders, Name: "orders_legacy_idx", SortOrder: 1,
+		Columns: []CatalogIndexColumn{{FieldID: fields["orders.legacy_id"].ID, Desc: true}}}
+	if err := repo.SaveIndex(byLegacy); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.RestoreSnapshotTable(snap.ID, orders); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.GetIndexesForTable(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != "ix1" || got[0].Unique ||
+		len(got[0].Columns) != 1 || got[0].Columns[0].FieldID != fields["orders.customer_id"].ID {
+		t.Errorf("indexes after restore = %+v", got)
+	}
+}
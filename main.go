@@ -3,12 +3,14 @@ package main
 import (
 	"embed"
 	"log"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
 
 	"schemastudio/internal/app"
+	"schemastudio/internal/cli"
 )
 
 var Version string = "0.4.2"
@@ -17,6 +19,11 @@ var Version string = "0.4.2"
 var assets embed.FS
 
 func main() {
+	// Subcommands (export, import, inspect, diff) run headless without a window.
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	a := app.NewApp(Version)
 	err := wails.Run(&options.App{
 		Title:  "Schema Studio",